@@ -0,0 +1,358 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"os"
+
+	"github.com/aakash4dev/ethereum-transaction-simulator/internal/chain"
+	"github.com/aakash4dev/ethereum-transaction-simulator/internal/config"
+	"github.com/aakash4dev/ethereum-transaction-simulator/internal/diagnostics"
+	"github.com/aakash4dev/ethereum-transaction-simulator/internal/runs"
+	"github.com/aakash4dev/ethereum-transaction-simulator/internal/transaction"
+	"github.com/aakash4dev/ethereum-transaction-simulator/internal/wallet"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// runCommand runs the subcommand named by args[0] with the remaining arguments
+func runCommand(ctx context.Context, cfg *config.Config, args []string, f flags) error {
+	name, args := args[0], args[1:]
+	switch name {
+	case "runs":
+		return runsCommand(args)
+	case "manifest":
+		return manifestCommand(args)
+	case "dashboards":
+		opts, err := diagnostics.ParseDashboardsArgs(args)
+		if err != nil {
+			return err
+		}
+		return diagnostics.ExportDashboards(opts, os.Stdout)
+	case "decode":
+		return decodeCommand(ctx, cfg, args)
+	case "simulate-tx":
+		return simulateCommand(ctx, cfg, args)
+	case "snapshot":
+		return snapshotCommand(ctx, cfg)
+	case "revert":
+		return revertCommand(ctx, cfg, args)
+	case "status":
+		return statusCommand(ctx, cfg, args)
+	case "wallets":
+		return walletsCommand(ctx, cfg, args)
+	case "fund":
+		return fundCommand(ctx, cfg, args, f)
+	case "sweep":
+		return sweepCommand(ctx, cfg, args, f)
+	case "cancel-pending":
+		return cancelCommand(ctx, cfg, args, f)
+	case "experiment":
+		return experimentCommand(ctx, cfg, args, f)
+	default:
+		return fmt.Errorf("unknown command %q", name)
+	}
+}
+
+// runsCommand lists past runs: `simulator runs list [--dir runs]`
+func runsCommand(args []string) error {
+	opts, err := runs.ParseRunsArgs(args)
+	if err != nil {
+		return err
+	}
+	listings, err := runs.List(opts.Dir)
+	if err != nil {
+		return err
+	}
+	runs.PrintRuns(listings)
+	return nil
+}
+
+// manifestCommand looks wallets up in a manifest: `simulator manifest --file manifest.json [address|index ...]`
+func manifestCommand(args []string) error {
+	opts, err := wallet.ParseManifestArgs(args)
+	if err != nil {
+		return err
+	}
+	manifest, err := wallet.LoadManifest(opts.Path)
+	if err != nil {
+		return err
+	}
+	entries, err := manifest.Lookup(opts.Queries)
+	if err != nil {
+		return err
+	}
+	wallet.PrintManifestEntries(os.Stdout, entries)
+	return nil
+}
+
+// decodeCommand decodes a transaction memo; with --data it needs no node
+func decodeCommand(ctx context.Context, cfg *config.Config, args []string) error {
+	opts, err := transaction.ParseDecodeArgs(args)
+	if err != nil {
+		return err
+	}
+	var client *ethclient.Client
+	if opts.Data == "" {
+		n, err := connect(ctx, cfg)
+		if err != nil {
+			return err
+		}
+		defer n.Close()
+		client = n.client
+	}
+	memo, payload, err := transaction.DecodeCommand(ctx, client, opts)
+	if err != nil {
+		return err
+	}
+	transaction.PrintMemo(memo, payload)
+	return nil
+}
+
+// simulateCommand runs a transaction against the pending block without sending it
+func simulateCommand(ctx context.Context, cfg *config.Config, args []string) error {
+	opts, err := transaction.ParseSimulateArgs(args)
+	if err != nil {
+		return err
+	}
+	if opts.Key == "" {
+		opts.Key = cfg.PrivateKey
+	}
+	n, err := connect(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	defer n.Close()
+
+	sim, err := transaction.SimulateCommand(ctx, n.rpc, opts)
+	if err != nil {
+		return err
+	}
+	transaction.PrintSimulation(sim)
+	return nil
+}
+
+// snapshotCommand takes an evm_snapshot of an anvil or hardhat node and prints its ID
+func snapshotCommand(ctx context.Context, cfg *config.Config) error {
+	dev, closeNode, err := connectDevNode(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	defer closeNode()
+
+	id, err := dev.Snapshot(ctx)
+	if err != nil {
+		return err
+	}
+	fmt.Println(id)
+	return nil
+}
+
+// revertCommand reverts an anvil or hardhat node to a snapshot: `simulator revert --id 0x...`
+func revertCommand(ctx context.Context, cfg *config.Config, args []string) error {
+	id, err := chain.ParseRevertArgs(args)
+	if err != nil {
+		return err
+	}
+	dev, closeNode, err := connectDevNode(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	defer closeNode()
+
+	if err := dev.Revert(ctx, id); err != nil {
+		return err
+	}
+	fmt.Printf("Reverted to snapshot %s\n", id)
+	return nil
+}
+
+// connectDevNode connects to the write endpoint and checks that it is a node with dev methods
+func connectDevNode(ctx context.Context, cfg *config.Config) (*chain.DevNode, func(), error) {
+	n, err := connect(ctx, cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+	n.caps = chain.Detect(ctx, n.write, capabilityTimeout)
+	dev, err := n.devNode()
+	if err != nil {
+		n.Close()
+		return nil, nil, err
+	}
+	return dev, n.Close, nil
+}
+
+// statusCommand prints chain and funder readiness: `simulator status [--wallets wallets.json] [--manifest manifest.json]`
+func statusCommand(ctx context.Context, cfg *config.Config, args []string) error {
+	opts, err := wallet.ParseStatusArgs(args)
+	if err != nil {
+		return err
+	}
+	if err := validate(cfg); err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+	n, err := connect(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	defer n.Close()
+
+	funder, err := funderWallet(cfg, n.client)
+	if err != nil {
+		return err
+	}
+	var wallets []*wallet.Wallet
+	if opts.WalletsPath != "" {
+		if wallets, err = wallet.LoadWallets(opts.WalletsPath, n.client); err != nil {
+			return err
+		}
+	}
+	if opts.ManifestPath != "" {
+		manifest, err := wallet.LoadManifest(opts.ManifestPath)
+		if err != nil {
+			return err
+		}
+		if err := manifest.Verify(wallets); err != nil {
+			return err
+		}
+		fmt.Printf("Wallet file matches manifest %s\n", opts.ManifestPath)
+	}
+
+	status, err := newManager(cfg, n, new(big.Int)).Status(ctx, funder.Address, wallets)
+	if err != nil {
+		return err
+	}
+	wallet.PrintStatus(status)
+	return nil
+}
+
+// walletsCommand audits a wallet file: `simulator wallets stats [--wallets wallets.json]`
+func walletsCommand(ctx context.Context, cfg *config.Config, args []string) error {
+	opts, err := wallet.ParseWalletsArgs(args)
+	if err != nil {
+		return err
+	}
+	n, err := connect(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	defer n.Close()
+
+	wallets, err := wallet.LoadWallets(opts.WalletsPath, n.client)
+	if err != nil {
+		return err
+	}
+	wallet.PrintWalletStats(newManager(cfg, n, new(big.Int)).WalletStats(ctx, wallets))
+	return nil
+}
+
+// fundCommand funds a wallet file ahead of a run: `simulator fund --count 500 --amount 0.01ether --out wallets.json`
+func fundCommand(ctx context.Context, cfg *config.Config, args []string, f flags) error {
+	opts, err := wallet.ParseFundArgs(args)
+	if err != nil {
+		return err
+	}
+	if err := validate(cfg); err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+	n, err := connect(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	defer n.Close()
+	if err := n.openSubmitter(ctx, cfg); err != nil {
+		return err
+	}
+
+	spend := new(big.Int).Mul(opts.Amount, big.NewInt(int64(opts.Count)))
+	if err := newInterlock(cfg, f.allowMainnet).Check(n.chainID(), spend); err != nil {
+		return err
+	}
+	funder, err := funderWallet(cfg, n.client)
+	if err != nil {
+		return err
+	}
+	return newManager(cfg, n, opts.Amount).FundToFile(ctx, funder, opts)
+}
+
+// sweepCommand drains a wallet file: `simulator sweep --wallets wallets.json --to 0x...`
+func sweepCommand(ctx context.Context, cfg *config.Config, args []string, f flags) error {
+	opts, err := wallet.ParseSweepArgs(args)
+	if err != nil {
+		return err
+	}
+	if err := validate(cfg); err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+	n, err := connect(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	defer n.Close()
+	if err := n.openSubmitter(ctx, cfg); err != nil {
+		return err
+	}
+	// Sweeping only pays gas, the balances stay with the operator
+	if err := newInterlock(cfg, f.allowMainnet).Check(n.chainID(), new(big.Int)); err != nil {
+		return err
+	}
+
+	wallets, err := wallet.LoadWallets(opts.WalletsPath, n.client)
+	if err != nil {
+		return err
+	}
+	return sweep(ctx, newManager(cfg, n, new(big.Int)), wallets, opts.To)
+}
+
+// sweep sends the balances of wallets to to and prints the total
+func sweep(ctx context.Context, manager *wallet.Manager, wallets []*wallet.Wallet, to common.Address) error {
+	fmt.Printf("Sweeping %d wallets to %s\n", len(wallets), to.Hex())
+	swept, err := manager.SweepWallets(ctx, wallets, to)
+	if swept != nil {
+		fmt.Printf("Swept %s wei\n", swept.String())
+	}
+	return err
+}
+
+// cancelCommand clears stuck transactions: `simulator cancel-pending [--wallets wallets.json] [--max-bumps 8]`
+func cancelCommand(ctx context.Context, cfg *config.Config, args []string, f flags) error {
+	opts, err := wallet.ParseCancelArgs(args)
+	if err != nil {
+		return err
+	}
+	if err := validate(cfg); err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+	n, err := connect(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	defer n.Close()
+	if err := n.openSubmitter(ctx, cfg); err != nil {
+		return err
+	}
+	// Cancelling sends zero-value transactions, so it only pays gas
+	if err := newInterlock(cfg, f.allowMainnet).Check(n.chainID(), new(big.Int)); err != nil {
+		return err
+	}
+
+	funder, err := funderWallet(cfg, n.client)
+	if err != nil {
+		return err
+	}
+	wallets := []*wallet.Wallet{funder}
+	if opts.WalletsPath != "" {
+		workers, err := wallet.LoadWallets(opts.WalletsPath, n.client)
+		if err != nil {
+			return err
+		}
+		wallets = append(wallets, workers...)
+	}
+	results, err := newManager(cfg, n, new(big.Int)).CancelPending(ctx, wallets, opts.MaxBumps)
+	if err != nil {
+		return err
+	}
+	wallet.PrintCancelResults(results)
+	return nil
+}
@@ -0,0 +1,53 @@
+// Command simulator generates transaction load against an EVM-compatible RPC
+// endpoint. Without a subcommand it runs the scenario selected by MODE; the
+// subcommands manage wallets, runs and dev nodes around those scenarios.
+package main
+
+import (
+	"context"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/aakash4dev/ethereum-transaction-simulator/internal/config"
+	"github.com/aakash4dev/ethereum-transaction-simulator/internal/wallet"
+)
+
+// flags holds the command-line flags accepted before or after any subcommand
+type flags struct {
+	estimate     bool // --estimate: print the run's cost and ask before sending
+	allowMainnet bool // --i-know-this-is-mainnet
+}
+
+func main() {
+	os.Exit(run(os.Args[1:]))
+}
+
+// run executes the subcommand or scenario selected by args and returns the exit code
+func run(args []string) int {
+	profile, args := config.ProfileFromArgs(args)
+	if profile != "" {
+		os.Setenv("PROFILE", profile)
+	}
+	var f flags
+	f.estimate, args = wallet.EstimateFromArgs(args)
+	f.allowMainnet, args = wallet.AllowMainnetFromArgs(args)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	cfg := config.Load()
+	config.WatchReload(ctx, cfg, checkComponents)
+	var err error
+	if len(args) > 0 {
+		err = runCommand(ctx, cfg, args, f)
+	} else {
+		err = runScenario(ctx, cfg, f)
+	}
+	if err != nil {
+		log.Printf("Error: %v", err)
+		return 1
+	}
+	return 0
+}
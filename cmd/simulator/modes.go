@@ -0,0 +1,540 @@
+package main
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"fmt"
+	"log"
+	"math/big"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aakash4dev/ethereum-transaction-simulator/internal/config"
+	"github.com/aakash4dev/ethereum-transaction-simulator/internal/contract"
+	"github.com/aakash4dev/ethereum-transaction-simulator/internal/loadtest"
+	"github.com/aakash4dev/ethereum-transaction-simulator/internal/probe"
+	"github.com/aakash4dev/ethereum-transaction-simulator/internal/runs"
+	"github.com/aakash4dev/ethereum-transaction-simulator/internal/transaction"
+	"github.com/aakash4dev/ethereum-transaction-simulator/internal/wallet"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+const (
+	// randomRecipients is the number of random addresses sequential and bundle transfers go to
+	randomRecipients = 25
+
+	// probePollInterval is how often probe modes check the pool
+	probePollInterval = 2 * time.Second
+)
+
+// runMode runs the workload MODE selects and returns the metrics for the run report
+func runMode(ctx context.Context, s *session) (interface{}, error) {
+	switch mode := strings.ToLower(s.cfg.Mode); mode {
+	case "transfer":
+		return nil, runTransfers(s, nil)
+	case "deploy", "interact":
+		return nil, runContracts(s, nil, mode == "interact")
+	case "all":
+		return nil, runAll(s)
+	case "bundles":
+		return nil, runBundles(ctx, s)
+	case "replay":
+		return runReplay(ctx, s)
+	case "reads", "logs", "archive", "ws-fanout", "trace":
+		return runReadWorkload(ctx, s)
+	case "parallel", "soak", "adaptive", "shaped", "gas-grief", "large-deploy", "call-depth", "cancun":
+		return runParallel(ctx, s)
+	default:
+		return runProbe(ctx, s)
+	}
+}
+
+// runTransfers sends value transfers from PRIVATE_KEY to random addresses, sharing
+// nonceManager when it is not nil
+func runTransfers(s *session, nonceManager *transaction.NonceManager) error {
+	cfg, n := s.cfg, s.node
+	confirmation, err := confirmationStrategy(cfg)
+	if err != nil {
+		return err
+	}
+	sender, err := transaction.NewSenderWithClient(n.client, n.chainID(), cfg.PrivateKey, &transaction.SenderConfig{
+		RandomAddresses: contract.GenerateRandomAddresses(randomRecipients),
+		Value:           cfg.ValueFor("transfer"),
+		GasLimit:        cfg.GasLimitFor("transfer"),
+		Data:            []byte(cfg.TransactionData),
+		MaxTransactions: cfg.MaxTransactions,
+		DelaySeconds:    cfg.DelaySeconds,
+		GasOnly:         cfg.GasOnly,
+		Confirmation:    confirmation,
+	}, nonceManager)
+	if err != nil {
+		return err
+	}
+	defer sender.Close()
+	sender.SetSubmitter(n.submitter)
+	return sender.SendTransactions()
+}
+
+// runContracts deploys storage contracts from PRIVATE_KEY and, with interact set,
+// calls them; PARALLEL_CONTRACTS are called instead of deploying when set
+func runContracts(s *session, nonceManager *transaction.NonceManager, interact bool) error {
+	deployer, err := newDeployer(s, nonceManager)
+	if err != nil {
+		return err
+	}
+	defer deployer.Close()
+	registry := contractRegistry(s.cfg)
+	if registry != nil {
+		deployer.SetRegistry(registry)
+		defer func() { contract.PrintRegistry(registry.Stats()) }()
+	}
+
+	addresses := s.cfg.ParallelContractAddresses()
+	if len(addresses) == 0 || !interact {
+		if addresses, err = deployer.DeployContract(); err != nil {
+			return err
+		}
+		s.recordContracts(map[string][]common.Address{"storage": addresses})
+	}
+	if !interact {
+		return nil
+	}
+	return deployer.InteractWithContract(addresses)
+}
+
+// runAll runs transfers alongside contract deployments and calls, both from
+// PRIVATE_KEY through one nonce manager
+func runAll(s *session) error {
+	funder, err := funderWallet(s.cfg, s.node.client)
+	if err != nil {
+		return err
+	}
+	var wg sync.WaitGroup
+	errs := make([]error, 2)
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		errs[0] = runTransfers(s, funder.NonceManager)
+	}()
+	go func() {
+		defer wg.Done()
+		errs[1] = runContracts(s, funder.NonceManager, true)
+	}()
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// newDeployer returns a deployer for PRIVATE_KEY on the session's node, sharing
+// nonceManager when it is not nil
+func newDeployer(s *session, nonceManager *transaction.NonceManager) (*contract.Deployer, error) {
+	cfg, n := s.cfg, s.node
+	deployer, err := contract.NewDeployerWithClient(n.client, n.chainID(), cfg.PrivateKey, &contract.DeployerConfig{
+		Value:            cfg.ValueFor("deploy"),
+		GasLimit:         cfg.GasLimitFor("deploy"),
+		InteractValue:    cfg.ValueFor("interact"),
+		InteractGasLimit: cfg.GasLimitFor("interact"),
+		MaxTransactions:  cfg.MaxTransactions,
+		DelaySeconds:     cfg.DelaySeconds,
+		Create2:          cfg.Create2Deploy,
+		Create2Namespace: cfg.Create2Namespace,
+	}, nonceManager)
+	if err != nil {
+		return nil, err
+	}
+	deployer.SetSubmitter(n.submitter)
+	return deployer, nil
+}
+
+// runBundles submits transfers as bundles to BUNDLE_RELAY_URL
+func runBundles(ctx context.Context, s *session) error {
+	cfg, n := s.cfg, s.node
+	var authKey *ecdsa.PrivateKey
+	var err error
+	if cfg.BundleAuthKey != "" {
+		authKey, err = crypto.HexToECDSA(strings.TrimPrefix(cfg.BundleAuthKey, "0x"))
+	} else {
+		authKey, err = crypto.GenerateKey()
+	}
+	if err != nil {
+		return fmt.Errorf("failed to load bundle auth key: %w", err)
+	}
+	sender, err := transaction.NewBundleSender(n.client, transaction.NewBundleClient(cfg.BundleRelayURL, authKey), cfg.PrivateKey, n.chainID(), &transaction.BundleConfig{
+		RandomAddresses: contract.GenerateRandomAddresses(randomRecipients),
+		Value:           cfg.ValueFor("transfer"),
+		GasLimit:        cfg.GasLimitFor("transfer"),
+		Data:            []byte(cfg.TransactionData),
+		BundleSize:      cfg.BundleSize,
+		MaxTransactions: cfg.MaxTransactions,
+		Policy:          addressPolicy(cfg),
+	})
+	if err != nil {
+		return err
+	}
+	return sender.SendBundles(ctx)
+}
+
+// runReplay submits the signed transactions in REPLAY_FILE as they are
+func runReplay(ctx context.Context, s *session) (interface{}, error) {
+	f, err := os.Open(s.cfg.ReplayFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open REPLAY_FILE: %w", err)
+	}
+	txs, err := transaction.ReadRawTransactions(f)
+	f.Close()
+	if err != nil {
+		return nil, err
+	}
+	fmt.Printf("Replaying %d transactions from %s\n", len(txs), s.cfg.ReplayFile)
+	result := transaction.Replay(ctx, s.node.submitter, txs, &transaction.ReplayConfig{Rate: float64(s.cfg.ReplayTPS)})
+	transaction.PrintReplayResults(result)
+	return result, ctx.Err()
+}
+
+// runProbe runs the probe mode of the session, which sends from PRIVATE_KEY. Probes
+// watch the pool their transactions are sent into, so they query the write endpoint.
+func runProbe(ctx context.Context, s *session) (interface{}, error) {
+	cfg, n := s.cfg, s.node
+	prober, err := probe.NewProber(n.writer, cfg.PrivateKey, n.chainID())
+	if err != nil {
+		return nil, err
+	}
+	prober.SetSubmitter(n.submitter)
+	observe := time.Duration(cfg.ProbeObserveSeconds) * time.Second
+	gasLimit := cfg.GasLimitFor("transfer")
+
+	switch strings.ToLower(cfg.Mode) {
+	case "spam-probe":
+		results, err := prober.RunSpamProbe(ctx, &probe.SpamConfig{
+			Rounds:       cfg.MaxTransactions,
+			Replacements: 5,
+			GasLimit:     gasLimit,
+			SettleTime:   observe,
+		})
+		probe.PrintResults("Spam Probe", results)
+		return results, err
+
+	case "fee-probe":
+		points, err := prober.RunFeeCurve(ctx, &probe.FeeCurveConfig{
+			MinPercent:   cfg.FeeProbeMinPercent,
+			MaxPercent:   cfg.FeeProbeMaxPercent,
+			Steps:        cfg.FeeProbeSteps,
+			GasLimit:     gasLimit,
+			ObserveFor:   observe,
+			PollInterval: probePollInterval,
+		})
+		probe.PrintFeeCurve(points)
+		return points, err
+
+	case "edge":
+		results, err := prober.RunEdgeSuite(ctx, observe)
+		probe.PrintResults("Edge Cases", results)
+		return results, err
+
+	case "invalid-tx":
+		results, err := prober.RunInvalidSuite(ctx)
+		probe.PrintInvalidResults(results)
+		if err != nil {
+			return results, err
+		}
+		if accepted, mismatched := probe.InvalidFailures(results); accepted > 0 || mismatched > 0 {
+			return results, fmt.Errorf("%d invalid transactions accepted, %d refused for an unexpected reason", accepted, mismatched)
+		}
+		return results, nil
+
+	case "canary":
+		return nil, prober.RunCanary(ctx, &probe.CanaryConfig{
+			Interval:    time.Duration(cfg.CanaryIntervalSeconds) * time.Second,
+			MaxLatency:  time.Duration(cfg.CanaryMaxLatency) * time.Second,
+			WebhookURL:  cfg.CanaryWebhookURL,
+			ExitOnAlert: cfg.CanaryExitOnAlert,
+		})
+
+	case "selfdestruct":
+		observations, err := prober.RunSelfdestructSuite(ctx, &probe.SelfdestructConfig{
+			Rounds:     cfg.SelfdestructRounds,
+			SettleTime: observe,
+		})
+		probe.PrintSelfdestructResults(observations)
+		return observations, err
+
+	case "diff":
+		other, err := ethclient.DialContext(ctx, cfg.DiffRPCURL)
+		if err != nil {
+			return nil, fmt.Errorf("failed to connect to DIFF_RPC_URL: %w", err)
+		}
+		defer other.Close()
+		entries, err := prober.RunDifferential(ctx, other, &probe.DiffConfig{
+			Transactions: cfg.DiffTransactions,
+			SettleTime:   observe,
+		})
+		probe.PrintDiffReport(entries)
+		return entries, err
+
+	case "propagation":
+		observer, err := ethclient.DialContext(ctx, cfg.PropagationRPCURL)
+		if err != nil {
+			return nil, fmt.Errorf("failed to connect to PROPAGATION_RPC_URL: %w", err)
+		}
+		defer observer.Close()
+		pc := &probe.PropagationConfig{
+			Samples:      cfg.PropagationSamples,
+			SendInterval: time.Duration(cfg.PropagationSendMs) * time.Millisecond,
+			PollInterval: time.Duration(cfg.PropagationPollMs) * time.Millisecond,
+			Timeout:      observe,
+		}
+		samples, err := prober.RunPropagation(ctx, observer, pc)
+		probe.PrintPropagationResults(samples, pc.PollInterval)
+		return samples, err
+
+	case "nonce-gap":
+		offsets, err := probe.ParseNonceOffsets(cfg.NonceGapOffsets)
+		if err != nil {
+			return nil, fmt.Errorf("NONCE_GAP_OFFSETS: %w", err)
+		}
+		results, err := prober.RunNonceGap(ctx, &probe.NonceGapConfig{
+			Offsets:      offsets,
+			Fill:         cfg.NonceGapFill,
+			GasLimit:     gasLimit,
+			ObserveFor:   observe,
+			PollInterval: probePollInterval,
+		})
+		probe.PrintNonceGap(results)
+		return results, err
+
+	case "pool-pressure":
+		return runPoolPressure(ctx, s, prober, observe, gasLimit)
+
+	default:
+		return nil, fmt.Errorf("unknown mode %q", cfg.Mode)
+	}
+}
+
+// runPoolPressure funds POOL_PRESSURE_ACCOUNTS new accounts and has each send
+// POOL_PRESSURE_TXS consecutive nonces
+func runPoolPressure(ctx context.Context, s *session, prober *probe.Prober, observe time.Duration, gasLimit uint64) (interface{}, error) {
+	cfg, n := s.cfg, s.node
+	funder, err := funderWallet(cfg, n.client)
+	if err != nil {
+		return nil, err
+	}
+	manager := newManager(cfg, n, new(big.Int))
+	amount, err := fundingAmount(ctx, cfg, manager, funder.Address, cfg.PoolPressureAccounts*cfg.PoolPressureTxs,
+		cfg.PoolPressureAccounts, gasLimit, cfg.ValueFor("transfer"))
+	if err != nil {
+		return nil, err
+	}
+	manager.SetFundingAmount(amount)
+	accounts := manager.GenerateWallets(cfg.PoolPressureAccounts)
+	if err := s.recordWallets(accounts); err != nil {
+		return nil, err
+	}
+	keys := make([]*ecdsa.PrivateKey, len(accounts))
+	for i, w := range accounts {
+		keys[i] = w.PrivateKey
+		if n.policy != nil {
+			n.policy.AllowOwn(w.Address)
+		}
+	}
+	if err := manager.FundWallets(ctx, funder, accounts); err != nil {
+		return nil, err
+	}
+
+	report, err := prober.RunPoolPressure(ctx, keys, &probe.PoolPressureConfig{
+		TxsPerAccount: cfg.PoolPressureTxs,
+		GasLimit:      gasLimit,
+		ObserveFor:    observe,
+		PollInterval:  probePollInterval,
+	})
+	if report != nil {
+		probe.PrintPoolPressure(report)
+	}
+	return report, err
+}
+
+// runReadWorkload runs the read-only workload of the session, with the parallel write
+// load alongside when WITH_WRITES is set
+func runReadWorkload(ctx context.Context, s *session) (interface{}, error) {
+	cfg, n := s.cfg, s.node
+	mode := strings.ToLower(cfg.Mode)
+	var targets []common.Address
+	if mode == "reads" || mode == "archive" {
+		var err error
+		if targets, err = readTargets(ctx, s); err != nil {
+			return nil, err
+		}
+	}
+	contracts := cfg.ParallelContractAddresses()
+
+	var writes *engine
+	var err error
+	writesDone := make(chan error, 1)
+	readCtx, stopWrites := context.WithCancel(ctx)
+	defer stopWrites()
+	if cfg.WithWrites {
+		if writes, err = newEngine(ctx, s); err != nil {
+			return nil, err
+		}
+		defer writes.Close()
+		go func() {
+			_, err := writes.run(readCtx)
+			writesDone <- err
+		}()
+	}
+
+	var report interface{}
+	var runErr error
+	switch mode {
+	case "reads":
+		mix, err := loadtest.ParseReadMix(cfg.ReadMix)
+		if err != nil {
+			return nil, fmt.Errorf("READ_MIX: %w", err)
+		}
+		r, err := loadtest.RunReads(ctx, n.client, &loadtest.ReadConfig{
+			Rate:          float64(cfg.ReadRPS),
+			Duration:      time.Duration(cfg.ReadDuration) * time.Second,
+			Concurrency:   cfg.ReadConcurrency,
+			Mix:           mix,
+			Targets:       targets,
+			CallData:      contract.GetGetFunctionData(),
+			LogBlockRange: cfg.ReadLogBlockRange,
+		})
+		if r != nil {
+			loadtest.PrintReadReport(r)
+		}
+		report, runErr = r, err
+	case "logs":
+		ranges, err := loadtest.ParseBlockRanges(cfg.LogsBlockRanges)
+		if err != nil {
+			return nil, fmt.Errorf("LOGS_BLOCK_RANGES: %w", err)
+		}
+		topics, err := loadtest.ParseTopics(cfg.LogsTopics)
+		if err != nil {
+			return nil, fmt.Errorf("LOGS_TOPICS: %w", err)
+		}
+		r, err := loadtest.RunLogs(ctx, n.client, &loadtest.LogsConfig{
+			Rate:        float64(cfg.LogsRPS),
+			Duration:    time.Duration(cfg.LogsDuration) * time.Second,
+			Concurrency: cfg.LogsConcurrency,
+			Contracts:   contracts,
+			Topics:      topics,
+			Ranges:      ranges,
+		})
+		if r != nil {
+			loadtest.PrintLogsReport(r)
+		}
+		report, runErr = r, err
+	case "archive":
+		r, err := loadtest.RunArchive(ctx, n.client, &loadtest.ArchiveConfig{
+			Rate:         float64(cfg.ArchiveRPS),
+			Duration:     time.Duration(cfg.ArchiveDuration) * time.Second,
+			Concurrency:  cfg.ArchiveConcurrency,
+			Targets:      targets,
+			MaxDepth:     cfg.ArchiveMaxDepth,
+			Distribution: strings.ToLower(cfg.ArchiveDepthDistribution),
+		})
+		if r != nil {
+			loadtest.PrintArchiveReport(r)
+		}
+		report, runErr = r, err
+	case "ws-fanout":
+		kinds, err := loadtest.ParseSubscriptionKinds(cfg.WSSubscriptions)
+		if err != nil {
+			return nil, fmt.Errorf("WS_SUBSCRIPTIONS: %w", err)
+		}
+		r, err := loadtest.RunSubscriptions(ctx, &loadtest.SubscriptionConfig{
+			URL:          cfg.WSURL,
+			Connections:  cfg.WSConnections,
+			Kinds:        kinds,
+			LogAddresses: contracts,
+			Duration:     time.Duration(cfg.WSDuration) * time.Second,
+		})
+		if r != nil {
+			loadtest.PrintSubscriptionReport(r)
+		}
+		report, runErr = r, err
+	case "trace":
+		r, err := loadtest.RunTraces(ctx, n.rpc, &loadtest.TraceConfig{
+			Rate:         float64(cfg.TraceRPS),
+			Duration:     time.Duration(cfg.TraceDuration) * time.Second,
+			Concurrency:  cfg.TraceConcurrency,
+			Tracer:       cfg.TraceTracer,
+			Timeout:      time.Duration(cfg.TraceTimeout) * time.Second,
+			BlockPercent: cfg.TraceBlockPercent,
+			RecentBlocks: cfg.TraceRecentBlocks,
+		})
+		if r != nil {
+			loadtest.PrintTraceReport(r)
+			if s.run != nil {
+				if err := s.run.WriteJSON(runs.TraceFile, r); err != nil {
+					log.Printf("Warning: %v", err)
+				}
+			}
+		}
+		report, runErr = r, err
+	}
+
+	if writes != nil {
+		stopWrites()
+		if writeErr := <-writesDone; runErr == nil && writeErr != nil && ctx.Err() == nil {
+			runErr = fmt.Errorf("write load: %w", writeErr)
+		}
+	}
+	return report, runErr
+}
+
+// readTargets returns the accounts and contracts reads and archive queries go to:
+// PARALLEL_CONTRACTS, or a storage contract deployed for the run, and the funding
+// wallet
+func readTargets(ctx context.Context, s *session) ([]common.Address, error) {
+	targets := s.cfg.ParallelContractAddresses()
+	funder, err := funderWallet(s.cfg, s.node.client)
+	if err != nil {
+		return nil, err
+	}
+	if len(targets) == 0 {
+		code, err := contract.GetContractBytecode()
+		if err != nil {
+			return nil, err
+		}
+		deployer, err := newDeployer(s, funder.NonceManager)
+		if err != nil {
+			return nil, err
+		}
+		defer deployer.Close()
+		address, err := deployer.DeployBytecode(ctx, code)
+		if err != nil {
+			return nil, fmt.Errorf("failed to deploy read target: %w", err)
+		}
+		fmt.Printf("Deployed read target contract at %s\n", address.Hex())
+		s.recordContracts(map[string][]common.Address{"storage": {address}})
+		targets = append(targets, address)
+	}
+	return append(targets, funder.Address), nil
+}
+
+// fundingAmount returns FUNDING_AMOUNT, or with FUNDING_AMOUNT=auto the amount each of
+// count wallets needs for its share of maxTransactions
+func fundingAmount(ctx context.Context, cfg *config.Config, manager *wallet.Manager, funder common.Address, maxTransactions, count int, gasLimit uint64, value *big.Int) (*big.Int, error) {
+	if !cfg.AutoFunding() {
+		amount, ok := new(big.Int).SetString(cfg.FundingAmount, 10)
+		if !ok {
+			return nil, fmt.Errorf("invalid FUNDING_AMOUNT: %s", cfg.FundingAmount)
+		}
+		return amount, nil
+	}
+	plan, err := manager.EstimateFunding(ctx, funder, maxTransactions, count, gasLimit, value, cfg.FundingSafetyPercent)
+	if err != nil {
+		return nil, err
+	}
+	fmt.Printf("Auto funding: %s wei per wallet for %d transactions each\n", plan.PerWallet, plan.TxPerWallet)
+	return plan.PerWallet, nil
+}
@@ -0,0 +1,846 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/aakash4dev/ethereum-transaction-simulator/internal/config"
+	"github.com/aakash4dev/ethereum-transaction-simulator/internal/contract"
+	"github.com/aakash4dev/ethereum-transaction-simulator/internal/distributed"
+	"github.com/aakash4dev/ethereum-transaction-simulator/internal/loadtest"
+	"github.com/aakash4dev/ethereum-transaction-simulator/internal/remotesign"
+	"github.com/aakash4dev/ethereum-transaction-simulator/internal/runs"
+	"github.com/aakash4dev/ethereum-transaction-simulator/internal/transaction"
+	"github.com/aakash4dev/ethereum-transaction-simulator/internal/wallet"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const (
+	// recipientCount is the number of random addresses parallel transfers go to
+	recipientCount = 100
+
+	// sizingBlocks is the number of recent blocks TARGET_TPS sizing measures the block time over
+	sizingBlocks = 20
+
+	// maxPendingPerWallet is how many transactions of one account geth keeps pending
+	// per block, which caps the rate a single wallet can sustain
+	maxPendingPerWallet = 16
+
+	// agentReportInterval is how often an agent sends its counts to the coordinator
+	agentReportInterval = 5 * time.Second
+)
+
+// engine runs the parallel sender for a session: the workload, the wallet pool it
+// sends from, and the runner the mode selects
+type engine struct {
+	s        *session
+	cfg      *config.Config
+	funder   *wallet.Wallet
+	manager  *wallet.Manager
+	workload *transaction.ParallelConfig // Built once per engine, copied for each sender
+	wallets  []*wallet.Wallet            // Wallets this process holds keys for, nil with a remote signer
+	pool     []*transaction.ParallelWallet
+	remote   *remotesign.Client        // Set in the sender role
+	agent    *distributed.Agent        // Set when running as a distributed agent
+	export   *transaction.ExportWriter // Set when EXPORT_FILE is set
+	registry *contract.Registry        // Nil when CONTRACT_STATS is off
+	closers  []func()
+}
+
+// newEngine builds the workload of the session's mode and prepares the wallet pool
+func newEngine(ctx context.Context, s *session) (*engine, error) {
+	e := &engine{s: s, cfg: s.cfg, registry: contractRegistry(s.cfg)}
+	ok := false
+	defer func() {
+		if !ok {
+			e.Close()
+		}
+	}()
+
+	var err error
+	if s.cfg.PrivateKey != "" {
+		if e.funder, err = funderWallet(s.cfg, s.node.client); err != nil {
+			return nil, err
+		}
+	}
+	if e.workload, err = e.buildWorkload(ctx); err != nil {
+		return nil, err
+	}
+	if err := e.openPool(ctx); err != nil {
+		return nil, err
+	}
+	if s.cfg.ExportFile != "" {
+		if e.export, err = transaction.NewExportWriter(s.cfg.ExportFile); err != nil {
+			return nil, err
+		}
+		e.closers = append(e.closers, func() {
+			fmt.Printf("Wrote %d signed transactions to %s\n", e.export.Count(), s.cfg.ExportFile)
+			if err := e.export.Close(); err != nil {
+				log.Printf("Warning: %v", err)
+			}
+		})
+	}
+	ok = true
+	return e, nil
+}
+
+// Close releases the plugins, signer, coordinator connection and export file
+func (e *engine) Close() {
+	for i := len(e.closers) - 1; i >= 0; i-- {
+		e.closers[i]()
+	}
+	e.closers = nil
+}
+
+// buildWorkload turns the configuration into the parallel sender's settings and, for
+// the contract workloads, deploys the contracts they call. Deploying happens before
+// funding so the deployer and the funding transfers do not race for the funder's nonces.
+func (e *engine) buildWorkload(ctx context.Context) (*transaction.ParallelConfig, error) {
+	pc, err := parallelConfig(e.cfg)
+	if err != nil {
+		return nil, err
+	}
+	pc.RunID = e.s.runID
+
+	registry, builder, fees, err := openPlugins(e.cfg)
+	if err != nil {
+		return nil, err
+	}
+	if registry != nil {
+		e.closers = append(e.closers, func() { registry.Close() })
+	}
+	if builder != nil {
+		pc.Builder = builder
+	}
+	pc.FeeStrategy = fees
+
+	switch strings.ToLower(e.cfg.Mode) {
+	case "gas-grief":
+		address, err := e.deploy(ctx, "gas-grief", contract.GetGasGriefBytecode)
+		if err != nil {
+			return nil, err
+		}
+		pc.Contracts = []common.Address{address}
+		pc.Calldata = contract.GasGriefGenerator(pc.GasLimit, e.cfg.GriefReserveGas, e.cfg.GriefRevertPercent)
+	case "large-deploy":
+		pc.InitCode = contract.LargeContractGenerator(e.cfg.LargeCodeSize)
+		pc.GasLimit = largeDeployGasLimit(e.cfg)
+		pc.Value = big.NewInt(0)
+	case "call-depth":
+		depths, err := contract.ParseCallDepths(e.cfg.CallDepths)
+		if err != nil {
+			return nil, fmt.Errorf("CALL_DEPTHS: %w", err)
+		}
+		address, err := e.deploy(ctx, "call-depth", contract.GetRecursiveBytecode)
+		if err != nil {
+			return nil, err
+		}
+		pc.Contracts = []common.Address{address}
+		pc.Calldata = contract.RecursiveGenerator(depths)
+	case "cancun":
+		for i := range contract.CancunWorkloads {
+			w := &contract.CancunWorkloads[i]
+			if err := w.Supported(ctx, e.s.node.client); err != nil {
+				log.Printf("Warning: skipping %s: %v", w.Name, err)
+				continue
+			}
+			address, err := e.deploy(ctx, w.Name, w.BytecodeBytes)
+			if err != nil {
+				return nil, err
+			}
+			pc.Contracts = append(pc.Contracts, address)
+		}
+		if len(pc.Contracts) == 0 {
+			return nil, errors.New("the node supports none of the cancun workloads")
+		}
+		pc.Calldata = contract.IterationGenerator(e.cfg.CancunIterations)
+		pc.Value = big.NewInt(0)
+	}
+	return pc, nil
+}
+
+// deploy deploys the contract built by bytecode from the funding wallet and records
+// it in contracts.json
+func (e *engine) deploy(ctx context.Context, label string, bytecode func() ([]byte, error)) (common.Address, error) {
+	code, err := bytecode()
+	if err != nil {
+		return common.Address{}, err
+	}
+	if e.funder == nil {
+		return common.Address{}, fmt.Errorf("%s mode deploys its contract from PRIVATE_KEY, which is not set", e.cfg.Mode)
+	}
+	deployer, err := newDeployer(e.s, e.funder.NonceManager)
+	if err != nil {
+		return common.Address{}, err
+	}
+	defer deployer.Close()
+	address, err := deployer.DeployBytecode(ctx, code)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("failed to deploy %s contract: %w", label, err)
+	}
+	fmt.Printf("Deployed %s contract at %s\n", label, address.Hex())
+	if e.registry != nil {
+		e.registry.Register(address, label)
+	}
+	e.s.recordContracts(map[string][]common.Address{label: {address}})
+	return address, nil
+}
+
+// parallelConfig returns the parallel sender settings configured by cfg
+func parallelConfig(cfg *config.Config) (*transaction.ParallelConfig, error) {
+	confirmation, err := confirmationStrategy(cfg)
+	if err != nil {
+		return nil, err
+	}
+	var events *transaction.EventAssertions
+	if cfg.ExpectedEvents != "" {
+		if events, err = transaction.NewEventAssertions(cfg.ExpectedEvents); err != nil {
+			return nil, fmt.Errorf("EXPECTED_EVENTS: %w", err)
+		}
+	}
+	templates, err := loadTemplates(cfg)
+	if err != nil {
+		return nil, err
+	}
+	script, err := loadScript(cfg)
+	if err != nil {
+		return nil, err
+	}
+	thinkTime, err := parseThinkTime(cfg)
+	if err != nil {
+		return nil, err
+	}
+	personas, err := loadPersonas(cfg)
+	if err != nil {
+		return nil, err
+	}
+	zipf, err := cfg.ZipfExponent()
+	if err != nil {
+		return nil, err
+	}
+	failureRate, err := cfg.FailureRateLimit()
+	if err != nil {
+		return nil, err
+	}
+
+	pc := &transaction.ParallelConfig{
+		Value:                 cfg.ValueFor("parallel"),
+		GasLimit:              cfg.GasLimitFor("parallel"),
+		Data:                  []byte(cfg.TransactionData),
+		MaxTransactions:       cfg.MaxTransactions,
+		MaxConcurrentRequests: cfg.MaxConcurrentRequests,
+		BalanceCheckInterval:  cfg.BalanceCheckInterval,
+		RetryDelay:            time.Duration(cfg.RetryDelay) * time.Second,
+		VerifySampleRate:      cfg.VerifySampleRate,
+		MaxInFlight:           cfg.MaxInFlight,
+		MaxInFlightPerWallet:  cfg.MaxInFlightPerWallet,
+		Contracts:             cfg.ParallelContractAddresses(),
+		DataTemplate:          cfg.TxDataTemplate,
+		Memo:                  cfg.TxMemo,
+		GasOnly:               cfg.GasOnly,
+		Audit:                 cfg.Audit,
+		RateLimit:             float64(cfg.RateLimit),
+		ExpectedEvents:        events,
+		Confirmation:          confirmation,
+		MaxGasPrice:           cfg.MaxGasPriceWei(),
+		ThinkTime:             thinkTime,
+		Personas:              personas,
+		ZipfExponent:          zipf,
+		SlowStart:             slowStart(cfg),
+		ReportPositions:       cfg.InclusionPositions,
+		DropTimeout:           time.Duration(cfg.DropTimeoutSeconds) * time.Second,
+		MaxFailureRate:        failureRate,
+		FailureRateWindow:     cfg.FailureRateWindow,
+		Chaos:                 chaosConfig(cfg),
+	}
+	if templates != nil {
+		pc.Builder = templates
+	}
+	if script != nil {
+		pc.Builder = script
+	}
+	if len(pc.Contracts) > 0 {
+		pc.Calldata = contract.RandomSetCalldata
+	}
+	return pc, nil
+}
+
+// openPool selects the wallets the run sends from: the signer's wallets in the
+// sender role, this agent's shard in a distributed run, WALLETS_FILE, or new wallets
+// funded from PRIVATE_KEY
+func (e *engine) openPool(ctx context.Context) error {
+	n := e.s.node
+	e.manager = newManager(e.cfg, n, new(big.Int))
+
+	switch {
+	case e.cfg.SplitRole == "sender":
+		remote, err := remotesign.Dial(e.cfg.SignerSocket)
+		if err != nil {
+			return err
+		}
+		e.remote = remote
+		e.closers = append(e.closers, remote.Close)
+		if e.pool, err = remote.Wallets(n.client); err != nil {
+			return err
+		}
+		fmt.Printf("Sending for %d wallets held by the signer at %s\n", len(e.pool), e.cfg.SignerSocket)
+		return nil
+
+	case strings.ToLower(e.cfg.DistributedRole) == "agent":
+		hostname, _ := os.Hostname()
+		agent, err := distributed.Join(e.cfg.CoordinatorAddr, hostname, e.cfg.CoordinatorSecret, e.s.ntp)
+		if err != nil {
+			return err
+		}
+		e.agent = agent
+		e.closers = append(e.closers, agent.Close)
+		a := agent.Assignment
+		fmt.Printf("Joined coordinator %s as agent %d of %d: wallets %d-%d\n",
+			e.cfg.CoordinatorAddr, a.AgentID, a.Agents, a.WalletOffset, a.WalletOffset+a.WalletCount-1)
+		wallets, err := wallet.LoadWallets(e.cfg.WalletsFile, n.client)
+		if err != nil {
+			return err
+		}
+		if a.WalletOffset+a.WalletCount > len(wallets) {
+			return fmt.Errorf("assigned wallets %d-%d, but %s holds %d", a.WalletOffset, a.WalletOffset+a.WalletCount-1, e.cfg.WalletsFile, len(wallets))
+		}
+		e.workload.MaxTransactions = a.MaxTransactions
+		e.setWallets(wallets[a.WalletOffset : a.WalletOffset+a.WalletCount])
+		return nil
+
+	case e.cfg.WalletsFile != "":
+		wallets, err := wallet.LoadWallets(e.cfg.WalletsFile, n.client)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Loaded %d wallets from %s\n", len(wallets), e.cfg.WalletsFile)
+		e.setWallets(wallets)
+		return nil
+	}
+
+	if e.cfg.TargetTPS > 0 {
+		return e.sizePool(ctx)
+	}
+	amount, err := e.fundingAmount(ctx, e.cfg.WalletCount)
+	if err != nil {
+		return err
+	}
+	wallets, err := e.createWallets(ctx, e.cfg.WalletCount, amount)
+	if err != nil {
+		return err
+	}
+	e.setWallets(wallets)
+	return nil
+}
+
+// setWallets makes wallets the pool and lets the recipient policy accept transfers
+// between them
+func (e *engine) setWallets(wallets []*wallet.Wallet) {
+	e.wallets = wallets
+	e.pool = make([]*transaction.ParallelWallet, len(wallets))
+	addresses := make([]common.Address, len(wallets))
+	for i, w := range wallets {
+		e.pool[i] = &transaction.ParallelWallet{PrivateKey: w.PrivateKey, Address: w.Address, NonceManager: w.NonceManager}
+		addresses[i] = w.Address
+	}
+	if e.s.node.policy != nil {
+		e.s.node.policy.AllowOwn(addresses...)
+	}
+}
+
+// createWallets generates count wallets, records them in the run directory and funds
+// each with amount the way the configuration asks
+func (e *engine) createWallets(ctx context.Context, count int, amount *big.Int) ([]*wallet.Wallet, error) {
+	e.manager.SetFundingAmount(amount)
+	fmt.Printf("Generating %d wallets...\n", count)
+	wallets := e.manager.GenerateWallets(count)
+	if err := e.s.recordWallets(wallets); err != nil {
+		return nil, err
+	}
+	if e.s.node.policy != nil {
+		for _, w := range wallets {
+			e.s.node.policy.AllowOwn(w.Address)
+		}
+	}
+	if err := e.fund(ctx, wallets, amount); err != nil {
+		return nil, err
+	}
+	return wallets, nil
+}
+
+// fund funds wallets with amount each: through the dev node's setBalance, by waiting
+// for an external funder, from the faucet when the funding wallet is empty, or with
+// transfers from the funding wallet
+func (e *engine) fund(ctx context.Context, wallets []*wallet.Wallet, amount *big.Int) error {
+	cfg := e.cfg
+	if cfg.FaucetURL != "" {
+		faucet, err := wallet.NewFaucet(cfg.FaucetType, cfg.FaucetURL, cfg.FaucetBody, cfg.FaucetAPIKey)
+		if err != nil {
+			return err
+		}
+		e.manager.SetFaucet(faucet, cfg.FaucetRequestsPerMinute, cfg.FaucetRetries)
+	}
+
+	switch {
+	case cfg.DevFunding:
+		dev, err := e.s.node.devNode()
+		if err != nil {
+			return err
+		}
+		return e.manager.FundBySetBalance(ctx, dev, wallets)
+
+	case cfg.ExternalFunding:
+		out := os.Stdout
+		if cfg.AddressExportFile != "" {
+			f, err := os.Create(cfg.AddressExportFile)
+			if err != nil {
+				return fmt.Errorf("failed to create address file: %w", err)
+			}
+			defer f.Close()
+			out = f
+		}
+		if err := wallet.ExportAddresses(out, wallets, cfg.AddressExportFormat); err != nil {
+			return err
+		}
+		if cfg.AddressExportFile != "" {
+			fmt.Printf("Wallet addresses written to %s\n", cfg.AddressExportFile)
+		}
+		fmt.Printf("Waiting for %d wallets to hold %s wei...\n", len(wallets), amount)
+		return e.manager.WaitForFunding(ctx, wallets, amount, time.Duration(cfg.FundingPollInterval)*time.Second)
+	}
+
+	minBalance, ok := new(big.Int).SetString(cfg.MinBalance, 10)
+	if !ok {
+		return fmt.Errorf("invalid MIN_BALANCE: %s", cfg.MinBalance)
+	}
+	enough, balance, err := e.manager.CheckBalance(ctx, e.funder.Address, minBalance)
+	if err != nil {
+		return err
+	}
+	if !enough {
+		if cfg.FaucetURL != "" && balance.Sign() == 0 {
+			fmt.Printf("Funding wallet is empty, requesting funds for %d wallets from the faucet\n", len(wallets))
+			return e.manager.FundFromFaucet(ctx, wallets, time.Duration(cfg.RetryDelay)*time.Second)
+		}
+		return fmt.Errorf("funding wallet balance %s wei is below MIN_BALANCE %s wei", balance, minBalance)
+	}
+	return e.manager.FundWallets(ctx, e.funder, wallets)
+}
+
+// sizePool sizes the wallet pool for TARGET_TPS. The first estimate assumes each
+// wallet gets its pending slots mined every block; a warm-up then measures what a
+// wallet actually sustains and the pool is grown if that falls short.
+func (e *engine) sizePool(ctx context.Context) error {
+	n := e.s.node
+	blockTime, err := wallet.MeasureBlockTime(ctx, n.client, sizingBlocks)
+	if err != nil {
+		return err
+	}
+	gasPrice, err := n.client.SuggestGasPrice(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get gas price: %w", err)
+	}
+	run := wallet.SizingRun{
+		MaxTransactions: e.workload.MaxTransactions,
+		GasLimit:        e.workload.GasLimit,
+		GasPrice:        gasPrice,
+		Value:           e.workload.Value,
+		SafetyPercent:   e.cfg.FundingSafetyPercent,
+	}
+	target := float64(e.cfg.TargetTPS)
+	sizing := wallet.PlanSizing(target, blockTime, 0, maxPendingPerWallet, run)
+	fmt.Printf("Block time %s: starting with %d wallets for %.0f TPS\n", blockTime, sizing.WalletCount, target)
+
+	amount, err := e.sizedAmount(ctx, sizing)
+	if err != nil {
+		return err
+	}
+	wallets, err := e.createWallets(ctx, sizing.WalletCount, amount)
+	if err != nil {
+		return err
+	}
+	e.setWallets(wallets)
+
+	warmup := time.Duration(e.cfg.WarmupSeconds) * time.Second
+	fmt.Printf("Warming up for %s...\n", warmup)
+	warm := *e.workload
+	warm.MaxTransactions = 0
+	perWallet, err := wallet.MeasureWalletRate(ctx, e.newSender(&warm), len(e.pool), warmup)
+	if err != nil {
+		return err
+	}
+	resized := wallet.PlanSizing(target, blockTime, perWallet, maxPendingPerWallet, run)
+	fmt.Printf("Measured %.2f TPS per wallet: %d wallets needed\n", resized.PerWalletTPS, resized.WalletCount)
+	if extra := resized.WalletCount - len(wallets); extra > 0 {
+		more, err := e.createWallets(ctx, extra, amount)
+		if err != nil {
+			return err
+		}
+		e.setWallets(append(wallets, more...))
+	}
+	return nil
+}
+
+// sizedAmount returns the funding per wallet of a sized pool
+func (e *engine) sizedAmount(ctx context.Context, sizing *wallet.Sizing) (*big.Int, error) {
+	if e.cfg.AutoFunding() && sizing.Funding != nil {
+		fmt.Printf("Auto funding: %s wei per wallet\n", sizing.Funding.PerWallet)
+		return sizing.Funding.PerWallet, nil
+	}
+	return e.fundingAmount(ctx, sizing.WalletCount)
+}
+
+// fundingAmount returns the funding for each of count wallets sending the workload
+func (e *engine) fundingAmount(ctx context.Context, count int) (*big.Int, error) {
+	if e.funder == nil {
+		return nil, errors.New("funding wallets needs PRIVATE_KEY; set WALLETS_FILE to send from funded wallets instead")
+	}
+	return fundingAmount(ctx, e.cfg, e.manager, e.funder.Address, e.workload.MaxTransactions, count, e.workload.GasLimit, e.workload.Value)
+}
+
+// newSender returns a parallel sender for the pool with settings pc
+func (e *engine) newSender(pc *transaction.ParallelConfig) *transaction.ParallelSender {
+	n := e.s.node
+	ps := transaction.NewParallelSender(n.client, n.chainID(), e.pool, contract.GenerateRandomAddresses(recipientCount), pc)
+	switch {
+	case e.export != nil:
+		ps.SetSubmitter(e.export)
+	case n.submitter != nil:
+		ps.SetSubmitter(n.submitter)
+	}
+	if e.remote != nil {
+		ps.SetSigner(e.remote.SignTx)
+	}
+	if e.s.stream != nil {
+		ps.StreamEvents(e.s.stream)
+	}
+	if e.registry != nil {
+		ps.ObserveContracts(e.registry)
+	}
+	return ps
+}
+
+// run sends the workload with the runner the mode and options select, printing the
+// monitors' reports afterwards
+func (e *engine) run(ctx context.Context) (map[string]int64, error) {
+	cfg := e.cfg
+	pc := *e.workload
+	ps := e.newSender(&pc)
+
+	if e.agent != nil {
+		fmt.Println("Waiting for the coordinator to start the run...")
+		if err := e.agent.WaitStart(ctx); err != nil {
+			return nil, err
+		}
+	}
+	progressCtx, stopProgress := context.WithCancel(ctx)
+	defer stopProgress()
+	stopRetune := config.OnReload(func(c *config.Config) {
+		settings, err := liveSettings(c)
+		if err != nil {
+			log.Printf("Ignoring reload: %v", err)
+			return
+		}
+		ps.Apply(settings)
+	})
+	defer stopRetune()
+
+	if e.agent != nil {
+		go reportProgress(progressCtx, e.agent, ps, agentReportInterval)
+	}
+	monitors := e.startMonitors(ctx, ps)
+	runErr := e.runMode(ctx, ps)
+	monitors()
+	stopRetune()
+	stopProgress()
+
+	if e.registry != nil {
+		contract.PrintRegistry(e.registry.Stats())
+	}
+	if e.agent != nil {
+		sent, succeeded, failed, _ := ps.GetMetrics()
+		if err := e.agent.Report(sent, succeeded, failed, true); err != nil {
+			log.Printf("Warning: failed to report to coordinator: %v", err)
+		}
+	}
+	if errors.Is(runErr, transaction.ErrFailureRateExceeded) && cfg.AbortSweepTo != "" && e.wallets != nil {
+		if err := sweep(ctx, e.manager, e.wallets, common.HexToAddress(cfg.AbortSweepTo)); err != nil {
+			log.Printf("Warning: sweep after abort failed: %v", err)
+		}
+	}
+	return ps.Stats(), runErr
+}
+
+// runMode runs ps with the runner of the session's mode
+func (e *engine) runMode(ctx context.Context, ps *transaction.ParallelSender) error {
+	cfg := e.cfg
+	switch strings.ToLower(cfg.Mode) {
+	case "soak":
+		_, err := loadtest.RunSoak(ctx, ps, &loadtest.SoakConfig{
+			TPS:                float64(cfg.SoakTPS),
+			Duration:           time.Duration(cfg.SoakDuration) * time.Minute,
+			Window:             time.Duration(cfg.SoakWindow) * time.Minute,
+			DegradationPercent: cfg.SoakDegradationPercent,
+		})
+		return err
+	case "adaptive":
+		_, err := loadtest.RunAdaptive(ctx, ps, &loadtest.AdaptiveConfig{
+			StartTPS:          float64(cfg.AdaptiveStartTPS),
+			Window:            time.Duration(cfg.AdaptiveWindow) * time.Second,
+			MaxP95Latency:     time.Duration(cfg.AdaptiveMaxLatency) * time.Second,
+			MaxFailurePercent: float64(cfg.AdaptiveMaxFailurePercent),
+			StepPercent:       float64(cfg.AdaptiveStepPercent),
+		})
+		return err
+	case "shaped":
+		shapeCfg, err := shapeConfig(cfg)
+		if err != nil {
+			return err
+		}
+		shape, err := loadtest.NewShape(shapeCfg)
+		if err != nil {
+			return err
+		}
+		var warp *loadtest.TimeWarp
+		if cfg.ShapeTimeWarp > 0 {
+			dev, err := e.s.node.devNode()
+			if err != nil {
+				return err
+			}
+			warp = timeWarp(cfg, shapeCfg, dev)
+		}
+		return loadtest.RunShaped(ctx, ps, shape, time.Duration(cfg.ShapeDuration)*time.Minute, warp)
+	}
+
+	assertions, err := parseAssertions(cfg)
+	if err != nil {
+		return err
+	}
+	if assertions.Enabled() || cfg.BaselineFile != "" || cfg.BaselineSaveFile != "" {
+		return e.runAgainstBaseline(ctx, ps, assertions)
+	}
+	if cfg.FinalityTracking {
+		report, err := loadtest.RunWithFinality(ctx, ps, e.s.node.client, finalityConfig(cfg))
+		loadtest.PrintFinalityReport(report)
+		return err
+	}
+	return ps.SendParallelTransactions(ctx)
+}
+
+// runAgainstBaseline runs ps compared with BASELINE_FILE as it goes, then checks the
+// ASSERT_* bounds against the whole run
+func (e *engine) runAgainstBaseline(ctx context.Context, ps *transaction.ParallelSender, assertions *loadtest.Assertions) error {
+	cfg := e.cfg
+	bc := &loadtest.BaselineConfig{
+		Window:       time.Duration(cfg.BaselineWindow) * time.Second,
+		Percent:      cfg.BaselineRegressionPercent,
+		AbortWindows: cfg.BaselineAbortWindows,
+		SavePath:     cfg.BaselineSaveFile,
+	}
+	if bc.Window <= 0 {
+		bc.Window = time.Minute
+	}
+	if cfg.BaselineFile != "" {
+		baseline, err := loadtest.LoadBaseline(cfg.BaselineFile)
+		if err != nil {
+			return err
+		}
+		bc.Baseline = baseline
+	}
+
+	start := time.Now()
+	stats, runErr := loadtest.RunAgainstBaseline(ctx, ps, bc)
+	if !assertions.Enabled() {
+		return runErr
+	}
+	verdict := assertions.Evaluate(stats, time.Since(start))
+	loadtest.PrintVerdict(verdict)
+	if runErr != nil {
+		return runErr
+	}
+	return verdict.Err()
+}
+
+// startMonitors starts the lag, node stats and host metric monitors that are
+// enabled. The returned function stops them and prints their reports.
+func (e *engine) startMonitors(ctx context.Context, ps *transaction.ParallelSender) func() {
+	cfg, n := e.cfg, e.s.node
+	monitorCtx, cancel := context.WithCancel(ctx)
+	var reports []func()
+	done := make(chan struct{})
+	pending := 0
+	finished := make(chan func(), 3)
+
+	if cfg.LagReportSeconds > 0 {
+		ps.TrackInclusion()
+		pending++
+		go func() {
+			samples := loadtest.MonitorAcceptance(monitorCtx, n.write, ps, time.Duration(cfg.LagReportSeconds)*time.Second)
+			finished <- func() { loadtest.PrintAcceptanceLag(samples, cfg.ShedThreshold()) }
+		}()
+	}
+	if cfg.NodeStatsSeconds > 0 {
+		pending++
+		go func() {
+			samples := loadtest.MonitorNode(monitorCtx, n.write, ps, time.Duration(cfg.NodeStatsSeconds)*time.Second)
+			finished <- func() { loadtest.PrintNodeStats(samples) }
+		}()
+	}
+	if cfg.HostMetricsEnabled() {
+		pending++
+		go func() {
+			samples, err := loadtest.MonitorHost(monitorCtx, hostMetricsConfig(cfg))
+			finished <- func() {
+				if err != nil {
+					log.Printf("Warning: host metrics: %v", err)
+				}
+				loadtest.PrintHostMetrics(samples)
+			}
+		}()
+	}
+	go func() {
+		for i := 0; i < pending; i++ {
+			reports = append(reports, <-finished)
+		}
+		close(done)
+	}()
+
+	return func() {
+		cancel()
+		<-done
+		for _, report := range reports {
+			report()
+		}
+	}
+}
+
+// runParallel runs the session's parallel-engine mode
+func runParallel(ctx context.Context, s *session) (interface{}, error) {
+	e, err := newEngine(ctx, s)
+	if err != nil {
+		return nil, err
+	}
+	defer e.Close()
+	return e.run(ctx)
+}
+
+// experimentCommand runs a parameter matrix: `simulator experiment --matrix RATE_LIMIT=50,100 --duration 5m`.
+// Every run sends from the same wallet pool with its combination applied.
+func experimentCommand(ctx context.Context, cfg *config.Config, args []string, f flags) (runErr error) {
+	ec, err := loadtest.ParseExperimentArgs(args)
+	if err != nil {
+		return err
+	}
+	if err := validate(cfg); err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+	if err := transaction.SetSignerType(cfg.Signer); err != nil {
+		return err
+	}
+	n, err := connect(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	defer n.Close()
+	if err := n.detect(ctx, cfg); err != nil {
+		return err
+	}
+	if err := n.openSubmitter(ctx, cfg); err != nil {
+		return err
+	}
+	if err := newInterlock(cfg, f.allowMainnet).Check(n.chainID(), nil); err != nil {
+		return err
+	}
+
+	s := &session{cfg: cfg, node: n, flags: f}
+	if s.runID, err = runID(cfg); err != nil {
+		return err
+	}
+	if cfg.RunsDir != "" {
+		if s.run, err = runs.Create(cfg.RunsDir, runs.FormatID(s.runID), "experiment"); err != nil {
+			return err
+		}
+		if err := s.run.CaptureOutput(); err != nil {
+			return err
+		}
+		defer func() {
+			if err := s.run.Finish(runErr); err != nil {
+				log.Printf("Warning: %v", err)
+			}
+		}()
+	}
+	e, err := newEngine(ctx, s)
+	if err != nil {
+		return err
+	}
+	defer e.Close()
+
+	report, err := loadtest.RunExperiment(ctx, ec, func(ctx context.Context, c loadtest.Combination) (*transaction.ParallelSender, error) {
+		runCfg, err := config.WithOverrides(c.Overrides())
+		if err != nil {
+			return nil, err
+		}
+		if err := checkComponents(runCfg); err != nil {
+			return nil, err
+		}
+		pc, err := parallelConfig(runCfg)
+		if err != nil {
+			return nil, err
+		}
+		pc.RunID = s.runID
+		pc.MaxTransactions = 0 // Each run lasts the experiment's run duration
+		pc.Contracts, pc.Calldata, pc.InitCode = e.workload.Contracts, e.workload.Calldata, e.workload.InitCode
+		return e.newSender(pc), nil
+	})
+	if report != nil {
+		loadtest.PrintExperimentReport(report)
+		if s.run != nil {
+			if reportErr := s.run.WriteReport(runs.NewEnvironment(n.caps.ClientVersion, n.chainID().String(), cfg.Resolved()), report); reportErr != nil {
+				log.Printf("Warning: %v", reportErr)
+			}
+		}
+	}
+	return err
+}
+
+// recordWallets writes the generated wallets and their manifest to the run directory
+// and WALLET_MANIFEST_FILE, before funding, so their keys are never lost with funds on them
+func (s *session) recordWallets(wallets []*wallet.Wallet) error {
+	manifest := wallet.BuildManifest(runs.FormatID(s.runID), s.node.chainID().String(), wallets)
+	if s.run != nil {
+		if err := wallet.SaveWallets(s.run.Path(runs.WalletsFile), wallets, false); err != nil {
+			return err
+		}
+		if err := wallet.WriteManifest(s.run.Path(runs.ManifestFile), manifest); err != nil {
+			return err
+		}
+	}
+	if s.cfg.WalletManifestFile != "" {
+		if err := wallet.WriteManifest(s.cfg.WalletManifestFile, manifest); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// recordContracts adds deployed contracts, by label, to the run's contracts.json
+func (s *session) recordContracts(deployed map[string][]common.Address) {
+	if s.run == nil {
+		return
+	}
+	if s.contracts == nil {
+		s.contracts = make(map[string][]common.Address)
+	}
+	for label, addresses := range deployed {
+		s.contracts[label] = append(s.contracts[label], addresses...)
+	}
+	if err := s.run.WriteJSON(runs.ContractsFile, s.contracts); err != nil {
+		log.Printf("Warning: %v", err)
+	}
+}
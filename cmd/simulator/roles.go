@@ -0,0 +1,111 @@
+package main
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/aakash4dev/ethereum-transaction-simulator/internal/config"
+	"github.com/aakash4dev/ethereum-transaction-simulator/internal/distributed"
+	"github.com/aakash4dev/ethereum-transaction-simulator/internal/remotesign"
+	"github.com/aakash4dev/ethereum-transaction-simulator/internal/transaction"
+	"github.com/aakash4dev/ethereum-transaction-simulator/internal/wallet"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// runSigner holds PRIVATE_KEY and the SIGNER_WALLETS keys and signs for a sender
+// process on SIGNER_SOCKET until ctx is done. The chain ID is read once at startup.
+func runSigner(ctx context.Context, cfg *config.Config) error {
+	var keys []*ecdsa.PrivateKey
+	if cfg.PrivateKey != "" {
+		key, err := crypto.HexToECDSA(strings.TrimPrefix(cfg.PrivateKey, "0x"))
+		if err != nil {
+			return fmt.Errorf("failed to parse private key: %w", err)
+		}
+		keys = append(keys, key)
+	}
+	if cfg.SignerWallets != "" {
+		wallets, err := wallet.LoadWallets(cfg.SignerWallets, nil)
+		if err != nil {
+			return err
+		}
+		for _, w := range wallets {
+			keys = append(keys, w.PrivateKey)
+		}
+	}
+
+	client, err := ethclient.DialContext(ctx, cfg.ReadURL())
+	if err != nil {
+		return fmt.Errorf("failed to connect to RPC: %w", err)
+	}
+	chainID, err := client.ChainID(ctx)
+	client.Close()
+	if err != nil {
+		return fmt.Errorf("failed to get chain ID: %w", err)
+	}
+
+	maxValue, maxGasPrice := cfg.SignerLimits()
+	signer, err := remotesign.NewSigner(chainID, keys, remotesign.SignPolicy{
+		Recipients:  addressPolicy(cfg),
+		MaxValue:    maxValue,
+		MaxGasPrice: maxGasPrice,
+	})
+	if err != nil {
+		return err
+	}
+	listener, err := remotesign.Listen(cfg.SignerSocket)
+	if err != nil {
+		return err
+	}
+	go func() {
+		<-ctx.Done()
+		listener.Close()
+	}()
+	fmt.Printf("Signing for %d wallets on chain %s at %s\n", len(keys), chainID, cfg.SignerSocket)
+	return signer.Serve(listener)
+}
+
+// runCoordinator shards WALLETS_FILE and MAX_TRANSACTIONS across AGENT_COUNT agents,
+// starts them together and prints their totals once all have finished
+func runCoordinator(ctx context.Context, cfg *config.Config) error {
+	wallets, err := wallet.LoadWallets(cfg.WalletsFile, nil)
+	if err != nil {
+		return err
+	}
+	coordinator, err := distributed.NewCoordinator(cfg.AgentCount, len(wallets), cfg.MaxTransactions,
+		time.Duration(cfg.AgentStartDelay)*time.Second, cfg.CoordinatorSecret)
+	if err != nil {
+		return err
+	}
+	listener, err := net.Listen("tcp", cfg.CoordinatorAddr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", cfg.CoordinatorAddr, err)
+	}
+	defer listener.Close()
+	go coordinator.Serve(listener)
+
+	fmt.Printf("Coordinating %d agents over %d wallets on %s\n", cfg.AgentCount, len(wallets), cfg.CoordinatorAddr)
+	err = coordinator.Wait(ctx)
+	coordinator.PrintTotals()
+	return err
+}
+
+// reportProgress sends the sender's counts to the coordinator every interval until
+// ctx is done
+func reportProgress(ctx context.Context, agent *distributed.Agent, ps *transaction.ParallelSender, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			sent, succeeded, failed, _ := ps.GetMetrics()
+			agent.Report(sent, succeeded, failed, false) // A lost report is made up by the next one
+		}
+	}
+}
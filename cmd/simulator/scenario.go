@@ -0,0 +1,275 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"math/big"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/aakash4dev/ethereum-transaction-simulator/internal/config"
+	"github.com/aakash4dev/ethereum-transaction-simulator/internal/diagnostics"
+	"github.com/aakash4dev/ethereum-transaction-simulator/internal/distributed"
+	"github.com/aakash4dev/ethereum-transaction-simulator/internal/runs"
+	"github.com/aakash4dev/ethereum-transaction-simulator/internal/schedule"
+	"github.com/aakash4dev/ethereum-transaction-simulator/internal/transaction"
+	"github.com/aakash4dev/ethereum-transaction-simulator/internal/wallet"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// ntpTimeout bounds the NTP query made at the start of a distributed run
+const ntpTimeout = 5 * time.Second
+
+// session is one run of a scenario: its configuration, node connections and artifacts
+type session struct {
+	cfg    *config.Config
+	node   *node
+	run    *runs.Run // Nil when RUNS_DIR is empty
+	runID  uint64
+	stream *transaction.EventStream // Nil when EVENT_STREAM is empty
+	ntp    *distributed.ClockOffset // Nil unless measured at the start of a distributed run
+	flags  flags
+
+	contracts map[string][]common.Address // Contracts deployed so far, by label, as written to contracts.json
+}
+
+// runScenario runs the scenario selected by MODE, once or on SCHEDULE
+func runScenario(ctx context.Context, cfg *config.Config, f flags) error {
+	if err := validate(cfg); err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+	if err := transaction.SetSignerType(cfg.Signer); err != nil {
+		return err
+	}
+	switch {
+	case cfg.SplitRole == "signer":
+		return runSigner(ctx, cfg)
+	case strings.ToLower(cfg.DistributedRole) == "coordinator":
+		return runCoordinator(ctx, cfg)
+	}
+
+	if cfg.DebugAddr != "" {
+		server, err := diagnostics.Start(cfg.DebugAddr)
+		if err != nil {
+			return err
+		}
+		defer server.Close()
+		diagnostics.OnReload(config.TriggerReload)
+	}
+	health := &diagnostics.Health{}
+	if cfg.HealthAddr != "" {
+		server, err := diagnostics.StartHealth(cfg.HealthAddr, health)
+		if err != nil {
+			return err
+		}
+		defer server.Close()
+	}
+
+	if cfg.Schedule == "" {
+		return runOnce(ctx, cfg, f, health)
+	}
+	// Each run picks up the configuration as last reloaded; the schedule itself is fixed
+	scheduler, err := schedule.NewScheduler(cfg.Schedule, time.Duration(cfg.ScheduleDuration)*time.Minute, cfg.ReportDir,
+		func(ctx context.Context, w io.Writer) error {
+			restore, err := teeStdout(w)
+			if err != nil {
+				return err
+			}
+			defer restore()
+			return runOnce(ctx, config.Latest(), f, health)
+		})
+	if err != nil {
+		return err
+	}
+	return scheduler.Run(ctx)
+}
+
+// runOnce connects, prepares the node and the run directory, and runs the mode
+func runOnce(ctx context.Context, cfg *config.Config, f flags, health *diagnostics.Health) (runErr error) {
+	defer health.SetReady(false)
+
+	stream, err := openEventStream(cfg)
+	if err != nil {
+		return err
+	}
+	if stream != nil {
+		defer stream.Close()
+	}
+
+	n, err := connect(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	defer n.Close()
+	fmt.Printf("Connected to chain %s: %s\n", n.chainID(), n.info)
+	if err := n.detect(ctx, cfg); err != nil {
+		return err
+	}
+	if err := n.openSubmitter(ctx, cfg); err != nil {
+		return err
+	}
+
+	s := &session{cfg: cfg, node: n, stream: stream, flags: f}
+	if s.runID, err = runID(cfg); err != nil {
+		return err
+	}
+	fmt.Printf("Run ID: %s\n", runs.FormatID(s.runID))
+	if cfg.RunsDir != "" {
+		if s.run, err = runs.Create(cfg.RunsDir, runs.FormatID(s.runID), cfg.Mode); err != nil {
+			return err
+		}
+		if err := s.run.CaptureOutput(); err != nil {
+			return err
+		}
+		defer func() {
+			if err := s.run.Finish(runErr); err != nil {
+				log.Printf("Warning: %v", err)
+			}
+		}()
+	}
+	if cfg.DistributedRole != "" && cfg.NTPServer != "" {
+		s.recordClockOffset()
+	}
+
+	if err := s.prepareDevNode(ctx); err != nil {
+		return err
+	}
+	if cfg.DevSnapshot {
+		dev, err := n.devNode()
+		if err != nil {
+			return err
+		}
+		id, err := dev.Snapshot(ctx)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Snapshot %s taken; revert by hand with `simulator revert --id %s` if the run is killed\n", id, id)
+		defer func() {
+			if err := dev.Revert(context.Background(), id); err != nil {
+				log.Printf("Warning: failed to revert to snapshot %s: %v", id, err)
+				return
+			}
+			fmt.Printf("Reverted to snapshot %s\n", id)
+		}()
+	}
+
+	if proceed, err := s.checkSpend(ctx); err != nil || !proceed {
+		return err
+	}
+
+	health.SetReady(true)
+	metrics, err := runMode(ctx, s)
+	n.printBroadcast()
+	if s.run != nil {
+		env := runs.NewEnvironment(n.caps.ClientVersion, n.chainID().String(), cfg.Resolved())
+		if reportErr := s.run.WriteReport(env, metrics); reportErr != nil {
+			log.Printf("Warning: %v", reportErr)
+		}
+	}
+	return err
+}
+
+// runID returns RUN_ID, or a new random run ID when it is not set
+func runID(cfg *config.Config) (uint64, error) {
+	if cfg.RunID != "" {
+		return transaction.ParseRunID(cfg.RunID)
+	}
+	return transaction.NewRunID()
+}
+
+// recordClockOffset records the local clock's offset from NTP_SERVER in run.json. An
+// unreachable server only logs a warning.
+func (s *session) recordClockOffset() {
+	offset, err := distributed.QueryNTP(s.cfg.NTPServer, ntpTimeout)
+	if err != nil {
+		log.Printf("Warning: failed to query NTP server %s: %v", s.cfg.NTPServer, err)
+		return
+	}
+	s.ntp = &offset
+	fmt.Printf("Clock offset: %s\n", offset)
+	if s.run != nil {
+		if err := s.run.RecordClockOffset(offset.Source, offset.Offset); err != nil {
+			log.Printf("Warning: %v", err)
+		}
+	}
+}
+
+// prepareDevNode sets the block production DEV_MINING asks for
+func (s *session) prepareDevNode(ctx context.Context) error {
+	mining := miningControl(s.cfg)
+	if mining == nil {
+		return nil
+	}
+	dev, err := s.node.devNode()
+	if err != nil {
+		return err
+	}
+	if err := dev.SetMining(ctx, *mining); err != nil {
+		return err
+	}
+	fmt.Printf("Mining: %s\n", mining)
+	return nil
+}
+
+// checkSpend applies the public network interlock and, with --estimate, prints the
+// run's cost and asks whether to go on. It returns false when the run should stop
+// without an error.
+func (s *session) checkSpend(ctx context.Context) (bool, error) {
+	var estimate *wallet.Estimate
+	input, inputErr := estimateInput(s.cfg)
+	if inputErr == nil {
+		gasPrice, err := s.node.client.SuggestGasPrice(ctx)
+		if err != nil {
+			return false, fmt.Errorf("failed to get gas price: %w", err)
+		}
+		estimate = wallet.BuildEstimate(input, gasPrice)
+	}
+
+	if s.flags.estimate {
+		if inputErr != nil {
+			return false, inputErr
+		}
+		wallet.PrintEstimate(estimate)
+		if !wallet.Interactive() {
+			return false, nil
+		}
+		if !wallet.ConfirmEstimate(os.Stdin, os.Stdout) {
+			fmt.Println("Aborted")
+			return false, nil
+		}
+	}
+
+	var spend *big.Int // Unknown cost, which the interlock treats as over the testnet limit
+	if estimate != nil {
+		spend = estimate.Required
+	}
+	if err := newInterlock(s.cfg, s.flags.allowMainnet).Check(s.node.chainID(), spend); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// teeStdout copies everything written to stdout into w until the returned function
+// is called, while still printing it
+func teeStdout(w io.Writer) (func(), error) {
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to capture output: %w", err)
+	}
+	stdout := os.Stdout
+	os.Stdout = writer
+	done := make(chan struct{})
+	go func() {
+		io.Copy(io.MultiWriter(stdout, w), reader)
+		close(done)
+	}()
+	return func() {
+		os.Stdout = stdout
+		writer.Close()
+		<-done
+		reader.Close()
+	}, nil
+}
@@ -0,0 +1,542 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aakash4dev/ethereum-transaction-simulator/internal/chain"
+	"github.com/aakash4dev/ethereum-transaction-simulator/internal/config"
+	"github.com/aakash4dev/ethereum-transaction-simulator/internal/contract"
+	"github.com/aakash4dev/ethereum-transaction-simulator/internal/loadtest"
+	"github.com/aakash4dev/ethereum-transaction-simulator/internal/probe"
+	"github.com/aakash4dev/ethereum-transaction-simulator/internal/schedule"
+	"github.com/aakash4dev/ethereum-transaction-simulator/internal/transaction"
+	"github.com/aakash4dev/ethereum-transaction-simulator/internal/wallet"
+	"github.com/aakash4dev/ethereum-transaction-simulator/pkg/plugin"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// validate runs cfg.Validate and then checkComponents
+func validate(cfg *config.Config) error {
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+	return checkComponents(cfg)
+}
+
+// checkComponents checks the settings only the packages that use them can parse.
+// cfg.Validate must have succeeded before calling it.
+func checkComponents(cfg *config.Config) error {
+	if policy := addressPolicy(cfg); policy != nil {
+		for _, target := range cfg.ParallelContractAddresses() {
+			if err := policy.Check(target); err != nil {
+				return fmt.Errorf("PARALLEL_CONTRACTS: %w", err)
+			}
+		}
+	}
+	if cfg.ExpectedEvents != "" {
+		if _, err := transaction.NewEventAssertions(cfg.ExpectedEvents); err != nil {
+			return fmt.Errorf("EXPECTED_EVENTS is invalid: %w", err)
+		}
+	}
+	if cfg.Schedule != "" {
+		if _, err := schedule.ParseCron(cfg.Schedule); err != nil {
+			return fmt.Errorf("SCHEDULE is invalid: %w", err)
+		}
+	}
+
+	switch strings.ToLower(cfg.Mode) {
+	case "reads":
+		if _, err := loadtest.ParseReadMix(cfg.ReadMix); err != nil {
+			return fmt.Errorf("READ_MIX is invalid: %w", err)
+		}
+	case "logs":
+		if _, err := loadtest.ParseBlockRanges(cfg.LogsBlockRanges); err != nil {
+			return fmt.Errorf("LOGS_BLOCK_RANGES is invalid: %w", err)
+		}
+		if _, err := loadtest.ParseTopics(cfg.LogsTopics); err != nil {
+			return fmt.Errorf("LOGS_TOPICS is invalid: %w", err)
+		}
+	case "archive":
+		if cfg.ArchiveDepthDistribution != loadtest.DepthUniform && cfg.ArchiveDepthDistribution != loadtest.DepthExponential {
+			return fmt.Errorf("ARCHIVE_DEPTH_DISTRIBUTION must be uniform or exponential (got: %s)", cfg.ArchiveDepthDistribution)
+		}
+	case "ws-fanout":
+		if _, err := loadtest.ParseSubscriptionKinds(cfg.WSSubscriptions); err != nil {
+			return fmt.Errorf("WS_SUBSCRIPTIONS is invalid: %w", err)
+		}
+	case "shaped":
+		shape, err := shapeConfig(cfg)
+		if err != nil {
+			return fmt.Errorf("TRAFFIC_SHAPE is invalid: %w", err)
+		}
+		if _, err := loadtest.NewShape(shape); err != nil {
+			return fmt.Errorf("TRAFFIC_SHAPE is invalid: %w", err)
+		}
+		if cfg.ShapeTimeWarp > 0 && shape.Kind == loadtest.ShapePoisson {
+			return errors.New("SHAPE_TIME_WARP_SECONDS needs a shape with phases (sine, step or spike)")
+		}
+	case "large-deploy":
+		if cfg.LargeCodeSize <= 0 || cfg.LargeCodeSize > contract.MaxCodeSize {
+			return fmt.Errorf("LARGE_CODE_SIZE must be between 1 and %d (got: %d)", contract.MaxCodeSize, cfg.LargeCodeSize)
+		}
+		if need := contract.LargeContractGasLimit(cfg.LargeCodeSize); cfg.DeployGasLimit > 0 && cfg.DeployGasLimit < need {
+			return fmt.Errorf("DEPLOY_GAS_LIMIT is too low for LARGE_CODE_SIZE (got: %d, need about: %d)", cfg.DeployGasLimit, need)
+		}
+	case "call-depth":
+		if _, err := contract.ParseCallDepths(cfg.CallDepths); err != nil {
+			return fmt.Errorf("CALL_DEPTHS is invalid: %w", err)
+		}
+	case "nonce-gap":
+		if _, err := probe.ParseNonceOffsets(cfg.NonceGapOffsets); err != nil {
+			return fmt.Errorf("NONCE_GAP_OFFSETS: %w", err)
+		}
+	}
+
+	if _, err := confirmationStrategy(cfg); err != nil {
+		return err
+	}
+	if _, err := loadTemplates(cfg); err != nil {
+		return err
+	}
+	if _, err := loadScript(cfg); err != nil {
+		return err
+	}
+	if _, err := parseThinkTime(cfg); err != nil {
+		return err
+	}
+	personas, err := loadPersonas(cfg)
+	if err != nil {
+		return err
+	}
+	if personas != nil && personas.UsesCalls() && cfg.ParallelContracts == "" {
+		return errors.New("personas with callPercent need PARALLEL_CONTRACTS to call")
+	}
+	if _, err := parseAssertions(cfg); err != nil {
+		return err
+	}
+	return nil
+}
+
+// parseAssertions returns the pass/fail bounds set by the ASSERT_* options
+func parseAssertions(cfg *config.Config) (*loadtest.Assertions, error) {
+	parse := func(key, value string) (float64, error) {
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil || f < 0 {
+			return 0, fmt.Errorf("%s must be a non-negative number (got: %s)", key, value)
+		}
+		return f, nil
+	}
+
+	a := &loadtest.Assertions{}
+	if cfg.AssertMinTPS != "" {
+		tps, err := parse("ASSERT_MIN_TPS", cfg.AssertMinTPS)
+		if err != nil {
+			return nil, err
+		}
+		a.MinTPS = tps
+	}
+	if cfg.AssertMaxFailurePercent != "" {
+		percent, err := parse("ASSERT_MAX_FAILURE_PERCENT", cfg.AssertMaxFailurePercent)
+		if err != nil {
+			return nil, err
+		}
+		if percent > 100 {
+			return nil, fmt.Errorf("ASSERT_MAX_FAILURE_PERCENT cannot exceed 100 (got: %s)", cfg.AssertMaxFailurePercent)
+		}
+		a.MaxFailureRate, a.CheckFailureRate = percent/100, true
+	}
+	if cfg.AssertMaxP95Seconds != "" {
+		seconds, err := parse("ASSERT_MAX_P95_SECONDS", cfg.AssertMaxP95Seconds)
+		if err != nil {
+			return nil, err
+		}
+		a.MaxP95Latency = time.Duration(seconds * float64(time.Second))
+	}
+	return a, nil
+}
+
+// shapeConfig returns the traffic shape selected by TRAFFIC_SHAPE and the SHAPE_* options
+func shapeConfig(cfg *config.Config) (*loadtest.ShapeConfig, error) {
+	steps, err := loadtest.ParseSteps(cfg.ShapeSteps)
+	if err != nil {
+		return nil, err
+	}
+	return &loadtest.ShapeConfig{
+		Kind:          strings.ToLower(cfg.TrafficShape),
+		BaseTPS:       float64(cfg.ShapeBaseTPS),
+		Amplitude:     float64(cfg.ShapeAmplitudeTPS),
+		Period:        time.Duration(cfg.ShapePeriod) * time.Minute,
+		Steps:         steps,
+		StepDuration:  time.Duration(cfg.ShapeStepSeconds) * time.Second,
+		SpikeTPS:      float64(cfg.ShapeSpikeTPS),
+		SpikeAt:       time.Duration(cfg.ShapeSpikeAt) * time.Second,
+		SpikeDuration: time.Duration(cfg.ShapeSpikeSeconds) * time.Second,
+	}, nil
+}
+
+// timeWarp returns the time warp of a shaped run against node, or nil when
+// SHAPE_TIME_WARP_SECONDS is 0
+func timeWarp(cfg *config.Config, shape *loadtest.ShapeConfig, node loadtest.TimeAdvancer) *loadtest.TimeWarp {
+	if cfg.ShapeTimeWarp == 0 {
+		return nil
+	}
+	return &loadtest.TimeWarp{Node: node, Phase: shape.Phase, Step: time.Duration(cfg.ShapeTimeWarp) * time.Second}
+}
+
+// largeDeployGasLimit returns the gas limit for large-deploy transactions: DEPLOY_GAS_LIMIT
+// when set, otherwise an estimate for LARGE_CODE_SIZE
+func largeDeployGasLimit(cfg *config.Config) uint64 {
+	if cfg.DeployGasLimit > 0 {
+		return cfg.DeployGasLimit
+	}
+	return contract.LargeContractGasLimit(cfg.LargeCodeSize)
+}
+
+// contractRegistry returns the registry contract statistics are kept in, with the
+// PARALLEL_CONTRACTS registered as external contracts, or nil when CONTRACT_STATS is off
+func contractRegistry(cfg *config.Config) *contract.Registry {
+	if !cfg.ContractStats {
+		return nil
+	}
+	registry := contract.NewRegistry()
+	for _, address := range cfg.ParallelContractAddresses() {
+		registry.Register(address, "external")
+	}
+	return registry
+}
+
+// confirmationStrategy returns what counts as a successful transaction, or nil when
+// CONFIRMATION is empty so each mode keeps its default
+func confirmationStrategy(cfg *config.Config) (*transaction.ConfirmationStrategy, error) {
+	if cfg.Confirmation == "" {
+		return nil, nil
+	}
+	level, err := transaction.ParseConfirmation(cfg.Confirmation)
+	if err != nil {
+		return nil, fmt.Errorf("CONFIRMATION: %w", err)
+	}
+	if level == transaction.ConfirmBlocks && cfg.ConfirmationBlocks == 0 {
+		return nil, errors.New("CONFIRMATION_BLOCKS must be greater than 0 when CONFIRMATION=blocks")
+	}
+	if cfg.ConfirmationTimeout <= 0 {
+		return nil, fmt.Errorf("CONFIRMATION_TIMEOUT_SECONDS must be greater than 0 (got: %d)", cfg.ConfirmationTimeout)
+	}
+	return &transaction.ConfirmationStrategy{
+		Level:   level,
+		Blocks:  cfg.ConfirmationBlocks,
+		Timeout: time.Duration(cfg.ConfirmationTimeout) * time.Second,
+	}, nil
+}
+
+// finalityConfig returns the finality tracking settings
+func finalityConfig(cfg *config.Config) *loadtest.FinalityConfig {
+	return &loadtest.FinalityConfig{
+		PollInterval: 4 * time.Second,
+		Wait:         time.Duration(cfg.FinalityWaitSeconds) * time.Second,
+	}
+}
+
+// hostMetricsConfig returns the host metrics settings
+func hostMetricsConfig(cfg *config.Config) *loadtest.HostMetricsConfig {
+	return &loadtest.HostMetricsConfig{
+		PrometheusURL:   cfg.HostPrometheusURL,
+		Selector:        cfg.HostSelector,
+		NodeExporterURL: cfg.HostExporterURL,
+		Interval:        time.Duration(cfg.HostMetricsSeconds) * time.Second,
+	}
+}
+
+// estimateInput returns the workload --estimate prices. Only modes that send a known
+// number of transactions can be estimated.
+func estimateInput(cfg *config.Config) (*wallet.EstimateInput, error) {
+	if cfg.MaxTransactions == 0 {
+		return nil, errors.New("--estimate needs MAX_TRANSACTIONS, an unlimited run has no fixed cost")
+	}
+	line := func(workload string) wallet.EstimateLine {
+		return wallet.EstimateLine{Type: workload, Count: cfg.MaxTransactions, GasLimit: cfg.GasLimitFor(workload), Value: cfg.ValueFor(workload)}
+	}
+	input := &wallet.EstimateInput{}
+	switch strings.ToLower(cfg.Mode) {
+	case "parallel":
+		input.Lines = []wallet.EstimateLine{line("parallel")}
+		input.WalletCount = cfg.WalletCount
+		input.Rate = float64(cfg.TargetTPS)
+	case "transfer", "deploy", "interact":
+		input.Lines = []wallet.EstimateLine{line(strings.ToLower(cfg.Mode))}
+	case "all":
+		input.Lines = []wallet.EstimateLine{line("transfer"), line("deploy"), line("interact")}
+	default:
+		return nil, fmt.Errorf("--estimate supports parallel, transfer, deploy, interact and all modes (got: %s)", cfg.Mode)
+	}
+	if input.Rate == 0 && cfg.DelaySeconds > 0 && strings.ToLower(cfg.Mode) != "parallel" {
+		input.Rate = float64(len(input.Lines)) / float64(cfg.DelaySeconds) // One per delay per workload
+	}
+	return input, nil
+}
+
+// newInterlock returns the public network safety check. allowMainnet is set by the
+// --i-know-this-is-mainnet flag. validate must have succeeded before calling it.
+func newInterlock(cfg *config.Config, allowMainnet bool) *wallet.Interlock {
+	limit, ok := new(big.Int).SetString(cfg.TestnetSpendLimit, 10)
+	if !ok {
+		limit = new(big.Int)
+	}
+	return &wallet.Interlock{
+		AllowMainnet:      allowMainnet,
+		TestnetSpendLimit: limit,
+		Interactive:       wallet.Interactive(),
+		In:                os.Stdin,
+		Out:               os.Stdout,
+	}
+}
+
+// addressPolicy returns the recipient allowlist and denylist, or nil when neither is
+// set. cfg.Validate must have succeeded before calling it.
+func addressPolicy(cfg *config.Config) *transaction.AddressPolicy {
+	if cfg.RecipientAllowlist == "" && cfg.RecipientDenylist == "" {
+		return nil
+	}
+	parse := func(list string) []common.Address {
+		var addresses []common.Address
+		for _, entry := range strings.Split(list, ",") {
+			if entry = strings.TrimSpace(entry); entry != "" {
+				addresses = append(addresses, common.HexToAddress(entry))
+			}
+		}
+		return addresses
+	}
+	return transaction.NewAddressPolicy(parse(cfg.RecipientAllowlist), parse(cfg.RecipientDenylist))
+}
+
+// loadTemplates loads TX_TEMPLATE_FILE, or returns nil when it is not set
+func loadTemplates(cfg *config.Config) (*transaction.TemplateSet, error) {
+	if cfg.TxTemplateFile == "" {
+		return nil, nil
+	}
+	set, err := transaction.LoadTemplates(cfg.TxTemplateFile)
+	if err != nil {
+		return nil, fmt.Errorf("TX_TEMPLATE_FILE: %w", err)
+	}
+	return set, nil
+}
+
+// loadScript loads SCRIPT_FILE, or returns nil when it is not set
+func loadScript(cfg *config.Config) (*transaction.ScriptHook, error) {
+	if cfg.ScriptFile == "" {
+		return nil, nil
+	}
+	hook, err := transaction.NewScriptHook(cfg.ScriptFile)
+	if err != nil {
+		return nil, fmt.Errorf("SCRIPT_FILE: %w", err)
+	}
+	return hook, nil
+}
+
+// openPlugins starts the plugins in PLUGIN_DIR and returns the workload and fee strategy
+// named by PLUGIN_WORKLOAD and PLUGIN_FEE_STRATEGY, each nil when not set. The
+// registry is nil when PLUGIN_DIR is empty and must otherwise be closed after the run.
+func openPlugins(cfg *config.Config) (*plugin.Registry, transaction.TxBuilder, transaction.FeeStrategy, error) {
+	if cfg.PluginDir == "" {
+		return nil, nil, nil, nil
+	}
+	registry, err := plugin.Discover(cfg.PluginDir)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	var builder transaction.TxBuilder
+	if cfg.PluginWorkload != "" {
+		workload, err := registry.Workload(cfg.PluginWorkload)
+		if err != nil {
+			registry.Close()
+			return nil, nil, nil, err
+		}
+		builder = transaction.NewPluginWorkload(cfg.PluginWorkload, workload)
+	}
+	var fees transaction.FeeStrategy
+	if cfg.PluginFeeStrategy != "" {
+		strategy, err := registry.FeeStrategy(cfg.PluginFeeStrategy)
+		if err != nil {
+			registry.Close()
+			return nil, nil, nil, err
+		}
+		fees = strategy
+	}
+	return registry, builder, fees, nil
+}
+
+// liveSettings returns the parallel-mode settings a reload can change mid-run:
+// RATE_LIMIT, MAX_GAS_PRICE and, when set, the templates in TX_TEMPLATE_FILE with
+// their weights or the script in SCRIPT_FILE. Everything else needs a restart.
+func liveSettings(cfg *config.Config) (*transaction.LiveSettings, error) {
+	settings := &transaction.LiveSettings{RateLimit: float64(cfg.RateLimit), MaxGasPrice: cfg.MaxGasPriceWei()}
+	templates, err := loadTemplates(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if templates != nil {
+		settings.Builder = templates
+	}
+	script, err := loadScript(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if script != nil {
+		settings.Builder = script
+	}
+	return settings, nil
+}
+
+// parseThinkTime parses THINK_TIME, or returns nil when it is not set
+func parseThinkTime(cfg *config.Config) (*transaction.ThinkTime, error) {
+	if cfg.ThinkTime == "" {
+		return nil, nil
+	}
+	t, err := transaction.ParseThinkTime(cfg.ThinkTime)
+	if err != nil {
+		return nil, fmt.Errorf("THINK_TIME: %w", err)
+	}
+	return t, nil
+}
+
+// loadPersonas loads PERSONA_FILE, or returns nil when it is not set
+func loadPersonas(cfg *config.Config) (*transaction.PersonaSet, error) {
+	if cfg.PersonaFile == "" {
+		return nil, nil
+	}
+	set, err := transaction.LoadPersonas(cfg.PersonaFile)
+	if err != nil {
+		return nil, fmt.Errorf("PERSONA_FILE: %w", err)
+	}
+	return set, nil
+}
+
+// chaosConfig returns the faults to inject into parallel sends, or nil when every
+// CHAOS_* setting is 0
+func chaosConfig(cfg *config.Config) *transaction.ChaosConfig {
+	if !cfg.ChaosEnabled() {
+		return nil
+	}
+	return &transaction.ChaosConfig{
+		DropPercent:    float64(cfg.ChaosDropPercent),
+		CorruptPercent: float64(cfg.ChaosCorruptPercent),
+		SignDelay:      time.Duration(cfg.ChaosSignDelayMs) * time.Millisecond,
+	}
+}
+
+// slowStart returns the wallet activation ramp, or nil when SLOW_START_SECONDS is 0
+func slowStart(cfg *config.Config) *transaction.SlowStart {
+	if cfg.SlowStartSeconds == 0 {
+		return nil
+	}
+	return &transaction.SlowStart{Initial: cfg.SlowStartWallets, Interval: time.Duration(cfg.SlowStartSeconds) * time.Second}
+}
+
+// miningControl returns the block production DEV_MINING sets on the node, or nil
+// to leave it alone
+func miningControl(cfg *config.Config) *chain.Mining {
+	interval := time.Duration(cfg.DevBlockTime) * time.Second
+	switch cfg.DevMining {
+	case "auto":
+		return &chain.Mining{Automine: true}
+	case "interval":
+		return &chain.Mining{Interval: interval}
+	case "both":
+		return &chain.Mining{Automine: true, Interval: interval}
+	}
+	return nil
+}
+
+// adapt fits the configuration to what the node serves, detected at startup. It
+// returns an error when the mode cannot run at all, turns off the parts of a mode
+// the node cannot serve, and returns a warning for every feature that will run
+// with less detail.
+func adapt(cfg *config.Config, caps *chain.Capabilities) (warnings []string, err error) {
+	mode := strings.ToLower(cfg.Mode)
+	if mode == "trace" {
+		traceTx, traceBlock := caps.Supports(chain.DebugTraceTx), caps.Supports(chain.DebugTraceBlock)
+		switch {
+		case !traceTx && !traceBlock:
+			return nil, fmt.Errorf("trace mode needs the debug namespace, which %s does not serve", caps)
+		case !traceTx && cfg.TraceBlockPercent < 100:
+			cfg.TraceBlockPercent = 100
+			warnings = append(warnings, "debug_traceTransaction is not served; tracing whole blocks only")
+		case !traceBlock && cfg.TraceBlockPercent > 0:
+			cfg.TraceBlockPercent = 0
+			warnings = append(warnings, "debug_traceBlockByNumber is not served; tracing single transactions only")
+		}
+	}
+	if (mode == "nonce-gap" || mode == "pool-pressure") && !caps.Supports(chain.TxpoolContentFrom) {
+		warnings = append(warnings, "txpool_contentFrom is not served; queued and pending transactions cannot be told apart, and each one is looked up by hash")
+	}
+	if mode == "pool-pressure" && !caps.Supports(chain.TxpoolStatus) {
+		warnings = append(warnings, "txpool_status is not served; the pool's peak size will not be reported")
+	}
+	if cfg.LagReportSeconds > 0 && !caps.Supports(chain.TxpoolStatus) {
+		warnings = append(warnings, "txpool_status is not served; the lag report cannot detect dropped transactions")
+	}
+	if cfg.NodeStatsSeconds > 0 {
+		if !caps.Supports(chain.TxpoolStatus) {
+			warnings = append(warnings, "txpool_status is not served; node stats will show n/a for the pool")
+		}
+		if !caps.Supports(chain.NetPeerCount) && !caps.Supports(chain.AdminPeers) {
+			warnings = append(warnings, "neither net_peerCount nor admin_peers is served; node stats will show n/a for peers")
+		}
+	}
+	timeWarp := mode == "shaped" && cfg.ShapeTimeWarp > 0
+	if cfg.DevFunding || cfg.DevSnapshot || cfg.DevMining != "" || timeWarp {
+		switch caps.Client {
+		case chain.Anvil, chain.Hardhat:
+		case chain.Geth:
+			if cfg.DevMining != "" {
+				return nil, errors.New("DEV_MINING needs anvil or hardhat; the block time of geth --dev is set at startup with --dev.period")
+			}
+			if timeWarp {
+				return nil, errors.New("SHAPE_TIME_WARP_SECONDS needs anvil or hardhat; geth --dev cannot move its clock")
+			}
+			return nil, errors.New("DEV_FUNDING and DEV_SNAPSHOT need anvil or hardhat; fund geth --dev wallets from its prefunded developer account instead")
+		default:
+			return nil, fmt.Errorf("DEV_FUNDING, DEV_SNAPSHOT, DEV_MINING and SHAPE_TIME_WARP_SECONDS need anvil or hardhat, the endpoint is %s", caps)
+		}
+	}
+	return warnings, nil
+}
+
+// checkBlockGasLimit checks GAS_LIMIT and the per-workload overrides against the
+// chain's actual block gas limit, which Validate can only bound by mainnet's
+func checkBlockGasLimit(cfg *config.Config, info *chain.Info) error {
+	limits := map[string]uint64{
+		"GAS_LIMIT":          cfg.GasLimit,
+		"TRANSFER_GAS_LIMIT": cfg.TransferGasLimit,
+		"DEPLOY_GAS_LIMIT":   cfg.DeployGasLimit,
+		"INTERACT_GAS_LIMIT": cfg.InteractGasLimit,
+		"PARALLEL_GAS_LIMIT": cfg.ParallelGasLimit,
+	}
+	names := make([]string, 0, len(limits))
+	for name := range limits {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if err := info.CheckGasLimit(name, limits[name]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// openEventStream opens EVENT_STREAM, or returns nil when it is not set. Streaming
+// to stdout sends the rest of the output to stderr. The stream must be closed after
+// the run.
+func openEventStream(cfg *config.Config) (*transaction.EventStream, error) {
+	if cfg.EventStream == "" {
+		return nil, nil
+	}
+	return transaction.OpenEventStream(cfg.EventStream)
+}
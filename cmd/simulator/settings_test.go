@@ -0,0 +1,50 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aakash4dev/ethereum-transaction-simulator/internal/chain"
+	"github.com/aakash4dev/ethereum-transaction-simulator/internal/config"
+)
+
+func TestAdapt(t *testing.T) {
+	t.Run("TraceFallsBackToBlocks", func(t *testing.T) {
+		cfg := &config.Config{Mode: "trace", TraceBlockPercent: 20}
+		caps := &chain.Capabilities{Methods: map[string]bool{chain.DebugTraceTx: false, chain.DebugTraceBlock: true}}
+		warnings, err := adapt(cfg, caps)
+		if err != nil || len(warnings) != 1 || cfg.TraceBlockPercent != 100 {
+			t.Errorf("expected block-only tracing with a warning, got %d%%, %v, %v", cfg.TraceBlockPercent, warnings, err)
+		}
+	})
+
+	t.Run("TraceNeedsDebug", func(t *testing.T) {
+		cfg := &config.Config{Mode: "trace"}
+		caps := &chain.Capabilities{Methods: map[string]bool{chain.DebugTraceTx: false, chain.DebugTraceBlock: false}}
+		if _, err := adapt(cfg, caps); err == nil {
+			t.Error("expected trace mode to fail without the debug namespace")
+		}
+	})
+
+	t.Run("DevSettingsNeedDevNode", func(t *testing.T) {
+		cfg := &config.Config{Mode: "parallel", DevMining: "interval", DevBlockTime: 2}
+		if _, err := adapt(cfg, &chain.Capabilities{Client: chain.Anvil, Methods: map[string]bool{}}); err != nil {
+			t.Errorf("expected anvil to be accepted, got %v", err)
+		}
+		if _, err := adapt(cfg, &chain.Capabilities{Client: chain.Geth, Methods: map[string]bool{}}); err == nil || !strings.Contains(err.Error(), "--dev.period") {
+			t.Errorf("expected geth to be refused with a hint, got %v", err)
+		}
+		if mining := miningControl(cfg); mining == nil || mining.Automine || mining.Interval != 2*time.Second {
+			t.Errorf("unexpected mining control: %v", mining)
+		}
+	})
+
+	t.Run("FullySupported", func(t *testing.T) {
+		cfg := &config.Config{Mode: "pool-pressure", NodeStatsSeconds: 10, LagReportSeconds: 5}
+		warnings, err := adapt(cfg, &chain.Capabilities{Methods: map[string]bool{}})
+		if err != nil || len(warnings) != 0 {
+			t.Errorf("expected no warnings, got %v, %v", warnings, err)
+		}
+	})
+}
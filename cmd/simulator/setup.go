@@ -0,0 +1,188 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math/big"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/aakash4dev/ethereum-transaction-simulator/internal/chain"
+	"github.com/aakash4dev/ethereum-transaction-simulator/internal/config"
+	"github.com/aakash4dev/ethereum-transaction-simulator/internal/transaction"
+	"github.com/aakash4dev/ethereum-transaction-simulator/internal/wallet"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// capabilityTimeout bounds each call made while detecting the node's capabilities
+const capabilityTimeout = 5 * time.Second
+
+// node holds the connections to the chain shared by a scenario or subcommand
+type node struct {
+	rpc       *rpc.Client // READ_RPC_URL, raw client for methods ethclient does not wrap
+	client    *ethclient.Client
+	write     *rpc.Client // WRITE_RPC_URL, for dev methods and the pool transactions are sent into; rpc when both are the same
+	writer    *ethclient.Client
+	info      *chain.Info
+	caps      *chain.Capabilities
+	submitter transaction.Submitter           // Write endpoint, with broadcast and address policy applied
+	policy    *transaction.PolicySubmitter    // Nil without RECIPIENT_ALLOWLIST or RECIPIENT_DENYLIST
+	broadcast *transaction.BroadcastSubmitter // Nil without BROADCAST_RPC_URLS
+	closers   []func()
+}
+
+// connect dials the read and write endpoints and reads the chain ID and latest
+// header from the read endpoint. The shared header is refreshed every
+// CHAIN_INFO_SECONDS until ctx is done.
+func connect(ctx context.Context, cfg *config.Config) (*node, error) {
+	rpcClient, err := rpc.DialContext(ctx, cfg.ReadURL())
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to RPC: %w", err)
+	}
+	n := &node{rpc: rpcClient, client: ethclient.NewClient(rpcClient), write: rpcClient}
+	n.closers = append(n.closers, rpcClient.Close)
+	if cfg.WriteURL() != cfg.ReadURL() {
+		if n.write, err = rpc.DialContext(ctx, cfg.WriteURL()); err != nil {
+			n.Close()
+			return nil, fmt.Errorf("failed to connect to write RPC: %w", err)
+		}
+		n.closers = append(n.closers, n.write.Close)
+	}
+	n.writer = ethclient.NewClient(n.write)
+
+	n.info, err = chain.NewInfo(ctx, n.client)
+	if err != nil {
+		n.Close()
+		return nil, err
+	}
+	if cfg.ChainInfoSeconds > 0 {
+		go n.info.Run(ctx, time.Duration(cfg.ChainInfoSeconds)*time.Second)
+	}
+	return n, nil
+}
+
+// chainID returns the ID of the connected chain
+func (n *node) chainID() *big.Int {
+	return n.info.ChainID()
+}
+
+// devNode returns the anvil or hardhat methods of the write endpoint, the node that
+// mines the run's transactions
+func (n *node) devNode() (*chain.DevNode, error) {
+	return chain.NewDevNode(n.write, n.caps)
+}
+
+// detect identifies the client behind the endpoint and, unless PROBE_CAPABILITIES is
+// off, probes the optional methods it serves, then fits cfg to them
+func (n *node) detect(ctx context.Context, cfg *config.Config) error {
+	if cfg.ProbeCapabilities {
+		n.caps = chain.Detect(ctx, n.rpc, capabilityTimeout)
+	} else {
+		n.caps = &chain.Capabilities{Client: chain.Unknown, Methods: map[string]bool{}}
+		callCtx, cancel := context.WithTimeout(ctx, capabilityTimeout)
+		if err := n.rpc.CallContext(callCtx, &n.caps.ClientVersion, "web3_clientVersion"); err == nil {
+			n.caps.Client, n.caps.Version = chain.ParseClientVersion(n.caps.ClientVersion)
+		}
+		cancel()
+	}
+	chain.PrintCapabilities(n.caps)
+
+	warnings, err := adapt(cfg, n.caps)
+	if err != nil {
+		return err
+	}
+	for _, warning := range warnings {
+		log.Printf("Warning: %s", warning)
+	}
+	return checkBlockGasLimit(cfg, n.info)
+}
+
+// openSubmitter builds the path signed transactions take: WRITE_RPC_URL with SEND_METHOD,
+// broadcast to BROADCAST_RPC_URLS when set, behind the recipient policy when set
+func (n *node) openSubmitter(ctx context.Context, cfg *config.Config) error {
+	write, err := transaction.NewRPCSubmitter(ctx, cfg.WriteURL(), cfg.SendMethod)
+	if err != nil {
+		return err
+	}
+	n.closers = append(n.closers, write.Close)
+	n.submitter = write
+
+	if urls := cfg.BroadcastURLs(); urls != nil {
+		endpoints := []transaction.BroadcastEndpoint{{Name: endpointName(urls[0]), Submitter: write}}
+		for _, u := range urls[1:] {
+			extra, err := transaction.NewRPCSubmitter(ctx, u, cfg.SendMethod)
+			if err != nil {
+				return err
+			}
+			n.closers = append(n.closers, extra.Close)
+			endpoints = append(endpoints, transaction.BroadcastEndpoint{Name: endpointName(u), Submitter: extra})
+		}
+		if n.broadcast, err = transaction.NewBroadcastSubmitter(endpoints); err != nil {
+			return err
+		}
+		n.submitter = n.broadcast
+	}
+
+	if policy := addressPolicy(cfg); policy != nil {
+		n.policy = transaction.NewPolicySubmitter(n.submitter, policy, n.chainID())
+		n.submitter = n.policy
+	}
+	return nil
+}
+
+// printBroadcast prints how each broadcast endpoint answered, if broadcasting
+func (n *node) printBroadcast() {
+	if n.broadcast != nil {
+		transaction.PrintBroadcastReport(n.broadcast.Stats())
+	}
+}
+
+// Close closes every connection in the reverse order they were opened
+func (n *node) Close() {
+	for i := len(n.closers) - 1; i >= 0; i-- {
+		n.closers[i]()
+	}
+}
+
+// endpointName reduces an endpoint URL to its host for reports, as providers put API
+// keys in paths and queries
+func endpointName(endpoint string) string {
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Host == "" {
+		return endpoint
+	}
+	return u.Host
+}
+
+// funderWallet returns the funding wallet for PRIVATE_KEY on client
+func funderWallet(cfg *config.Config, client *ethclient.Client) (*wallet.Wallet, error) {
+	key, err := crypto.HexToECDSA(strings.TrimPrefix(cfg.PrivateKey, "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse private key: %w", err)
+	}
+	address := crypto.PubkeyToAddress(key.PublicKey)
+	return &wallet.Wallet{
+		PrivateKey:   key,
+		Address:      address,
+		NonceManager: transaction.NewNonceManager(client, address),
+		Client:       client,
+	}, nil
+}
+
+// newManager returns a wallet manager funding wallets with amount through the node's
+// submitter, keeping FUNDER_RESERVE and pacing funding with FUNDING_CONCURRENCY
+func newManager(cfg *config.Config, n *node, amount *big.Int) *wallet.Manager {
+	manager := wallet.NewManager(n.client, n.chainID(), amount)
+	if n.submitter != nil {
+		manager.SetSubmitter(n.submitter)
+	}
+	if reserve, ok := new(big.Int).SetString(cfg.FunderReserve, 10); ok {
+		manager.SetReserve(reserve)
+	}
+	manager.SetFundingWindow(cfg.FundingWindow())
+	return manager
+}
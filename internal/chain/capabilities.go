@@ -0,0 +1,170 @@
+// Package chain describes the node the simulator runs against: which client it
+// is and which optional RPC methods it serves.
+package chain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// Client is the execution client implementation behind the endpoint
+type Client string
+
+const (
+	Geth       Client = "geth"
+	Erigon     Client = "erigon"
+	Nethermind Client = "nethermind"
+	Reth       Client = "reth"
+	Besu       Client = "besu"
+	Anvil      Client = "anvil"
+	Hardhat    Client = "hardhat"
+	Unknown    Client = "unknown"
+)
+
+// Optional RPC methods probed at startup
+const (
+	TxpoolStatus         = "txpool_status"
+	TxpoolContentFrom    = "txpool_contentFrom"
+	DebugTraceTx         = "debug_traceTransaction"
+	DebugTraceBlock      = "debug_traceBlockByNumber"
+	FeeHistory           = "eth_feeHistory"
+	MaxPriorityFeePerGas = "eth_maxPriorityFeePerGas"
+	AdminPeers           = "admin_peers"
+	NetPeerCount         = "net_peerCount"
+)
+
+// methodNotFound is the JSON-RPC error code for an unknown method
+const methodNotFound = -32601
+
+// probes are the calls used to test each optional method. Arguments are chosen so
+// a supporting node answers cheaply, usually with an empty result or a
+// "not found" error that still proves the method exists.
+var probes = []struct {
+	method string
+	args   []interface{}
+}{
+	{TxpoolStatus, nil},
+	{TxpoolContentFrom, []interface{}{common.Address{}}},
+	{DebugTraceTx, []interface{}{common.Hash{}}},
+	{DebugTraceBlock, []interface{}{"0x0"}},
+	{FeeHistory, []interface{}{"0x1", "latest", []float64{}}},
+	{MaxPriorityFeePerGas, nil},
+	{AdminPeers, nil},
+	{NetPeerCount, nil},
+}
+
+// Capabilities is what the node said about itself at startup
+type Capabilities struct {
+	ClientVersion string // web3_clientVersion, empty if the node did not answer
+	Client        Client
+	Version       string          // Client version parsed from ClientVersion, e.g. v1.13.5-stable
+	Methods       map[string]bool // Probed methods and whether the node serves them
+}
+
+// Detect asks the node for its client version and probes the optional methods,
+// giving each call up to timeout
+func Detect(ctx context.Context, client *rpc.Client, timeout time.Duration) *Capabilities {
+	caps := &Capabilities{Client: Unknown, Methods: make(map[string]bool, len(probes))}
+	callCtx, cancel := context.WithTimeout(ctx, timeout)
+	if err := client.CallContext(callCtx, &caps.ClientVersion, "web3_clientVersion"); err == nil {
+		caps.Client, caps.Version = ParseClientVersion(caps.ClientVersion)
+	}
+	cancel()
+
+	for _, probe := range probes {
+		var result interface{}
+		callCtx, cancel := context.WithTimeout(ctx, timeout)
+		err := client.CallContext(callCtx, &result, probe.method, probe.args...)
+		caps.Methods[probe.method] = callCtx.Err() == nil && !MethodMissing(err)
+		cancel()
+	}
+	return caps
+}
+
+// ParseClientVersion splits a web3_clientVersion such as
+// "Geth/v1.13.5-stable-916d6a44/linux-amd64/go1.21.4" into the client and its version
+func ParseClientVersion(clientVersion string) (Client, string) {
+	name, rest, _ := strings.Cut(clientVersion, "/")
+	version, _, _ := strings.Cut(rest, "/")
+	switch strings.ToLower(name) {
+	case "geth":
+		return Geth, version
+	case "erigon":
+		return Erigon, version
+	case "nethermind":
+		return Nethermind, version
+	case "reth":
+		return Reth, version
+	case "besu":
+		return Besu, version
+	case "anvil":
+		return Anvil, version
+	case "hardhatnetwork":
+		return Hardhat, version
+	}
+	return Unknown, version
+}
+
+// MethodMissing reports whether err says the node does not serve the method, as
+// opposed to the call failing for its arguments. Providers that block a namespace
+// answer with their own wording, so a few common messages are recognized too.
+func MethodMissing(err error) bool {
+	if err == nil {
+		return false
+	}
+	var rpcErr rpc.Error
+	if errors.As(err, &rpcErr) && rpcErr.ErrorCode() == methodNotFound {
+		return true
+	}
+	msg := strings.ToLower(err.Error())
+	for _, phrase := range []string{"method not found", "does not exist/is not available", "unsupported method", "method not supported", "method not allowed"} {
+		if strings.Contains(msg, phrase) {
+			return true
+		}
+	}
+	return false
+}
+
+// Supports reports whether the node serves method. Methods that were not probed
+// are assumed to be served.
+func (c *Capabilities) Supports(method string) bool {
+	supported, probed := c.Methods[method]
+	return supported || !probed
+}
+
+// String names the client and its version
+func (c *Capabilities) String() string {
+	if c.ClientVersion == "" {
+		return "unknown client (web3_clientVersion not served)"
+	}
+	if c.Client == Unknown {
+		return c.ClientVersion
+	}
+	return fmt.Sprintf("%s %s", c.Client, c.Version)
+}
+
+// PrintCapabilities prints the detected client and which optional methods it serves
+func PrintCapabilities(c *Capabilities) {
+	fmt.Printf("\n=== Node Capabilities ===\n")
+	fmt.Printf("Client: %s\n", c)
+	methods := make([]string, 0, len(c.Methods))
+	for method := range c.Methods {
+		methods = append(methods, method)
+	}
+	sort.Strings(methods)
+	for _, method := range methods {
+		status := "yes"
+		if !c.Methods[method] {
+			status = "no"
+		}
+		fmt.Printf("  %-26s %s\n", method, status)
+	}
+	fmt.Printf("==========================\n")
+}
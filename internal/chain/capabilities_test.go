@@ -0,0 +1,91 @@
+package chain
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// codedError is an RPC error with a JSON-RPC code
+type codedError struct {
+	code int
+	msg  string
+}
+
+func (e codedError) Error() string  { return e.msg }
+func (e codedError) ErrorCode() int { return e.code }
+
+func TestParseClientVersion(t *testing.T) {
+	cases := []struct {
+		version string
+		client  Client
+		tag     string
+	}{
+		{"Geth/v1.13.5-stable-916d6a44/linux-amd64/go1.21.4", Geth, "v1.13.5-stable-916d6a44"},
+		{"erigon/2.55.1/linux-amd64/go1.21.5", Erigon, "2.55.1"},
+		{"Nethermind/v1.25.4+20b10b35/linux-x64/dotnet8.0.2", Nethermind, "v1.25.4+20b10b35"},
+		{"reth/v0.1.0-alpha.19-5e3ac5c4/x86_64-unknown-linux-gnu", Reth, "v0.1.0-alpha.19-5e3ac5c4"},
+		{"besu/v24.1.2/linux-x86_64/openjdk-java-17", Besu, "v24.1.2"},
+		{"anvil/v0.2.0", Anvil, "v0.2.0"},
+		{"HardhatNetwork/2.22.2/@ethereumjs/vm/5.9.3", Hardhat, "2.22.2"},
+		{"Ganache/v7.9.1/EthereumJS TestRPC/v7.9.1/ethereum-js", Unknown, "v7.9.1"},
+	}
+	for _, c := range cases {
+		client, tag := ParseClientVersion(c.version)
+		if client != c.client || tag != c.tag {
+			t.Errorf("%s: expected %s %s, got %s %s", c.version, c.client, c.tag, client, tag)
+		}
+	}
+}
+
+func TestMethodMissing(t *testing.T) {
+	t.Run("MethodNotFoundCode", func(t *testing.T) {
+		if !MethodMissing(fmt.Errorf("call failed: %w", codedError{-32601, "the method txpool_status does not exist/is not available"})) {
+			t.Error("expected -32601 to mean the method is missing")
+		}
+	})
+
+	t.Run("ProviderMessage", func(t *testing.T) {
+		if !MethodMissing(errors.New("Method not allowed on this plan")) {
+			t.Error("expected a provider refusal to mean the method is missing")
+		}
+	})
+
+	t.Run("ArgumentErrorsProveTheMethodExists", func(t *testing.T) {
+		for _, err := range []error{nil, codedError{-32000, "transaction not found"}, errors.New("genesis is not traceable")} {
+			if MethodMissing(err) {
+				t.Errorf("expected %v to mean the method is served", err)
+			}
+		}
+	})
+}
+
+func TestSupports(t *testing.T) {
+	caps := &Capabilities{Methods: map[string]bool{TxpoolStatus: true, DebugTraceTx: false}}
+	if !caps.Supports(TxpoolStatus) || caps.Supports(DebugTraceTx) || !caps.Supports("eth_chainId") {
+		t.Errorf("unexpected support: %v", caps.Methods)
+	}
+}
+
+func TestInfo(t *testing.T) {
+	info := &Info{chainID: big.NewInt(1337), header: &types.Header{Number: big.NewInt(7), GasLimit: 30000000}}
+	if info.London() || info.BaseFee() != nil {
+		t.Error("expected no base fee on a pre-London header")
+	}
+	if err := info.CheckGasLimit("GAS_LIMIT", 30000000); err != nil {
+		t.Errorf("expected a gas limit equal to the block's to fit, got %v", err)
+	}
+	if err := info.CheckGasLimit("GAS_LIMIT", 30000001); err == nil {
+		t.Error("expected a gas limit above the block's to be rejected")
+	}
+
+	info.header = &types.Header{Number: big.NewInt(8), GasLimit: 30000000, BaseFee: big.NewInt(7)}
+	baseFee := info.BaseFee()
+	baseFee.SetInt64(0)
+	if info.Header().BaseFee.Int64() != 7 {
+		t.Error("expected BaseFee to return a copy")
+	}
+}
@@ -0,0 +1,126 @@
+package chain
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// DevNode drives the state-manipulation methods of local development nodes: chain
+// snapshots that a run can be reverted to, and balances set by fiat instead of by
+// transfers. Only anvil and hardhat serve both.
+type DevNode struct {
+	client *rpc.Client
+	kind   Client
+}
+
+// NewDevNode wraps client when the detected node is anvil or hardhat
+func NewDevNode(client *rpc.Client, caps *Capabilities) (*DevNode, error) {
+	if caps.Client != Anvil && caps.Client != Hardhat {
+		return nil, fmt.Errorf("snapshots and set balances need an anvil or hardhat node, the endpoint is %s", caps)
+	}
+	return &DevNode{client: client, kind: caps.Client}, nil
+}
+
+// Snapshot records the current chain state and returns the ID to revert to
+func (d *DevNode) Snapshot(ctx context.Context) (string, error) {
+	var id string
+	if err := d.client.CallContext(ctx, &id, "evm_snapshot"); err != nil {
+		return "", fmt.Errorf("evm_snapshot failed: %w", err)
+	}
+	return id, nil
+}
+
+// Revert restores the chain state of snapshot id. Both nodes drop the snapshot
+// (and later ones) on revert, so each ID can be reverted to once.
+func (d *DevNode) Revert(ctx context.Context, id string) error {
+	var reverted bool
+	if err := d.client.CallContext(ctx, &reverted, "evm_revert", id); err != nil {
+		return fmt.Errorf("evm_revert failed: %w", err)
+	}
+	if !reverted {
+		return fmt.Errorf("the node has no snapshot %s", id)
+	}
+	return nil
+}
+
+// SetBalance sets the balance of address to wei without a transaction
+func (d *DevNode) SetBalance(ctx context.Context, address common.Address, wei *big.Int) error {
+	method := "anvil_setBalance"
+	if d.kind == Hardhat {
+		method = "hardhat_setBalance"
+	}
+	if err := d.client.CallContext(ctx, nil, method, address, (*hexutil.Big)(wei)); err != nil {
+		return fmt.Errorf("%s failed: %w", method, err)
+	}
+	return nil
+}
+
+// Mining is how a development node produces blocks
+type Mining struct {
+	Automine bool          // Mine a block for every transaction
+	Interval time.Duration // Mine a block this often, 0 disables interval mining
+}
+
+// String describes the mining mode
+func (m Mining) String() string {
+	switch {
+	case m.Automine && m.Interval > 0:
+		return fmt.Sprintf("automine and a block every %s", m.Interval)
+	case m.Automine:
+		return "automine"
+	case m.Interval > 0:
+		return fmt.Sprintf("a block every %s", m.Interval)
+	}
+	return "manual (no blocks are mined)"
+}
+
+// SetMining switches the node to mining, making block time a variable of the run.
+// anvil takes the interval in whole seconds and hardhat in milliseconds. geth --dev
+// has no such methods; its block time is fixed at startup with --dev.period.
+func (d *DevNode) SetMining(ctx context.Context, mining Mining) error {
+	if err := d.client.CallContext(ctx, nil, "evm_setAutomine", mining.Automine); err != nil {
+		return fmt.Errorf("evm_setAutomine failed: %w", err)
+	}
+	interval := uint64(mining.Interval / time.Millisecond)
+	if d.kind == Anvil {
+		interval = uint64(mining.Interval / time.Second)
+	}
+	if err := d.client.CallContext(ctx, nil, "evm_setIntervalMining", interval); err != nil {
+		return fmt.Errorf("evm_setIntervalMining failed: %w", err)
+	}
+	return nil
+}
+
+// IncreaseTime moves the chain's clock forward by d and mines a block, so the next
+// transaction already sees the new time. Both nodes count in whole seconds.
+func (d *DevNode) IncreaseTime(ctx context.Context, by time.Duration) error {
+	var offset interface{}
+	if err := d.client.CallContext(ctx, &offset, "evm_increaseTime", uint64(by/time.Second)); err != nil {
+		return fmt.Errorf("evm_increaseTime failed: %w", err)
+	}
+	if err := d.client.CallContext(ctx, nil, "evm_mine"); err != nil {
+		return fmt.Errorf("evm_mine failed: %w", err)
+	}
+	return nil
+}
+
+// ParseRevertArgs parses `simulator revert --id 0x...`
+func ParseRevertArgs(args []string) (string, error) {
+	fs := flag.NewFlagSet("revert", flag.ContinueOnError)
+	id := fs.String("id", "", "snapshot ID printed by `simulator snapshot` or a run")
+	if err := fs.Parse(args); err != nil {
+		return "", err
+	}
+	if *id == "" {
+		return "", errors.New("--id is required")
+	}
+	return *id, nil
+}
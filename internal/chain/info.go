@@ -0,0 +1,126 @@
+package chain
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// Info caches what every component needs to know about the chain: its ID, fetched
+// once, and the latest header with the base fee and block gas limit, refreshed by
+// Run. One Info is created at startup and shared, so constructors take the chain
+// ID from it instead of asking the node again.
+type Info struct {
+	client  *ethclient.Client
+	chainID *big.Int
+
+	mu      sync.RWMutex
+	header  *types.Header
+	updated time.Time
+}
+
+// NewInfo fetches the chain ID and the latest header
+func NewInfo(ctx context.Context, client *ethclient.Client) (*Info, error) {
+	chainID, err := client.ChainID(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get chain ID: %w", err)
+	}
+	info := &Info{client: client, chainID: chainID}
+	if err := info.Refresh(ctx); err != nil {
+		return nil, err
+	}
+	return info, nil
+}
+
+// Refresh fetches the latest header
+func (i *Info) Refresh(ctx context.Context) error {
+	header, err := i.client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to get latest header: %w", err)
+	}
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.header = header
+	i.updated = time.Now()
+	return nil
+}
+
+// Run refreshes the latest header every interval until ctx is cancelled. A failed
+// refresh is logged and the previous header kept.
+func (i *Info) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := i.Refresh(ctx); err != nil && ctx.Err() == nil {
+				log.Printf("Warning: chain info refresh failed, keeping block %s: %v", i.Header().Number, err)
+			}
+		}
+	}
+}
+
+// ChainID returns the chain ID. It never changes, so callers may keep it.
+func (i *Info) ChainID() *big.Int {
+	return i.chainID
+}
+
+// Header returns the latest header as of the last refresh. It must not be modified.
+func (i *Info) Header() *types.Header {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	return i.header
+}
+
+// Age returns how long ago the header was fetched
+func (i *Info) Age() time.Duration {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	return time.Since(i.updated)
+}
+
+// BaseFee returns the latest block's base fee, or nil before London
+func (i *Info) BaseFee() *big.Int {
+	header := i.Header()
+	if header.BaseFee == nil {
+		return nil
+	}
+	return new(big.Int).Set(header.BaseFee)
+}
+
+// London reports whether the chain has EIP-1559 base fees
+func (i *Info) London() bool {
+	return i.Header().BaseFee != nil
+}
+
+// GasLimit returns the latest block's gas limit
+func (i *Info) GasLimit() uint64 {
+	return i.Header().GasLimit
+}
+
+// CheckGasLimit returns an error when a transaction with gasLimit could never fit
+// in a block. name is the setting the gas limit came from, for the message.
+func (i *Info) CheckGasLimit(name string, gasLimit uint64) error {
+	if blockLimit := i.GasLimit(); gasLimit > blockLimit {
+		return fmt.Errorf("%s (%d) exceeds the block gas limit (%d)", name, gasLimit, blockLimit)
+	}
+	return nil
+}
+
+// String summarizes the chain for the startup log
+func (i *Info) String() string {
+	header := i.Header()
+	s := fmt.Sprintf("chain %s at block %s, gas limit %d", i.chainID, header.Number, header.GasLimit)
+	if header.BaseFee != nil {
+		s += fmt.Sprintf(", base fee %s wei", header.BaseFee)
+	}
+	return s
+}
@@ -5,68 +5,533 @@ import (
 	"fmt"
 	"log"
 	"math/big"
+	"net/url"
 	"os"
+	"reflect"
 	"strconv"
 	"strings"
+	"sync"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/joho/godotenv"
 )
 
 // Config holds the application configuration
 type Config struct {
-	RPCURL                string
-	PrivateKey            string
-	Value                 string
-	GasLimit              uint64
-	TransactionData       string
-	MaxTransactions       int
-	DelaySeconds          int
-	RetryDelay            int
-	Mode                  string // "transfer", "deploy", "interact", "all", "parallel"
-	MinBalance            string // Minimum balance to create wallets (default: 100000)
-	WalletCount           int    // Number of wallets to create (default: 1000)
-	FundingAmount         string // Amount to fund each wallet (default: 100)
-	MaxConcurrentRequests int    // Maximum concurrent RPC requests (default: 2000)
-	BalanceCheckInterval  int    // Check balance every N transactions (default: 100)
-	FundingConcurrency    int    // Concurrent funding operations (default: 50)
+	RPCURL           string
+	ReadRPCURL       string // Endpoint for balance, nonce, gas and block queries, e.g. a replica; empty uses RPC_URL
+	WriteRPCURL      string // Endpoint for submitting transactions, empty uses RPC_URL
+	SendMethod       string // "eth_sendRawTransaction" or "eth_sendPrivateTransaction"
+	BroadcastRPCURLs string // Comma-separated extra endpoints every transaction is also sent to, first to accept wins
+	Signer           string // "auto", "eip155", or "homestead" (default: auto)
+	PrivateKey       string
+	Value            string
+	GasLimit         uint64
+	TransactionData  string
+	TxDataTemplate   string // Per-transaction data with {wallet}, {address}, {seq} placeholders, empty uses TX_DATA
+	TxMemo           bool   // Prefix transfer data with a run ID, wallet index and sequence memo (default: false)
+	RunID            string // Run ID encoded in memos as up to 16 hex digits, empty generates one per run
+	RunsDir          string // Artifacts of each run are written under RUNS_DIR/<run id>/, empty disables (default: runs)
+	// Per-workload overrides; zero/empty inherits GasLimit/Value
+	TransferGasLimit          uint64
+	DeployGasLimit            uint64
+	InteractGasLimit          uint64
+	ParallelGasLimit          uint64
+	TransferValue             string
+	DeployValue               string
+	InteractValue             string
+	ParallelValue             string
+	ParallelContracts         string // Comma-separated contracts parallel mode calls instead of transferring
+	ExpectedEvents            string // Events each contract call must emit, e.g. set(uint256)=ValueSet(uint256)
+	GasOnly                   bool   // Send value=0 transactions to the sender itself, only consuming gas (default: false)
+	Audit                     bool   // Reconcile wallet nonces and balances with the chain after a parallel run (default: false)
+	MaxTransactions           int    // 0 = unlimited
+	DelaySeconds              int
+	RetryDelay                int
+	Mode                      string // "transfer", "deploy", "interact", "all", "parallel", "bundles", "spam-probe", "fee-probe", "edge", "canary", "soak", "adaptive", "reads", "logs", "archive", "ws-fanout", "trace", "shaped", "gas-grief", "large-deploy", "call-depth", "cancun", "selfdestruct", "diff", "propagation", "replay", "nonce-gap", "pool-pressure", "invalid-tx"
+	MinBalance                string // Minimum balance to create wallets (default: 100000)
+	WalletCount               int    // Number of wallets to create (default: 1000)
+	WalletsFile               string // Wallet file from `simulator fund` parallel modes send from instead of generating and funding wallets
+	FundingAmount             string // Amount to fund each wallet, or "auto" to derive it from the workload (default: 100)
+	FundingSafetyPercent      int    // Headroom applied to auto funding, 150 = +50% (default: 150)
+	FunderReserve             string // Balance the funding wallet always keeps, in wei (default: 0)
+	ExternalFunding           bool   // Wait for wallets to be funded externally instead of funding them (default: false)
+	AddressExportFile         string // File the wallet addresses are written to for external funding, empty prints them
+	AddressExportFormat       string // "lines" or "json" (default: lines)
+	WalletManifestFile        string // Also write the wallet manifest (index, address, key fingerprint) here, empty writes it to the run directory only
+	FundingPollInterval       int    // Seconds between balance checks while waiting for external funding (default: 15)
+	FaucetURL                 string // Faucet that funds wallets when the funding wallet has no balance (optional)
+	FaucetType                string // "http" or a preset such as "eth-faucet" (default: http)
+	FaucetBody                string // Request body for the http faucet type, {address} is replaced by the recipient
+	FaucetAPIKey              string // Bearer token sent to the faucet (optional)
+	FaucetRequestsPerMinute   int    // Faucet rate limit, 0 = unlimited (default: 10)
+	FaucetRetries             int    // Retries per failed faucet request (default: 3)
+	DevFunding                bool   // Fund wallets with anvil_setBalance/hardhat_setBalance instead of transfers (default: false)
+	DevSnapshot               bool   // Snapshot an anvil/hardhat node before the run and revert to it afterwards (default: false)
+	DevMining                 string // Block production set on an anvil/hardhat node: "auto", "interval" or "both", empty leaves the node as it is
+	DevBlockTime              int    // Seconds between blocks for the interval DEV_MINING modes (default: 2)
+	MaxConcurrentRequests     int    // Maximum concurrent RPC requests (default: 2000)
+	BalanceCheckInterval      int    // Check balance every N transactions (default: 100)
+	FundingConcurrency        int    // Most funding transactions unmined at once; funding starts lower and adapts to the node's pool limits (default: 50)
+	VerifySampleRate          int    // Verify 1 in N parallel transactions, 0 disables (default: 0)
+	MaxInFlight               int    // Max unmined transactions across all wallets, 0 = unlimited (default: 0)
+	MaxInFlightPerWallet      int    // Max unmined transactions per wallet, 0 = unlimited (default: 0)
+	TargetTPS                 int    // Auto-size the wallet pool for this TPS, 0 uses WALLET_COUNT (default: 0)
+	RateLimit                 int    // Global parallel-mode send rate in transactions per second, 0 = unlimited; reloadable (default: 0)
+	MaxGasPrice               string // Cap on parallel-mode gas prices in wei, empty leaves them uncapped; reloadable
+	ThinkTime                 string // Per-wallet pause after each parallel-mode transaction: fixed:D, uniform:MIN-MAX or exponential:MEAN; empty sends in a tight loop
+	PersonaFile               string // JSON file of personas giving shares of the parallel-mode wallet pool their own behavior, empty disables
+	TargetDistribution        string // How parallel mode picks recipients and contracts: "uniform" or "zipf" (default: uniform)
+	TargetZipfExponent        string // Zipf exponent, above 1; higher concentrates traffic on fewer targets (default: 1.1)
+	SlowStartSeconds          int    // Double the active parallel-mode wallets this often until all send, 0 starts them together (default: 0)
+	SlowStartWallets          int    // Wallets active from the start of a slow start (default: 10)
+	InclusionPositions        bool   // Report where parallel-mode transactions landed within their blocks (default: false)
+	DropTimeoutSeconds        int    // Count parallel-mode transactions not mined this long after sending as dropped, 0 disables (default: 0)
+	ContractStats             bool   // Report calls, failures, reverts and gas per contract, fetching a receipt per mined call (default: false)
+	Create2Deploy             bool   // Deploy contracts through the CREATE2 factory and reuse those already on chain (default: false)
+	Create2Namespace          string // Salt namespace of CREATE2 deployments; runs sharing it share contracts (default: ethereum-transaction-simulator)
+	WarmupSeconds             int    // Warm-up duration used to measure per-wallet rate (default: 10)
+	BundleRelayURL            string // Flashbots-compatible relay for bundles mode
+	BundleSize                int    // Transactions per bundle (default: 5)
+	BundleAuthKey             string // Key signing relay requests, empty generates one per run
+	ProbeObserveSeconds       int    // How long probe modes watch their transactions (default: 120)
+	FeeProbeMinPercent        int    // Lowest fee-probe level, % of suggested gas price (default: 10)
+	FeeProbeMaxPercent        int    // Highest fee-probe level, % of suggested gas price (default: 150)
+	FeeProbeSteps             int    // Number of fee-probe levels (default: 15)
+	CanaryIntervalSeconds     int    // Seconds between canary transactions (default: 30)
+	CanaryMaxLatency          int    // Inclusion latency in seconds that raises a canary alert (default: 60)
+	CanaryWebhookURL          string // Receives canary alerts as JSON POSTs, empty only logs
+	CanaryExitOnAlert         bool   // Exit on the first canary alert (default: false)
+	DebugAddr                 string // Serve pprof and expvar on this address, empty disables (default: "")
+	DistributedRole           string // "coordinator" or "agent" for multi-machine runs, empty runs standalone
+	CoordinatorAddr           string // Coordinator listen/dial address (default: 127.0.0.1:7070)
+	CoordinatorSecret         string // Shared secret agents prove they know before the coordinator serves them
+	AgentCount                int    // Agents the coordinator waits for (default: 1)
+	AgentStartDelay           int    // Seconds between the last agent joining and the common start (default: 10)
+	NTPServer                 string // NTP server whose offset is recorded at run start in distributed runs, empty skips it (default: pool.ntp.org)
+	HealthAddr                string // Serve /healthz and /readyz on this address, empty disables (default: "")
+	Schedule                  string // Cron expression for recurring runs, empty runs once (default: "")
+	ScheduleDuration          int    // Maximum minutes per scheduled run, 0 = until the run ends (default: 60)
+	ReportDir                 string // Directory archiving one report per scheduled run, empty disables (default: "")
+	SoakTPS                   int    // Constant send rate in soak mode (default: 50)
+	SoakDuration              int    // Soak length in minutes (default: 720)
+	SoakWindow                int    // Soak measurement window in minutes (default: 10)
+	SoakDegradationPercent    int    // Change against the first window flagged as degradation (default: 25)
+	AdaptiveStartTPS          int    // Initial rate of the adaptive search (default: 10)
+	AdaptiveWindow            int    // Seconds measured at each rate (default: 30)
+	AdaptiveMaxLatency        int    // p95 inclusion latency in seconds considered unsustainable (default: 15)
+	AdaptiveMaxFailurePercent int    // Failure rate considered unsustainable (default: 5)
+	AdaptiveStepPercent       int    // Initial rate increase per healthy window (default: 50)
+	ReadRPS                   int    // Read queries per second, 0 = as fast as READ_CONCURRENCY allows (default: 100)
+	ReadDuration              int    // Seconds of read load (default: 60)
+	ReadConcurrency           int    // Maximum read queries in flight (default: 100)
+	ReadMix                   string // Weighted query mix (default: call=40,balance=30,logs=10,storage=20)
+	ReadLogBlockRange         uint64 // Blocks covered by each eth_getLogs query (default: 100)
+	LogsRPS                   int    // eth_getLogs queries per second, 0 = as fast as LOGS_CONCURRENCY allows (default: 20)
+	LogsDuration              int    // Seconds of eth_getLogs load (default: 60)
+	LogsConcurrency           int    // Maximum eth_getLogs queries in flight (default: 20)
+	LogsBlockRanges           string // Comma-separated block range widths (default: 100,1000,10000)
+	LogsTopics                string // Comma-separated topic0 filters, event signatures or hex (optional)
+	ArchiveRPS                int    // Historical state queries per second, 0 = as fast as ARCHIVE_CONCURRENCY allows (default: 50)
+	ArchiveDuration           int    // Seconds of historical state load (default: 60)
+	ArchiveConcurrency        int    // Maximum historical state queries in flight (default: 50)
+	ArchiveMaxDepth           uint64 // Deepest block queried, counted back from the head (default: 100000)
+	ArchiveDepthDistribution  string // "uniform" or "exponential" (default: uniform)
+	WSURL                     string // WebSocket endpoint for ws-fanout mode
+	WSConnections             int    // Concurrent WebSocket connections (default: 100)
+	WSSubscriptions           string // Subscriptions per connection (default: newHeads,logs,newPendingTransactions)
+	WSDuration                int    // Seconds to keep subscriptions open (default: 60)
+	TraceRPS                  int    // Trace calls per second, 0 = as fast as TRACE_CONCURRENCY allows (default: 5)
+	TraceDuration             int    // Seconds of trace load (default: 60)
+	TraceConcurrency          int    // Maximum trace calls in flight (default: 4)
+	TraceTracer               string // Tracer passed to debug_trace*, empty uses the struct logger (default: callTracer)
+	TraceTimeout              int    // Node-side tracer timeout in seconds (default: 10)
+	TraceBlockPercent         int    // Share of calls tracing whole blocks (default: 20)
+	TraceRecentBlocks         int    // Only blocks this close to the head are traced (default: 20)
+	TrafficShape              string // Shape of the target rate in shaped mode: sine, step, poisson, or spike (default: sine)
+	ShapeBaseTPS              int    // Base rate of the shape (default: 50)
+	ShapeAmplitudeTPS         int    // Sine: swing above and below the base rate (default: 40)
+	ShapePeriod               int    // Sine: minutes per cycle, e.g. a day compressed into an hour (default: 60)
+	ShapeSteps                string // Step: comma-separated rate levels (default: "20,50,100")
+	ShapeStepSeconds          int    // Step: seconds at each level (default: 300)
+	ShapeSpikeTPS             int    // Spike: rate during the flash crowd (default: 500)
+	ShapeSpikeAt              int    // Spike: seconds into the run the flash crowd starts (default: 600)
+	ShapeSpikeSeconds         int    // Spike: length of the flash crowd (default: 60)
+	ShapeDuration             int    // Minutes of shaped load (default: 60)
+	ShapeTimeWarp             int    // Seconds of chain time added on anvil/hardhat whenever the shape enters a new phase, 0 disables (default: 0)
+	AssertMinTPS              string // Fail the run below this mined TPS, empty disables
+	AssertMaxFailurePercent   string // Fail the run above this failure rate, empty disables
+	AssertMaxP95Seconds       string // Fail the run above this p95 inclusion latency, empty disables
+	BaselineFile              string // Baseline report the run is compared with as it goes, empty disables
+	BaselineSaveFile          string // Write this run as a baseline report when it ends, empty disables
+	BaselineWindow            int    // Seconds per rolling window compared with the baseline (default: 60)
+	BaselineRegressionPercent int    // Change against the baseline flagged as a regression (default: 25)
+	BaselineAbortWindows      int    // Abort after this many consecutive regressed windows, 0 = never (default: 0)
+	GriefReserveGas           uint64 // Gas each gas-grief call leaves unused, 0 runs out of gas (default: 1000)
+	GriefRevertPercent        int    // Revert after burning this share of the gas limit, 0 never reverts (default: 0)
+	LargeCodeSize             int    // Runtime code size of each large-deploy contract in bytes (default: 24000)
+	CallDepths                string // Recursion depths call-depth mode picks from per call (default: "8,64,256")
+	CancunIterations          int    // Loop iterations per cancun-mode call (default: 100)
+	SelfdestructRounds        int    // Contracts per case in selfdestruct mode (default: 5)
+	DiffRPCURL                string // Second endpoint diff mode compares RPC_URL against
+	DiffTransactions          int    // Transfers and calls diff mode sends after its two deployments (default: 20)
+	PropagationRPCURL         string // Endpoint propagation mode watches for transactions sent through RPC_URL
+	PropagationSamples        int    // Transactions propagation mode sends (default: 100)
+	PropagationSendMs         int    // Pause between propagation-mode sends in milliseconds (default: 500)
+	PropagationPollMs         int    // How often propagation mode polls the watched endpoint in milliseconds (default: 50)
+	ReplayFile                string // File of raw signed transactions, one hex line each, submitted by replay mode
+	ReplayTPS                 int    // Replay submission rate, 0 = as fast as possible (default: 0)
+	NonceGapOffsets           string // Future nonces nonce-gap mode sends, as offsets and ranges above the pending nonce (default: 1-5,10,20,64,100)
+	NonceGapFill              bool   // Send the missing nonces after observing so queued transactions can be promoted (default: true)
+	PoolPressureAccounts      int    // Accounts pool-pressure mode generates, funds and sends from (default: 50)
+	PoolPressureTxs           int    // Consecutive nonces each pool-pressure account sends (default: 100)
+	RecipientAllowlist        string // Comma-separated addresses transactions may be sent to, empty allows any
+	RecipientDenylist         string // Comma-separated addresses transactions must never be sent to
+	TestnetSpendLimit         string // Planned spend in wei above which a public testnet run must be confirmed (default: 0.1 ETH)
+	ExportFile                string // Write the parallel workload's signed transactions here instead of sending them, empty sends
+	EventStream               string // Stream per-transaction events as NDJSON to "stdout" or this file, empty disables
+	MaxFailureRate            string // Abort a parallel run when more than this share (0-1) of recent transactions failed, empty disables
+	FailureRateWindow         int    // Most recent transactions MAX_FAILURE_RATE is measured over (default: 100)
+	AbortSweepTo              string // Sweep the wallet pool's balances to this address after a MAX_FAILURE_RATE abort, empty keeps them
+	ChaosDropPercent          int    // Share of parallel sends dropped before reaching the node (default: 0)
+	ChaosCorruptPercent       int    // Share of parallel sends whose signature is replaced by an invalid one (default: 0)
+	ChaosSignDelayMs          int    // Delay each signing by a random time up to this many milliseconds, 0 disables (default: 0)
+	TxTemplateFile            string // JSON file of transaction templates parallel mode sends instead of its built-in workload
+	ScriptFile                string // Starlark script whose build() function builds every parallel-mode transaction, empty disables
+	PluginDir                 string // Directory whose executables are started as plugins at run start
+	PluginWorkload            string // Plugin workload that builds every parallel-mode transaction, empty disables
+	PluginFeeStrategy         string // Plugin fee strategy that sets parallel-mode gas prices, empty uses the node's suggestion
+	ProbeCapabilities         bool   // Detect the client and probe optional RPC methods at startup, adapting features to them (default: true)
+	ChainInfoSeconds          int    // Refresh the shared latest header, base fee and block gas limit this often, 0 fetches them once (default: 12)
+	NodeStatsSeconds          int    // Sample peer count, txpool size and sync status this often during parallel runs, 0 disables (default: 0)
+	LagReportSeconds          int    // Compare submit, accept and include rates this often during parallel runs, 0 disables (default: 0)
+	ShedThresholdPercent      int    // Share of submissions refused or dropped at which the node counts as shedding load (default: 5)
+	HostPrometheusURL         string // Prometheus server with the node host's node_exporter series (optional)
+	HostSelector              string // Label matchers picking the node host in Prometheus, e.g. instance="node:9100"
+	HostExporterURL           string // node_exporter /metrics endpoint scraped directly when no Prometheus is set (optional)
+	HostMetricsSeconds        int    // Seconds between host metric samples (default: 15)
+	Confirmation              string // "none", "mempool", "mined", "blocks", or "finalized"; empty keeps each mode's default
+	ConfirmationBlocks        uint64 // Confirmations required by CONFIRMATION=blocks (default: 6)
+	ConfirmationTimeout       int    // Seconds to wait for a transaction to be confirmed (default: 60)
+	FinalityTracking          bool   // Measure inclusion-to-safe and inclusion-to-finalized latency in parallel runs (default: false)
+	FinalityWaitSeconds       int    // Seconds to keep tracking finality after the load stops (default: 960)
+	VaultAddr                 string // HashiCorp Vault server the private key is read from when PRIVATE_KEY is empty
+	VaultPath                 string // Vault secret path, e.g. secret/data/simulator
+	VaultField                string // Field of the Vault secret holding the key (default: private_key)
+	VaultNamespace            string // Vault Enterprise namespace
+	VaultToken                string // Vault token; takes precedence over AppRole
+	VaultRoleID               string // Vault AppRole role_id
+	VaultSecretID             string // Vault AppRole secret_id
+	SplitRole                 string // "signer" holds keys and signs, "sender" sends without keys, empty runs both in one process
+	SignerSocket              string // Unix socket connecting the signer and sender roles (default: /tmp/simulator-signer/signer.sock)
+	SignerWallets             string // Wallet file from `simulator fund` whose keys the signer role holds
+	SignerMaxValue            string // Largest value in wei the signer role signs for, empty uses the parallel value
+	SignerMaxGasPrice         string // Largest gas price in wei the signer role signs for, empty uses MAX_GAS_PRICE
+	Profile                   string // Named profile applied from PROFILES_FILE, selected by PROFILE or --profile
+	WithWrites                bool   // Run the parallel write load alongside the reads, logs, archive, ws-fanout or trace workload (default: false)
+
+	loadErr error // Failure reading a value, applying PROFILE or fetching secrets, returned by Validate
 }
 
 // Load loads configuration from .env file and environment variables with defaults
 func Load() *Config {
+	snapshotProcessEnv()
+
 	// Try to load .env file, ignore error if it doesn't exist
 	if err := godotenv.Load(); err != nil {
 		log.Println("No .env file found, using environment variables and defaults")
 	}
+	// Mounted config file (e.g. a Kubernetes ConfigMap), lower precedence than .env
+	if path := os.Getenv("CONFIG_FILE"); path != "" {
+		if err := godotenv.Load(path); err != nil {
+			log.Printf("Failed to load CONFIG_FILE %s: %v", path, err)
+		}
+	}
+	// Named profile, overriding both files; an error is reported by Validate
+	profileErr := applyProfile()
 
-	return &Config{
-		RPCURL:                getEnv("RPC_URL", "http://127.0.0.1:8545"),
-		PrivateKey:            getEnv("PRIVATE_KEY", ""),
-		Value:                 getEnv("VALUE", "1"),
-		GasLimit:              getEnvUint64("GAS_LIMIT", 210000),
-		TransactionData:       getEnv("TX_DATA", "lets bomb the network with transactions! AMF to the moon : ) 🚀"),
-		MaxTransactions:       getEnvInt("MAX_TRANSACTIONS", 10000),
-		DelaySeconds:          getEnvInt("DELAY_SECONDS", 1),
-		RetryDelay:            getEnvInt("RETRY_DELAY", 10),
-		Mode:                  getEnv("MODE", "all"),
-		MinBalance:            getEnv("MIN_BALANCE", "100000"),
-		WalletCount:           getEnvInt("WALLET_COUNT", 1000),
-		FundingAmount:         getEnv("FUNDING_AMOUNT", "100"),
-		MaxConcurrentRequests: getEnvInt("MAX_CONCURRENT_REQUESTS", 2000),
-		BalanceCheckInterval:  getEnvInt("BALANCE_CHECK_INTERVAL", 100),
-		FundingConcurrency:    getEnvInt("FUNDING_CONCURRENCY", 50),
+	cfg := fromEnv()
+	if profileErr != nil {
+		cfg.loadErr = profileErr
 	}
+	return cfg
 }
 
-func getEnv(key, defaultValue string) string {
+// envMu serializes fromEnv, and envErr holds the first value it failed to read
+var (
+	envMu  sync.Mutex
+	envErr error
+)
+
+// fromEnv builds a Config from the current environment. A value that cannot be
+// read is left at its default and reported by Validate.
+func fromEnv() *Config {
+	envMu.Lock()
+	defer envMu.Unlock()
+	envErr = nil
+
+	cfg := &Config{
+		RPCURL:                    getEnv("RPC_URL", "http://127.0.0.1:8545"),
+		ReadRPCURL:                getEnv("READ_RPC_URL", ""),
+		WriteRPCURL:               getEnv("WRITE_RPC_URL", ""),
+		SendMethod:                getEnv("SEND_METHOD", "eth_sendRawTransaction"),
+		BroadcastRPCURLs:          getEnv("BROADCAST_RPC_URLS", ""),
+		Signer:                    getEnv("SIGNER", "auto"),
+		PrivateKey:                getEnv("PRIVATE_KEY", ""),
+		Value:                     getEnv("VALUE", "1"),
+		GasLimit:                  getEnvUint64("GAS_LIMIT", 210000),
+		TransactionData:           getEnv("TX_DATA", "lets bomb the network with transactions! AMF to the moon : ) 🚀"),
+		TxDataTemplate:            getEnv("TX_DATA_TEMPLATE", ""),
+		TxMemo:                    getEnvBool("TX_MEMO", false),
+		RunID:                     getEnv("RUN_ID", ""),
+		RunsDir:                   getEnv("RUNS_DIR", "runs"),
+		TransferGasLimit:          getEnvUint64("TRANSFER_GAS_LIMIT", 0),
+		DeployGasLimit:            getEnvUint64("DEPLOY_GAS_LIMIT", 0),
+		InteractGasLimit:          getEnvUint64("INTERACT_GAS_LIMIT", 0),
+		ParallelGasLimit:          getEnvUint64("PARALLEL_GAS_LIMIT", 0),
+		TransferValue:             getEnv("TRANSFER_VALUE", ""),
+		DeployValue:               getEnv("DEPLOY_VALUE", ""),
+		InteractValue:             getEnv("INTERACT_VALUE", ""),
+		ParallelValue:             getEnv("PARALLEL_VALUE", ""),
+		ParallelContracts:         getEnv("PARALLEL_CONTRACTS", ""),
+		ExpectedEvents:            getEnv("EXPECTED_EVENTS", ""),
+		GasOnly:                   getEnvBool("GAS_ONLY", false),
+		Audit:                     getEnvBool("AUDIT", false),
+		MaxTransactions:           getEnvInt("MAX_TRANSACTIONS", 10000),
+		DelaySeconds:              getEnvInt("DELAY_SECONDS", 1),
+		RetryDelay:                getEnvInt("RETRY_DELAY", 10),
+		Mode:                      getEnv("MODE", "all"),
+		MinBalance:                getEnv("MIN_BALANCE", "100000"),
+		WalletCount:               getEnvInt("WALLET_COUNT", 1000),
+		WalletsFile:               getEnv("WALLETS_FILE", ""),
+		FundingAmount:             getEnv("FUNDING_AMOUNT", "100"),
+		FundingSafetyPercent:      getEnvInt("FUNDING_SAFETY_PERCENT", 150),
+		FunderReserve:             getEnv("FUNDER_RESERVE", "0"),
+		ExternalFunding:           getEnvBool("EXTERNAL_FUNDING", false),
+		AddressExportFile:         getEnv("ADDRESS_EXPORT_FILE", ""),
+		AddressExportFormat:       getEnv("ADDRESS_EXPORT_FORMAT", "lines"),
+		WalletManifestFile:        getEnv("WALLET_MANIFEST_FILE", ""),
+		FundingPollInterval:       getEnvInt("FUNDING_POLL_INTERVAL", 15),
+		FaucetURL:                 getEnv("FAUCET_URL", ""),
+		FaucetType:                getEnv("FAUCET_TYPE", "http"),
+		FaucetBody:                getEnv("FAUCET_BODY", ""),
+		FaucetAPIKey:              getEnv("FAUCET_API_KEY", ""),
+		FaucetRequestsPerMinute:   getEnvInt("FAUCET_REQUESTS_PER_MINUTE", 10),
+		FaucetRetries:             getEnvInt("FAUCET_RETRIES", 3),
+		DevFunding:                getEnvBool("DEV_FUNDING", false),
+		DevSnapshot:               getEnvBool("DEV_SNAPSHOT", false),
+		DevMining:                 getEnv("DEV_MINING", ""),
+		DevBlockTime:              getEnvInt("DEV_BLOCK_TIME", 2),
+		MaxConcurrentRequests:     getEnvInt("MAX_CONCURRENT_REQUESTS", 2000),
+		BalanceCheckInterval:      getEnvInt("BALANCE_CHECK_INTERVAL", 100),
+		FundingConcurrency:        getEnvInt("FUNDING_CONCURRENCY", 50),
+		VerifySampleRate:          getEnvInt("VERIFY_SAMPLE_RATE", 0),
+		MaxInFlight:               getEnvInt("MAX_IN_FLIGHT", 0),
+		MaxInFlightPerWallet:      getEnvInt("MAX_IN_FLIGHT_PER_WALLET", 0),
+		TargetTPS:                 getEnvInt("TARGET_TPS", 0),
+		RateLimit:                 getEnvInt("RATE_LIMIT", 0),
+		MaxGasPrice:               getEnv("MAX_GAS_PRICE", ""),
+		ThinkTime:                 getEnv("THINK_TIME", ""),
+		PersonaFile:               getEnv("PERSONA_FILE", ""),
+		TargetDistribution:        getEnv("TARGET_DISTRIBUTION", "uniform"),
+		TargetZipfExponent:        getEnv("TARGET_ZIPF_EXPONENT", "1.1"),
+		SlowStartSeconds:          getEnvInt("SLOW_START_SECONDS", 0),
+		SlowStartWallets:          getEnvInt("SLOW_START_WALLETS", 10),
+		InclusionPositions:        getEnvBool("INCLUSION_POSITIONS", false),
+		DropTimeoutSeconds:        getEnvInt("DROP_TIMEOUT_SECONDS", 0),
+		ContractStats:             getEnvBool("CONTRACT_STATS", false),
+		Create2Deploy:             getEnvBool("CREATE2_DEPLOY", false),
+		Create2Namespace:          getEnv("CREATE2_NAMESPACE", "ethereum-transaction-simulator"),
+		WarmupSeconds:             getEnvInt("WARMUP_SECONDS", 10),
+		BundleRelayURL:            getEnv("BUNDLE_RELAY_URL", "https://relay.flashbots.net"),
+		BundleSize:                getEnvInt("BUNDLE_SIZE", 5),
+		BundleAuthKey:             getEnv("BUNDLE_AUTH_KEY", ""),
+		ProbeObserveSeconds:       getEnvInt("PROBE_OBSERVE_SECONDS", 120),
+		FeeProbeMinPercent:        getEnvInt("FEE_PROBE_MIN_PERCENT", 10),
+		FeeProbeMaxPercent:        getEnvInt("FEE_PROBE_MAX_PERCENT", 150),
+		FeeProbeSteps:             getEnvInt("FEE_PROBE_STEPS", 15),
+		CanaryIntervalSeconds:     getEnvInt("CANARY_INTERVAL_SECONDS", 30),
+		CanaryMaxLatency:          getEnvInt("CANARY_MAX_LATENCY_SECONDS", 60),
+		CanaryWebhookURL:          getEnv("CANARY_WEBHOOK_URL", ""),
+		CanaryExitOnAlert:         getEnvBool("CANARY_EXIT_ON_ALERT", false),
+		DebugAddr:                 getEnv("DEBUG_ADDR", ""),
+		DistributedRole:           getEnv("DISTRIBUTED_ROLE", ""),
+		CoordinatorAddr:           getEnv("COORDINATOR_ADDR", "127.0.0.1:7070"),
+		CoordinatorSecret:         getEnv("COORDINATOR_SECRET", ""),
+		AgentCount:                getEnvInt("AGENT_COUNT", 1),
+		AgentStartDelay:           getEnvInt("AGENT_START_DELAY", 10),
+		NTPServer:                 getEnv("NTP_SERVER", "pool.ntp.org"),
+		HealthAddr:                getEnv("HEALTH_ADDR", ""),
+		Schedule:                  getEnv("SCHEDULE", ""),
+		ScheduleDuration:          getEnvInt("SCHEDULE_DURATION_MINUTES", 60),
+		ReportDir:                 getEnv("REPORT_DIR", ""),
+		SoakTPS:                   getEnvInt("SOAK_TPS", 50),
+		SoakDuration:              getEnvInt("SOAK_DURATION_MINUTES", 720),
+		SoakWindow:                getEnvInt("SOAK_WINDOW_MINUTES", 10),
+		SoakDegradationPercent:    getEnvInt("SOAK_DEGRADATION_PERCENT", 25),
+		AdaptiveStartTPS:          getEnvInt("ADAPTIVE_START_TPS", 10),
+		AdaptiveWindow:            getEnvInt("ADAPTIVE_WINDOW_SECONDS", 30),
+		AdaptiveMaxLatency:        getEnvInt("ADAPTIVE_MAX_LATENCY_SECONDS", 15),
+		AdaptiveMaxFailurePercent: getEnvInt("ADAPTIVE_MAX_FAILURE_PERCENT", 5),
+		AdaptiveStepPercent:       getEnvInt("ADAPTIVE_STEP_PERCENT", 50),
+		ReadRPS:                   getEnvInt("READ_RPS", 100),
+		ReadDuration:              getEnvInt("READ_DURATION_SECONDS", 60),
+		ReadConcurrency:           getEnvInt("READ_CONCURRENCY", 100),
+		ReadMix:                   getEnv("READ_MIX", "call=40,balance=30,logs=10,storage=20"),
+		ReadLogBlockRange:         getEnvUint64("READ_LOG_BLOCK_RANGE", 100),
+		LogsRPS:                   getEnvInt("LOGS_RPS", 20),
+		LogsDuration:              getEnvInt("LOGS_DURATION_SECONDS", 60),
+		LogsConcurrency:           getEnvInt("LOGS_CONCURRENCY", 20),
+		LogsBlockRanges:           getEnv("LOGS_BLOCK_RANGES", "100,1000,10000"),
+		LogsTopics:                getEnv("LOGS_TOPICS", ""),
+		ArchiveRPS:                getEnvInt("ARCHIVE_RPS", 50),
+		ArchiveDuration:           getEnvInt("ARCHIVE_DURATION_SECONDS", 60),
+		ArchiveConcurrency:        getEnvInt("ARCHIVE_CONCURRENCY", 50),
+		ArchiveMaxDepth:           getEnvUint64("ARCHIVE_MAX_DEPTH", 100000),
+		ArchiveDepthDistribution:  getEnv("ARCHIVE_DEPTH_DISTRIBUTION", "uniform"),
+		WSURL:                     getEnv("WS_URL", ""),
+		WSConnections:             getEnvInt("WS_CONNECTIONS", 100),
+		WSSubscriptions:           getEnv("WS_SUBSCRIPTIONS", "newHeads,logs,newPendingTransactions"),
+		WSDuration:                getEnvInt("WS_DURATION_SECONDS", 60),
+		TraceRPS:                  getEnvInt("TRACE_RPS", 5),
+		TraceDuration:             getEnvInt("TRACE_DURATION_SECONDS", 60),
+		TraceConcurrency:          getEnvInt("TRACE_CONCURRENCY", 4),
+		TraceTracer:               getEnv("TRACE_TRACER", "callTracer"),
+		TraceTimeout:              getEnvInt("TRACE_TIMEOUT_SECONDS", 10),
+		TraceBlockPercent:         getEnvInt("TRACE_BLOCK_PERCENT", 20),
+		TraceRecentBlocks:         getEnvInt("TRACE_RECENT_BLOCKS", 20),
+		TrafficShape:              getEnv("TRAFFIC_SHAPE", "sine"),
+		ShapeBaseTPS:              getEnvInt("SHAPE_BASE_TPS", 50),
+		ShapeAmplitudeTPS:         getEnvInt("SHAPE_AMPLITUDE_TPS", 40),
+		ShapePeriod:               getEnvInt("SHAPE_PERIOD_MINUTES", 60),
+		ShapeSteps:                getEnv("SHAPE_STEPS", "20,50,100"),
+		ShapeStepSeconds:          getEnvInt("SHAPE_STEP_SECONDS", 300),
+		ShapeSpikeTPS:             getEnvInt("SHAPE_SPIKE_TPS", 500),
+		ShapeSpikeAt:              getEnvInt("SHAPE_SPIKE_AT_SECONDS", 600),
+		ShapeSpikeSeconds:         getEnvInt("SHAPE_SPIKE_SECONDS", 60),
+		ShapeTimeWarp:             getEnvInt("SHAPE_TIME_WARP_SECONDS", 0),
+		ShapeDuration:             getEnvInt("SHAPE_DURATION_MINUTES", 60),
+		AssertMinTPS:              getEnv("ASSERT_MIN_TPS", ""),
+		AssertMaxFailurePercent:   getEnv("ASSERT_MAX_FAILURE_PERCENT", ""),
+		AssertMaxP95Seconds:       getEnv("ASSERT_MAX_P95_SECONDS", ""),
+		BaselineFile:              getEnv("BASELINE_FILE", ""),
+		BaselineSaveFile:          getEnv("BASELINE_SAVE_FILE", ""),
+		BaselineWindow:            getEnvInt("BASELINE_WINDOW_SECONDS", 60),
+		BaselineRegressionPercent: getEnvInt("BASELINE_REGRESSION_PERCENT", 25),
+		BaselineAbortWindows:      getEnvInt("BASELINE_ABORT_WINDOWS", 0),
+		GriefReserveGas:           getEnvUint64("GRIEF_RESERVE_GAS", 1000),
+		GriefRevertPercent:        getEnvInt("GRIEF_REVERT_PERCENT", 0),
+		LargeCodeSize:             getEnvInt("LARGE_CODE_SIZE", 24000),
+		CallDepths:                getEnv("CALL_DEPTHS", "8,64,256"),
+		CancunIterations:          getEnvInt("CANCUN_ITERATIONS", 100),
+		SelfdestructRounds:        getEnvInt("SELFDESTRUCT_ROUNDS", 5),
+		DiffRPCURL:                getEnv("DIFF_RPC_URL", ""),
+		DiffTransactions:          getEnvInt("DIFF_TRANSACTIONS", 20),
+		PropagationRPCURL:         getEnv("PROPAGATION_RPC_URL", ""),
+		PropagationSamples:        getEnvInt("PROPAGATION_SAMPLES", 100),
+		PropagationSendMs:         getEnvInt("PROPAGATION_SEND_INTERVAL_MS", 500),
+		PropagationPollMs:         getEnvInt("PROPAGATION_POLL_MS", 50),
+		ReplayFile:                getEnv("REPLAY_FILE", ""),
+		ReplayTPS:                 getEnvInt("REPLAY_TPS", 0),
+		NonceGapOffsets:           getEnv("NONCE_GAP_OFFSETS", "1-5,10,20,64,100"),
+		NonceGapFill:              getEnvBool("NONCE_GAP_FILL", true),
+		PoolPressureAccounts:      getEnvInt("POOL_PRESSURE_ACCOUNTS", 50),
+		PoolPressureTxs:           getEnvInt("POOL_PRESSURE_TXS_PER_ACCOUNT", 100),
+		RecipientAllowlist:        getEnv("RECIPIENT_ALLOWLIST", ""),
+		RecipientDenylist:         getEnv("RECIPIENT_DENYLIST", ""),
+		TestnetSpendLimit:         getEnv("TESTNET_SPEND_LIMIT", "100000000000000000"),
+		ExportFile:                getEnv("EXPORT_FILE", ""),
+		EventStream:               getEnv("EVENT_STREAM", ""),
+		MaxFailureRate:            getEnv("MAX_FAILURE_RATE", ""),
+		FailureRateWindow:         getEnvInt("FAILURE_RATE_WINDOW", 100),
+		AbortSweepTo:              getEnv("ABORT_SWEEP_TO", ""),
+		ChaosDropPercent:          getEnvInt("CHAOS_DROP_PERCENT", 0),
+		ChaosCorruptPercent:       getEnvInt("CHAOS_CORRUPT_PERCENT", 0),
+		ChaosSignDelayMs:          getEnvInt("CHAOS_SIGN_DELAY_MS", 0),
+		TxTemplateFile:            getEnv("TX_TEMPLATE_FILE", ""),
+		ScriptFile:                getEnv("SCRIPT_FILE", ""),
+		PluginDir:                 getEnv("PLUGIN_DIR", ""),
+		PluginWorkload:            getEnv("PLUGIN_WORKLOAD", ""),
+		PluginFeeStrategy:         getEnv("PLUGIN_FEE_STRATEGY", ""),
+		ProbeCapabilities:         getEnvBool("PROBE_CAPABILITIES", true),
+		ChainInfoSeconds:          getEnvInt("CHAIN_INFO_SECONDS", 12),
+		NodeStatsSeconds:          getEnvInt("NODE_STATS_SECONDS", 0),
+		LagReportSeconds:          getEnvInt("LAG_REPORT_SECONDS", 0),
+		ShedThresholdPercent:      getEnvInt("SHED_THRESHOLD_PERCENT", 5),
+		HostPrometheusURL:         getEnv("HOST_PROMETHEUS_URL", ""),
+		HostSelector:              getEnv("HOST_SELECTOR", ""),
+		HostExporterURL:           getEnv("HOST_EXPORTER_URL", ""),
+		HostMetricsSeconds:        getEnvInt("HOST_METRICS_SECONDS", 15),
+		Confirmation:              getEnv("CONFIRMATION", ""),
+		ConfirmationBlocks:        getEnvUint64("CONFIRMATION_BLOCKS", 6),
+		ConfirmationTimeout:       getEnvInt("CONFIRMATION_TIMEOUT_SECONDS", 60),
+		FinalityTracking:          getEnvBool("FINALITY_TRACKING", false),
+		FinalityWaitSeconds:       getEnvInt("FINALITY_WAIT_SECONDS", 960),
+		VaultAddr:                 getEnv("VAULT_ADDR", ""),
+		VaultPath:                 getEnv("VAULT_PATH", ""),
+		VaultField:                getEnv("VAULT_FIELD", "private_key"),
+		VaultNamespace:            getEnv("VAULT_NAMESPACE", ""),
+		VaultToken:                getEnv("VAULT_TOKEN", ""),
+		VaultRoleID:               getEnv("VAULT_ROLE_ID", ""),
+		VaultSecretID:             getEnv("VAULT_SECRET_ID", ""),
+		SplitRole:                 getEnv("SPLIT_ROLE", ""),
+		SignerSocket:              getEnv("SIGNER_SOCKET", "/tmp/simulator-signer/signer.sock"),
+		SignerWallets:             getEnv("SIGNER_WALLETS", ""),
+		SignerMaxValue:            getEnv("SIGNER_MAX_VALUE", ""),
+		SignerMaxGasPrice:         getEnv("SIGNER_MAX_GAS_PRICE", ""),
+		Profile:                   getEnv("PROFILE", ""),
+		WithWrites:                getEnvBool("WITH_WRITES", false),
+	}
+	if envErr != nil {
+		cfg.loadErr = envErr
+		return cfg
+	}
+	if cfg.PrivateKey == "" && cfg.VaultAddr != "" {
+		cfg.PrivateKey, cfg.loadErr = fetchPrivateKey(cfg)
+	}
+	return cfg
+}
+
+// lookupEnv returns the value of key, or the contents of the file named by key_FILE
+// so secrets can be mounted as files (e.g. PRIVATE_KEY_FILE=/var/run/secrets/key).
+// ${VAR} references and @file: values are resolved, see resolveValue.
+func lookupEnv(key string) string {
 	if value := os.Getenv(key); value != "" {
+		resolved, err := resolveValue(value)
+		if err != nil {
+			recordEnvErr(fmt.Errorf("failed to resolve %s: %w", key, err))
+			return ""
+		}
+		return resolved
+	}
+	if path := os.Getenv(key + "_FILE"); path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			recordEnvErr(fmt.Errorf("failed to read %s_FILE: %w", key, err))
+			return ""
+		}
+		return strings.TrimSpace(string(data))
+	}
+	return ""
+}
+
+// recordEnvErr keeps the first failure to read a value for fromEnv to report
+func recordEnvErr(err error) {
+	if envErr == nil {
+		envErr = err
+	}
+}
+
+func getEnv(key, defaultValue string) string {
+	if value := lookupEnv(key); value != "" {
 		return value
 	}
 	return defaultValue
 }
 
 func getEnvInt(key string, defaultValue int) int {
-	if value := os.Getenv(key); value != "" {
+	if value := lookupEnv(key); value != "" {
 		if intValue, err := strconv.Atoi(value); err == nil {
 			return intValue
 		}
@@ -75,7 +540,7 @@ func getEnvInt(key string, defaultValue int) int {
 }
 
 func getEnvUint64(key string, defaultValue uint64) uint64 {
-	if value := os.Getenv(key); value != "" {
+	if value := lookupEnv(key); value != "" {
 		if uintValue, err := strconv.ParseUint(value, 10, 64); err == nil {
 			return uintValue
 		}
@@ -83,47 +548,146 @@ func getEnvUint64(key string, defaultValue uint64) uint64 {
 	return defaultValue
 }
 
+func getEnvBool(key string, defaultValue bool) bool {
+	if value := lookupEnv(key); value != "" {
+		if boolValue, err := strconv.ParseBool(value); err == nil {
+			return boolValue
+		}
+	}
+	return defaultValue
+}
+
+// needsKey reports whether the run signs anything itself. The sender role has the
+// signer process sign, and replay mode submits transactions signed elsewhere.
+func (c *Config) needsKey() bool {
+	return c.SplitRole != "sender" && strings.ToLower(c.Mode) != "replay"
+}
+
 // Validate validates the configuration and returns an error if invalid
 func (c *Config) Validate() error {
+	if c.loadErr != nil {
+		return c.loadErr
+	}
+
+	// Validate split roles; the sender role never holds a key
+	switch c.SplitRole {
+	case "":
+	case "signer":
+		if c.SignerWallets == "" {
+			return errors.New("SIGNER_WALLETS is required when SPLIT_ROLE=signer")
+		}
+	case "sender":
+		if c.PrivateKey != "" {
+			return errors.New("PRIVATE_KEY must not be set when SPLIT_ROLE=sender, the signer holds the keys")
+		}
+		if strings.ToLower(c.Mode) != "parallel" {
+			return fmt.Errorf("SPLIT_ROLE=sender only supports parallel mode (got: %s)", c.Mode)
+		}
+	default:
+		return fmt.Errorf("SPLIT_ROLE must be signer, sender, or empty (got: %s)", c.SplitRole)
+	}
+	if (c.SplitRole == "signer" || c.SplitRole == "sender") && c.SignerSocket == "" {
+		return errors.New("SIGNER_SOCKET is required when SPLIT_ROLE is set")
+	}
+	if c.SignerMaxValue != "" {
+		if value, ok := new(big.Int).SetString(c.SignerMaxValue, 10); !ok || value.Sign() < 0 {
+			return fmt.Errorf("SIGNER_MAX_VALUE must be a non-negative number of wei (got: %s)", c.SignerMaxValue)
+		}
+	}
+	if c.SignerMaxGasPrice != "" {
+		if price, ok := new(big.Int).SetString(c.SignerMaxGasPrice, 10); !ok || price.Sign() <= 0 {
+			return fmt.Errorf("SIGNER_MAX_GAS_PRICE must be a positive number of wei (got: %s)", c.SignerMaxGasPrice)
+		}
+	}
+
 	// Validate private key
-	if c.PrivateKey == "" {
+	if c.PrivateKey == "" && c.needsKey() {
 		return errors.New("PRIVATE_KEY is required")
 	}
-	
-	// Remove 0x prefix if present
-	privateKeyHex := strings.TrimPrefix(c.PrivateKey, "0x")
-	
-	// Validate private key format (should be 64 hex characters)
-	if len(privateKeyHex) != 64 {
-		return fmt.Errorf("PRIVATE_KEY must be 64 hex characters (got %d)", len(privateKeyHex))
-	}
-	
-	// Try to parse private key to ensure it's valid
-	_, err := crypto.HexToECDSA(privateKeyHex)
-	if err != nil {
-		return fmt.Errorf("PRIVATE_KEY is invalid: %w", err)
-	}
-	
+
+	if c.PrivateKey != "" || c.needsKey() {
+		// Remove 0x prefix if present
+		privateKeyHex := strings.TrimPrefix(c.PrivateKey, "0x")
+
+		// Validate private key format (should be 64 hex characters)
+		if len(privateKeyHex) != 64 {
+			return fmt.Errorf("PRIVATE_KEY must be 64 hex characters (got %d)", len(privateKeyHex))
+		}
+
+		// Try to parse private key to ensure it's valid
+		if _, err := crypto.HexToECDSA(privateKeyHex); err != nil {
+			return fmt.Errorf("PRIVATE_KEY is invalid: %w", err)
+		}
+	}
+
 	// Validate RPC URL
 	if c.RPCURL == "" {
 		return errors.New("RPC_URL is required")
 	}
-	if !strings.HasPrefix(c.RPCURL, "http://") && !strings.HasPrefix(c.RPCURL, "https://") && !strings.HasPrefix(c.RPCURL, "ws://") && !strings.HasPrefix(c.RPCURL, "wss://") {
+	if !hasRPCScheme(c.RPCURL) {
 		return fmt.Errorf("RPC_URL must start with http://, https://, ws://, or wss://")
 	}
-	
+	if c.ReadRPCURL != "" && !hasRPCScheme(c.ReadRPCURL) {
+		return fmt.Errorf("READ_RPC_URL must start with http://, https://, ws://, or wss://")
+	}
+	if c.WriteRPCURL != "" && !hasRPCScheme(c.WriteRPCURL) {
+		return fmt.Errorf("WRITE_RPC_URL must start with http://, https://, ws://, or wss://")
+	}
+	if c.SendMethod != "eth_sendRawTransaction" && c.SendMethod != "eth_sendPrivateTransaction" {
+		return fmt.Errorf("SEND_METHOD must be one of: eth_sendRawTransaction, eth_sendPrivateTransaction (got: %s)", c.SendMethod)
+	}
+	for _, url := range splitList(c.BroadcastRPCURLs) {
+		if !hasRPCScheme(url) {
+			return fmt.Errorf("BROADCAST_RPC_URLS entries must start with http://, https://, ws://, or wss:// (got: %s)", url)
+		}
+	}
+
+	// Validate signer
+	validSigners := map[string]bool{
+		"auto":      true,
+		"eip155":    true,
+		"homestead": true,
+	}
+	if !validSigners[c.Signer] {
+		return fmt.Errorf("SIGNER must be one of: auto, eip155, homestead (got: %s)", c.Signer)
+	}
+
 	// Validate mode
 	validModes := map[string]bool{
-		"parallel": true,
-		"transfer": true,
-		"deploy":   true,
-		"interact": true,
-		"all":      true,
+		"parallel":      true,
+		"transfer":      true,
+		"deploy":        true,
+		"interact":      true,
+		"all":           true,
+		"bundles":       true,
+		"spam-probe":    true,
+		"fee-probe":     true,
+		"edge":          true,
+		"canary":        true,
+		"soak":          true,
+		"adaptive":      true,
+		"reads":         true,
+		"logs":          true,
+		"archive":       true,
+		"ws-fanout":     true,
+		"trace":         true,
+		"shaped":        true,
+		"gas-grief":     true,
+		"large-deploy":  true,
+		"call-depth":    true,
+		"cancun":        true,
+		"selfdestruct":  true,
+		"diff":          true,
+		"propagation":   true,
+		"replay":        true,
+		"nonce-gap":     true,
+		"pool-pressure": true,
+		"invalid-tx":    true,
 	}
 	if !validModes[strings.ToLower(c.Mode)] {
-		return fmt.Errorf("MODE must be one of: parallel, transfer, deploy, interact, all (got: %s)", c.Mode)
+		return fmt.Errorf("MODE must be one of: parallel, transfer, deploy, interact, all, bundles, spam-probe, fee-probe, edge, canary, soak, adaptive, reads, logs, archive, ws-fanout, trace, shaped, gas-grief, large-deploy, call-depth, cancun, selfdestruct, diff, propagation, replay, nonce-gap, pool-pressure, invalid-tx (got: %s)", c.Mode)
 	}
-	
+
 	// Validate value (must be a valid number)
 	value, ok := new(big.Int).SetString(c.Value, 10)
 	if !ok {
@@ -132,7 +696,7 @@ func (c *Config) Validate() error {
 	if value.Sign() < 0 {
 		return errors.New("VALUE cannot be negative")
 	}
-	
+
 	// Validate gas limit
 	if c.GasLimit == 0 {
 		return errors.New("GAS_LIMIT must be greater than 0")
@@ -140,17 +704,48 @@ func (c *Config) Validate() error {
 	if c.GasLimit > 30000000 { // Ethereum block gas limit is around 30M
 		return fmt.Errorf("GAS_LIMIT is too high (max: 30000000, got: %d)", c.GasLimit)
 	}
-	
+
+	// Validate per-workload overrides
+	gasOverrides := map[string]uint64{
+		"TRANSFER_GAS_LIMIT": c.TransferGasLimit,
+		"DEPLOY_GAS_LIMIT":   c.DeployGasLimit,
+		"INTERACT_GAS_LIMIT": c.InteractGasLimit,
+		"PARALLEL_GAS_LIMIT": c.ParallelGasLimit,
+	}
+	for name, gasLimit := range gasOverrides {
+		if gasLimit > 30000000 {
+			return fmt.Errorf("%s is too high (max: 30000000, got: %d)", name, gasLimit)
+		}
+	}
+	valueOverrides := map[string]string{
+		"TRANSFER_VALUE": c.TransferValue,
+		"DEPLOY_VALUE":   c.DeployValue,
+		"INTERACT_VALUE": c.InteractValue,
+		"PARALLEL_VALUE": c.ParallelValue,
+	}
+	for name, override := range valueOverrides {
+		if override == "" {
+			continue
+		}
+		value, ok := new(big.Int).SetString(override, 10)
+		if !ok {
+			return fmt.Errorf("%s must be a valid number (got: %s)", name, override)
+		}
+		if value.Sign() < 0 {
+			return fmt.Errorf("%s cannot be negative", name)
+		}
+	}
+
 	// Validate max transactions
 	if c.MaxTransactions < 0 {
 		return errors.New("MAX_TRANSACTIONS cannot be negative")
 	}
-	
+
 	// Validate delay seconds
 	if c.DelaySeconds < 0 {
 		return errors.New("DELAY_SECONDS cannot be negative")
 	}
-	
+
 	// Validate min balance
 	minBalance, ok := new(big.Int).SetString(c.MinBalance, 10)
 	if !ok {
@@ -159,7 +754,7 @@ func (c *Config) Validate() error {
 	if minBalance.Sign() < 0 {
 		return errors.New("MIN_BALANCE cannot be negative")
 	}
-	
+
 	// Validate wallet count
 	if c.WalletCount < 0 {
 		return errors.New("WALLET_COUNT cannot be negative")
@@ -167,16 +762,79 @@ func (c *Config) Validate() error {
 	if c.WalletCount > 10000 {
 		return fmt.Errorf("WALLET_COUNT is too high (max: 10000, got: %d)", c.WalletCount)
 	}
-	
+
 	// Validate funding amount
-	fundingAmount, ok := new(big.Int).SetString(c.FundingAmount, 10)
+	if c.AutoFunding() {
+		if c.FundingSafetyPercent < 100 {
+			return fmt.Errorf("FUNDING_SAFETY_PERCENT must be at least 100 (got: %d)", c.FundingSafetyPercent)
+		}
+	} else {
+		fundingAmount, ok := new(big.Int).SetString(c.FundingAmount, 10)
+		if !ok {
+			return fmt.Errorf("FUNDING_AMOUNT must be a valid number or \"auto\" (got: %s)", c.FundingAmount)
+		}
+		if fundingAmount.Sign() < 0 {
+			return errors.New("FUNDING_AMOUNT cannot be negative")
+		}
+	}
+
+	// Validate run ID
+	if c.RunID != "" {
+		if _, err := strconv.ParseUint(strings.TrimPrefix(c.RunID, "0x"), 16, 64); err != nil {
+			return fmt.Errorf("RUN_ID must be up to 16 hex digits (got: %s)", c.RunID)
+		}
+	}
+
+	// Validate funder reserve
+	funderReserve, ok := new(big.Int).SetString(c.FunderReserve, 10)
 	if !ok {
-		return fmt.Errorf("FUNDING_AMOUNT must be a valid number (got: %s)", c.FundingAmount)
+		return fmt.Errorf("FUNDER_RESERVE must be a valid number (got: %s)", c.FunderReserve)
+	}
+	if funderReserve.Sign() < 0 {
+		return errors.New("FUNDER_RESERVE cannot be negative")
+	}
+
+	// Validate external funding
+	if c.ExternalFunding {
+		format := strings.ToLower(c.AddressExportFormat)
+		if format != "lines" && format != "json" {
+			return fmt.Errorf("ADDRESS_EXPORT_FORMAT must be lines or json (got: %s)", c.AddressExportFormat)
+		}
+		if c.FundingPollInterval <= 0 {
+			return errors.New("FUNDING_POLL_INTERVAL must be greater than 0")
+		}
 	}
-	if fundingAmount.Sign() < 0 {
-		return errors.New("FUNDING_AMOUNT cannot be negative")
+
+	// Validate faucet settings
+	if c.FaucetURL != "" {
+		if !strings.HasPrefix(c.FaucetURL, "http://") && !strings.HasPrefix(c.FaucetURL, "https://") {
+			return fmt.Errorf("FAUCET_URL must start with http:// or https:// (got: %s)", c.FaucetURL)
+		}
+		if c.FaucetType != "http" && c.FaucetType != "eth-faucet" {
+			return fmt.Errorf("FAUCET_TYPE must be http or eth-faucet (got: %s)", c.FaucetType)
+		}
+		if c.FaucetRequestsPerMinute < 0 {
+			return errors.New("FAUCET_REQUESTS_PER_MINUTE cannot be negative")
+		}
+		if c.FaucetRetries < 0 {
+			return errors.New("FAUCET_RETRIES cannot be negative")
+		}
+	}
+
+	// Validate development node funding
+	if c.DevFunding && c.ExternalFunding {
+		return errors.New("DEV_FUNDING cannot be combined with EXTERNAL_FUNDING")
+	}
+	switch c.DevMining {
+	case "", "auto":
+	case "interval", "both":
+		if c.DevBlockTime <= 0 {
+			return errors.New("DEV_BLOCK_TIME must be greater than 0")
+		}
+	default:
+		return fmt.Errorf("DEV_MINING must be auto, interval or both (got: %s)", c.DevMining)
 	}
-	
+
 	// Validate max concurrent requests
 	if c.MaxConcurrentRequests <= 0 {
 		return errors.New("MAX_CONCURRENT_REQUESTS must be greater than 0")
@@ -184,12 +842,12 @@ func (c *Config) Validate() error {
 	if c.MaxConcurrentRequests > 10000 {
 		return fmt.Errorf("MAX_CONCURRENT_REQUESTS is too high (max: 10000, got: %d)", c.MaxConcurrentRequests)
 	}
-	
+
 	// Validate balance check interval
 	if c.BalanceCheckInterval <= 0 {
 		return errors.New("BALANCE_CHECK_INTERVAL must be greater than 0")
 	}
-	
+
 	// Validate funding concurrency
 	if c.FundingConcurrency <= 0 {
 		return errors.New("FUNDING_CONCURRENCY must be greater than 0")
@@ -197,7 +855,706 @@ func (c *Config) Validate() error {
 	if c.FundingConcurrency > 1000 {
 		return fmt.Errorf("FUNDING_CONCURRENCY is too high (max: 1000, got: %d)", c.FundingConcurrency)
 	}
-	
+
+	// Validate verification sample rate
+	if c.VerifySampleRate < 0 {
+		return errors.New("VERIFY_SAMPLE_RATE cannot be negative")
+	}
+
+	// Validate in-flight caps
+	if c.MaxInFlight < 0 {
+		return errors.New("MAX_IN_FLIGHT cannot be negative")
+	}
+	if c.MaxInFlightPerWallet < 0 {
+		return errors.New("MAX_IN_FLIGHT_PER_WALLET cannot be negative")
+	}
+
+	// Validate parallel contract targets
+	for _, entry := range splitList(c.ParallelContracts) {
+		if !common.IsHexAddress(entry) {
+			return fmt.Errorf("PARALLEL_CONTRACTS contains an invalid address: %s", entry)
+		}
+	}
+
+	// Validate recipient policy
+	for _, entry := range splitList(c.RecipientAllowlist) {
+		if !common.IsHexAddress(entry) {
+			return fmt.Errorf("RECIPIENT_ALLOWLIST contains an invalid address: %s", entry)
+		}
+	}
+	for _, entry := range splitList(c.RecipientDenylist) {
+		if !common.IsHexAddress(entry) {
+			return fmt.Errorf("RECIPIENT_DENYLIST contains an invalid address: %s", entry)
+		}
+	}
+
+	// Validate wallet auto-sizing
+	if c.TargetTPS < 0 {
+		return errors.New("TARGET_TPS cannot be negative")
+	}
+	if c.TargetTPS > 0 && c.WarmupSeconds <= 0 {
+		return errors.New("WARMUP_SECONDS must be greater than 0 when TARGET_TPS is set")
+	}
+
+	// Validate bundle settings
+	if strings.ToLower(c.Mode) == "bundles" {
+		if !strings.HasPrefix(c.BundleRelayURL, "http://") && !strings.HasPrefix(c.BundleRelayURL, "https://") {
+			return fmt.Errorf("BUNDLE_RELAY_URL must start with http:// or https://")
+		}
+		if c.BundleSize <= 0 {
+			return errors.New("BUNDLE_SIZE must be greater than 0")
+		}
+		if c.BundleAuthKey != "" {
+			if _, err := crypto.HexToECDSA(strings.TrimPrefix(c.BundleAuthKey, "0x")); err != nil {
+				return fmt.Errorf("BUNDLE_AUTH_KEY is invalid: %w", err)
+			}
+		}
+	}
+
+	// Validate probe settings
+	if c.ProbeObserveSeconds < 0 {
+		return errors.New("PROBE_OBSERVE_SECONDS cannot be negative")
+	}
+	if strings.ToLower(c.Mode) == "fee-probe" {
+		if c.FeeProbeMinPercent <= 0 || c.FeeProbeMinPercent >= c.FeeProbeMaxPercent {
+			return fmt.Errorf("FEE_PROBE_MIN_PERCENT must be greater than 0 and below FEE_PROBE_MAX_PERCENT (got: %d, %d)", c.FeeProbeMinPercent, c.FeeProbeMaxPercent)
+		}
+		if c.FeeProbeSteps < 2 {
+			return errors.New("FEE_PROBE_STEPS must be at least 2")
+		}
+	}
+
+	// Validate canary settings
+	if strings.ToLower(c.Mode) == "canary" {
+		if c.CanaryIntervalSeconds <= 0 {
+			return errors.New("CANARY_INTERVAL_SECONDS must be greater than 0")
+		}
+		if c.CanaryMaxLatency <= 0 {
+			return errors.New("CANARY_MAX_LATENCY_SECONDS must be greater than 0")
+		}
+		if c.CanaryWebhookURL != "" && !strings.HasPrefix(c.CanaryWebhookURL, "http://") && !strings.HasPrefix(c.CanaryWebhookURL, "https://") {
+			return fmt.Errorf("CANARY_WEBHOOK_URL must start with http:// or https://")
+		}
+	}
+
+	// Validate distributed settings
+	switch strings.ToLower(c.DistributedRole) {
+	case "":
+	case "coordinator", "agent":
+		if c.CoordinatorAddr == "" {
+			return errors.New("COORDINATOR_ADDR is required when DISTRIBUTED_ROLE is set")
+		}
+		if c.CoordinatorSecret == "" {
+			return errors.New("COORDINATOR_SECRET is required when DISTRIBUTED_ROLE is set")
+		}
+		if c.AgentCount <= 0 {
+			return errors.New("AGENT_COUNT must be greater than 0")
+		}
+		if c.AgentStartDelay < 0 {
+			return errors.New("AGENT_START_DELAY cannot be negative")
+		}
+		if c.WalletsFile == "" {
+			return errors.New("WALLETS_FILE is required when DISTRIBUTED_ROLE is set, agents share a wallet file from `simulator fund`")
+		}
+	default:
+		return fmt.Errorf("DISTRIBUTED_ROLE must be coordinator, agent, or empty (got: %s)", c.DistributedRole)
+	}
+
+	// Validate schedule
+	if c.ScheduleDuration < 0 {
+		return errors.New("SCHEDULE_DURATION_MINUTES cannot be negative")
+	}
+
+	// Validate soak settings
+	if strings.ToLower(c.Mode) == "soak" {
+		if c.SoakTPS <= 0 {
+			return errors.New("SOAK_TPS must be greater than 0")
+		}
+		if c.SoakWindow <= 0 || c.SoakDuration < 2*c.SoakWindow {
+			return fmt.Errorf("SOAK_DURATION_MINUTES must cover at least two SOAK_WINDOW_MINUTES windows (got: %d, %d)", c.SoakDuration, c.SoakWindow)
+		}
+		if c.SoakDegradationPercent <= 0 {
+			return errors.New("SOAK_DEGRADATION_PERCENT must be greater than 0")
+		}
+	}
+
+	// Validate adaptive search settings
+	if strings.ToLower(c.Mode) == "adaptive" {
+		if c.AdaptiveStartTPS <= 0 || c.AdaptiveWindow <= 0 || c.AdaptiveMaxLatency <= 0 {
+			return errors.New("ADAPTIVE_START_TPS, ADAPTIVE_WINDOW_SECONDS and ADAPTIVE_MAX_LATENCY_SECONDS must be greater than 0")
+		}
+		if c.AdaptiveMaxFailurePercent < 0 || c.AdaptiveMaxFailurePercent > 100 {
+			return fmt.Errorf("ADAPTIVE_MAX_FAILURE_PERCENT must be between 0 and 100 (got: %d)", c.AdaptiveMaxFailurePercent)
+		}
+		if c.AdaptiveStepPercent < 10 {
+			return fmt.Errorf("ADAPTIVE_STEP_PERCENT must be at least 10 (got: %d)", c.AdaptiveStepPercent)
+		}
+	}
+
+	// Validate read load settings
+	if strings.ToLower(c.Mode) == "reads" {
+		if c.ReadRPS < 0 {
+			return errors.New("READ_RPS cannot be negative")
+		}
+		if c.ReadDuration <= 0 || c.ReadConcurrency <= 0 {
+			return errors.New("READ_DURATION_SECONDS and READ_CONCURRENCY must be greater than 0")
+		}
+	}
+
+	// Validate eth_getLogs stress settings
+	if strings.ToLower(c.Mode) == "logs" {
+		if c.LogsRPS < 0 {
+			return errors.New("LOGS_RPS cannot be negative")
+		}
+		if c.LogsDuration <= 0 || c.LogsConcurrency <= 0 {
+			return errors.New("LOGS_DURATION_SECONDS and LOGS_CONCURRENCY must be greater than 0")
+		}
+	}
+
+	// Validate archive state settings
+	if strings.ToLower(c.Mode) == "archive" {
+		if c.ArchiveRPS < 0 {
+			return errors.New("ARCHIVE_RPS cannot be negative")
+		}
+		if c.ArchiveDuration <= 0 || c.ArchiveConcurrency <= 0 || c.ArchiveMaxDepth == 0 {
+			return errors.New("ARCHIVE_DURATION_SECONDS, ARCHIVE_CONCURRENCY and ARCHIVE_MAX_DEPTH must be greater than 0")
+		}
+	}
+
+	// Validate WebSocket fan-out settings
+	if strings.ToLower(c.Mode) == "ws-fanout" {
+		if !strings.HasPrefix(c.WSURL, "ws://") && !strings.HasPrefix(c.WSURL, "wss://") {
+			return fmt.Errorf("WS_URL must start with ws:// or wss:// (got: %s)", c.WSURL)
+		}
+		if c.WSConnections <= 0 || c.WSDuration <= 0 {
+			return errors.New("WS_CONNECTIONS and WS_DURATION_SECONDS must be greater than 0")
+		}
+	}
+
+	// Validate trace settings
+	if strings.ToLower(c.Mode) == "trace" {
+		if c.TraceRPS < 0 || c.TraceTimeout < 0 {
+			return errors.New("TRACE_RPS and TRACE_TIMEOUT_SECONDS cannot be negative")
+		}
+		if c.TraceDuration <= 0 || c.TraceConcurrency <= 0 || c.TraceRecentBlocks <= 0 {
+			return errors.New("TRACE_DURATION_SECONDS, TRACE_CONCURRENCY and TRACE_RECENT_BLOCKS must be greater than 0")
+		}
+		if c.TraceBlockPercent < 0 || c.TraceBlockPercent > 100 {
+			return fmt.Errorf("TRACE_BLOCK_PERCENT must be between 0 and 100 (got: %d)", c.TraceBlockPercent)
+		}
+	}
+
+	// Validate traffic shape settings
+	if strings.ToLower(c.Mode) == "shaped" {
+		if c.ShapeDuration <= 0 {
+			return fmt.Errorf("SHAPE_DURATION_MINUTES must be greater than 0 (got: %d)", c.ShapeDuration)
+		}
+		if c.ShapeBaseTPS <= 0 {
+			return fmt.Errorf("SHAPE_BASE_TPS must be greater than 0 (got: %d)", c.ShapeBaseTPS)
+		}
+		if c.ShapeTimeWarp < 0 {
+			return errors.New("SHAPE_TIME_WARP_SECONDS cannot be negative")
+		}
+	}
+
+	// Validate gas griefing settings
+	if strings.ToLower(c.Mode) == "gas-grief" {
+		if c.GriefRevertPercent < 0 || c.GriefRevertPercent > 99 {
+			return fmt.Errorf("GRIEF_REVERT_PERCENT must be between 0 and 99 (got: %d)", c.GriefRevertPercent)
+		}
+		if gasLimit := c.GasLimitFor("parallel"); c.GriefReserveGas >= gasLimit {
+			return fmt.Errorf("GRIEF_RESERVE_GAS must be below the parallel gas limit (got: %d, limit: %d)", c.GriefReserveGas, gasLimit)
+		}
+	}
+
+	// Validate Cancun opcode settings
+	if strings.ToLower(c.Mode) == "cancun" && c.CancunIterations <= 0 {
+		return fmt.Errorf("CANCUN_ITERATIONS must be greater than 0 (got: %d)", c.CancunIterations)
+	}
+
+	// Validate selfdestruct settings
+	if strings.ToLower(c.Mode) == "selfdestruct" && c.SelfdestructRounds <= 0 {
+		return fmt.Errorf("SELFDESTRUCT_ROUNDS must be greater than 0 (got: %d)", c.SelfdestructRounds)
+	}
+
+	// Validate differential test settings
+	if strings.ToLower(c.Mode) == "diff" {
+		if c.DiffRPCURL == "" {
+			return errors.New("DIFF_RPC_URL is required in diff mode")
+		}
+		if !hasRPCScheme(c.DiffRPCURL) {
+			return fmt.Errorf("DIFF_RPC_URL must start with http://, https://, ws://, or wss://")
+		}
+		if c.DiffTransactions < 0 {
+			return fmt.Errorf("DIFF_TRANSACTIONS cannot be negative (got: %d)", c.DiffTransactions)
+		}
+	}
+
+	// Validate propagation measurement settings
+	if strings.ToLower(c.Mode) == "propagation" {
+		if c.PropagationRPCURL == "" {
+			return errors.New("PROPAGATION_RPC_URL is required in propagation mode")
+		}
+		if !hasRPCScheme(c.PropagationRPCURL) {
+			return fmt.Errorf("PROPAGATION_RPC_URL must start with http://, https://, ws://, or wss://")
+		}
+		if c.PropagationSamples <= 0 {
+			return fmt.Errorf("PROPAGATION_SAMPLES must be greater than 0 (got: %d)", c.PropagationSamples)
+		}
+		if c.PropagationSendMs < 0 {
+			return fmt.Errorf("PROPAGATION_SEND_INTERVAL_MS cannot be negative (got: %d)", c.PropagationSendMs)
+		}
+		if c.PropagationPollMs <= 0 {
+			return fmt.Errorf("PROPAGATION_POLL_MS must be greater than 0 (got: %d)", c.PropagationPollMs)
+		}
+	}
+
+	// Validate chain info refresh
+	if c.ChainInfoSeconds < 0 {
+		return fmt.Errorf("CHAIN_INFO_SECONDS cannot be negative (got: %d)", c.ChainInfoSeconds)
+	}
+
+	// Validate node stats sampling
+	if c.NodeStatsSeconds < 0 {
+		return fmt.Errorf("NODE_STATS_SECONDS cannot be negative (got: %d)", c.NodeStatsSeconds)
+	}
+
+	// Validate acceptance lag settings
+	if c.LagReportSeconds < 0 {
+		return fmt.Errorf("LAG_REPORT_SECONDS cannot be negative (got: %d)", c.LagReportSeconds)
+	}
+	if c.LagReportSeconds > 0 && (c.ShedThresholdPercent <= 0 || c.ShedThresholdPercent >= 100) {
+		return fmt.Errorf("SHED_THRESHOLD_PERCENT must be between 1 and 99 (got: %d)", c.ShedThresholdPercent)
+	}
+
+	// Validate host metrics settings
+	if c.HostPrometheusURL != "" && !strings.HasPrefix(c.HostPrometheusURL, "http://") && !strings.HasPrefix(c.HostPrometheusURL, "https://") {
+		return fmt.Errorf("HOST_PROMETHEUS_URL must start with http:// or https:// (got: %s)", c.HostPrometheusURL)
+	}
+	if c.HostExporterURL != "" && !strings.HasPrefix(c.HostExporterURL, "http://") && !strings.HasPrefix(c.HostExporterURL, "https://") {
+		return fmt.Errorf("HOST_EXPORTER_URL must start with http:// or https:// (got: %s)", c.HostExporterURL)
+	}
+	if c.HostMetricsEnabled() && c.HostMetricsSeconds <= 0 {
+		return fmt.Errorf("HOST_METRICS_SECONDS must be greater than 0 (got: %d)", c.HostMetricsSeconds)
+	}
+
+	// Validate finality tracking settings
+	if c.FinalityTracking && c.FinalityWaitSeconds < 0 {
+		return fmt.Errorf("FINALITY_WAIT_SECONDS cannot be negative (got: %d)", c.FinalityWaitSeconds)
+	}
+
+	// Validate replay settings
+	if strings.ToLower(c.Mode) == "replay" {
+		if c.ReplayFile == "" {
+			return errors.New("REPLAY_FILE is required in replay mode")
+		}
+		if c.ReplayTPS < 0 {
+			return fmt.Errorf("REPLAY_TPS cannot be negative (got: %d)", c.ReplayTPS)
+		}
+	}
+
+	// Validate pool-pressure settings
+	if strings.ToLower(c.Mode) == "pool-pressure" {
+		if c.PoolPressureAccounts <= 0 || c.PoolPressureAccounts > 10000 {
+			return fmt.Errorf("POOL_PRESSURE_ACCOUNTS must be between 1 and 10000 (got: %d)", c.PoolPressureAccounts)
+		}
+		if c.PoolPressureTxs <= 0 || c.PoolPressureTxs > 10000 {
+			return fmt.Errorf("POOL_PRESSURE_TXS_PER_ACCOUNT must be between 1 and 10000 (got: %d)", c.PoolPressureTxs)
+		}
+	}
+
+	// Validate export settings; nothing is sent, so nothing can be waited on
+	if c.ExportFile != "" {
+		if strings.ToLower(c.Mode) != "parallel" {
+			return fmt.Errorf("EXPORT_FILE only supports parallel mode (got: %s)", c.Mode)
+		}
+		if c.MaxTransactions == 0 {
+			return errors.New("MAX_TRANSACTIONS must be set when EXPORT_FILE is set, exported wallets never run out of balance")
+		}
+		if c.VerifySampleRate > 0 || c.MaxInFlight > 0 || c.MaxInFlightPerWallet > 0 || c.FinalityTracking {
+			return errors.New("VERIFY_SAMPLE_RATE, MAX_IN_FLIGHT, MAX_IN_FLIGHT_PER_WALLET and FINALITY_TRACKING need sent transactions and cannot be used with EXPORT_FILE")
+		}
+	}
+
+	// Validate CREATE2 deployment; each run keeps a fixed set of contracts
+	if c.Create2Deploy {
+		if c.Create2Namespace == "" {
+			return errors.New("CREATE2_NAMESPACE cannot be empty when CREATE2_DEPLOY is set")
+		}
+		if mode := strings.ToLower(c.Mode); (mode == "deploy" || mode == "all") && c.MaxTransactions == 0 {
+			return errors.New("MAX_TRANSACTIONS must be set when CREATE2_DEPLOY is set, it is the number of contracts kept")
+		}
+	}
+
+	// Validate the event stream; exported transactions are never sent, so there are no events
+	if c.EventStream != "" {
+		if strings.ToLower(c.Mode) != "parallel" {
+			return fmt.Errorf("EVENT_STREAM only supports parallel mode (got: %s)", c.Mode)
+		}
+		if c.ExportFile != "" {
+			return errors.New("EVENT_STREAM and EXPORT_FILE cannot both be set, exported transactions are not sent")
+		}
+	}
+
+	// Validate the failure rate guard
+	if c.MaxFailureRate != "" {
+		if strings.ToLower(c.Mode) != "parallel" {
+			return fmt.Errorf("MAX_FAILURE_RATE only supports parallel mode (got: %s)", c.Mode)
+		}
+		if _, err := c.FailureRateLimit(); err != nil {
+			return err
+		}
+		if c.FailureRateWindow <= 0 {
+			return fmt.Errorf("FAILURE_RATE_WINDOW must be greater than 0 (got: %d)", c.FailureRateWindow)
+		}
+	}
+	if c.AbortSweepTo != "" {
+		if c.MaxFailureRate == "" {
+			return errors.New("ABORT_SWEEP_TO needs MAX_FAILURE_RATE, it only applies to aborted runs")
+		}
+		if !common.IsHexAddress(c.AbortSweepTo) {
+			return fmt.Errorf("ABORT_SWEEP_TO must be a valid address (got: %s)", c.AbortSweepTo)
+		}
+	}
+
+	// Validate fault injection
+	if c.ChaosEnabled() {
+		if strings.ToLower(c.Mode) != "parallel" {
+			return fmt.Errorf("CHAOS_* settings only support parallel mode (got: %s)", c.Mode)
+		}
+		if c.ChaosDropPercent < 0 || c.ChaosDropPercent > 100 {
+			return fmt.Errorf("CHAOS_DROP_PERCENT must be between 0 and 100 (got: %d)", c.ChaosDropPercent)
+		}
+		if c.ChaosCorruptPercent < 0 || c.ChaosCorruptPercent > 100 {
+			return fmt.Errorf("CHAOS_CORRUPT_PERCENT must be between 0 and 100 (got: %d)", c.ChaosCorruptPercent)
+		}
+		if c.ChaosSignDelayMs < 0 {
+			return errors.New("CHAOS_SIGN_DELAY_MS cannot be negative")
+		}
+	}
+
+	// Validate transaction templates
+	if c.TxTemplateFile != "" {
+		if strings.ToLower(c.Mode) != "parallel" {
+			return fmt.Errorf("TX_TEMPLATE_FILE only supports parallel mode (got: %s)", c.Mode)
+		}
+		if c.ParallelContracts != "" {
+			return errors.New("TX_TEMPLATE_FILE and PARALLEL_CONTRACTS cannot both be set, templates choose their own recipients")
+		}
+	}
+
+	// Validate the transaction script
+	if c.ScriptFile != "" {
+		if strings.ToLower(c.Mode) != "parallel" {
+			return fmt.Errorf("SCRIPT_FILE only supports parallel mode (got: %s)", c.Mode)
+		}
+		if c.TxTemplateFile != "" || c.ParallelContracts != "" {
+			return errors.New("SCRIPT_FILE cannot be combined with TX_TEMPLATE_FILE or PARALLEL_CONTRACTS, the script chooses its own recipients")
+		}
+	}
+
+	// Validate plugin settings; plugins are only started when the run begins
+	if c.PluginWorkload != "" || c.PluginFeeStrategy != "" {
+		if c.PluginDir == "" {
+			return errors.New("PLUGIN_DIR is required when PLUGIN_WORKLOAD or PLUGIN_FEE_STRATEGY is set")
+		}
+		if strings.ToLower(c.Mode) != "parallel" {
+			return fmt.Errorf("plugin workloads and fee strategies only support parallel mode (got: %s)", c.Mode)
+		}
+	}
+	if c.PluginWorkload != "" && (c.TxTemplateFile != "" || c.ScriptFile != "" || c.ParallelContracts != "") {
+		return errors.New("PLUGIN_WORKLOAD cannot be combined with TX_TEMPLATE_FILE, SCRIPT_FILE or PARALLEL_CONTRACTS")
+	}
+	if c.PluginDir != "" {
+		if info, err := os.Stat(c.PluginDir); err != nil || !info.IsDir() {
+			return fmt.Errorf("PLUGIN_DIR must be a directory (got: %s)", c.PluginDir)
+		}
+	}
+
+	// Validate reloadable parallel-mode limits
+	if c.RateLimit < 0 {
+		return fmt.Errorf("RATE_LIMIT cannot be negative (got: %d)", c.RateLimit)
+	}
+	if c.MaxGasPrice != "" {
+		if price, ok := new(big.Int).SetString(c.MaxGasPrice, 10); !ok || price.Sign() <= 0 {
+			return fmt.Errorf("MAX_GAS_PRICE must be a positive number of wei (got: %s)", c.MaxGasPrice)
+		}
+	}
+
+	// Validate personas
+	if c.PersonaFile != "" {
+		if strings.ToLower(c.Mode) != "parallel" {
+			return fmt.Errorf("PERSONA_FILE only supports parallel mode (got: %s)", c.Mode)
+		}
+	}
+
+	// Validate target selection
+	if _, err := c.ZipfExponent(); err != nil {
+		return err
+	}
+
+	// Validate slow start
+	if c.SlowStartSeconds < 0 {
+		return fmt.Errorf("SLOW_START_SECONDS cannot be negative (got: %d)", c.SlowStartSeconds)
+	}
+	if c.SlowStartSeconds > 0 && c.SlowStartWallets <= 0 {
+		return fmt.Errorf("SLOW_START_WALLETS must be greater than 0 (got: %d)", c.SlowStartWallets)
+	}
+
+	// Validate drop detection
+	if c.DropTimeoutSeconds < 0 {
+		return fmt.Errorf("DROP_TIMEOUT_SECONDS cannot be negative (got: %d)", c.DropTimeoutSeconds)
+	}
+
+	// Validate the public testnet spend limit
+	if limit, ok := new(big.Int).SetString(c.TestnetSpendLimit, 10); !ok || limit.Sign() < 0 {
+		return fmt.Errorf("TESTNET_SPEND_LIMIT must be a non-negative number of wei (got: %s)", c.TestnetSpendLimit)
+	}
+
+	// Validate baseline comparison settings
+	if c.BaselineFile != "" || c.BaselineSaveFile != "" {
+		if c.BaselineWindow <= 0 {
+			return fmt.Errorf("BASELINE_WINDOW_SECONDS must be greater than 0 (got: %d)", c.BaselineWindow)
+		}
+		if c.BaselineRegressionPercent <= 0 {
+			return fmt.Errorf("BASELINE_REGRESSION_PERCENT must be greater than 0 (got: %d)", c.BaselineRegressionPercent)
+		}
+		if c.BaselineAbortWindows < 0 {
+			return fmt.Errorf("BASELINE_ABORT_WINDOWS cannot be negative (got: %d)", c.BaselineAbortWindows)
+		}
+	}
+
 	return nil
 }
 
+// AutoFunding reports whether FUNDING_AMOUNT should be derived from the workload
+func (c *Config) AutoFunding() bool {
+	return strings.EqualFold(c.FundingAmount, "auto")
+}
+
+// GasLimitFor returns the gas limit for a workload ("transfer", "deploy", "interact",
+// "parallel"), falling back to GAS_LIMIT when no override is set
+func (c *Config) GasLimitFor(workload string) uint64 {
+	overrides := map[string]uint64{
+		"transfer": c.TransferGasLimit,
+		"deploy":   c.DeployGasLimit,
+		"interact": c.InteractGasLimit,
+		"parallel": c.ParallelGasLimit,
+	}
+	if gasLimit := overrides[workload]; gasLimit > 0 {
+		return gasLimit
+	}
+	return c.GasLimit
+}
+
+// ValueFor returns the value in wei for a workload, falling back to VALUE when no
+// override is set, or zero when GAS_ONLY is set. Validate must have succeeded before calling it.
+func (c *Config) ValueFor(workload string) *big.Int {
+	if c.GasOnly {
+		return big.NewInt(0)
+	}
+	overrides := map[string]string{
+		"transfer": c.TransferValue,
+		"deploy":   c.DeployValue,
+		"interact": c.InteractValue,
+		"parallel": c.ParallelValue,
+	}
+	raw := c.Value
+	if override := overrides[workload]; override != "" {
+		raw = override
+	}
+	value, _ := new(big.Int).SetString(raw, 10)
+	return value
+}
+
+// ParallelContractAddresses parses PARALLEL_CONTRACTS. Validate must have succeeded before calling it.
+func (c *Config) ParallelContractAddresses() []common.Address {
+	var addresses []common.Address
+	for _, entry := range splitList(c.ParallelContracts) {
+		addresses = append(addresses, common.HexToAddress(entry))
+	}
+	return addresses
+}
+
+// HostMetricsEnabled reports whether node host metrics are collected during runs
+func (c *Config) HostMetricsEnabled() bool {
+	return c.HostPrometheusURL != "" || c.HostExporterURL != ""
+}
+
+// ReadURL returns the endpoint balance, nonce, gas price and block queries go to:
+// READ_RPC_URL, or RPC_URL
+func (c *Config) ReadURL() string {
+	if c.ReadRPCURL != "" {
+		return c.ReadRPCURL
+	}
+	return c.RPCURL
+}
+
+// WriteURL returns the endpoint signed transactions are submitted to: WRITE_RPC_URL,
+// or RPC_URL
+func (c *Config) WriteURL() string {
+	if c.WriteRPCURL != "" {
+		return c.WriteRPCURL
+	}
+	return c.RPCURL
+}
+
+// BroadcastURLs returns the endpoints each transaction is broadcast to: the write
+// endpoint followed by BROADCAST_RPC_URLS. It returns nil when no broadcast endpoints
+// are configured.
+func (c *Config) BroadcastURLs() []string {
+	extra := splitList(c.BroadcastRPCURLs)
+	if len(extra) == 0 {
+		return nil
+	}
+	return append([]string{c.WriteURL()}, extra...)
+}
+
+// splitList splits a comma-separated value, dropping empty entries
+func splitList(value string) []string {
+	var entries []string
+	for _, entry := range strings.Split(value, ",") {
+		if entry = strings.TrimSpace(entry); entry != "" {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
+
+// hasRPCScheme reports whether url uses a scheme supported by the RPC client
+func hasRPCScheme(url string) bool {
+	return strings.HasPrefix(url, "http://") || strings.HasPrefix(url, "https://") || strings.HasPrefix(url, "ws://") || strings.HasPrefix(url, "wss://")
+}
+
+// SignerLimits returns the largest value and gas price the signer role signs for.
+// The gas price is nil when neither SIGNER_MAX_GAS_PRICE nor MAX_GAS_PRICE is set.
+// Validate must have succeeded before calling it.
+func (c *Config) SignerLimits() (maxValue, maxGasPrice *big.Int) {
+	maxValue = c.ValueFor("parallel")
+	if c.SignerMaxValue != "" {
+		maxValue, _ = new(big.Int).SetString(c.SignerMaxValue, 10)
+	}
+	maxGasPrice = c.MaxGasPriceWei()
+	if c.SignerMaxGasPrice != "" {
+		maxGasPrice, _ = new(big.Int).SetString(c.SignerMaxGasPrice, 10)
+	}
+	return maxValue, maxGasPrice
+}
+
+// MaxGasPriceWei parses MAX_GAS_PRICE, or returns nil when it is not set. Validate
+// must have succeeded before calling it.
+func (c *Config) MaxGasPriceWei() *big.Int {
+	if c.MaxGasPrice == "" {
+		return nil
+	}
+	price, _ := new(big.Int).SetString(c.MaxGasPrice, 10)
+	return price
+}
+
+// ZipfExponent returns the Zipf exponent parallel mode picks targets with, or 0 for
+// uniform selection
+func (c *Config) ZipfExponent() (float64, error) {
+	switch strings.ToLower(c.TargetDistribution) {
+	case "", "uniform":
+		return 0, nil
+	case "zipf":
+		exponent, err := strconv.ParseFloat(c.TargetZipfExponent, 64)
+		if err != nil || exponent <= 1 {
+			return 0, fmt.Errorf("TARGET_ZIPF_EXPONENT must be a number greater than 1 (got: %s)", c.TargetZipfExponent)
+		}
+		return exponent, nil
+	default:
+		return 0, fmt.Errorf("TARGET_DISTRIBUTION must be uniform or zipf (got: %s)", c.TargetDistribution)
+	}
+}
+
+// FailureRateLimit returns MAX_FAILURE_RATE as a fraction, or 0 when it is not set
+func (c *Config) FailureRateLimit() (float64, error) {
+	if c.MaxFailureRate == "" {
+		return 0, nil
+	}
+	rate, err := strconv.ParseFloat(c.MaxFailureRate, 64)
+	if err != nil || rate <= 0 || rate >= 1 {
+		return 0, fmt.Errorf("MAX_FAILURE_RATE must be a fraction between 0 and 1, e.g. 0.5 (got: %s)", c.MaxFailureRate)
+	}
+	return rate, nil
+}
+
+// ChaosEnabled reports whether any CHAOS_* setting asks for faults to be injected
+func (c *Config) ChaosEnabled() bool {
+	return c.ChaosDropPercent != 0 || c.ChaosCorruptPercent != 0 || c.ChaosSignDelayMs != 0
+}
+
+// ShedThreshold returns SHED_THRESHOLD_PERCENT as a fraction
+func (c *Config) ShedThreshold() float64 {
+	return float64(c.ShedThresholdPercent) / 100
+}
+
+// secretFields are left out of Resolved, whatever their value
+var secretFields = map[string]bool{
+	"PrivateKey":    true,
+	"FaucetAPIKey":  true,
+	"BundleAuthKey": true,
+	"VaultToken":    true,
+	"VaultRoleID":   true,
+	"VaultSecretID": true,
+}
+
+// Resolved returns every setting after defaults and file references were applied,
+// keyed by field name, for embedding in the run report. Secrets are redacted, and
+// URLs keep only their scheme and host, as providers put API keys in paths and
+// queries.
+func (c *Config) Resolved() map[string]interface{} {
+	resolved := make(map[string]interface{})
+	v := reflect.ValueOf(c).Elem()
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		value := v.Field(i).Interface()
+		switch {
+		case secretFields[field.Name]:
+			if !v.Field(i).IsZero() {
+				value = "[redacted]"
+			}
+		case strings.HasSuffix(field.Name, "URL") || strings.HasSuffix(field.Name, "URLs"):
+			value = redactURLs(value.(string))
+		}
+		resolved[field.Name] = value
+	}
+	return resolved
+}
+
+// redactURLs reduces each comma-separated URL to its scheme and host, keeping
+// local socket paths
+func redactURLs(value string) string {
+	parts := splitList(value)
+	for i, part := range parts {
+		if strings.HasPrefix(part, "/") {
+			continue // IPC socket path
+		}
+		u, err := url.Parse(part)
+		if err != nil || u.Host == "" {
+			parts[i] = "[redacted]"
+			continue
+		}
+		redacted := u.Scheme + "://" + u.Host
+		if (u.Path != "" && u.Path != "/") || u.RawQuery != "" {
+			redacted += "/[redacted]"
+		}
+		parts[i] = redacted
+	}
+	return strings.Join(parts, ",")
+}
+
+// FundingWindow returns the funding pacer's starting and largest window: funding
+// starts at geth's 16 guaranteed slots per account and may grow to FUNDING_CONCURRENCY
+func (c *Config) FundingWindow() (window, maxWindow int) {
+	window = 16
+	if c.FundingConcurrency < window {
+		window = c.FundingConcurrency
+	}
+	return window, c.FundingConcurrency
+}
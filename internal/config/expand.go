@@ -0,0 +1,66 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// filePrefix marks a value that is read from a file, e.g. PRIVATE_KEY=@file:/run/secrets/key
+const filePrefix = "@file:"
+
+// resolveValue expands ${VAR} and ${VAR:-default} references in a configuration
+// value, then, if the result starts with @file:, replaces it with the trimmed contents
+// of that file. $${ is kept as a literal ${.
+func resolveValue(value string) (string, error) {
+	expanded, err := expandVars(value)
+	if err != nil {
+		return "", err
+	}
+	if !strings.HasPrefix(expanded, filePrefix) {
+		return expanded, nil
+	}
+	path := strings.TrimPrefix(expanded, filePrefix)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// expandVars replaces ${VAR} and ${VAR:-default} with the environment value of VAR.
+// A bare $ is left alone, as it can appear in URLs and passwords.
+func expandVars(value string) (string, error) {
+	if !strings.Contains(value, "${") {
+		return value, nil
+	}
+	var b strings.Builder
+	for {
+		i := strings.Index(value, "${")
+		if i < 0 {
+			b.WriteString(value)
+			return b.String(), nil
+		}
+		if i > 0 && value[i-1] == '$' {
+			b.WriteString(value[:i-1])
+			b.WriteString("${")
+			value = value[i+2:]
+			continue
+		}
+		end := strings.IndexByte(value[i:], '}')
+		if end < 0 {
+			return "", fmt.Errorf("unterminated ${ in %q", value)
+		}
+		b.WriteString(value[:i])
+		name, fallback, hasFallback := strings.Cut(value[i+2:i+end], ":-")
+		if name == "" {
+			return "", fmt.Errorf("empty variable name in %q", value)
+		}
+		if v := os.Getenv(name); v != "" || !hasFallback {
+			b.WriteString(v)
+		} else {
+			b.WriteString(fallback)
+		}
+		value = value[i+end+1:]
+	}
+}
@@ -0,0 +1,152 @@
+package config
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/joho/godotenv"
+)
+
+// defaultProfilesFile is read when PROFILE is set without PROFILES_FILE
+const defaultProfilesFile = "profiles.env"
+
+// profileSection is one [name] or [name : parent] section of a profiles file
+type profileSection struct {
+	parent string
+	body   strings.Builder
+}
+
+// parseProfiles splits a profiles file into its sections. Each section body uses the
+// same KEY=value syntax as .env; lines before the first section are ignored.
+func parseProfiles(text string) (map[string]*profileSection, error) {
+	sections := make(map[string]*profileSection)
+	var current *profileSection
+	scanner := bufio.NewScanner(strings.NewReader(text))
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "[") {
+			if !strings.HasSuffix(line, "]") {
+				return nil, fmt.Errorf("line %d: unterminated profile header %q", lineNo, line)
+			}
+			name, parent, _ := strings.Cut(line[1:len(line)-1], ":")
+			name, parent = strings.TrimSpace(name), strings.TrimSpace(parent)
+			if name == "" {
+				return nil, fmt.Errorf("line %d: profile header without a name", lineNo)
+			}
+			if _, ok := sections[name]; ok {
+				return nil, fmt.Errorf("line %d: profile %q defined twice", lineNo, name)
+			}
+			current = &profileSection{parent: parent}
+			sections[name] = current
+			continue
+		}
+		if current != nil {
+			current.body.WriteString(scanner.Text())
+			current.body.WriteByte('\n')
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return sections, nil
+}
+
+// LoadProfile reads the named profile from a profiles file and returns its values
+// merged over those of the profiles it inherits from, so a child only lists what it
+// changes:
+//
+//	[base]
+//	MODE=parallel
+//	GAS_LIMIT=21000
+//
+//	[sepolia-heavy : base]
+//	RPC_URL=https://sepolia.example.org
+//	PARALLEL_TPS=200
+func LoadProfile(path, name string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read profiles file: %w", err)
+	}
+	sections, err := parseProfiles(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("invalid profiles file %s: %w", path, err)
+	}
+
+	// Walk up to the root profile, then apply each level from the root down
+	var chain []string
+	seen := make(map[string]bool)
+	for current := name; current != ""; current = sections[current].parent {
+		if seen[current] {
+			return nil, fmt.Errorf("profile %q inherits from itself", current)
+		}
+		seen[current] = true
+		if _, ok := sections[current]; !ok {
+			if current == name {
+				return nil, fmt.Errorf("profile %q not found in %s", name, path)
+			}
+			return nil, fmt.Errorf("profile %q inherits from unknown profile %q", chain[len(chain)-1], current)
+		}
+		chain = append(chain, current)
+	}
+
+	values := make(map[string]string)
+	for i := len(chain) - 1; i >= 0; i-- {
+		level, err := godotenv.Unmarshal(sections[chain[i]].body.String())
+		if err != nil {
+			return nil, fmt.Errorf("invalid profile %q: %w", chain[i], err)
+		}
+		for key, value := range level {
+			values[key] = value
+		}
+	}
+	return values, nil
+}
+
+// applyProfile sets the values of the profile named by PROFILE, if any. Profile
+// values override .env and CONFIG_FILE but not the real environment.
+func applyProfile() error {
+	name := os.Getenv("PROFILE")
+	if name == "" {
+		return nil
+	}
+	path := getEnv("PROFILES_FILE", defaultProfilesFile)
+	values, err := LoadProfile(path, name)
+	if err != nil {
+		return err
+	}
+	processEnvMu.Lock()
+	defer processEnvMu.Unlock()
+	for key, value := range values {
+		if !processEnv[key] {
+			os.Setenv(key, value)
+		}
+	}
+	return nil
+}
+
+// ProfileFromArgs removes a --profile NAME or --profile=NAME flag from args and
+// returns the profile name with the remaining arguments. It is read before the
+// subcommand flags, so the flag can appear anywhere on the command line.
+func ProfileFromArgs(args []string) (string, []string) {
+	profile := ""
+	rest := make([]string, 0, len(args))
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--profile" || arg == "-profile":
+			if i+1 < len(args) {
+				profile = args[i+1]
+				i++
+			}
+		case strings.HasPrefix(arg, "--profile="):
+			profile = strings.TrimPrefix(arg, "--profile=")
+		case strings.HasPrefix(arg, "-profile="):
+			profile = strings.TrimPrefix(arg, "-profile=")
+		default:
+			rest = append(rest, arg)
+		}
+	}
+	return profile, rest
+}
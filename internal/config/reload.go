@@ -0,0 +1,190 @@
+package config
+
+import (
+	"context"
+	"log"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+
+	"github.com/joho/godotenv"
+)
+
+var (
+	// processEnv holds the keys set in the real environment at startup, which always
+	// take precedence over values read from .env or CONFIG_FILE
+	processEnv   map[string]bool
+	processEnvMu sync.Mutex
+)
+
+// snapshotProcessEnv records which keys came from the real environment
+func snapshotProcessEnv() {
+	processEnvMu.Lock()
+	defer processEnvMu.Unlock()
+	if processEnv != nil {
+		return
+	}
+	processEnv = make(map[string]bool)
+	for _, entry := range os.Environ() {
+		key, _, _ := strings.Cut(entry, "=")
+		processEnv[key] = true
+	}
+}
+
+// Reload re-reads .env, CONFIG_FILE and the selected profile and returns the validated configuration.
+// Keys set in the real environment keep their startup values, as they cannot change
+// without a restart; everything read from files picks up edits.
+func Reload() (*Config, error) {
+	snapshotProcessEnv()
+
+	files := []string{".env"}
+	if path := os.Getenv("CONFIG_FILE"); path != "" {
+		files = append(files, path)
+	}
+	// Earlier files win, matching Load
+	for i := len(files) - 1; i >= 0; i-- {
+		values, err := godotenv.Read(files[i])
+		if err != nil {
+			continue
+		}
+		processEnvMu.Lock()
+		for key, value := range values {
+			if !processEnv[key] {
+				os.Setenv(key, value)
+			}
+		}
+		processEnvMu.Unlock()
+	}
+	if err := applyProfile(); err != nil {
+		return nil, err
+	}
+
+	cfg := fromEnv()
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// reloadRequests carries reloads requested through TriggerReload
+var reloadRequests = make(chan struct{}, 1)
+
+// TriggerReload asks WatchReload to reload as if the process had received SIGHUP,
+// e.g. from the debug server's /reload endpoint. A request made while another is
+// pending is merged into it.
+func TriggerReload() {
+	select {
+	case reloadRequests <- struct{}{}:
+	default:
+	}
+}
+
+var (
+	reloadMu        sync.Mutex
+	latest          *Config               // Configuration from the last successful reload
+	reloadListeners map[int]func(*Config) // Registered with OnReload, by id
+	nextListener    int
+)
+
+// WatchReload reloads the configuration every time the process receives SIGHUP or
+// TriggerReload is called, until the context is cancelled, starting from cfg.
+// The SIGHUP handler is installed before it returns, so call it once at startup:
+// without it SIGHUP kills the process. Configurations that fail Validate or check
+// are logged and ignored so a bad edit does not stop a running generator.
+func WatchReload(ctx context.Context, cfg *Config, check func(*Config) error) {
+	reloadMu.Lock()
+	latest = cfg
+	reloadMu.Unlock()
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGHUP)
+	go func() {
+		defer signal.Stop(signals)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-signals:
+			case <-reloadRequests:
+			}
+			cfg, err := Reload()
+			if err == nil && check != nil {
+				err = check(cfg)
+			}
+			if err != nil {
+				log.Printf("Ignoring reload: %v", err)
+				continue
+			}
+			log.Println("Configuration reloaded")
+
+			reloadMu.Lock()
+			latest = cfg
+			listeners := make([]func(*Config), 0, len(reloadListeners))
+			for _, f := range reloadListeners {
+				listeners = append(listeners, f)
+			}
+			reloadMu.Unlock()
+			for _, f := range listeners {
+				f(cfg)
+			}
+		}
+	}()
+}
+
+// Latest returns the configuration from the last successful reload, or the one
+// WatchReload started from
+func Latest() *Config {
+	reloadMu.Lock()
+	defer reloadMu.Unlock()
+	return latest
+}
+
+// OnReload calls f with every configuration reloaded from now on and returns a
+// function that stops it, e.g. to retune a load only while it runs
+func OnReload(f func(*Config)) (remove func()) {
+	reloadMu.Lock()
+	defer reloadMu.Unlock()
+	if reloadListeners == nil {
+		reloadListeners = make(map[int]func(*Config))
+	}
+	id := nextListener
+	nextListener++
+	reloadListeners[id] = f
+	return func() {
+		reloadMu.Lock()
+		defer reloadMu.Unlock()
+		delete(reloadListeners, id)
+	}
+}
+
+// WithOverrides returns the validated configuration with each key in overrides set to
+// its value, as if it were in the environment. The environment itself is left as it
+// was, so experiment runs can each apply their own combination.
+func WithOverrides(overrides map[string]string) (*Config, error) {
+	previous := make(map[string]*string, len(overrides))
+	for key, value := range overrides {
+		if old, ok := os.LookupEnv(key); ok {
+			previous[key] = &old
+		} else {
+			previous[key] = nil
+		}
+		os.Setenv(key, value)
+	}
+	defer func() {
+		for key, old := range previous {
+			if old == nil {
+				os.Unsetenv(key)
+			} else {
+				os.Setenv(key, *old)
+			}
+		}
+	}()
+
+	cfg := fromEnv()
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
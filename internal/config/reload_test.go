@@ -0,0 +1,33 @@
+package config
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestWatchReload(t *testing.T) {
+	t.Setenv("PRIVATE_KEY", "ac0974bec39a17e36ba4a6b4d238ff944bacb478cbed5efcae784d7bf4f2ff80")
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	initial := &Config{}
+	WatchReload(ctx, initial, nil)
+	if Latest() != initial {
+		t.Fatal("expected the initial configuration before any reload")
+	}
+
+	reloaded := make(chan *Config, 1)
+	remove := OnReload(func(cfg *Config) { reloaded <- cfg })
+	defer remove()
+	TriggerReload()
+
+	select {
+	case cfg := <-reloaded:
+		if Latest() != cfg {
+			t.Error("expected Latest to return the reloaded configuration")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the reload")
+	}
+}
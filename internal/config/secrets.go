@@ -0,0 +1,61 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/aakash4dev/ethereum-transaction-simulator/internal/secrets"
+)
+
+var (
+	// fetchedKeys caches keys read from a secret store per Vault configuration, so
+	// reloads and sweep overrides do not log in to Vault again
+	fetchedKeys   = make(map[secrets.VaultConfig]string)
+	fetchedKeysMu sync.Mutex
+)
+
+// VaultConfig returns the Vault settings used to read the private key
+func (c *Config) VaultConfig() secrets.VaultConfig {
+	return secrets.VaultConfig{
+		Addr:      c.VaultAddr,
+		Path:      c.VaultPath,
+		Field:     c.VaultField,
+		Namespace: c.VaultNamespace,
+		Token:     c.VaultToken,
+		RoleID:    c.VaultRoleID,
+		SecretID:  c.VaultSecretID,
+	}
+}
+
+// SecretsProvider returns the store the private key is read from, or nil when the
+// key is configured directly
+func (c *Config) SecretsProvider() (secrets.Provider, error) {
+	if c.VaultAddr == "" {
+		return nil, nil
+	}
+	return secrets.NewVault(c.VaultConfig())
+}
+
+// fetchPrivateKey reads the private key from the configured secret store at startup
+func fetchPrivateKey(c *Config) (string, error) {
+	fetchedKeysMu.Lock()
+	defer fetchedKeysMu.Unlock()
+	if key, ok := fetchedKeys[c.VaultConfig()]; ok {
+		return key, nil
+	}
+
+	provider, err := c.SecretsProvider()
+	if err != nil {
+		return "", fmt.Errorf("invalid Vault configuration: %w", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	key, err := provider.PrivateKey(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch PRIVATE_KEY from Vault: %w", err)
+	}
+	fetchedKeys[c.VaultConfig()] = key
+	return key, nil
+}
@@ -0,0 +1,68 @@
+package contract
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	mathrand "math/rand"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// OpcodeWorkload is a contract that exercises an opcode in a loop. Its calldata is the
+// uint256 iteration count.
+type OpcodeWorkload struct {
+	Name     string
+	Bytecode string // Creation bytecode
+	Probe    string // Minimal code using the opcode, run as an eth_call creation to detect support
+}
+
+// CancunWorkloads exercise the opcodes introduced by the Cancun upgrade. Both contracts
+// are hand-assembled, as they need an EVM version newer than this repo's toolchain.
+var CancunWorkloads = []OpcodeWorkload{
+	{
+		// loop n times: tstore(i, i); pop(tload(i))
+		Name:     "transient-storage",
+		Bytecode: "601880600b6000396000f3" + "6000355b801560165780805d805c50600190036003565b00",
+		Probe:    "600160005d00", // tstore(0, 1)
+	},
+	{
+		// loop n times: mcopy(1024, 0, 1024)
+		Name:     "mcopy",
+		Bytecode: "601b80600b6000396000f3" + "6000355b801560195761040060006104005e600190036003565b00",
+		Probe:    "6000600060005e00", // mcopy(0, 0, 0)
+	},
+}
+
+// BytecodeBytes returns the decoded creation bytecode
+func (w *OpcodeWorkload) BytecodeBytes() ([]byte, error) {
+	bytecode, err := hex.DecodeString(w.Bytecode)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode %s bytecode: %w", w.Name, err)
+	}
+	return bytecode, nil
+}
+
+// Supported runs the workload's probe code through eth_call and returns an error when
+// the node rejects it, typically as an invalid opcode on chains without the upgrade
+func (w *OpcodeWorkload) Supported(ctx context.Context, client *ethclient.Client) error {
+	probe, err := hex.DecodeString(w.Probe)
+	if err != nil {
+		return fmt.Errorf("failed to decode %s probe: %w", w.Name, err)
+	}
+	if _, err := client.CallContract(ctx, ethereum.CallMsg{Data: probe, Gas: 100000}, nil); err != nil {
+		return fmt.Errorf("%s not supported by the node: %w", w.Name, err)
+	}
+	return nil
+}
+
+// IterationGenerator returns a calldata generator for the parallel sender that runs
+// iterations loop iterations per call
+func IterationGenerator(iterations int) func(rng *mathrand.Rand) ([]byte, error) {
+	data := new(big.Int).SetInt64(int64(iterations)).FillBytes(make([]byte, 32))
+	return func(*mathrand.Rand) ([]byte, error) {
+		return data, nil
+	}
+}
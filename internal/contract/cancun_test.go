@@ -0,0 +1,27 @@
+package contract
+
+import (
+	"testing"
+)
+
+func TestCancunWorkloads(t *testing.T) {
+	for _, w := range CancunWorkloads {
+		t.Run(w.Name, func(t *testing.T) {
+			bytecode, err := w.BytecodeBytes()
+			if err != nil {
+				t.Fatal(err)
+			}
+			size, offset := int(bytecode[1]), int(bytecode[4])
+			if offset+size != len(bytecode) {
+				t.Errorf("init code copies %d bytes from %d, but runtime is %d bytes", size, offset, len(bytecode)-offset)
+			}
+		})
+	}
+
+	t.Run("Iterations", func(t *testing.T) {
+		data, _ := IterationGenerator(500)(nil)
+		if len(data) != 32 || data[30] != 0x01 || data[31] != 0xf4 {
+			t.Errorf("unexpected calldata %x", data)
+		}
+	})
+}
@@ -0,0 +1,160 @@
+package contract
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/aakash4dev/ethereum-transaction-simulator/internal/transaction"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// DeterministicDeployer is the CREATE2 factory of Arachnid's deterministic deployment
+// proxy. It has the same address on every chain it is deployed on, which includes most
+// public networks; a call with salt followed by init code deploys at an address that
+// depends only on the two.
+var DeterministicDeployer = common.HexToAddress("0x4e59b44847b379578588920ca78fbf26c0b4956c")
+
+// The proxy is deployed on chains without it by a presigned transaction without
+// replay protection, from a signer nobody holds the key of. Its fee has to be sent
+// to the signer first: 100000 gas at 100 gwei.
+var (
+	deterministicDeployerSigner = common.HexToAddress("0x3fab184622dc19b6109349b94811493bf2a45362")
+	deterministicDeployerTx     = "0xf8a58085174876e800830186a08080b853604580600e600039806000f350fe7fffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffe03601600081602082378035828234f58015156039578182fd5b8082525050506014600cf31ba02222222222222222222222222222222222222222222222222222222222222222a02222222222222222222222222222222222222222222222222222222222222222"
+	deterministicDeployerFee    = new(big.Int).Mul(big.NewInt(100000), big.NewInt(100_000_000_000))
+)
+
+// Create2Salt derives the salt of the index-th contract of a kind from namespace, so
+// runs with the same namespace find each other's contracts and runs with different
+// ones do not
+func Create2Salt(namespace, label string, index int) [32]byte {
+	return crypto.Keccak256Hash([]byte(fmt.Sprintf("%s:%s:%d", namespace, label, index)))
+}
+
+// Create2Address returns where the deterministic deployer puts initCode under salt
+func Create2Address(salt [32]byte, initCode []byte) common.Address {
+	return crypto.CreateAddress2(DeterministicDeployer, salt, crypto.Keccak256(initCode))
+}
+
+// EnsureDeterministicDeployer deploys the CREATE2 factory when the chain does not have
+// it yet, funding its signer from the deployer's key first. Nodes that only accept
+// replay-protected transactions refuse the presigned deployment; geth accepts it
+// with --rpc.allow-unprotected-txs.
+func (d *Deployer) EnsureDeterministicDeployer(ctx context.Context) error {
+	code, err := d.client.CodeAt(ctx, DeterministicDeployer, nil)
+	if err != nil {
+		return fmt.Errorf("failed to check for the CREATE2 factory: %w", err)
+	}
+	if len(code) > 0 {
+		return nil
+	}
+	fmt.Printf("Deploying the CREATE2 factory at %s\n", DeterministicDeployer.Hex())
+
+	balance, err := d.client.BalanceAt(ctx, deterministicDeployerSigner, nil)
+	if err != nil {
+		return fmt.Errorf("failed to get the CREATE2 factory signer's balance: %w", err)
+	}
+	if missing := new(big.Int).Sub(deterministicDeployerFee, balance); missing.Sign() > 0 {
+		if err := d.transfer(ctx, deterministicDeployerSigner, missing); err != nil {
+			return fmt.Errorf("failed to fund the CREATE2 factory signer: %w", err)
+		}
+	}
+
+	raw, err := hexutil.Decode(deterministicDeployerTx)
+	if err != nil {
+		return err
+	}
+	tx := new(types.Transaction)
+	if err := tx.UnmarshalBinary(raw); err != nil {
+		return fmt.Errorf("failed to decode the CREATE2 factory deployment: %w", err)
+	}
+	if err := d.submitter.SendTransaction(ctx, tx); err != nil && !transaction.IsAlreadyKnown(err) {
+		if strings.Contains(strings.ToLower(err.Error()), "eip-155") || strings.Contains(strings.ToLower(err.Error()), "replay") {
+			return fmt.Errorf("the node refuses the CREATE2 factory deployment, which is not replay-protected (enable unprotected transactions, e.g. geth --rpc.allow-unprotected-txs): %w", err)
+		}
+		return fmt.Errorf("failed to deploy the CREATE2 factory: %w", err)
+	}
+	if _, err := d.waitForReceipt(ctx, tx.Hash()); err != nil {
+		return err
+	}
+	return nil
+}
+
+// DeployCreate2 deploys initCode through the deterministic deployer under salt and
+// waits for it to be mined. When the chain already has code at the address, from an
+// earlier run, nothing is sent and reused is true.
+func (d *Deployer) DeployCreate2(ctx context.Context, label string, salt [32]byte, initCode []byte) (address common.Address, reused bool, err error) {
+	address = Create2Address(salt, initCode)
+	code, err := d.client.CodeAt(ctx, address, nil)
+	if err != nil {
+		return common.Address{}, false, fmt.Errorf("failed to check %s for code: %w", address.Hex(), err)
+	}
+	if len(code) > 0 {
+		if d.registry != nil {
+			d.registry.Register(address, label)
+		}
+		return address, true, nil
+	}
+
+	nonce, err := d.nonceManager.GetNextNonce(ctx)
+	if err != nil {
+		return common.Address{}, false, fmt.Errorf("failed to get nonce: %w", err)
+	}
+	gasPrice, err := d.suggestGasPrice(ctx)
+	if err != nil {
+		return common.Address{}, false, err
+	}
+	data := append(salt[:], initCode...)
+	tx := types.NewTransaction(nonce, DeterministicDeployer, big.NewInt(0), d.config.GasLimit, gasPrice, data)
+	signedTx, err := transaction.SignTx(tx, d.chainID, d.privateKey)
+	if err != nil {
+		return common.Address{}, false, fmt.Errorf("failed to sign transaction: %w", err)
+	}
+	if err := d.submitter.SendTransaction(ctx, signedTx); err != nil {
+		return common.Address{}, false, fmt.Errorf("failed to send transaction: %w", err)
+	}
+
+	receipt, err := d.waitForReceipt(ctx, signedTx.Hash())
+	if err != nil {
+		return common.Address{}, false, err
+	}
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		return common.Address{}, false, fmt.Errorf("CREATE2 deployment %s reverted (gas used %d of %d)", signedTx.Hash().Hex(), receipt.GasUsed, d.config.GasLimit)
+	}
+	if d.registry != nil {
+		d.registry.RecordDeploy(address, label, signedTx.Hash(), receipt.GasUsed)
+	}
+	return address, false, nil
+}
+
+// transfer sends value from the deployer's key to to and waits for it to be mined
+func (d *Deployer) transfer(ctx context.Context, to common.Address, value *big.Int) error {
+	nonce, err := d.nonceManager.GetNextNonce(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get nonce: %w", err)
+	}
+	gasPrice, err := d.suggestGasPrice(ctx)
+	if err != nil {
+		return err
+	}
+	signedTx, err := transaction.SignTx(types.NewTransaction(nonce, to, value, 21000, gasPrice, nil), d.chainID, d.privateKey)
+	if err != nil {
+		return fmt.Errorf("failed to sign transaction: %w", err)
+	}
+	if err := d.submitter.SendTransaction(ctx, signedTx); err != nil {
+		return fmt.Errorf("failed to send transaction: %w", err)
+	}
+	receipt, err := d.waitForReceipt(ctx, signedTx.Hash())
+	if err != nil {
+		return err
+	}
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		return errors.New("transfer reverted")
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package contract
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func TestCreate2(t *testing.T) {
+	bytecode := []byte{0x60, 0x00}
+	salt := Create2Salt("team-a", "storage", 0)
+	if Create2Address(salt, bytecode) != Create2Address(Create2Salt("team-a", "storage", 0), bytecode) {
+		t.Error("expected the same namespace, label and index to give the same address")
+	}
+	if Create2Address(salt, bytecode) == Create2Address(Create2Salt("team-b", "storage", 0), bytecode) {
+		t.Error("expected namespaces to give different addresses")
+	}
+	if Create2Address(salt, bytecode) == Create2Address(Create2Salt("team-a", "storage", 1), bytecode) {
+		t.Error("expected indexes to give different addresses")
+	}
+
+	// The presigned factory deployment must come from the known signer and create
+	// the factory at its well-known address
+	raw, err := hexutil.Decode(deterministicDeployerTx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx := new(types.Transaction)
+	if err := tx.UnmarshalBinary(raw); err != nil {
+		t.Fatal(err)
+	}
+	from, err := types.HomesteadSigner{}.Sender(tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if from != deterministicDeployerSigner {
+		t.Errorf("expected the factory deployment to be signed by %s, got %s", deterministicDeployerSigner.Hex(), from.Hex())
+	}
+	if crypto.CreateAddress(from, tx.Nonce()) != DeterministicDeployer {
+		t.Errorf("expected the factory deployment to create %s", DeterministicDeployer.Hex())
+	}
+	if tx.Cost().Cmp(deterministicDeployerFee) != 0 {
+		t.Errorf("expected the factory deployment to cost %s, got %s", deterministicDeployerFee, tx.Cost())
+	}
+}
@@ -23,71 +23,86 @@ type Deployer struct {
 	chainID     *big.Int
 	config      *DeployerConfig
 	nonceManager *transaction.NonceManager
+	submitter   transaction.Submitter
+	registry    *Registry // Records deployments and calls when set
+	ownsClient  bool // Close the client on Close (false for injected clients)
 }
 
 // DeployerConfig holds configuration for contract operations
 type DeployerConfig struct {
 	Value            *big.Int
 	GasLimit         uint64
-	MaxTransactions  int
+	InteractValue    *big.Int // Value for contract calls, nil uses Value
+	InteractGasLimit uint64   // Gas limit for contract calls, 0 uses GasLimit
+	MaxTransactions  int // 0 = unlimited
 	DelaySeconds     int
+	Create2          bool   // Deploy through the CREATE2 factory, reusing contracts already on chain
+	Create2Namespace string // Salt namespace of CREATE2 deployments; runs sharing it share contracts
 }
 
 // NewDeployer creates a new contract deployer
 func NewDeployer(rpcURL, privateKeyHex string, config *DeployerConfig) (*Deployer, error) {
+	return NewDeployerWithNonceManager(rpcURL, privateKeyHex, config, nil)
+}
+
+// NewDeployerWithNonceManager creates a new contract deployer with a shared nonce manager
+//
+// Deprecated: dials a new client and fetches the chain ID again; callers that already
+// hold both should use NewDeployerWithClient.
+func NewDeployerWithNonceManager(rpcURL, privateKeyHex string, config *DeployerConfig, nonceManager *transaction.NonceManager) (*Deployer, error) {
 	client, err := ethclient.Dial(rpcURL)
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to RPC: %w", err)
 	}
 
-	privateKey, err := crypto.HexToECDSA(strings.TrimPrefix(privateKeyHex, "0x"))
-	if err != nil {
-		return nil, fmt.Errorf("failed to parse private key: %w", err)
-	}
-
 	chainID, err := client.ChainID(context.Background())
 	if err != nil {
+		client.Close()
 		return nil, fmt.Errorf("failed to get chain ID: %w", err)
 	}
 
-	fromAddress := crypto.PubkeyToAddress(privateKey.PublicKey)
-	nonceManager := transaction.NewNonceManager(client, fromAddress)
-
-	return &Deployer{
-		client:       client,
-		privateKey:  privateKey,
-		chainID:     chainID,
-		config:      config,
-		nonceManager: nonceManager,
-	}, nil
-}
-
-// NewDeployerWithNonceManager creates a new contract deployer with a shared nonce manager
-func NewDeployerWithNonceManager(rpcURL, privateKeyHex string, config *DeployerConfig, nonceManager *transaction.NonceManager) (*Deployer, error) {
-	client, err := ethclient.Dial(rpcURL)
+	deployer, err := NewDeployerWithClient(client, chainID, privateKeyHex, config, nonceManager)
 	if err != nil {
-		return nil, fmt.Errorf("failed to connect to RPC: %w", err)
+		client.Close()
+		return nil, err
 	}
+	deployer.ownsClient = true
+	return deployer, nil
+}
 
+// NewDeployerWithClient creates a new contract deployer on an existing client and chain ID.
+// A nil nonceManager creates one for the key's address. The client is not closed by Close.
+func NewDeployerWithClient(client *ethclient.Client, chainID *big.Int, privateKeyHex string, config *DeployerConfig, nonceManager *transaction.NonceManager) (*Deployer, error) {
 	privateKey, err := crypto.HexToECDSA(strings.TrimPrefix(privateKeyHex, "0x"))
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse private key: %w", err)
 	}
 
-	chainID, err := client.ChainID(context.Background())
-	if err != nil {
-		return nil, fmt.Errorf("failed to get chain ID: %w", err)
+	if nonceManager == nil {
+		nonceManager = transaction.NewNonceManager(client, crypto.PubkeyToAddress(privateKey.PublicKey))
 	}
 
 	return &Deployer{
 		client:       client,
-		privateKey:  privateKey,
-		chainID:     chainID,
-		config:      config,
+		privateKey:   privateKey,
+		chainID:      chainID,
+		config:       config,
 		nonceManager: nonceManager,
+		submitter:    client,
 	}, nil
 }
 
+// SetSubmitter routes signed transactions to a separate write endpoint
+func (d *Deployer) SetSubmitter(submitter transaction.Submitter) {
+	d.submitter = submitter
+}
+
+// SetRegistry records every contract the deployer deploys, and every call it sends,
+// in registry
+func (d *Deployer) SetRegistry(registry *Registry) {
+	d.registry = registry
+}
+
 // DeployContract deploys a smart contract multiple times and returns deployed addresses
 func (d *Deployer) DeployContract() ([]common.Address, error) {
 	fromAddress := crypto.PubkeyToAddress(d.privateKey.PublicKey)
@@ -98,53 +113,47 @@ func (d *Deployer) DeployContract() ([]common.Address, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to get contract bytecode: %w", err)
 	}
+	if d.config.Create2 {
+		return d.deployCreate2Contracts(ctx, bytecode)
+	}
 
-	for i := 0; i < d.config.MaxTransactions; i++ {
-		fmt.Printf("Deploying contract %d/%d\n", i+1, d.config.MaxTransactions)
+	for i := 0; transaction.WithinLimit(i, d.config.MaxTransactions); i++ {
+		fmt.Printf("Deploying contract %s\n", transaction.FormatProgress(i, d.config.MaxTransactions))
 
 		nonce, err := d.nonceManager.GetNextNonce(ctx)
 		if err != nil {
 			return nil, fmt.Errorf("failed to get nonce: %w", err)
 		}
 
-		// Retry getting gas price in case of transient node errors
-		var gasPrice *big.Int
-		maxRetries := 3
-		for retry := 0; retry < maxRetries; retry++ {
-			gasPrice, err = d.client.SuggestGasPrice(context.Background())
-			if err == nil {
-				break
-			}
-			if retry < maxRetries-1 {
-				// Wait a bit before retrying (exponential backoff)
-				time.Sleep(time.Duration(retry+1) * 200 * time.Millisecond)
-			}
-		}
+		gasPrice, err := d.suggestGasPrice(ctx)
 		if err != nil {
-			return nil, fmt.Errorf("failed to get gas price after %d retries: %w", maxRetries, err)
+			return nil, err
 		}
 
 		tx := types.NewContractCreation(nonce, d.config.Value, d.config.GasLimit, gasPrice, bytecode)
 
-		signedTx, err := types.SignTx(tx, types.NewEIP155Signer(d.chainID), d.privateKey)
+		signedTx, err := transaction.SignTx(tx, d.chainID, d.privateKey)
 		if err != nil {
 			return nil, fmt.Errorf("failed to sign transaction: %w", err)
 		}
 
-		if err := d.client.SendTransaction(context.Background(), signedTx); err != nil {
+		if err := d.submitter.SendTransaction(context.Background(), signedTx); err != nil {
 			return nil, fmt.Errorf("failed to send transaction: %w", err)
 		}
 
 		// Calculate contract address
 		contractAddress := crypto.CreateAddress(fromAddress, nonce)
 		deployedAddresses = append(deployedAddresses, contractAddress)
+		if d.registry != nil {
+			d.registry.RecordDeploy(contractAddress, "storage", signedTx.Hash(), 0)
+		}
 
 		fmt.Printf("Deployment transaction hash: %s, contract address: %s\n", 
 			signedTx.Hash().Hex(), contractAddress.Hex())
 
 		// Wait for the node to accept the transaction into mempool before proceeding
 		// This prevents nonce conflicts when sending transactions rapidly
-		if i < d.config.MaxTransactions-1 {
+		if !transaction.IsLast(i, d.config.MaxTransactions) {
 			if d.config.DelaySeconds > 0 {
 				// Wait for transaction receipt or use delay as fallback
 				time.Sleep(time.Duration(d.config.DelaySeconds) * time.Second)
@@ -159,6 +168,36 @@ func (d *Deployer) DeployContract() ([]common.Address, error) {
 	return deployedAddresses, nil
 }
 
+// deployCreate2Contracts deploys MaxTransactions storage contracts through the CREATE2
+// factory, one after another, reusing those an earlier run already deployed. A CREATE2
+// deployment needs a cap, as every run would otherwise only reuse.
+func (d *Deployer) deployCreate2Contracts(ctx context.Context, bytecode []byte) ([]common.Address, error) {
+	if d.config.MaxTransactions == transaction.Unlimited {
+		return nil, fmt.Errorf("CREATE2 deployment needs MAX_TRANSACTIONS to know how many contracts to keep")
+	}
+	if err := d.EnsureDeterministicDeployer(ctx); err != nil {
+		return nil, err
+	}
+	addresses := make([]common.Address, 0, d.config.MaxTransactions)
+	reusedCount := 0
+	for i := 0; i < d.config.MaxTransactions; i++ {
+		salt := Create2Salt(d.config.Create2Namespace, "storage", i)
+		address, reused, err := d.DeployCreate2(ctx, "storage", salt, bytecode)
+		if err != nil {
+			return nil, err
+		}
+		if reused {
+			reusedCount++
+			fmt.Printf("Reusing contract %s: %s\n", transaction.FormatProgress(i, d.config.MaxTransactions), address.Hex())
+		} else {
+			fmt.Printf("Deployed contract %s: %s\n", transaction.FormatProgress(i, d.config.MaxTransactions), address.Hex())
+		}
+		addresses = append(addresses, address)
+	}
+	fmt.Printf("Contracts: %d deployed, %d reused from earlier runs\n", len(addresses)-reusedCount, reusedCount)
+	return addresses, nil
+}
+
 // InteractWithContract calls a contract function multiple times on deployed contracts
 func (d *Deployer) InteractWithContract(contractAddresses []common.Address) error {
 	if len(contractAddresses) == 0 {
@@ -169,7 +208,7 @@ func (d *Deployer) InteractWithContract(contractAddresses []common.Address) erro
 	rng := rand.New(rand.NewSource(time.Now().UnixNano()))
 	ctx := context.Background()
 
-	for i := 0; i < d.config.MaxTransactions; i++ {
+	for i := 0; transaction.WithinLimit(i, d.config.MaxTransactions); i++ {
 		// Select random contract address
 		contractIndex := rng.Intn(len(contractAddresses))
 		contractAddress := contractAddresses[contractIndex]
@@ -181,52 +220,46 @@ func (d *Deployer) InteractWithContract(contractAddresses []common.Address) erro
 			return fmt.Errorf("failed to generate function data: %w", err)
 		}
 
-		fmt.Printf("Calling contract function %d/%d on %s with value %s\n", 
-			i+1, d.config.MaxTransactions, contractAddress.Hex(), randomValue.String())
+		fmt.Printf("Calling contract function %s on %s with value %s\n", 
+			transaction.FormatProgress(i, d.config.MaxTransactions), contractAddress.Hex(), randomValue.String())
 
 		nonce, err := d.nonceManager.GetNextNonce(ctx)
 		if err != nil {
 			return fmt.Errorf("failed to get nonce: %w", err)
 		}
 
-		// Retry getting gas price in case of transient node errors
-		var gasPrice *big.Int
-		maxRetries := 3
-		for retry := 0; retry < maxRetries; retry++ {
-			gasPrice, err = d.client.SuggestGasPrice(context.Background())
-			if err == nil {
-				break
-			}
-			if retry < maxRetries-1 {
-				// Wait a bit before retrying (exponential backoff)
-				time.Sleep(time.Duration(retry+1) * 200 * time.Millisecond)
-			}
-		}
+		gasPrice, err := d.suggestGasPrice(ctx)
 		if err != nil {
-			return fmt.Errorf("failed to get gas price after %d retries: %w", maxRetries, err)
+			return err
 		}
 
 		tx := types.NewTransaction(
 			nonce,
 			contractAddress,
-			d.config.Value,
-			d.config.GasLimit,
+			d.interactValue(),
+			d.interactGasLimit(),
 			gasPrice,
 			functionData,
 		)
 
-		signedTx, err := types.SignTx(tx, types.NewEIP155Signer(d.chainID), d.privateKey)
+		signedTx, err := transaction.SignTx(tx, d.chainID, d.privateKey)
 		if err != nil {
 			return fmt.Errorf("failed to sign transaction: %w", err)
 		}
 
-		if err := d.client.SendTransaction(context.Background(), signedTx); err != nil {
+		if err := d.submitter.SendTransaction(context.Background(), signedTx); err != nil {
+			if d.registry != nil {
+				d.registry.RecordSendFailure(contractAddress)
+			}
 			return fmt.Errorf("failed to send transaction: %w", err)
 		}
+		if d.registry != nil {
+			d.registry.RecordSent(contractAddress)
+		}
 
 		fmt.Printf("Interaction transaction hash: %s\n", signedTx.Hash().Hex())
 
-		if i < d.config.MaxTransactions-1 {
+		if !transaction.IsLast(i, d.config.MaxTransactions) {
 			time.Sleep(time.Duration(d.config.DelaySeconds) * time.Second)
 		}
 	}
@@ -234,9 +267,113 @@ func (d *Deployer) InteractWithContract(contractAddresses []common.Address) erro
 	return nil
 }
 
-// Close closes the Ethereum client connection
+// DeployBytecode deploys bytecode once and waits for it to be mined, returning the
+// contract address. Workloads that call a special-purpose contract deploy it this way.
+// With Create2 set, a contract an earlier run deployed is reused.
+func (d *Deployer) DeployBytecode(ctx context.Context, bytecode []byte) (common.Address, error) {
+	if d.config.Create2 {
+		// The address covers the bytecode, so one salt serves every workload contract
+		if err := d.EnsureDeterministicDeployer(ctx); err != nil {
+			return common.Address{}, err
+		}
+		salt := Create2Salt(d.config.Create2Namespace, "bytecode", 0)
+		address, reused, err := d.DeployCreate2(ctx, "bytecode", salt, bytecode)
+		if err == nil && reused {
+			fmt.Printf("Reusing contract %s from an earlier run\n", address.Hex())
+		}
+		return address, err
+	}
+	fromAddress := crypto.PubkeyToAddress(d.privateKey.PublicKey)
+	nonce, err := d.nonceManager.GetNextNonce(ctx)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("failed to get nonce: %w", err)
+	}
+	gasPrice, err := d.suggestGasPrice(ctx)
+	if err != nil {
+		return common.Address{}, err
+	}
+
+	tx := types.NewContractCreation(nonce, big.NewInt(0), d.config.GasLimit, gasPrice, bytecode)
+	signedTx, err := transaction.SignTx(tx, d.chainID, d.privateKey)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("failed to sign transaction: %w", err)
+	}
+	if err := d.submitter.SendTransaction(ctx, signedTx); err != nil {
+		return common.Address{}, fmt.Errorf("failed to send transaction: %w", err)
+	}
+
+	receipt, err := d.waitForReceipt(ctx, signedTx.Hash())
+	if err != nil {
+		return common.Address{}, err
+	}
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		return common.Address{}, fmt.Errorf("deployment %s reverted (gas used %d of %d)", signedTx.Hash().Hex(), receipt.GasUsed, d.config.GasLimit)
+	}
+	address := crypto.CreateAddress(fromAddress, nonce)
+	if d.registry != nil {
+		d.registry.RecordDeploy(address, "bytecode", signedTx.Hash(), receipt.GasUsed)
+	}
+	return address, nil
+}
+
+// suggestGasPrice fetches the suggested gas price, retrying transient node errors
+func (d *Deployer) suggestGasPrice(ctx context.Context) (*big.Int, error) {
+	var gasPrice *big.Int
+	var err error
+	maxRetries := 3
+	for retry := 0; retry < maxRetries; retry++ {
+		gasPrice, err = d.client.SuggestGasPrice(ctx)
+		if err == nil {
+			return gasPrice, nil
+		}
+		if retry < maxRetries-1 {
+			// Wait a bit before retrying (exponential backoff)
+			time.Sleep(time.Duration(retry+1) * 200 * time.Millisecond)
+		}
+	}
+	return nil, fmt.Errorf("failed to get gas price after %d retries: %w", maxRetries, err)
+}
+
+// waitForReceipt polls for a transaction's receipt until it is mined or a minute passes
+func (d *Deployer) waitForReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error) {
+	timeout := time.After(time.Minute)
+	ticker := time.NewTicker(500 * time.Millisecond)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-timeout:
+			return nil, fmt.Errorf("timeout waiting for transaction %s", txHash.Hex())
+		case <-ticker.C:
+			receipt, err := d.client.TransactionReceipt(ctx, txHash)
+			if err == nil && receipt != nil {
+				return receipt, nil
+			}
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// interactValue returns the value sent with contract calls
+func (d *Deployer) interactValue() *big.Int {
+	if d.config.InteractValue != nil {
+		return d.config.InteractValue
+	}
+	return d.config.Value
+}
+
+// interactGasLimit returns the gas limit for contract calls
+func (d *Deployer) interactGasLimit() uint64 {
+	if d.config.InteractGasLimit > 0 {
+		return d.config.InteractGasLimit
+	}
+	return d.config.GasLimit
+}
+
+// Close closes the Ethereum client connection if the deployer dialed it
 func (d *Deployer) Close() {
-	if d.client != nil {
+	if d.client != nil && d.ownsClient {
 		d.client.Close()
 	}
 }
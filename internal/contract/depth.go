@@ -0,0 +1,66 @@
+package contract
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	mathrand "math/rand"
+	"strconv"
+	"strings"
+)
+
+// MaxCallDepth is the deepest recursion the EVM allows below a transaction's own call
+const MaxCallDepth = 1023
+
+// RecursiveContractBytecode calls itself depth times, where depth is the uint256 calldata
+// argument, and reverts the whole transaction if any nested call fails. Hand-assembled:
+//
+//	d := calldata[0:32]; if d == 0 stop
+//	mstore(0, d-1); if !call(gas(), address(), 0, 0, 32, 0, 0) revert(0, 0)
+//
+// Each call forwards all but 1/64 of the remaining gas (EIP-150), so the reachable depth
+// is bounded by the gas limit well before MaxCallDepth.
+var RecursiveContractBytecode = "602880600b6000396000f3" +
+	"60003580156021576001900360005260006000602060006000305af115602357005b005b600080fd"
+
+// GetRecursiveBytecode returns the creation bytecode of the recursive call contract
+func GetRecursiveBytecode() ([]byte, error) {
+	bytecode, err := hex.DecodeString(RecursiveContractBytecode)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode recursive bytecode: %w", err)
+	}
+	return bytecode, nil
+}
+
+// ParseCallDepths parses comma-separated recursion depths such as "8,64,256"
+func ParseCallDepths(s string) ([]int, error) {
+	var depths []int
+	for _, entry := range strings.Split(s, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		depth, err := strconv.Atoi(entry)
+		if err != nil || depth < 1 || depth > MaxCallDepth {
+			return nil, fmt.Errorf("invalid call depth %q: must be between 1 and %d", entry, MaxCallDepth)
+		}
+		depths = append(depths, depth)
+	}
+	if len(depths) == 0 {
+		return nil, fmt.Errorf("at least one call depth is required")
+	}
+	return depths, nil
+}
+
+// RecursiveCalldata returns calldata that recurses depth times
+func RecursiveCalldata(depth int) []byte {
+	return new(big.Int).SetInt64(int64(depth)).FillBytes(make([]byte, 32))
+}
+
+// RecursiveGenerator returns a calldata generator for the parallel sender that picks
+// one of depths at random for each call
+func RecursiveGenerator(depths []int) func(rng *mathrand.Rand) ([]byte, error) {
+	return func(rng *mathrand.Rand) ([]byte, error) {
+		return RecursiveCalldata(depths[rng.Intn(len(depths))]), nil
+	}
+}
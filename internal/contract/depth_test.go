@@ -0,0 +1,37 @@
+package contract
+
+import (
+	"testing"
+)
+
+func TestRecursiveContract(t *testing.T) {
+	t.Run("InitCodeCopiesRuntime", func(t *testing.T) {
+		bytecode, err := GetRecursiveBytecode()
+		if err != nil {
+			t.Fatal(err)
+		}
+		size, offset := int(bytecode[1]), int(bytecode[4])
+		if offset+size != len(bytecode) {
+			t.Errorf("init code copies %d bytes from %d, but runtime is %d bytes", size, offset, len(bytecode)-offset)
+		}
+	})
+
+	t.Run("ParseCallDepths", func(t *testing.T) {
+		depths, err := ParseCallDepths("8, 64,256")
+		if err != nil || len(depths) != 3 || depths[2] != 256 {
+			t.Errorf("unexpected depths %v (err: %v)", depths, err)
+		}
+		for _, s := range []string{"", "0", "1024", "deep"} {
+			if _, err := ParseCallDepths(s); err == nil {
+				t.Errorf("%q: expected error", s)
+			}
+		}
+	})
+
+	t.Run("Calldata", func(t *testing.T) {
+		data := RecursiveCalldata(300)
+		if len(data) != 32 || data[30] != 0x01 || data[31] != 0x2c {
+			t.Errorf("unexpected calldata %x", data)
+		}
+	})
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/hex"
 	"fmt"
 	"math/big"
+	mathrand "math/rand"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
@@ -60,3 +61,14 @@ func GetSetFunctionData(value *big.Int) ([]byte, error) {
 	return data, nil
 }
 
+// GetGetFunctionData returns the call data for the get() view function
+// Keccak256("get()") = 0x6d4ce63c (first 4 bytes)
+func GetGetFunctionData() []byte {
+	return []byte{0x6d, 0x4c, 0xe6, 0x3c}
+}
+
+// RandomSetCalldata returns set(uint256) calldata with a random value, for use as the
+// parallel sender's calldata generator
+func RandomSetCalldata(rng *mathrand.Rand) ([]byte, error) {
+	return GetSetFunctionData(big.NewInt(int64(rng.Intn(1000000) + 1)))
+}
@@ -0,0 +1,52 @@
+package contract
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	mathrand "math/rand"
+)
+
+// GasGriefContractBytecode burns gas in a loop until at most a given amount is left, then
+// stops or reverts. Hand-assembled, as no Solidity construct leaves a precise gas remainder:
+//
+//	loop: JUMPDEST; if gas() > calldata[0:32] goto loop
+//	      if calldata[32:64] != 0 revert(0, 0)
+//	      stop
+//
+// Each iteration costs 25 gas, so a call leaves between the reserve and reserve+25
+// unused. A reserve of 0 runs the call out of gas.
+var GasGriefContractBytecode = "601580600b6000396000f3" + "5b6000355a11600057602035601057005b600080fd"
+
+// GetGasGriefBytecode returns the creation bytecode of the gas griefing contract
+func GetGasGriefBytecode() ([]byte, error) {
+	bytecode, err := hex.DecodeString(GasGriefContractBytecode)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode gas grief bytecode: %w", err)
+	}
+	return bytecode, nil
+}
+
+// GasGriefCalldata returns calldata that burns gas until reserve is left, then reverts
+// if revert is set
+func GasGriefCalldata(reserve uint64, revert bool) []byte {
+	data := make([]byte, 64)
+	new(big.Int).SetUint64(reserve).FillBytes(data[:32])
+	if revert {
+		data[63] = 1
+	}
+	return data
+}
+
+// GasGriefGenerator returns a calldata generator for the parallel sender. With
+// revertPercent 0 every call stops with reserve gas left; otherwise every call reverts
+// once revertPercent of gasLimit has been burned.
+func GasGriefGenerator(gasLimit, reserve uint64, revertPercent int) func(rng *mathrand.Rand) ([]byte, error) {
+	data := GasGriefCalldata(reserve, false)
+	if revertPercent > 0 {
+		data = GasGriefCalldata(gasLimit*uint64(100-revertPercent)/100, true)
+	}
+	return func(*mathrand.Rand) ([]byte, error) {
+		return data, nil
+	}
+}
@@ -0,0 +1,34 @@
+package contract
+
+import (
+	"testing"
+)
+
+func TestGasGrief(t *testing.T) {
+	t.Run("InitCodeCopiesRuntime", func(t *testing.T) {
+		bytecode, err := GetGasGriefBytecode()
+		if err != nil {
+			t.Fatal(err)
+		}
+		// PUSH1 size, DUP1, PUSH1 offset, ...
+		size, offset := int(bytecode[1]), int(bytecode[4])
+		if offset+size != len(bytecode) {
+			t.Errorf("init code copies %d bytes from %d, but runtime is %d bytes", size, offset, len(bytecode)-offset)
+		}
+	})
+
+	t.Run("Calldata", func(t *testing.T) {
+		data := GasGriefCalldata(1000, true)
+		if len(data) != 64 || data[30] != 0x03 || data[31] != 0xe8 || data[63] != 1 {
+			t.Errorf("unexpected calldata %x", data)
+		}
+	})
+
+	t.Run("RevertPercentSetsReserve", func(t *testing.T) {
+		data, _ := GasGriefGenerator(200000, 1000, 25)(nil)
+		reserve := uint64(data[29])<<16 | uint64(data[30])<<8 | uint64(data[31])
+		if reserve != 150000 || data[63] != 1 {
+			t.Errorf("expected a reverting call with 150000 reserve, got %d (%x)", reserve, data)
+		}
+	})
+}
@@ -0,0 +1,51 @@
+package contract
+
+import (
+	"fmt"
+	mathrand "math/rand"
+)
+
+// MaxCodeSize is the EIP-170 limit on deployed runtime code
+const MaxCodeSize = 24576
+
+// largeInitCodeSize is the length of the init code that returns the runtime:
+// PUSH2 size, DUP1, PUSH1 offset, PUSH1 0, CODECOPY, PUSH1 0, RETURN
+const largeInitCodeSize = 12
+
+// LargeContractBytecode returns creation bytecode whose runtime code is size bytes: a
+// STOP followed by random padding, so every deployment stores distinct code
+func LargeContractBytecode(size int, rng *mathrand.Rand) ([]byte, error) {
+	if size < 1 || size > MaxCodeSize {
+		return nil, fmt.Errorf("code size must be between 1 and %d bytes (got: %d)", MaxCodeSize, size)
+	}
+	bytecode := make([]byte, largeInitCodeSize+size)
+	copy(bytecode, []byte{
+		0x61, byte(size >> 8), byte(size), // PUSH2 size
+		0x80,                    // DUP1
+		0x60, largeInitCodeSize, // PUSH1 offset
+		0x60, 0x00, // PUSH1 0
+		0x39,       // CODECOPY
+		0x60, 0x00, // PUSH1 0
+		0xf3, // RETURN
+	})
+	// The runtime starts with STOP (0x00), so the padding is never executed
+	rng.Read(bytecode[largeInitCodeSize+1:])
+	return bytecode, nil
+}
+
+// LargeContractGasLimit estimates the gas needed to deploy a contract of size bytes:
+// the creation cost, calldata, the 200 gas per byte code deposit, and headroom for
+// the init code's execution and memory expansion
+func LargeContractGasLimit(size int) uint64 {
+	const txCreateGas, depositGasPerByte, calldataGasPerByte, headroom = 53000, 200, 16, 20000
+	length := uint64(largeInitCodeSize + size)
+	return txCreateGas + length*calldataGasPerByte + uint64(size)*depositGasPerByte + headroom
+}
+
+// LargeContractGenerator returns an init code generator for the parallel sender that
+// deploys a fresh contract of size bytes with each transaction
+func LargeContractGenerator(size int) func(rng *mathrand.Rand) ([]byte, error) {
+	return func(rng *mathrand.Rand) ([]byte, error) {
+		return LargeContractBytecode(size, rng)
+	}
+}
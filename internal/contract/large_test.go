@@ -0,0 +1,45 @@
+package contract
+
+import (
+	"bytes"
+	mathrand "math/rand"
+	"testing"
+)
+
+func TestLargeContractBytecode(t *testing.T) {
+	rng := mathrand.New(mathrand.NewSource(1))
+
+	t.Run("InitCodeCopiesRuntime", func(t *testing.T) {
+		bytecode, err := LargeContractBytecode(MaxCodeSize, rng)
+		if err != nil {
+			t.Fatal(err)
+		}
+		size, offset := int(bytecode[1])<<8|int(bytecode[2]), int(bytecode[5])
+		if size != MaxCodeSize || offset+size != len(bytecode) {
+			t.Errorf("init code copies %d bytes from %d, but bytecode is %d bytes", size, offset, len(bytecode))
+		}
+		if bytecode[offset] != 0x00 {
+			t.Errorf("expected runtime to start with STOP, got %#x", bytecode[offset])
+		}
+	})
+
+	t.Run("DistinctCode", func(t *testing.T) {
+		a, _ := LargeContractBytecode(1024, rng)
+		b, _ := LargeContractBytecode(1024, rng)
+		if bytes.Equal(a, b) {
+			t.Error("expected each contract to have distinct padding")
+		}
+	})
+
+	t.Run("SizeLimit", func(t *testing.T) {
+		if _, err := LargeContractBytecode(MaxCodeSize+1, rng); err == nil {
+			t.Error("expected an error above the code-size limit")
+		}
+	})
+
+	t.Run("GasCoversDeposit", func(t *testing.T) {
+		if gas := LargeContractGasLimit(24000); gas < 53000+200*24000 || gas > 6000000 {
+			t.Errorf("unexpected gas estimate %d", gas)
+		}
+	})
+}
@@ -0,0 +1,139 @@
+package contract
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// ContractStats is what the run did with one contract
+type ContractStats struct {
+	Address      common.Address `json:"address"`
+	Label        string         `json:"label"`               // What the contract is, e.g. "storage" or "external"
+	DeployTx     *common.Hash   `json:"deployTx,omitempty"`  // Nil for contracts the run did not deploy
+	DeployGas    uint64         `json:"deployGas,omitempty"` // Gas used by the deployment, 0 when not waited for
+	Calls        int64          `json:"calls"`               // Calls the node accepted
+	SendFailures int64          `json:"sendFailures"`        // Calls given up on before the node accepted them
+	Mined        int64          `json:"mined"`               // Calls seen in a block
+	Reverted     int64          `json:"reverted"`            // Mined calls that reverted
+	GasUsed      uint64         `json:"gasUsed"`             // Total gas of mined calls
+}
+
+// AvgGas returns the average gas used by the mined calls
+func (s ContractStats) AvgGas() uint64 {
+	if s.Mined == 0 {
+		return 0
+	}
+	return s.GasUsed / uint64(s.Mined)
+}
+
+// Registry keeps the contracts of a run and what happened to the calls sent to
+// them, so a contract-heavy run shows which targets behaved badly. It satisfies
+// transaction.ContractObserver.
+type Registry struct {
+	mu        sync.Mutex
+	contracts map[common.Address]*ContractStats
+	order     []common.Address // Registration order, kept for the report
+}
+
+// NewRegistry creates an empty contract registry
+func NewRegistry() *Registry {
+	return &Registry{contracts: make(map[common.Address]*ContractStats)}
+}
+
+// Register adds a contract under label. Registering a known address keeps its stats.
+func (r *Registry) Register(address common.Address, label string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.register(address, label)
+}
+
+// register adds a contract; the caller holds the lock
+func (r *Registry) register(address common.Address, label string) *ContractStats {
+	stats, ok := r.contracts[address]
+	if !ok {
+		stats = &ContractStats{Address: address, Label: label}
+		r.contracts[address] = stats
+		r.order = append(r.order, address)
+	}
+	return stats
+}
+
+// RecordDeploy registers a contract deployed by the run. gasUsed is 0 when the
+// deployment was not waited for.
+func (r *Registry) RecordDeploy(address common.Address, label string, txHash common.Hash, gasUsed uint64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stats := r.register(address, label)
+	stats.DeployTx = &txHash
+	stats.DeployGas = gasUsed
+}
+
+// Watches reports whether address is a registered contract
+func (r *Registry) Watches(address common.Address) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	_, ok := r.contracts[address]
+	return ok
+}
+
+// RecordSent counts a call the node accepted
+func (r *Registry) RecordSent(address common.Address) {
+	r.update(address, func(s *ContractStats) { s.Calls++ })
+}
+
+// RecordSendFailure counts a call given up on before the node accepted it
+func (r *Registry) RecordSendFailure(address common.Address) {
+	r.update(address, func(s *ContractStats) { s.SendFailures++ })
+}
+
+// RecordReceipt counts a mined call and its gas
+func (r *Registry) RecordReceipt(address common.Address, receipt *types.Receipt) {
+	r.update(address, func(s *ContractStats) {
+		s.Mined++
+		s.GasUsed += receipt.GasUsed
+		if receipt.Status != types.ReceiptStatusSuccessful {
+			s.Reverted++
+		}
+	})
+}
+
+// update applies fn to a registered contract; calls to unknown addresses are ignored
+func (r *Registry) update(address common.Address, fn func(*ContractStats)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if stats, ok := r.contracts[address]; ok {
+		fn(stats)
+	}
+}
+
+// Stats returns a copy of every contract's stats in registration order, for the report
+func (r *Registry) Stats() []ContractStats {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stats := make([]ContractStats, 0, len(r.order))
+	for _, address := range r.order {
+		stats = append(stats, *r.contracts[address])
+	}
+	return stats
+}
+
+// PrintRegistry prints the calls, failures, reverts and gas of every contract
+func PrintRegistry(stats []ContractStats) {
+	fmt.Printf("\n=== Contracts ===\n")
+	for _, s := range stats {
+		fmt.Printf("%s (%s)\n", s.Address.Hex(), s.Label)
+		if s.DeployTx != nil {
+			fmt.Printf("  deployed in %s, gas used: %d\n", s.DeployTx.Hex(), s.DeployGas)
+		}
+		revertPercent := 0.0
+		if s.Mined > 0 {
+			revertPercent = float64(s.Reverted) * 100 / float64(s.Mined)
+		}
+		fmt.Printf("  calls: %d, send failures: %d, mined: %d, reverted: %d (%.1f%%), avg gas: %d\n",
+			s.Calls, s.SendFailures, s.Mined, s.Reverted, revertPercent, s.AvgGas())
+	}
+	fmt.Printf("==========================\n")
+}
@@ -0,0 +1,36 @@
+package contract
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+func TestRegistry(t *testing.T) {
+	registry := NewRegistry()
+	external, deployed := common.Address{0x01}, common.Address{0x02}
+	registry.Register(external, "external")
+	registry.RecordDeploy(deployed, "storage", common.Hash{0xaa}, 120000)
+
+	registry.RecordSent(external)
+	registry.RecordSent(external)
+	registry.RecordSendFailure(external)
+	registry.RecordReceipt(external, &types.Receipt{Status: types.ReceiptStatusSuccessful, GasUsed: 30000})
+	registry.RecordReceipt(external, &types.Receipt{Status: types.ReceiptStatusFailed, GasUsed: 50000})
+	registry.RecordSent(common.Address{0x03}) // Not registered, ignored
+
+	if registry.Watches(common.Address{0x03}) {
+		t.Error("expected unregistered addresses not to be watched")
+	}
+	stats := registry.Stats()
+	if len(stats) != 2 || stats[0].Address != external || stats[1].Address != deployed {
+		t.Fatalf("expected both contracts in registration order, got %+v", stats)
+	}
+	if s := stats[0]; s.Calls != 2 || s.SendFailures != 1 || s.Mined != 2 || s.Reverted != 1 || s.AvgGas() != 40000 {
+		t.Errorf("unexpected stats for the external contract: %+v", s)
+	}
+	if s := stats[1]; s.DeployTx == nil || s.DeployGas != 120000 || s.Label != "storage" {
+		t.Errorf("unexpected stats for the deployed contract: %+v", s)
+	}
+}
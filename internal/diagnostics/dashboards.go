@@ -0,0 +1,168 @@
+package diagnostics
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+)
+
+// DashboardOptions holds the options of `simulator dashboards export`
+type DashboardOptions struct {
+	Output     string // File the dashboard is written to, empty writes to stdout
+	Datasource string // Default Prometheus datasource UID, selectable in Grafana afterwards
+}
+
+// ParseDashboardsArgs parses `simulator dashboards export [--out file] [--datasource uid]`
+func ParseDashboardsArgs(args []string) (*DashboardOptions, error) {
+	if len(args) == 0 || args[0] != "export" {
+		return nil, errors.New("usage: simulator dashboards export [--out dashboard.json] [--datasource uid]")
+	}
+	fs := flag.NewFlagSet("dashboards export", flag.ContinueOnError)
+	output := fs.String("out", "", "file to write the dashboard JSON to, stdout when empty")
+	datasource := fs.String("datasource", "prometheus", "UID of the Prometheus datasource selected by default")
+	if err := fs.Parse(args[1:]); err != nil {
+		return nil, err
+	}
+	return &DashboardOptions{Output: *output, Datasource: *datasource}, nil
+}
+
+// dashboard is the subset of Grafana's dashboard model the export fills in
+type dashboard struct {
+	UID           string     `json:"uid"`
+	Title         string     `json:"title"`
+	Tags          []string   `json:"tags"`
+	SchemaVersion int        `json:"schemaVersion"`
+	Refresh       string     `json:"refresh"`
+	Time          timeRange  `json:"time"`
+	Templating    templating `json:"templating"`
+	Panels        []panel    `json:"panels"`
+}
+
+type timeRange struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+type templating struct {
+	List []variable `json:"list"`
+}
+
+type variable struct {
+	Name    string            `json:"name"`
+	Label   string            `json:"label"`
+	Type    string            `json:"type"`
+	Query   string            `json:"query"`
+	Current map[string]string `json:"current"`
+}
+
+type panel struct {
+	ID          int         `json:"id"`
+	Type        string      `json:"type"`
+	Title       string      `json:"title"`
+	GridPos     gridPos     `json:"gridPos"`
+	Datasource  datasource  `json:"datasource"`
+	FieldConfig fieldConfig `json:"fieldConfig"`
+	Targets     []target    `json:"targets"`
+}
+
+type gridPos struct {
+	H int `json:"h"`
+	W int `json:"w"`
+	X int `json:"x"`
+	Y int `json:"y"`
+}
+
+type datasource struct {
+	Type string `json:"type"`
+	UID  string `json:"uid"`
+}
+
+type fieldConfig struct {
+	Defaults struct {
+		Unit string `json:"unit"`
+	} `json:"defaults"`
+}
+
+type target struct {
+	RefID        string     `json:"refId"`
+	Datasource   datasource `json:"datasource"`
+	Expr         string     `json:"expr"`
+	LegendFormat string     `json:"legendFormat"`
+}
+
+// Dashboard builds a Grafana dashboard with one time series panel per schema
+// panel, in schema order. Counters are charted as per-second rates and gauges as
+// they are; every series is split by the instance label, so the agents of a
+// distributed run show up side by side.
+func Dashboard(datasourceUID string) ([]byte, error) {
+	ds := datasource{Type: "prometheus", UID: "${datasource}"}
+	d := dashboard{
+		UID:           "ets-simulator",
+		Title:         "Ethereum Transaction Simulator",
+		Tags:          []string{"ethereum", "load-test"},
+		SchemaVersion: 38,
+		Refresh:       "5s",
+		Time:          timeRange{From: "now-30m", To: "now"},
+		Templating: templating{List: []variable{{
+			Name:    "datasource",
+			Label:   "Prometheus",
+			Type:    "datasource",
+			Query:   "prometheus",
+			Current: map[string]string{"value": datasourceUID},
+		}}},
+	}
+
+	panels := make(map[string]*panel)
+	var order []string
+	for _, m := range Schema {
+		p, ok := panels[m.Panel]
+		if !ok {
+			i := len(order)
+			p = &panel{
+				ID:         i + 1,
+				Type:       "timeseries",
+				Title:      m.Panel,
+				GridPos:    gridPos{H: 8, W: 12, X: (i % 2) * 12, Y: (i / 2) * 8},
+				Datasource: ds,
+			}
+			p.FieldConfig.Defaults.Unit = m.Unit
+			panels[m.Panel] = p
+			order = append(order, m.Panel)
+		}
+		expr := m.Name
+		if m.Type == Counter {
+			expr = fmt.Sprintf("rate(%s[$__rate_interval])", m.Name)
+		}
+		p.Targets = append(p.Targets, target{
+			RefID:        string(rune('A' + len(p.Targets))),
+			Datasource:   ds,
+			Expr:         expr,
+			LegendFormat: m.Help + " {{instance}}",
+		})
+	}
+	for _, title := range order {
+		d.Panels = append(d.Panels, *panels[title])
+	}
+	return json.MarshalIndent(d, "", "  ")
+}
+
+// ExportDashboards writes the dashboard to opts.Output, or to stdout
+func ExportDashboards(opts *DashboardOptions, stdout io.Writer) error {
+	data, err := Dashboard(opts.Datasource)
+	if err != nil {
+		return fmt.Errorf("failed to encode dashboard: %w", err)
+	}
+	data = append(data, '\n')
+	if opts.Output == "" {
+		_, err = stdout.Write(data)
+		return err
+	}
+	if err := os.WriteFile(opts.Output, data, 0644); err != nil {
+		return fmt.Errorf("failed to write dashboard: %w", err)
+	}
+	fmt.Printf("Dashboard written to %s; import it in Grafana under Dashboards > New > Import\n", opts.Output)
+	return nil
+}
@@ -0,0 +1,50 @@
+package diagnostics
+
+import (
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+// Health backs the /healthz and /readyz probes of a long-running simulator
+type Health struct {
+	ready atomic.Bool
+}
+
+// SetReady marks the simulator as ready (connected and funded) or not ready
+func (h *Health) SetReady(ready bool) {
+	h.ready.Store(ready)
+}
+
+// StartHealth serves /healthz (the process is alive) and /readyz (SetReady(true) was
+// called) on addr for Kubernetes liveness and readiness probes
+func StartHealth(addr string, h *Health) (*http.Server, error) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintln(w, "ok")
+	})
+	mux.HandleFunc("/readyz", func(w http.ResponseWriter, r *http.Request) {
+		if !h.ready.Load() {
+			http.Error(w, "not ready", http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprintln(w, "ready")
+	})
+
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on %s: %w", addr, err)
+	}
+
+	server := &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		if err := server.Serve(listener); err != nil && err != http.ErrServerClosed {
+			log.Printf("Health server stopped: %v", err)
+		}
+	}()
+	return server, nil
+}
@@ -0,0 +1,87 @@
+package diagnostics
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// Metric types of the Prometheus exposition format
+const (
+	Counter = "counter"
+	Gauge   = "gauge"
+)
+
+// Metric maps one published value to a Prometheus series
+type Metric struct {
+	Name  string // Prometheus name
+	Help  string
+	Type  string // Counter or Gauge
+	Var   string // Name the value is published under
+	Key   string // Entry of a published map[string]int64, empty for a plain number
+	Panel string // Dashboard panel the series is charted in
+	Unit  string // Grafana unit of the panel
+}
+
+// Schema lists every series /metrics exposes. Dashboards are generated from it, so a
+// value published without a schema entry shows up in /debug/vars only.
+var Schema = []Metric{
+	{Name: "simulator_transactions_sent_total", Help: "Transactions the node accepted", Type: Counter, Var: "parallel", Key: "sent", Panel: "Transaction throughput", Unit: "ops"},
+	{Name: "simulator_transactions_succeeded_total", Help: "Sent transactions that reached the success criterion", Type: Counter, Var: "parallel", Key: "succeeded", Panel: "Transaction throughput", Unit: "ops"},
+	{Name: "simulator_transactions_failed_total", Help: "Transactions that failed to send or verify", Type: Counter, Var: "parallel", Key: "failed", Panel: "Failures", Unit: "ops"},
+	{Name: "simulator_transactions_rejected_total", Help: "Transactions the node refused", Type: Counter, Var: "parallel", Key: "rejected", Panel: "Failures", Unit: "ops"},
+	{Name: "simulator_transactions_submitted_total", Help: "Send attempts, including retries", Type: Counter, Var: "parallel", Key: "submitted", Panel: "Send attempts", Unit: "ops"},
+	{Name: "simulator_transactions_reserved_total", Help: "Transactions a wallet has been assigned a nonce for", Type: Counter, Var: "parallel", Key: "reserved", Panel: "Send attempts", Unit: "ops"},
+	{Name: "simulator_transactions_in_flight", Help: "Sent transactions not yet seen in a block", Type: Gauge, Var: "parallel", Key: "inFlight", Panel: "In flight", Unit: "short"},
+	{Name: "simulator_event_assertions_checked_total", Help: "Receipts checked against the expected events", Type: Counter, Var: "parallel", Key: "eventsChecked", Panel: "Event assertions", Unit: "ops"},
+	{Name: "simulator_event_assertions_failed_total", Help: "Receipts missing an expected event", Type: Counter, Var: "parallel", Key: "eventAssertionsFailed", Panel: "Event assertions", Unit: "ops"},
+	{Name: "simulator_goroutines", Help: "Goroutines of the simulator process", Type: Gauge, Var: "goroutines", Panel: "Goroutines", Unit: "short"},
+}
+
+// WriteMetrics writes the current value of every schema series in the Prometheus
+// text format. Series whose value is not published, e.g. the parallel counters
+// before a run starts, are left out.
+func WriteMetrics(w io.Writer) error {
+	publishedMu.RLock()
+	values := make(map[string]interface{}, len(published))
+	for name, f := range published {
+		values[name] = f()
+	}
+	publishedMu.RUnlock()
+
+	for _, m := range Schema {
+		value, ok := metricValue(values[m.Var], m.Key)
+		if !ok {
+			continue
+		}
+		if _, err := fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n%s %d\n", m.Name, m.Help, m.Name, m.Type, m.Name, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// metricValue extracts a series' value from a published value
+func metricValue(value interface{}, key string) (int64, bool) {
+	if key != "" {
+		entries, ok := value.(map[string]int64)
+		if !ok {
+			return 0, false
+		}
+		v, ok := entries[key]
+		return v, ok
+	}
+	switch v := value.(type) {
+	case int:
+		return int64(v), true
+	case int64:
+		return v, true
+	}
+	return 0, false
+}
+
+// handleMetrics serves the schema series for Prometheus to scrape
+func handleMetrics(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	WriteMetrics(w)
+}
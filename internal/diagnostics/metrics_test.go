@@ -0,0 +1,64 @@
+package diagnostics
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestWriteMetrics(t *testing.T) {
+	Publish("parallel", func() interface{} { return map[string]int64{"sent": 42, "failed": 3} })
+
+	var buf bytes.Buffer
+	if err := WriteMetrics(&buf); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	out := buf.String()
+	for _, line := range []string{
+		"# TYPE simulator_transactions_sent_total counter\nsimulator_transactions_sent_total 42\n",
+		"simulator_transactions_failed_total 3\n",
+		"# TYPE simulator_goroutines gauge\n",
+	} {
+		if !strings.Contains(out, line) {
+			t.Errorf("expected %q in:\n%s", line, out)
+		}
+	}
+	if strings.Contains(out, "simulator_transactions_in_flight") {
+		t.Error("expected unpublished series to be left out")
+	}
+}
+
+func TestDashboard(t *testing.T) {
+	data, err := Dashboard("prom")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var d dashboard
+	if err := json.Unmarshal(data, &d); err != nil {
+		t.Fatalf("invalid dashboard JSON: %v", err)
+	}
+
+	charted := make(map[string]bool)
+	for _, p := range d.Panels {
+		for _, target := range p.Targets {
+			for _, m := range Schema {
+				if strings.Contains(target.Expr, m.Name+"[") || target.Expr == m.Name {
+					charted[m.Name] = true
+				}
+			}
+		}
+	}
+	for _, m := range Schema {
+		if !charted[m.Name] {
+			t.Errorf("%s is not charted", m.Name)
+		}
+	}
+	if d.Templating.List[0].Current["value"] != "prom" {
+		t.Errorf("expected the default datasource to be prom, got %v", d.Templating.List[0].Current)
+	}
+
+	if _, err := ParseDashboardsArgs([]string{"--out", "d.json"}); err == nil {
+		t.Error("expected an error without the export action")
+	}
+}
@@ -0,0 +1,102 @@
+package diagnostics
+
+import (
+	"expvar"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"net/http/pprof"
+	"runtime"
+	"sync"
+	"time"
+)
+
+func init() {
+	Publish("goroutines", func() interface{} { return runtime.NumGoroutine() })
+}
+
+// Start serves pprof under /debug/pprof/, expvar under /debug/vars and the Schema
+// series under /metrics on addr so the simulator itself can be profiled and
+// scraped during high-concurrency runs. The endpoints are unauthenticated, so addr
+// should normally be a loopback address.
+func Start(addr string) (*http.Server, error) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	mux.Handle("/debug/vars", expvar.Handler())
+	mux.HandleFunc("/metrics", handleMetrics)
+	mux.HandleFunc("/reload", handleReload)
+
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on %s: %w", addr, err)
+	}
+
+	server := &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		if err := server.Serve(listener); err != nil && err != http.ErrServerClosed {
+			log.Printf("Debug server stopped: %v", err)
+		}
+	}()
+	log.Printf("Debug endpoints on http://%s/debug/pprof/ and http://%s/debug/vars", listener.Addr(), listener.Addr())
+	return server, nil
+}
+
+var (
+	reloadMu sync.Mutex
+	reload   func()
+)
+
+// OnReload makes POST /reload call f, so operators can reload the configuration of
+// a running process the same way as with SIGHUP
+func OnReload(f func()) {
+	reloadMu.Lock()
+	defer reloadMu.Unlock()
+	reload = f
+}
+
+// handleReload requests a reload; it only accepts POST so a crawler or a stray
+// browser visit cannot trigger one
+func handleReload(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	reloadMu.Lock()
+	f := reload
+	reloadMu.Unlock()
+	if f == nil {
+		http.Error(w, "reload not supported by this mode", http.StatusNotFound)
+		return
+	}
+	f()
+	w.WriteHeader(http.StatusAccepted)
+	fmt.Fprintln(w, "reload requested")
+}
+
+var (
+	published   = make(map[string]func() interface{})
+	publishedMu sync.RWMutex
+)
+
+// Publish exposes the value returned by f under name in /debug/vars. Publishing a
+// name again replaces its function instead of panicking like expvar.Publish, so a
+// new run can take over the counters of the previous one.
+func Publish(name string, f func() interface{}) {
+	publishedMu.Lock()
+	defer publishedMu.Unlock()
+	if _, ok := published[name]; !ok {
+		expvar.Publish(name, expvar.Func(func() interface{} {
+			publishedMu.RLock()
+			current := published[name]
+			publishedMu.RUnlock()
+			return current()
+		}))
+	}
+	published[name] = f
+}
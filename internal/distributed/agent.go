@@ -0,0 +1,114 @@
+package distributed
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+)
+
+// Agent is a simulator instance running one shard of a coordinated scenario
+type Agent struct {
+	conn       *grpc.ClientConn
+	Assignment Assignment
+	Clock      ClockOffset // Coordinator clock minus local clock
+	started    time.Time   // When the common start was reached, with its monotonic reading
+	stop       chan struct{}
+	stopOnce   sync.Once
+}
+
+// Join connects to the coordinator at addr, proves it knows secret, measures the
+// offset between the two clocks and registers for a shard. ntp is the local clock's
+// NTP offset taken at run start, nil if it was not measured. The agent sends
+// heartbeats until it is closed.
+func Join(addr, name, secret string, ntp *ClockOffset) (*Agent, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), handshakeTimeout)
+	defer cancel()
+	conn, err := grpc.DialContext(ctx, addr,
+		grpc.WithTransportCredentials(&secretCredentials{secret: []byte(secret)}),
+		grpc.WithDefaultCallOptions(grpc.CallContentSubtype(codecName)),
+		grpc.WithBlock(),
+		grpc.FailOnNonTempDialError(true))
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to coordinator: %w", err)
+	}
+
+	agent := &Agent{conn: conn, stop: make(chan struct{})}
+	if agent.Clock, err = measureCoordinatorOffset(conn); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	args := &RegisterArgs{Name: name, Coordinator: agent.Clock, NTP: ntp}
+	if err := conn.Invoke(ctx, fullMethod("Register"), args, &agent.Assignment); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to register with coordinator: %w", err)
+	}
+	go agent.heartbeat()
+	return agent, nil
+}
+
+// heartbeat tells the coordinator the agent is alive until the agent is closed
+func (a *Agent) heartbeat() {
+	ticker := time.NewTicker(heartbeatInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-a.stop:
+			return
+		case <-ticker.C:
+			// A lost heartbeat is made up by the next one
+			ctx, cancel := context.WithTimeout(context.Background(), heartbeatInterval)
+			a.conn.Invoke(ctx, fullMethod("Heartbeat"), &HeartbeatArgs{AgentID: a.Assignment.AgentID}, &empty{})
+			cancel()
+		}
+	}
+}
+
+// WaitStart blocks until all agents have joined, then waits until the common start
+// time. The start time is on the coordinator's clock and is converted to the local
+// one with the measured offset, so agents start together even if their clocks do not
+// agree. It returns early with the context's error when ctx is cancelled.
+func (a *Agent) WaitStart(ctx context.Context) error {
+	var startAt time.Time
+	if err := a.conn.Invoke(ctx, fullMethod("WaitStart"), &empty{}, &startAt); err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return fmt.Errorf("failed to get start time: %w", err)
+	}
+
+	timer := time.NewTimer(time.Until(startAt.Add(-a.Clock.Offset)))
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	a.started = time.Now()
+	return nil
+}
+
+// Report sends the agent's current metrics; done marks the agent as finished
+func (a *Agent) Report(sent, succeeded, failed int64, done bool) error {
+	metrics := Metrics{
+		AgentID:   a.Assignment.AgentID,
+		Sent:      sent,
+		Succeeded: succeeded,
+		Failed:    failed,
+		Done:      done,
+	}
+	if !a.started.IsZero() {
+		metrics.Elapsed = time.Since(a.started)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), handshakeTimeout)
+	defer cancel()
+	return a.conn.Invoke(ctx, fullMethod("Report"), &metrics, &empty{})
+}
+
+// Close stops the heartbeats and closes the connection to the coordinator
+func (a *Agent) Close() {
+	a.stopOnce.Do(func() { close(a.stop) })
+	a.conn.Close()
+}
@@ -0,0 +1,134 @@
+package distributed
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+
+	"google.golang.org/grpc"
+)
+
+// ntpEpochOffset is the number of seconds between the NTP epoch (1900) and the Unix epoch
+const ntpEpochOffset = 2208988800
+
+// clockSamples is the number of round trips used to measure an offset; the one
+// with the shortest round trip is kept, as its midpoint is the most certain
+const clockSamples = 5
+
+// ClockOffset is how far a reference clock is ahead of the local one, measured at
+// run start. Latencies are timed on each machine's monotonic clock; the offset is
+// only needed to line up wall-clock timestamps from different machines.
+type ClockOffset struct {
+	Source string        // NTP server, or "coordinator"
+	Offset time.Duration // Reference time minus local time
+	RTT    time.Duration // Round trip of the sample the offset was taken from
+}
+
+// String formats the offset for logs and summaries
+func (o ClockOffset) String() string {
+	return fmt.Sprintf("%+.1fms vs %s (rtt %.1fms)", ms(o.Offset), o.Source, ms(o.RTT))
+}
+
+// ms converts d to fractional milliseconds
+func ms(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+// QueryNTP measures the local clock's offset from an SNTP server such as
+// pool.ntp.org. server may omit the port.
+func QueryNTP(server string, timeout time.Duration) (ClockOffset, error) {
+	addr := server
+	if _, _, err := net.SplitHostPort(server); err != nil {
+		addr = net.JoinHostPort(server, "123")
+	}
+	conn, err := net.DialTimeout("udp", addr, timeout)
+	if err != nil {
+		return ClockOffset{}, fmt.Errorf("failed to reach NTP server %s: %w", server, err)
+	}
+	defer conn.Close()
+
+	var best ClockOffset
+	for i := 0; i < clockSamples; i++ {
+		if err := conn.SetDeadline(time.Now().Add(timeout)); err != nil {
+			return ClockOffset{}, err
+		}
+		request := make([]byte, 48)
+		request[0] = 0x23 // Leap indicator 0, version 4, client mode
+		sent := time.Now()
+		binary.BigEndian.PutUint64(request[40:], toNTPTime(sent))
+		if _, err := conn.Write(request); err != nil {
+			return ClockOffset{}, fmt.Errorf("NTP request to %s failed: %w", server, err)
+		}
+		response := make([]byte, 48)
+		if _, err := conn.Read(response); err != nil {
+			return ClockOffset{}, fmt.Errorf("no NTP response from %s: %w", server, err)
+		}
+		// The round trip comes from the monotonic clock, so a wall-clock step
+		// during the exchange cannot distort it
+		received := sent.Add(time.Since(sent))
+
+		offset, rtt, err := ntpOffset(response, sent, received)
+		if err != nil {
+			return ClockOffset{}, fmt.Errorf("NTP server %s: %w", server, err)
+		}
+		if i == 0 || rtt < best.RTT {
+			best = ClockOffset{Source: server, Offset: offset, RTT: rtt}
+		}
+	}
+	return best, nil
+}
+
+// ntpOffset computes the offset and round trip of one SNTP exchange (RFC 4330)
+func ntpOffset(response []byte, sent, received time.Time) (offset, rtt time.Duration, err error) {
+	if len(response) < 48 {
+		return 0, 0, errors.New("short response")
+	}
+	if response[1] == 0 {
+		return 0, 0, errors.New("kiss-of-death response")
+	}
+	serverReceived := fromNTPTime(binary.BigEndian.Uint64(response[32:]))
+	serverSent := fromNTPTime(binary.BigEndian.Uint64(response[40:]))
+	offset = (serverReceived.Sub(sent) + serverSent.Sub(received)) / 2
+	rtt = received.Sub(sent) - serverSent.Sub(serverReceived)
+	return offset, rtt, nil
+}
+
+// toNTPTime encodes t as a 64-bit NTP timestamp
+func toNTPTime(t time.Time) uint64 {
+	seconds := uint64(t.Unix() + ntpEpochOffset)
+	fraction := uint64(t.Nanosecond()) << 32 / uint64(time.Second)
+	return seconds<<32 | fraction
+}
+
+// fromNTPTime decodes a 64-bit NTP timestamp
+func fromNTPTime(ts uint64) time.Time {
+	seconds := int64(ts>>32) - ntpEpochOffset
+	nanos := (ts & 0xffffffff) * uint64(time.Second) >> 32
+	return time.Unix(seconds, int64(nanos))
+}
+
+// measureCoordinatorOffset measures how far the coordinator's clock is ahead of
+// the local one over the gRPC connection, so its start time can be converted to
+// local time even when the machines' clocks disagree
+func measureCoordinatorOffset(conn *grpc.ClientConn) (ClockOffset, error) {
+	var best ClockOffset
+	for i := 0; i < clockSamples; i++ {
+		var remote time.Time
+		ctx, cancel := context.WithTimeout(context.Background(), handshakeTimeout)
+		sent := time.Now()
+		err := conn.Invoke(ctx, fullMethod("Clock"), &empty{}, &remote)
+		cancel()
+		if err != nil {
+			return ClockOffset{}, fmt.Errorf("failed to read coordinator clock: %w", err)
+		}
+		rtt := time.Since(sent)
+		offset := remote.Sub(sent.Add(rtt / 2))
+		if i == 0 || rtt < best.RTT {
+			best = ClockOffset{Source: "coordinator", Offset: offset, RTT: rtt}
+		}
+	}
+	return best, nil
+}
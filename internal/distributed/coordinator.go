@@ -0,0 +1,317 @@
+package distributed
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+)
+
+// Assignment is the share of a scenario handed to one agent
+type Assignment struct {
+	AgentID         int
+	Agents          int
+	WalletOffset    int // First wallet index (into the shared wallet file) owned by this agent
+	WalletCount     int // Number of wallets owned by this agent
+	MaxTransactions int // This agent's share of the transaction cap (0 = unlimited)
+}
+
+// skewWarning is the spread of agent clocks above which the summary warns that
+// their wall-clock timestamps should not be compared directly
+const skewWarning = 50 * time.Millisecond
+
+const (
+	heartbeatInterval = 5 * time.Second  // How often agents tell the coordinator they are alive
+	heartbeatTimeout  = 30 * time.Second // Silence after which the coordinator gives an agent up
+)
+
+// RegisterArgs identifies an agent joining the coordinator
+type RegisterArgs struct {
+	Name        string
+	Coordinator ClockOffset  // Coordinator clock minus agent clock
+	NTP         *ClockOffset // NTP time minus agent clock at run start, nil if not measured
+}
+
+// Metrics is an agent's progress report
+type Metrics struct {
+	AgentID   int
+	Sent      int64
+	Succeeded int64
+	Failed    int64
+	Elapsed   time.Duration // Since the agent started, on its monotonic clock
+	Done      bool
+}
+
+// Coordinator shards a scenario across agents, releases them at a common start time
+// and aggregates their metrics. Agents talk to it over gRPC once they have proven
+// they know the shared secret.
+type Coordinator struct {
+	agents          int
+	wallets         int
+	maxTransactions int
+	startDelay      time.Duration
+	secret          []byte
+	timeout         time.Duration // Heartbeat silence after which an agent is given up
+
+	mu         sync.Mutex
+	registered int
+	names      []string
+	clocks     []RegisterArgs
+	lastSeen   []time.Time
+	startAt    time.Time
+	ready      chan struct{}
+	metrics    map[int]Metrics
+	done       chan struct{}
+	closed     bool
+}
+
+// NewCoordinator creates a coordinator for agents agents sharing wallets wallets and
+// maxTransactions transactions. Agents start startDelay after the last one registers,
+// and must know secret to connect.
+func NewCoordinator(agents, wallets, maxTransactions int, startDelay time.Duration, secret string) (*Coordinator, error) {
+	if agents <= 0 {
+		return nil, errors.New("agent count must be greater than 0")
+	}
+	if wallets < agents {
+		return nil, fmt.Errorf("need at least one wallet per agent (wallets: %d, agents: %d)", wallets, agents)
+	}
+	// A zero share would mean unlimited for that agent
+	if maxTransactions > 0 && maxTransactions < agents {
+		return nil, fmt.Errorf("need at least one transaction per agent (max transactions: %d, agents: %d)", maxTransactions, agents)
+	}
+	if secret == "" {
+		return nil, errors.New("coordinator secret must not be empty")
+	}
+	return &Coordinator{
+		agents:          agents,
+		wallets:         wallets,
+		maxTransactions: maxTransactions,
+		startDelay:      startDelay,
+		secret:          []byte(secret),
+		timeout:         heartbeatTimeout,
+		ready:           make(chan struct{}),
+		metrics:         make(map[int]Metrics),
+		done:            make(chan struct{}),
+	}, nil
+}
+
+// Register assigns the next disjoint wallet range to a joining agent
+func (c *Coordinator) Register(_ context.Context, args *RegisterArgs) (*Assignment, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.registered >= c.agents {
+		return nil, fmt.Errorf("all %d agent slots are taken", c.agents)
+	}
+
+	id := c.registered
+	offset, count := Shard(c.wallets, c.agents, id)
+	_, maxTx := Shard(c.maxTransactions, c.agents, id)
+	reply := &Assignment{
+		AgentID:         id,
+		Agents:          c.agents,
+		WalletOffset:    offset,
+		WalletCount:     count,
+		MaxTransactions: maxTx,
+	}
+	c.registered++
+	c.names = append(c.names, args.Name)
+	c.clocks = append(c.clocks, *args)
+	c.lastSeen = append(c.lastSeen, time.Now())
+	log.Printf("Agent %d (%s) registered: wallets %d-%d, clock %s", id, args.Name, offset, offset+count-1, args.Coordinator)
+
+	if c.registered == c.agents {
+		c.startAt = time.Now().Add(c.startDelay)
+		close(c.ready)
+		log.Printf("All %d agents registered, starting at %s", c.agents, c.startAt.Format(time.RFC3339))
+	}
+	return reply, nil
+}
+
+// Clock returns the coordinator's current time, for agents measuring their offset
+func (c *Coordinator) Clock(context.Context, *empty) (*time.Time, error) {
+	now := time.Now()
+	return &now, nil
+}
+
+// WaitStart blocks until every agent has registered, or the agent gives up, and
+// returns the common start time
+func (c *Coordinator) WaitStart(ctx context.Context, _ *empty) (*time.Time, error) {
+	select {
+	case <-c.ready:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	startAt := c.startAt
+	return &startAt, nil
+}
+
+// Heartbeat records that the agent with the given ID is alive
+func (c *Coordinator) Heartbeat(_ context.Context, args *HeartbeatArgs) (*empty, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return &empty{}, c.seen(args.AgentID)
+}
+
+// seen records contact from the agent with the given ID; the caller holds mu
+func (c *Coordinator) seen(agentID int) error {
+	if agentID < 0 || agentID >= c.registered {
+		return fmt.Errorf("unknown agent %d", agentID)
+	}
+	c.lastSeen[agentID] = time.Now()
+	return nil
+}
+
+// Report records an agent's latest metrics
+func (c *Coordinator) Report(_ context.Context, metrics *Metrics) (*empty, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.seen(metrics.AgentID); err != nil {
+		return nil, err
+	}
+	c.metrics[metrics.AgentID] = *metrics
+
+	finished := 0
+	for _, m := range c.metrics {
+		if m.Done {
+			finished++
+		}
+	}
+	if finished == c.agents && !c.closed {
+		c.closed = true
+		close(c.done)
+	}
+	return &empty{}, nil
+}
+
+// Serve serves agents over gRPC on listener until it is closed, then drops their
+// connections. Connections that do not prove they know the secret are closed
+// before any call is served.
+func (c *Coordinator) Serve(listener net.Listener) error {
+	server := grpc.NewServer(grpc.Creds(&secretCredentials{secret: c.secret}))
+	server.RegisterService(&coordinatorService, c)
+	defer server.Stop()
+	if err := server.Serve(listener); err != nil && !errors.Is(err, net.ErrClosed) {
+		return err
+	}
+	return nil
+}
+
+// Wait blocks until every agent has reported Done. It fails when a registered agent
+// that has not finished sends no heartbeat for the heartbeat timeout, or when the
+// context is cancelled.
+func (c *Coordinator) Wait(ctx context.Context) error {
+	ticker := time.NewTicker(c.timeout / 4)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-c.done:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			if err := c.checkAlive(); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// checkAlive returns an error for the first unfinished agent silent for longer than the timeout
+func (c *Coordinator) checkAlive() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for id := 0; id < c.registered; id++ {
+		if c.metrics[id].Done {
+			continue
+		}
+		if silent := time.Since(c.lastSeen[id]); silent > c.timeout {
+			return fmt.Errorf("agent %d (%s) sent no heartbeat for %s", id, c.names[id], silent.Round(time.Second))
+		}
+	}
+	return nil
+}
+
+// Totals aggregates the latest metrics of all agents
+func (c *Coordinator) Totals() Metrics {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var total Metrics
+	for _, m := range c.metrics {
+		total.Sent += m.Sent
+		total.Succeeded += m.Succeeded
+		total.Failed += m.Failed
+		if m.Elapsed > total.Elapsed {
+			total.Elapsed = m.Elapsed
+		}
+	}
+	return total
+}
+
+// PrintTotals prints the aggregated metrics per agent and overall
+func (c *Coordinator) PrintTotals() {
+	c.mu.Lock()
+	fmt.Printf("\n=== Distributed Run Summary ===\n")
+	for id := 0; id < c.registered; id++ {
+		m := c.metrics[id]
+		fmt.Printf("Agent %d (%s): sent %d, succeeded %d, failed %d in %s\n", id, c.names[id], m.Sent, m.Succeeded, m.Failed, m.Elapsed.Round(time.Millisecond))
+		line := fmt.Sprintf("  clock %s", c.clocks[id].Coordinator)
+		if ntp := c.clocks[id].NTP; ntp != nil {
+			line += fmt.Sprintf(", %s", ntp)
+		}
+		fmt.Println(line)
+	}
+	skew := ClockSkew(c.clocks)
+	c.mu.Unlock()
+
+	total := c.Totals()
+	fmt.Printf("Total sent: %d, succeeded: %d, failed: %d\n", total.Sent, total.Succeeded, total.Failed)
+	if total.Elapsed > 0 {
+		fmt.Printf("Aggregate rate: %.1f tx/s over %s\n", float64(total.Sent)/total.Elapsed.Seconds(), total.Elapsed.Round(time.Millisecond))
+	}
+	if skew > skewWarning {
+		fmt.Printf("Warning: agent clocks differ by %s; start times were corrected, but compare their wall-clock timestamps with care\n", skew.Round(time.Millisecond))
+	}
+	fmt.Printf("==========================\n")
+}
+
+// ClockSkew returns the spread of the agents' clocks: the largest difference
+// between their offsets from the coordinator
+func ClockSkew(clocks []RegisterArgs) time.Duration {
+	if len(clocks) == 0 {
+		return 0
+	}
+	lo, hi := clocks[0].Coordinator.Offset, clocks[0].Coordinator.Offset
+	for _, clock := range clocks[1:] {
+		if clock.Coordinator.Offset < lo {
+			lo = clock.Coordinator.Offset
+		}
+		if clock.Coordinator.Offset > hi {
+			hi = clock.Coordinator.Offset
+		}
+	}
+	return hi - lo
+}
+
+// Shard splits total into parts as evenly as possible and returns the offset and size
+// of part i. The first total%parts parts get one extra item.
+func Shard(total, parts, i int) (offset, count int) {
+	base, extra := total/parts, total%parts
+	count = base
+	if i < extra {
+		count++
+	}
+	offset = i * base
+	if i < extra {
+		offset += i
+	} else {
+		offset += extra
+	}
+	return offset, count
+}
@@ -0,0 +1,182 @@
+package distributed
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestShard(t *testing.T) {
+	t.Run("DisjointAndComplete", func(t *testing.T) {
+		next := 0
+		for i := 0; i < 3; i++ {
+			offset, count := Shard(10, 3, i)
+			if offset != next {
+				t.Errorf("part %d: expected offset %d, got %d", i, next, offset)
+			}
+			next = offset + count
+		}
+		if next != 10 {
+			t.Errorf("expected shards to cover 10 items, covered %d", next)
+		}
+	})
+
+	t.Run("Unlimited", func(t *testing.T) {
+		if _, count := Shard(0, 4, 2); count != 0 {
+			t.Errorf("expected 0 to stay unlimited, got %d", count)
+		}
+	})
+}
+
+func TestClockSkew(t *testing.T) {
+	clocks := []RegisterArgs{
+		{Coordinator: ClockOffset{Offset: 20 * time.Millisecond}},
+		{Coordinator: ClockOffset{Offset: -15 * time.Millisecond}},
+		{Coordinator: ClockOffset{Offset: 5 * time.Millisecond}},
+	}
+	if skew := ClockSkew(clocks); skew != 35*time.Millisecond {
+		t.Errorf("expected 35ms, got %s", skew)
+	}
+	if skew := ClockSkew(nil); skew != 0 {
+		t.Errorf("expected 0 without agents, got %s", skew)
+	}
+}
+
+func TestNTPOffset(t *testing.T) {
+	t.Run("RoundTrip", func(t *testing.T) {
+		now := time.Unix(1700000000, 123456789)
+		if got := fromNTPTime(toNTPTime(now)); got.Sub(now).Abs() > time.Microsecond {
+			t.Errorf("expected %s, got %s", now, got)
+		}
+	})
+
+	t.Run("ServerAhead", func(t *testing.T) {
+		sent := time.Unix(1700000000, 0)
+		received := sent.Add(40 * time.Millisecond)
+		response := make([]byte, 48)
+		response[1] = 2 // Stratum
+		// The server is 100ms ahead and takes 10ms to answer, with a 15ms trip each way
+		binary.BigEndian.PutUint64(response[32:], toNTPTime(sent.Add(115*time.Millisecond)))
+		binary.BigEndian.PutUint64(response[40:], toNTPTime(sent.Add(125*time.Millisecond)))
+		offset, rtt, err := ntpOffset(response, sent, received)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if (offset-100*time.Millisecond).Abs() > time.Microsecond || (rtt-30*time.Millisecond).Abs() > time.Microsecond {
+			t.Errorf("expected 100ms offset and 30ms rtt, got %s and %s", offset, rtt)
+		}
+	})
+
+	t.Run("KissOfDeath", func(t *testing.T) {
+		if _, _, err := ntpOffset(make([]byte, 48), time.Now(), time.Now()); err == nil {
+			t.Error("expected stratum 0 to be rejected")
+		}
+	})
+}
+
+// listen serves coordinator on a local TCP port and returns its address
+func listen(t *testing.T, coordinator *Coordinator) string {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+	go coordinator.Serve(listener)
+	return listener.Addr().String()
+}
+
+func TestCoordinatorOverListener(t *testing.T) {
+	t.Run("RegisterWaitStartReport", func(t *testing.T) {
+		coordinator, err := NewCoordinator(2, 10, 100, 0, "secret")
+		if err != nil {
+			t.Fatal(err)
+		}
+		addr := listen(t, coordinator)
+
+		agents := make([]*Agent, 2)
+		for i := range agents {
+			agent, err := Join(addr, "agent", "secret", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer agent.Close()
+			agents[i] = agent
+		}
+		if a, b := agents[0].Assignment, agents[1].Assignment; a.WalletCount != 5 || b.WalletOffset != 5 || a.MaxTransactions != 50 {
+			t.Errorf("expected disjoint halves, got %+v and %+v", a, b)
+		}
+
+		for i, agent := range agents {
+			if err := agent.WaitStart(context.Background()); err != nil {
+				t.Fatal(err)
+			}
+			if err := agent.Report(int64(10*(i+1)), int64(i+1), 0, true); err != nil {
+				t.Fatal(err)
+			}
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := coordinator.Wait(ctx); err != nil {
+			t.Fatal(err)
+		}
+		if total := coordinator.Totals(); total.Sent != 30 || total.Succeeded != 3 {
+			t.Errorf("expected 30 sent and 3 succeeded, got %+v", total)
+		}
+	})
+
+	t.Run("RefusesWrongSecret", func(t *testing.T) {
+		coordinator, err := NewCoordinator(1, 1, 0, 0, "secret")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := Join(listen(t, coordinator), "agent", "guess", nil); !errors.Is(err, ErrUnauthorized) {
+			t.Errorf("expected ErrUnauthorized, got %v", err)
+		}
+		if coordinator.registered != 0 {
+			t.Errorf("expected no agent to be registered, got %d", coordinator.registered)
+		}
+	})
+
+	t.Run("WaitStartRespectsContext", func(t *testing.T) {
+		coordinator, err := NewCoordinator(2, 2, 0, 0, "secret")
+		if err != nil {
+			t.Fatal(err)
+		}
+		agent, err := Join(listen(t, coordinator), "agent", "secret", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer agent.Close()
+
+		// The second agent never joins
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+		if err := agent.WaitStart(ctx); !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("expected the wait to end with the context, got %v", err)
+		}
+	})
+
+	t.Run("WaitGivesUpSilentAgent", func(t *testing.T) {
+		coordinator, err := NewCoordinator(1, 1, 0, 0, "secret")
+		if err != nil {
+			t.Fatal(err)
+		}
+		coordinator.timeout = 100 * time.Millisecond
+		agent, err := Join(listen(t, coordinator), "agent", "secret", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// The agent dies before reporting, so no heartbeat arrives
+		agent.Close()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := coordinator.Wait(ctx); err == nil || errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("expected the silent agent to fail the wait, got %v", err)
+		}
+	})
+}
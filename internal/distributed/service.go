@@ -0,0 +1,100 @@
+package distributed
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/encoding"
+)
+
+// The coordinator is a gRPC service. Its messages are the Go types in this package
+// encoded as JSON, so the service needs no generated protobuf code; agents select
+// the codec with its content subtype.
+const (
+	serviceName = "simulator.distributed.Coordinator"
+	codecName   = "json"
+)
+
+// jsonCodec encodes gRPC messages as JSON
+type jsonCodec struct{}
+
+func (jsonCodec) Marshal(v interface{}) ([]byte, error)      { return json.Marshal(v) }
+func (jsonCodec) Unmarshal(data []byte, v interface{}) error { return json.Unmarshal(data, v) }
+func (jsonCodec) Name() string                               { return codecName }
+
+func init() {
+	encoding.RegisterCodec(jsonCodec{})
+}
+
+// empty is the request or reply of a method without one
+type empty struct{}
+
+// HeartbeatArgs identifies the agent sending a heartbeat
+type HeartbeatArgs struct {
+	AgentID int
+}
+
+// coordinatorServer is the service the coordinator serves to agents
+type coordinatorServer interface {
+	Register(ctx context.Context, args *RegisterArgs) (*Assignment, error)
+	Clock(ctx context.Context, _ *empty) (*time.Time, error)
+	WaitStart(ctx context.Context, _ *empty) (*time.Time, error)
+	Heartbeat(ctx context.Context, args *HeartbeatArgs) (*empty, error)
+	Report(ctx context.Context, metrics *Metrics) (*empty, error)
+}
+
+// coordinatorService describes coordinatorServer to gRPC
+var coordinatorService = grpc.ServiceDesc{
+	ServiceName: serviceName,
+	HandlerType: (*coordinatorServer)(nil),
+	Methods: []grpc.MethodDesc{
+		method("Register", func() interface{} { return new(RegisterArgs) },
+			func(s coordinatorServer, ctx context.Context, req interface{}) (interface{}, error) {
+				return s.Register(ctx, req.(*RegisterArgs))
+			}),
+		method("Clock", func() interface{} { return new(empty) },
+			func(s coordinatorServer, ctx context.Context, req interface{}) (interface{}, error) {
+				return s.Clock(ctx, req.(*empty))
+			}),
+		method("WaitStart", func() interface{} { return new(empty) },
+			func(s coordinatorServer, ctx context.Context, req interface{}) (interface{}, error) {
+				return s.WaitStart(ctx, req.(*empty))
+			}),
+		method("Heartbeat", func() interface{} { return new(HeartbeatArgs) },
+			func(s coordinatorServer, ctx context.Context, req interface{}) (interface{}, error) {
+				return s.Heartbeat(ctx, req.(*HeartbeatArgs))
+			}),
+		method("Report", func() interface{} { return new(Metrics) },
+			func(s coordinatorServer, ctx context.Context, req interface{}) (interface{}, error) {
+				return s.Report(ctx, req.(*Metrics))
+			}),
+	},
+}
+
+// method describes the unary method name, whose request is made by newRequest and
+// which is served by call
+func method(name string, newRequest func() interface{}, call func(s coordinatorServer, ctx context.Context, req interface{}) (interface{}, error)) grpc.MethodDesc {
+	return grpc.MethodDesc{
+		MethodName: name,
+		Handler: func(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+			req := newRequest()
+			if err := dec(req); err != nil {
+				return nil, err
+			}
+			handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+				return call(srv.(coordinatorServer), ctx, req)
+			}
+			if interceptor == nil {
+				return handler(ctx, req)
+			}
+			return interceptor(ctx, req, &grpc.UnaryServerInfo{Server: srv, FullMethod: fullMethod(name)}, handler)
+		},
+	}
+}
+
+// fullMethod returns the gRPC path of the coordinator method name
+func fullMethod(name string) string {
+	return "/" + serviceName + "/" + name
+}
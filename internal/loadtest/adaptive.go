@@ -0,0 +1,137 @@
+package loadtest
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aakash4dev/ethereum-transaction-simulator/internal/transaction"
+)
+
+// minStepPercent is the rate step below which the adaptive search has converged
+const minStepPercent = 5
+
+// AdaptiveConfig holds configuration for the adaptive throughput search
+type AdaptiveConfig struct {
+	StartTPS          float64       // Initial send rate
+	Window            time.Duration // Measurement time at each rate
+	MaxP95Latency     time.Duration // p95 inclusion latency considered unsustainable
+	MaxFailurePercent float64       // Failure rate considered unsustainable
+	StepPercent       float64       // Initial rate increase per healthy window
+}
+
+// AdaptiveResult is the outcome of the adaptive throughput search
+type AdaptiveResult struct {
+	SustainableTPS float64 // Highest rate that stayed within thresholds
+	Steps          []AdaptiveStep
+}
+
+// AdaptiveStep records one window of the search
+type AdaptiveStep struct {
+	TPS     float64
+	Stats   WindowStats
+	Healthy bool
+}
+
+// search is the rate controller: it grows the rate while windows are healthy and, on
+// an unhealthy window, backs off to the last healthy rate and halves the step
+type search struct {
+	rate     float64
+	lastGood float64
+	step     float64 // Percent
+}
+
+// next records whether the window at the current rate was healthy and moves to the
+// next rate. It returns true once the step has shrunk below minStepPercent.
+func (s *search) next(healthy bool) bool {
+	if healthy {
+		s.lastGood = s.rate
+	} else {
+		s.step /= 2
+	}
+	if s.step < minStepPercent {
+		return true
+	}
+	base := s.rate
+	if !healthy {
+		base = s.lastGood
+	}
+	s.rate = base * (1 + s.step/100)
+	return false
+}
+
+// healthy reports whether a window stayed within the thresholds. A window that sent
+// transactions but had none mined is never healthy, whatever its latency.
+func (config *AdaptiveConfig) healthy(stats WindowStats) bool {
+	if stats.Sent > 0 && stats.Mined == 0 {
+		return false
+	}
+	return stats.P95Latency <= config.MaxP95Latency && stats.FailureRate*100 <= config.MaxFailurePercent
+}
+
+// RunAdaptive steers the parallel sender's rate until it converges on the chain's
+// maximum sustainable throughput: the highest rate whose p95 inclusion latency and
+// failure rate stay within the configured thresholds.
+func RunAdaptive(ctx context.Context, ps *transaction.ParallelSender, config *AdaptiveConfig) (*AdaptiveResult, error) {
+	c := &collector{}
+	ps.ObserveBlocks(c.observe)
+	ps.SetRate(config.StartTPS)
+
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	errChan := make(chan error, 1)
+	go func() {
+		errChan <- ps.SendParallelTransactions(runCtx)
+	}()
+
+	s := &search{rate: config.StartTPS, step: config.StepPercent}
+	result := &AdaptiveResult{}
+	ticker := time.NewTicker(config.Window)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			sent, _, failed, _ := ps.GetMetrics()
+			c.addPending(ps.PendingAges())
+			stats := c.close(sent, failed)
+			healthy := config.healthy(stats)
+			result.Steps = append(result.Steps, AdaptiveStep{TPS: s.rate, Stats: stats, Healthy: healthy})
+			fmt.Printf("%.1f TPS: p95 latency %s, failure rate %.1f%%, healthy: %t\n",
+				s.rate, stats.P95Latency.Round(time.Millisecond), stats.FailureRate*100, healthy)
+
+			done := s.next(healthy)
+			result.SustainableTPS = s.lastGood
+			if done {
+				cancel()
+				<-errChan
+				PrintAdaptiveResult(result)
+				return result, nil
+			}
+			ps.SetRate(s.rate)
+		case err := <-errChan:
+			// Wallets ran dry or the run was cancelled before converging
+			PrintAdaptiveResult(result)
+			return result, err
+		}
+	}
+}
+
+// PrintAdaptiveResult prints the search steps and the sustainable throughput
+func PrintAdaptiveResult(result *AdaptiveResult) {
+	fmt.Printf("\n=== Adaptive Throughput Search ===\n")
+	for _, step := range result.Steps {
+		status := "ok"
+		if !step.Healthy {
+			status = "over threshold"
+		}
+		fmt.Printf("%8.1f TPS  p95 %-10s failures %5.1f%%  %s\n",
+			step.TPS, step.Stats.P95Latency.Round(time.Millisecond), step.Stats.FailureRate*100, status)
+	}
+	if result.SustainableTPS > 0 {
+		fmt.Printf("Maximum sustainable throughput: %.1f TPS\n", result.SustainableTPS)
+	} else {
+		fmt.Printf("No rate stayed within thresholds; lower the start rate\n")
+	}
+	fmt.Printf("==========================\n")
+}
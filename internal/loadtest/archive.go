@@ -0,0 +1,160 @@
+package loadtest
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// Depth distributions for historical state queries
+const (
+	DepthUniform     = "uniform"     // Every depth up to MaxDepth equally likely
+	DepthExponential = "exponential" // Biased toward recent blocks, mean depth MaxDepth/5
+)
+
+// archiveBucketLimits are the upper depth bounds the report groups queries by. The first
+// bucket roughly matches the recent state full nodes keep in memory.
+var archiveBucketLimits = []uint64{128, 1024, 10000, 100000}
+
+// ArchiveConfig holds configuration for the historical state workload
+type ArchiveConfig struct {
+	Rate         float64          // Queries per second (0 = as fast as Concurrency allows)
+	Duration     time.Duration    // How long to send queries
+	Concurrency  int              // Maximum queries in flight
+	Targets      []common.Address // Accounts and contracts queried
+	MaxDepth     uint64           // Deepest block queried, counted back from the head
+	Distribution string           // DepthUniform or DepthExponential
+}
+
+// ArchiveBucket summarizes the queries whose depth falls in one bucket
+type ArchiveBucket struct {
+	MinDepth uint64
+	MaxDepth uint64 // Inclusive
+	ReadStats
+}
+
+// ArchiveReport summarizes a historical state run
+type ArchiveReport struct {
+	Duration time.Duration
+	Head     uint64
+	Buckets  []*ArchiveBucket
+}
+
+// newArchiveBuckets returns empty buckets covering depths 1 to maxDepth
+func newArchiveBuckets(maxDepth uint64) []*ArchiveBucket {
+	var buckets []*ArchiveBucket
+	from := uint64(1)
+	for _, limit := range archiveBucketLimits {
+		if limit >= maxDepth {
+			break
+		}
+		buckets = append(buckets, &ArchiveBucket{MinDepth: from, MaxDepth: limit})
+		from = limit + 1
+	}
+	return append(buckets, &ArchiveBucket{MinDepth: from, MaxDepth: maxDepth})
+}
+
+// bucketFor returns the bucket covering depth
+func bucketFor(buckets []*ArchiveBucket, depth uint64) *ArchiveBucket {
+	for _, b := range buckets {
+		if depth <= b.MaxDepth {
+			return b
+		}
+	}
+	return buckets[len(buckets)-1]
+}
+
+// sampleDepth draws a query depth in [1, maxDepth] from the distribution
+func sampleDepth(distribution string, maxDepth uint64, rng *rand.Rand) uint64 {
+	var depth uint64
+	if distribution == DepthExponential {
+		depth = 1 + uint64(rng.ExpFloat64()*float64(maxDepth)/5)
+	} else {
+		depth = 1 + uint64(rng.Int63n(int64(maxDepth)))
+	}
+	if depth > maxDepth {
+		depth = maxDepth
+	}
+	return depth
+}
+
+// RunArchive queries balances and storage slots at historical blocks, with depths drawn
+// from the configured distribution, to benchmark an archive node's state access. Run it
+// alongside the parallel sender to measure archive performance under write load.
+func RunArchive(ctx context.Context, client *ethclient.Client, config *ArchiveConfig) (*ArchiveReport, error) {
+	if len(config.Targets) == 0 {
+		return nil, fmt.Errorf("archive load needs at least one target address")
+	}
+	head, err := client.BlockNumber(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get latest block: %w", err)
+	}
+	maxDepth := config.MaxDepth
+	if maxDepth >= head {
+		maxDepth = head // Genesis is the deepest block there is
+	}
+	if maxDepth == 0 {
+		return nil, fmt.Errorf("chain has no history to query yet")
+	}
+	ctx, cancel := context.WithTimeout(ctx, config.Duration)
+	defer cancel()
+
+	report := &ArchiveReport{Head: head, Buckets: newArchiveBuckets(maxDepth)}
+	var mu sync.Mutex
+	report.Duration = runQueries(ctx, config.Rate, config.Concurrency, func(rng *rand.Rand) func() {
+		depth := sampleDepth(config.Distribution, maxDepth, rng)
+		block := new(big.Int).SetUint64(head - depth)
+		target := config.Targets[rng.Intn(len(config.Targets))]
+		storage := rng.Intn(2) == 0
+		return func() {
+			sentAt := time.Now()
+			var err error
+			if storage {
+				_, err = client.StorageAt(ctx, target, common.Hash{}, block)
+			} else {
+				_, err = client.BalanceAt(ctx, target, block)
+			}
+			latency := time.Since(sentAt)
+			if ctx.Err() != nil {
+				return // Cut off by the end of the run, not a node failure
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			stats := &bucketFor(report.Buckets, depth).ReadStats
+			stats.Count++
+			if err != nil {
+				stats.Errors++
+				return
+			}
+			stats.latencies = append(stats.latencies, latency)
+		}
+	})
+
+	for _, b := range report.Buckets {
+		b.P50Latency = Percentile(b.latencies, 50)
+		b.P95Latency = Percentile(b.latencies, 95)
+		b.latencies = nil
+	}
+	return report, nil
+}
+
+// PrintArchiveReport prints per-depth query counts, errors and latencies
+func PrintArchiveReport(report *ArchiveReport) {
+	fmt.Printf("\n=== Archive State Summary ===\n")
+	fmt.Printf("Head at start: %d, duration: %s\n", report.Head, report.Duration.Round(time.Second))
+	for _, b := range report.Buckets {
+		if b.Count == 0 {
+			continue
+		}
+		fmt.Printf("  depth %d-%d: %d queries, %d errors, p50 %s, p95 %s\n", b.MinDepth, b.MaxDepth, b.Count, b.Errors,
+			b.P50Latency.Round(time.Millisecond), b.P95Latency.Round(time.Millisecond))
+	}
+	fmt.Printf("==========================\n")
+}
@@ -0,0 +1,48 @@
+package loadtest
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestArchiveDepths(t *testing.T) {
+	t.Run("Buckets", func(t *testing.T) {
+		buckets := newArchiveBuckets(5000)
+		if len(buckets) != 3 {
+			t.Fatalf("expected 3 buckets, got %d", len(buckets))
+		}
+		if buckets[0].MinDepth != 1 || buckets[0].MaxDepth != 128 || buckets[2].MinDepth != 1025 || buckets[2].MaxDepth != 5000 {
+			t.Errorf("unexpected bucket bounds: %+v %+v", *buckets[0], *buckets[2])
+		}
+		if b := bucketFor(buckets, 128); b != buckets[0] {
+			t.Error("depth 128 should fall in the first bucket")
+		}
+		if b := bucketFor(buckets, 129); b != buckets[1] {
+			t.Error("depth 129 should fall in the second bucket")
+		}
+		if len(newArchiveBuckets(50)) != 1 {
+			t.Error("shallow history should have a single bucket")
+		}
+	})
+
+	t.Run("Distributions", func(t *testing.T) {
+		rng := rand.New(rand.NewSource(1))
+		var uniformSum, exponentialSum uint64
+		for i := 0; i < 10000; i++ {
+			for _, distribution := range []string{DepthUniform, DepthExponential} {
+				depth := sampleDepth(distribution, 1000, rng)
+				if depth < 1 || depth > 1000 {
+					t.Fatalf("%s: depth %d out of range", distribution, depth)
+				}
+				if distribution == DepthUniform {
+					uniformSum += depth
+				} else {
+					exponentialSum += depth
+				}
+			}
+		}
+		if exponentialSum >= uniformSum/2 {
+			t.Errorf("exponential depths should be biased toward recent blocks (uniform sum %d, exponential sum %d)", uniformSum, exponentialSum)
+		}
+	})
+}
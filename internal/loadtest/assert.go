@@ -0,0 +1,118 @@
+package loadtest
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrAssertionsFailed is returned by Verdict.Err when any assertion was violated
+var ErrAssertionsFailed = errors.New("run assertions failed")
+
+// Assertions are pass/fail bounds checked against a finished run
+type Assertions struct {
+	MinTPS           float64       // Lowest acceptable mined TPS (0 = not checked)
+	MaxFailureRate   float64       // Highest acceptable failure rate, as a fraction
+	CheckFailureRate bool          // Whether MaxFailureRate is checked; 0 is a valid bound
+	MaxP95Latency    time.Duration // Highest acceptable p95 inclusion latency (0 = not checked)
+}
+
+// Enabled reports whether any assertion is set
+func (a *Assertions) Enabled() bool {
+	return a.MinTPS > 0 || a.CheckFailureRate || a.MaxP95Latency > 0
+}
+
+// AssertionResult is the outcome of one assertion
+type AssertionResult struct {
+	Name   string
+	Limit  string
+	Actual string
+	Passed bool
+}
+
+// Verdict is the outcome of every assertion set for a run
+type Verdict struct {
+	Results []AssertionResult
+}
+
+// Passed reports whether every assertion held
+func (v *Verdict) Passed() bool {
+	for _, r := range v.Results {
+		if !r.Passed {
+			return false
+		}
+	}
+	return true
+}
+
+// Err returns ErrAssertionsFailed, with the number of violations, when the verdict
+// failed so the process can exit non-zero
+func (v *Verdict) Err() error {
+	failed := 0
+	for _, r := range v.Results {
+		if !r.Passed {
+			failed++
+		}
+	}
+	if failed == 0 {
+		return nil
+	}
+	return fmt.Errorf("%w: %d of %d violated", ErrAssertionsFailed, failed, len(v.Results))
+}
+
+// Evaluate checks a run measured over duration against the assertions. A run that mined
+// nothing fails the latency bound too, since there is no latency to vouch for.
+func (a *Assertions) Evaluate(stats WindowStats, duration time.Duration) *Verdict {
+	verdict := &Verdict{}
+	if a.MinTPS > 0 {
+		tps := 0.0
+		if duration > 0 {
+			tps = float64(stats.Mined) / duration.Seconds()
+		}
+		verdict.Results = append(verdict.Results, AssertionResult{
+			Name:   "mined TPS",
+			Limit:  fmt.Sprintf(">= %.1f", a.MinTPS),
+			Actual: fmt.Sprintf("%.1f", tps),
+			Passed: tps >= a.MinTPS,
+		})
+	}
+	if a.CheckFailureRate {
+		verdict.Results = append(verdict.Results, AssertionResult{
+			Name:   "failure rate",
+			Limit:  fmt.Sprintf("<= %.2f%%", a.MaxFailureRate*100),
+			Actual: fmt.Sprintf("%.2f%%", stats.FailureRate*100),
+			Passed: stats.FailureRate <= a.MaxFailureRate,
+		})
+	}
+	if a.MaxP95Latency > 0 {
+		result := AssertionResult{
+			Name:   "p95 inclusion latency",
+			Limit:  fmt.Sprintf("<= %s", a.MaxP95Latency),
+			Actual: stats.P95Latency.Round(time.Millisecond).String(),
+			Passed: stats.Mined > 0 && stats.P95Latency <= a.MaxP95Latency,
+		}
+		if stats.Mined == 0 {
+			result.Actual = "nothing mined"
+		}
+		verdict.Results = append(verdict.Results, result)
+	}
+	return verdict
+}
+
+// PrintVerdict prints each assertion with its bound and measured value, then the overall verdict
+func PrintVerdict(verdict *Verdict) {
+	fmt.Printf("\n=== Assertions ===\n")
+	for _, r := range verdict.Results {
+		status := "PASS"
+		if !r.Passed {
+			status = "FAIL"
+		}
+		fmt.Printf("%s  %s %s (got %s)\n", status, r.Name, r.Limit, r.Actual)
+	}
+	if verdict.Passed() {
+		fmt.Printf("Verdict: PASS\n")
+	} else {
+		fmt.Printf("Verdict: FAIL\n")
+	}
+	fmt.Printf("==========================\n")
+}
@@ -0,0 +1,47 @@
+package loadtest
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestAssertions(t *testing.T) {
+	a := &Assertions{MinTPS: 100, MaxFailureRate: 0.01, CheckFailureRate: true, MaxP95Latency: 12 * time.Second}
+
+	t.Run("Pass", func(t *testing.T) {
+		stats := WindowStats{Mined: 12000, FailureRate: 0.005, P95Latency: 8 * time.Second}
+		verdict := a.Evaluate(stats, 100*time.Second)
+		if !verdict.Passed() || verdict.Err() != nil || len(verdict.Results) != 3 {
+			t.Errorf("expected 3 passing assertions, got %+v", verdict.Results)
+		}
+	})
+
+	t.Run("Fail", func(t *testing.T) {
+		stats := WindowStats{Mined: 5000, FailureRate: 0.05, P95Latency: 8 * time.Second}
+		verdict := a.Evaluate(stats, 100*time.Second)
+		if verdict.Passed() || !errors.Is(verdict.Err(), ErrAssertionsFailed) {
+			t.Fatalf("expected a failed verdict, got %+v", verdict.Results)
+		}
+		if verdict.Results[0].Passed || verdict.Results[1].Passed || !verdict.Results[2].Passed {
+			t.Errorf("expected TPS and failure rate to fail, got %+v", verdict.Results)
+		}
+	})
+
+	t.Run("NothingMinedFailsLatency", func(t *testing.T) {
+		latency := &Assertions{MaxP95Latency: time.Second}
+		if latency.Evaluate(WindowStats{}, time.Minute).Passed() {
+			t.Error("expected a run with nothing mined to fail the latency bound")
+		}
+	})
+
+	t.Run("ZeroFailureBound", func(t *testing.T) {
+		strict := &Assertions{CheckFailureRate: true}
+		if !strict.Enabled() || strict.Evaluate(WindowStats{FailureRate: 0.001}, time.Minute).Passed() {
+			t.Error("expected a 0% failure bound to be enforced")
+		}
+		if (&Assertions{}).Enabled() {
+			t.Error("expected empty assertions to be disabled")
+		}
+	})
+}
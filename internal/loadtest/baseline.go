@@ -0,0 +1,162 @@
+package loadtest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/aakash4dev/ethereum-transaction-simulator/internal/transaction"
+)
+
+// ErrBaselineRegression is returned when a run is aborted for falling behind its baseline
+var ErrBaselineRegression = errors.New("run aborted: regressed against baseline")
+
+// Baseline is the on-disk summary of a reference run
+type Baseline struct {
+	RecordedAt        time.Time `json:"recordedAt"`
+	DurationSeconds   float64   `json:"durationSeconds"`
+	MinedTPS          float64   `json:"minedTps"`
+	FailureRate       float64   `json:"failureRate"`
+	P95LatencySeconds float64   `json:"p95LatencySeconds"`
+	AvgBlockTxs       float64   `json:"avgBlockTxs"`
+}
+
+// NewBaseline summarizes a run measured over duration
+func NewBaseline(stats WindowStats, duration time.Duration) *Baseline {
+	b := &Baseline{
+		RecordedAt:        time.Now().UTC(),
+		DurationSeconds:   duration.Seconds(),
+		FailureRate:       stats.FailureRate,
+		P95LatencySeconds: stats.P95Latency.Seconds(),
+		AvgBlockTxs:       stats.AvgBlockTxs,
+	}
+	if duration > 0 {
+		b.MinedTPS = float64(stats.Mined) / duration.Seconds()
+	}
+	return b
+}
+
+// SaveBaseline writes b to path as JSON
+func SaveBaseline(path string, b *Baseline) error {
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode baseline: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write baseline file: %w", err)
+	}
+	return nil
+}
+
+// LoadBaseline reads a baseline written by SaveBaseline
+func LoadBaseline(path string) (*Baseline, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read baseline file: %w", err)
+	}
+	var b Baseline
+	if err := json.Unmarshal(data, &b); err != nil {
+		return nil, fmt.Errorf("failed to decode baseline file: %w", err)
+	}
+	return &b, nil
+}
+
+// BaselineConfig holds configuration for comparing a running load with a baseline
+type BaselineConfig struct {
+	Baseline     *Baseline     // Reference to compare against, nil only records
+	Window       time.Duration // Length of each rolling window compared with the baseline
+	Percent      int           // Change against the baseline flagged as a regression
+	AbortWindows int           // Abort after this many consecutive regressed windows (0 = never)
+	SavePath     string        // Write this run as a new baseline when it ends, empty disables
+}
+
+// Regressions returns a description of every metric in a window of length window that
+// regressed by more than percent against b. Latency, failure rate and block fill are
+// compared as in Compare; mined TPS is compared as a rate so window length does not matter.
+func (b *Baseline) Regressions(stats WindowStats, window time.Duration, percent int) []string {
+	reference := WindowStats{
+		FailureRate: b.FailureRate,
+		P95Latency:  time.Duration(b.P95LatencySeconds * float64(time.Second)),
+		AvgBlockTxs: b.AvgBlockTxs,
+	}
+	regressed := Compare(reference, stats, percent)
+	tps := float64(stats.Mined) / window.Seconds()
+	if b.MinedTPS > 0 && tps*(1+float64(percent)/100) < b.MinedTPS {
+		regressed = append(regressed, fmt.Sprintf("mined TPS %.1f vs baseline %.1f", tps, b.MinedTPS))
+	}
+	return regressed
+}
+
+// RunAgainstBaseline runs the parallel sender, closing a window every config.Window
+// and comparing it with the baseline so a clearly worse run is noticed, and with
+// AbortWindows stopped, long before it ends. The whole run is returned as one window.
+func RunAgainstBaseline(ctx context.Context, ps *transaction.ParallelSender, config *BaselineConfig) (WindowStats, error) {
+	window, total := &collector{}, &collector{}
+	ps.ObserveBlocks(func(stats transaction.BlockStats) {
+		window.observe(stats)
+		total.observe(stats)
+	})
+
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	errChan := make(chan error, 1)
+	go func() {
+		errChan <- ps.SendParallelTransactions(runCtx)
+	}()
+
+	start := time.Now()
+	ticker := time.NewTicker(config.Window)
+	defer ticker.Stop()
+	index, streak := 0, 0
+	var aborted bool
+	for {
+		select {
+		case <-ticker.C:
+			sent, _, failed, _ := ps.GetMetrics()
+			stats := window.close(sent, failed)
+			if config.Baseline == nil {
+				continue
+			}
+			regressed := config.Baseline.Regressions(stats, config.Window, config.Percent)
+			printBaselineWindow(index, stats, config.Window, regressed)
+			index++
+			if len(regressed) == 0 {
+				streak = 0
+				continue
+			}
+			streak++
+			if config.AbortWindows > 0 && streak >= config.AbortWindows && !aborted {
+				fmt.Printf("Aborting: %d consecutive windows regressed against the baseline\n", streak)
+				aborted = true
+				cancel()
+			}
+		case err := <-errChan:
+			duration := time.Since(start)
+			sent, _, failed, _ := ps.GetMetrics()
+			stats := total.close(sent, failed)
+			if config.SavePath != "" && !aborted {
+				if saveErr := SaveBaseline(config.SavePath, NewBaseline(stats, duration)); saveErr != nil {
+					return stats, saveErr
+				}
+				fmt.Printf("Baseline written to %s\n", config.SavePath)
+			}
+			if aborted {
+				return stats, ErrBaselineRegression
+			}
+			return stats, err
+		}
+	}
+}
+
+// printBaselineWindow prints one window and its regressions as it closes
+func printBaselineWindow(index int, stats WindowStats, window time.Duration, regressed []string) {
+	fmt.Printf("Window %d: mined %.1f TPS, failures %.1f%%, p95 latency %s, avg txs/block %.1f\n",
+		index, float64(stats.Mined)/window.Seconds(), stats.FailureRate*100,
+		stats.P95Latency.Round(time.Millisecond), stats.AvgBlockTxs)
+	for _, r := range regressed {
+		fmt.Printf("  REGRESSED: %s\n", r)
+	}
+}
@@ -0,0 +1,40 @@
+package loadtest
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestBaseline(t *testing.T) {
+	baseline := NewBaseline(WindowStats{Mined: 6000, FailureRate: 0.01, P95Latency: 4 * time.Second, AvgBlockTxs: 100}, time.Minute)
+
+	t.Run("RoundTrip", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "baseline.json")
+		if err := SaveBaseline(path, baseline); err != nil {
+			t.Fatal(err)
+		}
+		loaded, err := LoadBaseline(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if loaded.MinedTPS != 100 || loaded.P95LatencySeconds != 4 || loaded.AvgBlockTxs != 100 {
+			t.Errorf("unexpected baseline after round trip: %+v", loaded)
+		}
+	})
+
+	t.Run("Stable", func(t *testing.T) {
+		// Half the baseline's window length at the same rate
+		current := WindowStats{Mined: 2800, FailureRate: 0.02, P95Latency: 4500 * time.Millisecond, AvgBlockTxs: 90}
+		if regressed := baseline.Regressions(current, 30*time.Second, 25); len(regressed) != 0 {
+			t.Errorf("expected no regressions, got %v", regressed)
+		}
+	})
+
+	t.Run("Regressed", func(t *testing.T) {
+		current := WindowStats{Mined: 3000, FailureRate: 0.5, P95Latency: 10 * time.Second, AvgBlockTxs: 40}
+		if regressed := baseline.Regressions(current, time.Minute, 25); len(regressed) != 4 {
+			t.Errorf("expected 4 regressions, got %v", regressed)
+		}
+	})
+}
@@ -0,0 +1,259 @@
+package loadtest
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"math/rand"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/aakash4dev/ethereum-transaction-simulator/internal/transaction"
+)
+
+// matrixKey matches the configuration keys a matrix may vary
+var matrixKey = regexp.MustCompile(`^[A-Z][A-Z0-9_]*$`)
+
+// Parameter is one configuration key and the values an experiment tries for it
+type Parameter struct {
+	Key    string
+	Values []string
+}
+
+// Setting is one configuration key set to one value
+type Setting struct {
+	Key   string
+	Value string
+}
+
+// Combination is one run of an experiment: a value for every matrix parameter
+type Combination []Setting
+
+// String formats the combination as space-separated KEY=value pairs
+func (c Combination) String() string {
+	parts := make([]string, len(c))
+	for i, s := range c {
+		parts[i] = s.Key + "=" + s.Value
+	}
+	return strings.Join(parts, " ")
+}
+
+// Overrides returns the combination as a key to value map
+func (c Combination) Overrides() map[string]string {
+	overrides := make(map[string]string, len(c))
+	for _, s := range c {
+		overrides[s.Key] = s.Value
+	}
+	return overrides
+}
+
+// ExperimentConfig holds configuration for a parameter sweep
+type ExperimentConfig struct {
+	Matrix      []Parameter
+	RunDuration time.Duration // Length of each run
+	Cooldown    time.Duration // Pause between runs so the mempool drains
+	Seed        int64         // Shuffles the run order when non-zero; the same seed gives the same order
+}
+
+// ExperimentRun is the outcome of one combination
+type ExperimentRun struct {
+	Combination Combination
+	Stats       WindowStats
+	Duration    time.Duration
+	Err         error // Setup or run failure; Stats are empty when setup failed
+}
+
+// SentTPS returns the rate transactions were submitted at
+func (r *ExperimentRun) SentTPS() float64 {
+	if r.Duration <= 0 {
+		return 0
+	}
+	return float64(r.Stats.Sent) / r.Duration.Seconds()
+}
+
+// MinedTPS returns the rate the run's transactions were included at
+func (r *ExperimentRun) MinedTPS() float64 {
+	if r.Duration <= 0 {
+		return 0
+	}
+	return float64(r.Stats.Mined) / r.Duration.Seconds()
+}
+
+// ExperimentReport collects the runs of an experiment in the order they ran
+type ExperimentReport struct {
+	Seed int64
+	Runs []*ExperimentRun
+}
+
+// ParseExperimentArgs parses the arguments of the experiment subcommand
+func ParseExperimentArgs(args []string) (*ExperimentConfig, error) {
+	fs := flag.NewFlagSet("experiment", flag.ContinueOnError)
+	matrix := fs.String("matrix", "", `parameter values to combine, e.g. "WALLET_COUNT=10,50;GAS_LIMIT=21000,50000"`)
+	duration := fs.Duration("duration", 2*time.Minute, "length of each run")
+	cooldown := fs.Duration("cooldown", time.Minute, "pause between runs")
+	seed := fs.Int64("seed", 0, "shuffle the run order with this seed (0 keeps matrix order)")
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+	params, err := ParseMatrix(*matrix)
+	if err != nil {
+		return nil, err
+	}
+	if *duration <= 0 {
+		return nil, errors.New("--duration must be greater than 0")
+	}
+	if *cooldown < 0 {
+		return nil, errors.New("--cooldown cannot be negative")
+	}
+	return &ExperimentConfig{Matrix: params, RunDuration: *duration, Cooldown: *cooldown, Seed: *seed}, nil
+}
+
+// ParseMatrix parses semicolon-separated parameters, each a configuration key and its
+// comma-separated values, such as "WALLET_COUNT=10,50,100;GAS_LIMIT=21000,50000"
+func ParseMatrix(s string) ([]Parameter, error) {
+	var params []Parameter
+	seen := make(map[string]bool)
+	for _, entry := range strings.Split(s, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		key, values, ok := strings.Cut(entry, "=")
+		key = strings.TrimSpace(key)
+		if !ok || !matrixKey.MatchString(key) {
+			return nil, fmt.Errorf("invalid matrix entry %q: expected KEY=value1,value2", entry)
+		}
+		if seen[key] {
+			return nil, fmt.Errorf("matrix key %s is listed twice", key)
+		}
+		seen[key] = true
+
+		param := Parameter{Key: key}
+		for _, value := range strings.Split(values, ",") {
+			if value = strings.TrimSpace(value); value != "" {
+				param.Values = append(param.Values, value)
+			}
+		}
+		if len(param.Values) == 0 {
+			return nil, fmt.Errorf("matrix key %s has no values", key)
+		}
+		params = append(params, param)
+	}
+	if len(params) == 0 {
+		return nil, errors.New("matrix must list at least one parameter")
+	}
+	return params, nil
+}
+
+// Combinations returns every combination of the parameter values, with the last
+// parameter varying fastest. A non-zero seed shuffles the order reproducibly, so slow
+// drift in the chain (state growth, mempool backlog) is not mistaken for a trend.
+func Combinations(params []Parameter, seed int64) []Combination {
+	combos := []Combination{{}}
+	for _, p := range params {
+		var next []Combination
+		for _, combo := range combos {
+			for _, value := range p.Values {
+				c := append(Combination{}, combo...)
+				next = append(next, append(c, Setting{Key: p.Key, Value: value}))
+			}
+		}
+		combos = next
+	}
+	if seed != 0 {
+		rng := rand.New(rand.NewSource(seed))
+		rng.Shuffle(len(combos), func(i, j int) { combos[i], combos[j] = combos[j], combos[i] })
+	}
+	return combos
+}
+
+// RunExperiment runs every combination of the matrix in turn, pausing config.Cooldown
+// between runs. setup builds a parallel sender configured with the combination's
+// overrides; a setup failure is recorded for that run and the experiment moves on.
+func RunExperiment(ctx context.Context, config *ExperimentConfig, setup func(ctx context.Context, c Combination) (*transaction.ParallelSender, error)) (*ExperimentReport, error) {
+	report := &ExperimentReport{Seed: config.Seed}
+	combos := Combinations(config.Matrix, config.Seed)
+	for i, combo := range combos {
+		if i > 0 && config.Cooldown > 0 {
+			fmt.Printf("Cooling down for %s\n", config.Cooldown)
+			if err := sleepContext(ctx, config.Cooldown); err != nil {
+				return report, err
+			}
+		}
+		fmt.Printf("\n[%d/%d] %s\n", i+1, len(combos), combo)
+
+		run := &ExperimentRun{Combination: combo}
+		report.Runs = append(report.Runs, run)
+		ps, err := setup(ctx, combo)
+		if err != nil {
+			run.Err = err
+			continue
+		}
+		start := time.Now()
+		run.Stats, run.Err = MeasureRun(ctx, ps, config.RunDuration)
+		run.Duration = time.Since(start)
+		if ctx.Err() != nil {
+			return report, ctx.Err()
+		}
+	}
+	return report, nil
+}
+
+// MeasureRun runs the parallel sender for duration, or until it stops when duration is
+// 0, and returns the run as a single window
+func MeasureRun(ctx context.Context, ps *transaction.ParallelSender, duration time.Duration) (WindowStats, error) {
+	c := &collector{}
+	ps.ObserveBlocks(c.observe)
+	runCtx := ctx
+	if duration > 0 {
+		var cancel context.CancelFunc
+		runCtx, cancel = context.WithTimeout(ctx, duration)
+		defer cancel()
+	}
+	err := ps.SendParallelTransactions(runCtx)
+	sent, _, failed, _ := ps.GetMetrics()
+	return c.close(sent, failed), err
+}
+
+// sleepContext waits for d or until ctx is cancelled
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// PrintExperimentReport prints one row per run and the combination with the highest
+// inclusion throughput
+func PrintExperimentReport(report *ExperimentReport) {
+	fmt.Printf("\n=== Experiment Summary ===\n")
+	if report.Seed != 0 {
+		fmt.Printf("Seed: %d\n", report.Seed)
+	}
+	var best *ExperimentRun
+	for _, run := range report.Runs {
+		if run.Err != nil && run.Duration == 0 {
+			fmt.Printf("%s\n  setup failed: %v\n", run.Combination, run.Err)
+			continue
+		}
+		fmt.Printf("%s\n  sent %.1f TPS, mined %.1f TPS, failures %.1f%%, p95 latency %s, avg txs/block %.1f\n",
+			run.Combination, run.SentTPS(), run.MinedTPS(), run.Stats.FailureRate*100,
+			run.Stats.P95Latency.Round(time.Millisecond), run.Stats.AvgBlockTxs)
+		if run.Err != nil {
+			fmt.Printf("  run error: %v\n", run.Err)
+		}
+		if best == nil || run.MinedTPS() > best.MinedTPS() {
+			best = run
+		}
+	}
+	if best != nil {
+		fmt.Printf("Highest mined throughput: %.1f TPS with %s\n", best.MinedTPS(), best.Combination)
+	}
+	fmt.Printf("==========================\n")
+}
@@ -0,0 +1,62 @@
+package loadtest
+
+import (
+	"testing"
+)
+
+func TestParseMatrix(t *testing.T) {
+	t.Run("Valid", func(t *testing.T) {
+		params, err := ParseMatrix("WALLET_COUNT=10, 50,100; GAS_LIMIT=21000,50000")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(params) != 2 || params[0].Key != "WALLET_COUNT" || len(params[0].Values) != 3 || params[1].Values[1] != "50000" {
+			t.Errorf("unexpected matrix: %+v", params)
+		}
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		for _, s := range []string{"", "WALLET_COUNT", "wallet_count=10", "GAS_LIMIT=", "GAS_LIMIT=1;GAS_LIMIT=2"} {
+			if _, err := ParseMatrix(s); err == nil {
+				t.Errorf("%q: expected error", s)
+			}
+		}
+	})
+}
+
+func TestCombinations(t *testing.T) {
+	params := []Parameter{
+		{Key: "WALLET_COUNT", Values: []string{"10", "50", "100"}},
+		{Key: "GAS_LIMIT", Values: []string{"21000", "50000"}},
+	}
+
+	t.Run("MatrixOrder", func(t *testing.T) {
+		combos := Combinations(params, 0)
+		if len(combos) != 6 {
+			t.Fatalf("expected 6 combinations, got %d", len(combos))
+		}
+		if got := combos[0].String(); got != "WALLET_COUNT=10 GAS_LIMIT=21000" {
+			t.Errorf("unexpected first combination %q", got)
+		}
+		if got := combos[1].String(); got != "WALLET_COUNT=10 GAS_LIMIT=50000" {
+			t.Errorf("unexpected second combination %q", got)
+		}
+		if got := combos[5].Overrides()["WALLET_COUNT"]; got != "100" {
+			t.Errorf("expected last combination to use 100 wallets, got %s", got)
+		}
+	})
+
+	t.Run("SeedIsReproducible", func(t *testing.T) {
+		first, second := Combinations(params, 42), Combinations(params, 42)
+		seen := make(map[string]bool)
+		for i := range first {
+			if first[i].String() != second[i].String() {
+				t.Fatalf("run %d differs between identical seeds", i)
+			}
+			seen[first[i].String()] = true
+		}
+		if len(seen) != 6 {
+			t.Errorf("expected every combination once, got %d distinct", len(seen))
+		}
+	})
+}
@@ -0,0 +1,202 @@
+package loadtest
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/aakash4dev/ethereum-transaction-simulator/internal/transaction"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// FinalityConfig holds configuration for finality latency tracking
+type FinalityConfig struct {
+	PollInterval time.Duration // How often the safe and finalized blocks are read
+	Wait         time.Duration // How long to keep tracking after the load stops
+}
+
+// finalityBlock is one block that included our transactions
+type finalityBlock struct {
+	number     uint64
+	txs        int
+	includedAt time.Time
+	safe       time.Duration // Inclusion to safe, 0 while not yet safe
+	finalized  time.Duration // Inclusion to finalized, 0 while not yet finalized
+}
+
+// FinalityReport is the inclusion-to-safe and inclusion-to-finalized latency of the
+// run's transactions. Percentiles are per transaction.
+type FinalityReport struct {
+	Transactions     int // Included transactions tracked
+	Safe             int // Of those, how many reached a safe block
+	Finalized        int // Of those, how many reached a finalized block
+	SafeP50          time.Duration
+	SafeP95          time.Duration
+	SafeMax          time.Duration
+	FinalizedP50     time.Duration
+	FinalizedP95     time.Duration
+	FinalizedMax     time.Duration
+	SafeUnsupported  bool // The node does not serve the "safe" block tag
+	FinalUnsupported bool // The node does not serve the "finalized" block tag
+}
+
+// finalityTracker records when our blocks were included and when they became safe
+// and finalized
+type finalityTracker struct {
+	mu               sync.Mutex
+	blocks           []*finalityBlock
+	safeUnsupported  bool
+	finalUnsupported bool
+}
+
+// observe is registered as the parallel sender's block observer
+func (f *finalityTracker) observe(stats transaction.BlockStats) {
+	if stats.Ours == 0 {
+		return
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.blocks = append(f.blocks, &finalityBlock{number: stats.Number, txs: stats.Ours, includedAt: time.Now()})
+}
+
+// poll reads the safe and finalized blocks and stamps every block they now cover.
+// It reports whether every tracked block is finalized.
+func (f *finalityTracker) poll(ctx context.Context, client *ethclient.Client) bool {
+	safe, safeErr := client.HeaderByNumber(ctx, big.NewInt(int64(rpc.SafeBlockNumber)))
+	finalized, finalErr := client.HeaderByNumber(ctx, big.NewInt(int64(rpc.FinalizedBlockNumber)))
+	now := time.Now()
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if ctx.Err() == nil {
+		// A node without the tags answers with an error every time, not just once
+		f.safeUnsupported = safeErr != nil
+		f.finalUnsupported = finalErr != nil
+	}
+	done := true
+	for _, b := range f.blocks {
+		if b.safe == 0 && safeErr == nil && safe.Number.Uint64() >= b.number {
+			b.safe = now.Sub(b.includedAt)
+		}
+		if b.finalized == 0 && finalErr == nil && finalized.Number.Uint64() >= b.number {
+			b.finalized = now.Sub(b.includedAt)
+			if b.safe == 0 {
+				b.safe = b.finalized // Finalized implies safe, even if both moved in one poll
+			}
+		}
+		if b.finalized == 0 {
+			done = false
+		}
+	}
+	return done
+}
+
+// weightedPercentile returns the p-th percentile of latencies each weighted by a
+// transaction count, ignoring zero latencies
+func weightedPercentile(blocks []*finalityBlock, latency func(*finalityBlock) time.Duration, p int) time.Duration {
+	var reached []*finalityBlock
+	total := 0
+	for _, b := range blocks {
+		if latency(b) > 0 {
+			reached = append(reached, b)
+			total += b.txs
+		}
+	}
+	if total == 0 {
+		return 0
+	}
+	sort.Slice(reached, func(i, j int) bool { return latency(reached[i]) < latency(reached[j]) })
+	rank := (total - 1) * p / 100
+	for _, b := range reached {
+		if rank < b.txs {
+			return latency(b)
+		}
+		rank -= b.txs
+	}
+	return latency(reached[len(reached)-1])
+}
+
+// report summarizes the tracked blocks
+func (f *finalityTracker) report() *FinalityReport {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	r := &FinalityReport{SafeUnsupported: f.safeUnsupported, FinalUnsupported: f.finalUnsupported}
+	safe := func(b *finalityBlock) time.Duration { return b.safe }
+	final := func(b *finalityBlock) time.Duration { return b.finalized }
+	for _, b := range f.blocks {
+		r.Transactions += b.txs
+		if b.safe > 0 {
+			r.Safe += b.txs
+		}
+		if b.finalized > 0 {
+			r.Finalized += b.txs
+		}
+	}
+	r.SafeP50 = weightedPercentile(f.blocks, safe, 50)
+	r.SafeP95 = weightedPercentile(f.blocks, safe, 95)
+	r.SafeMax = weightedPercentile(f.blocks, safe, 100)
+	r.FinalizedP50 = weightedPercentile(f.blocks, final, 50)
+	r.FinalizedP95 = weightedPercentile(f.blocks, final, 95)
+	r.FinalizedMax = weightedPercentile(f.blocks, final, 100)
+	return r
+}
+
+// RunWithFinality runs the parallel sender and tracks how long its transactions take
+// to go from inclusion to a "safe" and then a "finalized" block. After the load stops
+// it keeps polling for up to config.Wait so the last blocks can finalize; on mainnet
+// finality takes two epochs, about 13 minutes.
+func RunWithFinality(ctx context.Context, ps *transaction.ParallelSender, client *ethclient.Client, config *FinalityConfig) (*FinalityReport, error) {
+	tracker := &finalityTracker{}
+	ps.ObserveBlocks(tracker.observe)
+
+	errChan := make(chan error, 1)
+	go func() {
+		errChan <- ps.SendParallelTransactions(ctx)
+	}()
+
+	ticker := time.NewTicker(config.PollInterval)
+	defer ticker.Stop()
+	var runErr error
+	var deadline <-chan time.Time
+	for {
+		select {
+		case runErr = <-errChan:
+			errChan = nil
+			fmt.Printf("Load stopped; waiting up to %s for tracked blocks to finalize\n", config.Wait)
+			deadline = time.After(config.Wait)
+		case <-deadline:
+			return tracker.report(), runErr
+		case <-ticker.C:
+			// The sender stops on ctx cancellation; keep draining so its error is kept
+			if tracker.poll(ctx, client) && errChan == nil {
+				return tracker.report(), runErr
+			}
+		}
+		if errChan == nil && ctx.Err() != nil {
+			return tracker.report(), runErr
+		}
+	}
+}
+
+// PrintFinalityReport prints the safe and finalized latency percentiles
+func PrintFinalityReport(r *FinalityReport) {
+	fmt.Printf("\n=== Finality Latency ===\n")
+	fmt.Printf("Included transactions tracked: %d\n", r.Transactions)
+	if r.SafeUnsupported {
+		fmt.Printf("Safe: the node does not serve the \"safe\" block tag\n")
+	} else {
+		fmt.Printf("Safe: %d, p50: %s, p95: %s, max: %s\n", r.Safe,
+			r.SafeP50.Round(time.Second), r.SafeP95.Round(time.Second), r.SafeMax.Round(time.Second))
+	}
+	if r.FinalUnsupported {
+		fmt.Printf("Finalized: the node does not serve the \"finalized\" block tag\n")
+	} else {
+		fmt.Printf("Finalized: %d, p50: %s, p95: %s, max: %s\n", r.Finalized,
+			r.FinalizedP50.Round(time.Second), r.FinalizedP95.Round(time.Second), r.FinalizedMax.Round(time.Second))
+	}
+	fmt.Printf("==========================\n")
+}
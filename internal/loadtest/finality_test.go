@@ -0,0 +1,46 @@
+package loadtest
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWeightedPercentile(t *testing.T) {
+	blocks := []*finalityBlock{
+		{txs: 1, finalized: 30 * time.Second},
+		{txs: 8, finalized: 10 * time.Second},
+		{txs: 1, finalized: 20 * time.Second},
+		{txs: 5}, // Not finalized yet
+	}
+	final := func(b *finalityBlock) time.Duration { return b.finalized }
+
+	t.Run("weighted by transactions", func(t *testing.T) {
+		if got := weightedPercentile(blocks, final, 50); got != 10*time.Second {
+			t.Errorf("expected p50 10s, got %s", got)
+		}
+		if got := weightedPercentile(blocks, final, 90); got != 20*time.Second {
+			t.Errorf("expected p90 20s, got %s", got)
+		}
+		if got := weightedPercentile(blocks, final, 100); got != 30*time.Second {
+			t.Errorf("expected max 30s, got %s", got)
+		}
+	})
+
+	t.Run("nothing reached", func(t *testing.T) {
+		if got := weightedPercentile(blocks[3:], final, 50); got != 0 {
+			t.Errorf("expected 0, got %s", got)
+		}
+	})
+}
+
+func TestFinalityReport(t *testing.T) {
+	tracker := &finalityTracker{blocks: []*finalityBlock{
+		{txs: 3, safe: 5 * time.Second, finalized: 10 * time.Second},
+		{txs: 2, safe: 6 * time.Second},
+		{txs: 4},
+	}}
+	r := tracker.report()
+	if r.Transactions != 9 || r.Safe != 5 || r.Finalized != 3 {
+		t.Errorf("expected 9 tracked, 5 safe, 3 finalized, got %+v", r)
+	}
+}
@@ -0,0 +1,327 @@
+package loadtest
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// HostMetricsConfig holds configuration for sampling the node's host during a run.
+// PrometheusURL takes precedence when both sources are set.
+type HostMetricsConfig struct {
+	PrometheusURL   string        // Prometheus server queried over /api/v1/query
+	Selector        string        // Label matchers picking the node's host, e.g. instance="node:9100"
+	NodeExporterURL string        // node_exporter /metrics endpoint scraped directly
+	Interval        time.Duration // Time between samples
+}
+
+// HostSample is one reading of the node host's resource usage. Values the source
+// could not provide are -1.
+type HostSample struct {
+	Elapsed        time.Duration
+	CPUPercent     float64
+	MemoryPercent  float64 // Memory in use, excluding reclaimable caches
+	DiskReadBytes  float64 // Per second
+	DiskWriteBytes float64 // Per second
+}
+
+// hostSource reads one host sample
+type hostSource interface {
+	sample(ctx context.Context) (HostSample, bool, error)
+}
+
+// promRateWindow is the range the Prometheus rate() queries average over
+const promRateWindow = "1m"
+
+// prometheusSource computes samples with PromQL over node_exporter series
+type prometheusSource struct {
+	url      string
+	selector string
+	client   *http.Client
+}
+
+// matchers joins label matchers into a PromQL selector body
+func matchers(extra ...string) string {
+	var parts []string
+	for _, m := range extra {
+		if m != "" {
+			parts = append(parts, m)
+		}
+	}
+	return "{" + strings.Join(parts, ",") + "}"
+}
+
+func (p *prometheusSource) sample(ctx context.Context) (HostSample, bool, error) {
+	sel := matchers(p.selector)
+	s := HostSample{}
+	queries := []struct {
+		query string
+		value *float64
+	}{
+		{fmt.Sprintf(`100 * (1 - avg(rate(node_cpu_seconds_total%s[%s])))`, matchers(`mode="idle"`, p.selector), promRateWindow), &s.CPUPercent},
+		{fmt.Sprintf(`100 * (1 - sum(node_memory_MemAvailable_bytes%s) / sum(node_memory_MemTotal_bytes%s))`, sel, sel), &s.MemoryPercent},
+		{fmt.Sprintf(`sum(rate(node_disk_read_bytes_total%s[%s]))`, sel, promRateWindow), &s.DiskReadBytes},
+		{fmt.Sprintf(`sum(rate(node_disk_written_bytes_total%s[%s]))`, sel, promRateWindow), &s.DiskWriteBytes},
+	}
+
+	var lastErr error
+	for _, q := range queries {
+		v, err := p.query(ctx, q.query)
+		if err != nil {
+			lastErr = err
+			v = -1
+		}
+		*q.value = v
+	}
+	if s.CPUPercent < 0 && s.MemoryPercent < 0 && s.DiskReadBytes < 0 && s.DiskWriteBytes < 0 {
+		return s, false, lastErr
+	}
+	return s, true, nil
+}
+
+// query runs an instant query that must return a single scalar-like vector
+func (p *prometheusSource) query(ctx context.Context, query string) (float64, error) {
+	endpoint := strings.TrimSuffix(p.url, "/") + "/api/v1/query?query=" + url.QueryEscape(query)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return 0, err
+	}
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("prometheus query failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var response struct {
+		Status string `json:"status"`
+		Error  string `json:"error"`
+		Data   struct {
+			Result []struct {
+				Value []interface{} `json:"value"`
+			} `json:"result"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return 0, fmt.Errorf("failed to decode prometheus response (status %d): %w", resp.StatusCode, err)
+	}
+	if response.Status != "success" {
+		return 0, fmt.Errorf("prometheus query failed: %s", response.Error)
+	}
+	if len(response.Data.Result) == 0 || len(response.Data.Result[0].Value) != 2 {
+		return 0, errors.New("prometheus query returned no data")
+	}
+	raw, _ := response.Data.Result[0].Value[1].(string)
+	return strconv.ParseFloat(raw, 64)
+}
+
+// exporterCounters are the node_exporter series a sample is computed from, summed
+// across CPUs and disks
+type exporterCounters struct {
+	at           time.Time
+	cpuIdle      float64
+	cpuTotal     float64
+	memAvailable float64
+	memTotal     float64
+	diskRead     float64
+	diskWritten  float64
+}
+
+// parseExporterMetrics reads the counters used for host samples from node_exporter's
+// text exposition format
+func parseExporterMetrics(text string) exporterCounters {
+	var c exporterCounters
+	scanner := bufio.NewScanner(strings.NewReader(text))
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		name, labels, rest := line, "", ""
+		if i := strings.IndexByte(line, '{'); i >= 0 {
+			end := strings.LastIndexByte(line, '}')
+			if end < i {
+				continue
+			}
+			name, labels, rest = line[:i], line[i+1:end], line[end+1:]
+		} else if i := strings.IndexByte(line, ' '); i >= 0 {
+			name, rest = line[:i], line[i:]
+		}
+		fields := strings.Fields(rest)
+		if len(fields) == 0 {
+			continue
+		}
+		value, err := strconv.ParseFloat(fields[0], 64)
+		if err != nil {
+			continue
+		}
+		switch name {
+		case "node_cpu_seconds_total":
+			c.cpuTotal += value
+			if strings.Contains(labels, `mode="idle"`) {
+				c.cpuIdle += value
+			}
+		case "node_memory_MemAvailable_bytes":
+			c.memAvailable = value
+		case "node_memory_MemTotal_bytes":
+			c.memTotal = value
+		case "node_disk_read_bytes_total":
+			c.diskRead += value
+		case "node_disk_written_bytes_total":
+			c.diskWritten += value
+		}
+	}
+	return c
+}
+
+// hostSampleBetween computes CPU and disk rates from two scrapes, and memory from the later one
+func hostSampleBetween(prev, cur exporterCounters) HostSample {
+	s := HostSample{CPUPercent: -1, MemoryPercent: -1, DiskReadBytes: -1, DiskWriteBytes: -1}
+	if cpu := cur.cpuTotal - prev.cpuTotal; cpu > 0 {
+		s.CPUPercent = 100 * (1 - (cur.cpuIdle-prev.cpuIdle)/cpu)
+	}
+	if cur.memTotal > 0 {
+		s.MemoryPercent = 100 * (1 - cur.memAvailable/cur.memTotal)
+	}
+	if seconds := cur.at.Sub(prev.at).Seconds(); seconds > 0 {
+		s.DiskReadBytes = (cur.diskRead - prev.diskRead) / seconds
+		s.DiskWriteBytes = (cur.diskWritten - prev.diskWritten) / seconds
+	}
+	return s
+}
+
+// exporterSource scrapes node_exporter directly and differentiates its counters
+type exporterSource struct {
+	url    string
+	client *http.Client
+	prev   *exporterCounters
+}
+
+func (e *exporterSource) sample(ctx context.Context) (HostSample, bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, e.url, nil)
+	if err != nil {
+		return HostSample{}, false, err
+	}
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return HostSample{}, false, fmt.Errorf("node_exporter scrape failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return HostSample{}, false, fmt.Errorf("node_exporter scrape failed: status %d", resp.StatusCode)
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return HostSample{}, false, fmt.Errorf("failed to read node_exporter response: %w", err)
+	}
+
+	cur := parseExporterMetrics(string(body))
+	cur.at = time.Now()
+	prev := e.prev
+	e.prev = &cur
+	if prev == nil {
+		return HostSample{}, false, nil // Rates need a second scrape
+	}
+	return hostSampleBetween(*prev, cur), true, nil
+}
+
+// MonitorHost samples the node host's CPU, memory and disk I/O every interval until
+// ctx is cancelled. Run it alongside the load so host usage lands in the run report.
+func MonitorHost(ctx context.Context, config *HostMetricsConfig) ([]HostSample, error) {
+	client := &http.Client{Timeout: 10 * time.Second}
+	var source hostSource
+	switch {
+	case config.PrometheusURL != "":
+		source = &prometheusSource{url: config.PrometheusURL, selector: config.Selector, client: client}
+	case config.NodeExporterURL != "":
+		exporter := &exporterSource{url: config.NodeExporterURL, client: client}
+		exporter.sample(ctx) // First scrape so the first tick can compute rates
+		source = exporter
+	default:
+		return nil, errors.New("host metrics need a Prometheus or node_exporter URL")
+	}
+
+	ticker := time.NewTicker(config.Interval)
+	defer ticker.Stop()
+	start := time.Now()
+	var samples []HostSample
+	var lastErr error
+	for {
+		select {
+		case <-ctx.Done():
+			if len(samples) == 0 && lastErr != nil {
+				return samples, lastErr
+			}
+			return samples, nil
+		case <-ticker.C:
+			s, ok, err := source.sample(ctx)
+			if ctx.Err() != nil {
+				continue
+			}
+			if err != nil {
+				lastErr = err
+			}
+			if !ok {
+				continue
+			}
+			s.Elapsed = time.Since(start)
+			samples = append(samples, s)
+		}
+	}
+}
+
+// formatHostValue prints a value with its unit, or n/a when it was not available
+func formatHostValue(v float64, unit string) string {
+	if v < 0 {
+		return "n/a"
+	}
+	if unit == "%" {
+		return fmt.Sprintf("%.1f%%", v)
+	}
+	return fmt.Sprintf("%.1f MB/s", v/1e6)
+}
+
+// PrintHostMetrics prints every host sample and the average and peak of each metric
+func PrintHostMetrics(samples []HostSample) {
+	fmt.Printf("\n=== Node Host Metrics ===\n")
+	fmt.Printf("%10s %8s %8s %12s %12s\n", "elapsed", "cpu", "memory", "disk read", "disk write")
+	for _, s := range samples {
+		fmt.Printf("%10s %8s %8s %12s %12s\n", s.Elapsed.Round(time.Second),
+			formatHostValue(s.CPUPercent, "%"), formatHostValue(s.MemoryPercent, "%"),
+			formatHostValue(s.DiskReadBytes, "B"), formatHostValue(s.DiskWriteBytes, "B"))
+	}
+	metrics := []struct {
+		name  string
+		unit  string
+		value func(HostSample) float64
+	}{
+		{"CPU", "%", func(s HostSample) float64 { return s.CPUPercent }},
+		{"Memory", "%", func(s HostSample) float64 { return s.MemoryPercent }},
+		{"Disk read", "B", func(s HostSample) float64 { return s.DiskReadBytes }},
+		{"Disk write", "B", func(s HostSample) float64 { return s.DiskWriteBytes }},
+	}
+	for _, m := range metrics {
+		sum, peak, n := 0.0, 0.0, 0
+		for _, s := range samples {
+			if v := m.value(s); v >= 0 {
+				sum += v
+				n++
+				if v > peak {
+					peak = v
+				}
+			}
+		}
+		if n > 0 {
+			fmt.Printf("%s avg: %s, peak: %s\n", m.name, formatHostValue(sum/float64(n), m.unit), formatHostValue(peak, m.unit))
+		}
+	}
+	fmt.Printf("==========================\n")
+}
@@ -0,0 +1,58 @@
+package loadtest
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+const exporterScrape = `# HELP node_cpu_seconds_total Seconds the CPUs spent in each mode.
+# TYPE node_cpu_seconds_total counter
+node_cpu_seconds_total{cpu="0",mode="idle"} 80
+node_cpu_seconds_total{cpu="0",mode="user"} 20
+node_cpu_seconds_total{cpu="1",mode="idle"} 70
+node_cpu_seconds_total{cpu="1",mode="system"} 30
+node_memory_MemAvailable_bytes 2.5e+09
+node_memory_MemTotal_bytes 1e+10
+node_disk_read_bytes_total{device="sda"} 1000
+node_disk_written_bytes_total{device="sda"} 4000
+node_disk_written_bytes_total{device="sdb"} 6000
+`
+
+func TestParseExporterMetrics(t *testing.T) {
+	c := parseExporterMetrics(exporterScrape)
+	if c.cpuIdle != 150 || c.cpuTotal != 200 {
+		t.Errorf("expected 150 idle of 200 CPU seconds, got %f of %f", c.cpuIdle, c.cpuTotal)
+	}
+	if c.memAvailable != 2.5e9 || c.memTotal != 1e10 {
+		t.Errorf("unexpected memory counters: %f of %f", c.memAvailable, c.memTotal)
+	}
+	if c.diskRead != 1000 || c.diskWritten != 10000 {
+		t.Errorf("expected disk counters summed over devices, got read %f written %f", c.diskRead, c.diskWritten)
+	}
+}
+
+func TestHostSampleBetween(t *testing.T) {
+	start := time.Now()
+	prev := exporterCounters{at: start, cpuIdle: 150, cpuTotal: 200, diskRead: 1000, diskWritten: 10000}
+	cur := exporterCounters{at: start.Add(10 * time.Second), cpuIdle: 165, cpuTotal: 220,
+		memAvailable: 2.5e9, memTotal: 1e10, diskRead: 11000, diskWritten: 30000}
+
+	s := hostSampleBetween(prev, cur)
+	if math.Abs(s.CPUPercent-25) > 1e-9 {
+		t.Errorf("expected 25%% CPU, got %f", s.CPUPercent)
+	}
+	if math.Abs(s.MemoryPercent-75) > 1e-9 {
+		t.Errorf("expected 75%% memory, got %f", s.MemoryPercent)
+	}
+	if s.DiskReadBytes != 1000 || s.DiskWriteBytes != 2000 {
+		t.Errorf("expected 1000 B/s read and 2000 B/s written, got %f and %f", s.DiskReadBytes, s.DiskWriteBytes)
+	}
+
+	t.Run("missing series are unavailable", func(t *testing.T) {
+		s := hostSampleBetween(exporterCounters{at: start}, exporterCounters{at: start})
+		if s.CPUPercent != -1 || s.MemoryPercent != -1 || s.DiskReadBytes != -1 {
+			t.Errorf("expected unavailable values, got %+v", s)
+		}
+	})
+}
@@ -0,0 +1,168 @@
+package loadtest
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aakash4dev/ethereum-transaction-simulator/internal/transaction"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// lagChartWidth is the width of the widest bar in the lag chart
+const lagChartWidth = 40
+
+// LagSample compares what the simulator submitted with what the node took during
+// one interval. Rates are per second; fields that could not be read are -1.
+type LagSample struct {
+	Elapsed     time.Duration
+	SubmitRate  float64 // SendTransaction calls, including retries
+	AcceptRate  float64 // Submissions the node accepted
+	RejectRate  float64 // Submissions the node refused
+	IncludeRate float64 // Accepted transactions included in blocks
+	InFlight    int64   // Accepted so far but not yet included
+	PoolSize    int     // txpool_status pending plus queued
+	Dropped     int64   // In flight beyond the whole pool's size: accepted, then silently dropped
+}
+
+// Shedding reports whether the node was refusing or dropping more than threshold
+// (a fraction, e.g. 0.05) of what was submitted in the sample
+func (s LagSample) Shedding(threshold float64) bool {
+	if s.SubmitRate <= 0 {
+		return false
+	}
+	if s.RejectRate/s.SubmitRate > threshold {
+		return true
+	}
+	return s.InFlight > 0 && float64(s.Dropped)/float64(s.InFlight) > threshold
+}
+
+// MonitorAcceptance samples the parallel sender's submit, accept and reject counts,
+// its inclusion count and the node's txpool size every interval until ctx is
+// cancelled. ps.TrackInclusion must be called before the load starts for the
+// include rate to be known.
+func MonitorAcceptance(ctx context.Context, client *rpc.Client, ps *transaction.ParallelSender, interval time.Duration) []LagSample {
+	reader := &nodeStatsReader{client: client, unavailable: make(map[string]bool)}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	last := time.Now()
+	start := last
+	var lastSubmitted, lastAccepted, lastRejected, lastIncluded int64
+	var samples []LagSample
+	for {
+		select {
+		case <-ctx.Done():
+			return samples
+		case <-ticker.C:
+			submitted, accepted, rejected, included := ps.AcceptanceCounts()
+			now := time.Now()
+			var status struct {
+				Pending hexutil.Uint64 `json:"pending"`
+				Queued  hexutil.Uint64 `json:"queued"`
+			}
+			poolSize := -1
+			if reader.call(ctx, &status, "txpool_status") {
+				poolSize = int(status.Pending + status.Queued)
+			}
+			if ctx.Err() != nil {
+				return samples
+			}
+
+			seconds := now.Sub(last).Seconds()
+			s := LagSample{
+				Elapsed:     now.Sub(start),
+				SubmitRate:  float64(submitted-lastSubmitted) / seconds,
+				AcceptRate:  float64(accepted-lastAccepted) / seconds,
+				RejectRate:  float64(rejected-lastRejected) / seconds,
+				IncludeRate: -1,
+				InFlight:    -1,
+				PoolSize:    poolSize,
+				Dropped:     -1,
+			}
+			if included >= 0 {
+				s.IncludeRate = float64(included-lastIncluded) / seconds
+				s.InFlight = accepted - included
+				if poolSize >= 0 && s.InFlight > int64(poolSize) {
+					s.Dropped = s.InFlight - int64(poolSize)
+				} else if poolSize >= 0 {
+					s.Dropped = 0
+				}
+			}
+			samples = append(samples, s)
+			last = now
+			lastSubmitted, lastAccepted, lastRejected, lastIncluded = submitted, accepted, rejected, included
+		}
+	}
+}
+
+// SheddingOnset returns the first sample in which the node shed more than threshold
+// of the submissions, and false when it never did. Its SubmitRate is the rate at
+// which the node started shedding.
+func SheddingOnset(samples []LagSample, threshold float64) (LagSample, bool) {
+	for _, s := range samples {
+		if s.Shedding(threshold) {
+			return s, true
+		}
+	}
+	return LagSample{}, false
+}
+
+// formatRate prints a rate, or n/a when it could not be measured
+func formatRate(r float64) string {
+	if r < 0 {
+		return "n/a"
+	}
+	return fmt.Sprintf("%.1f", r)
+}
+
+// lagBar draws the submit rate as a bar, with the part the node did not accept
+// marked so the lag is visible down the chart
+func lagBar(s LagSample, scale float64) string {
+	if scale <= 0 {
+		return ""
+	}
+	total := int(s.SubmitRate/scale + 0.5)
+	taken := int(s.AcceptRate/scale + 0.5)
+	if taken > total {
+		taken = total
+	}
+	return strings.Repeat("#", taken) + strings.Repeat("x", total-taken)
+}
+
+// PrintAcceptanceLag prints one row per sample with a bar chart of submitted (#
+// accepted, x refused) transactions, then the rate at which shedding began
+func PrintAcceptanceLag(samples []LagSample, threshold float64) {
+	fmt.Printf("\n=== Submit vs Acceptance ===\n")
+	peak := 0.0
+	for _, s := range samples {
+		if s.SubmitRate > peak {
+			peak = s.SubmitRate
+		}
+	}
+	scale := peak / lagChartWidth
+	fmt.Printf("%10s %9s %9s %9s %9s %9s %8s %8s\n", "elapsed", "submit/s", "accept/s", "reject/s", "include/s", "inflight", "pool", "dropped")
+	for _, s := range samples {
+		marker := " "
+		if s.Shedding(threshold) {
+			marker = "!"
+		}
+		fmt.Printf("%10s %9.1f %9.1f %9.1f %9s %9s %8s %8s %s|%s\n", s.Elapsed.Round(time.Second),
+			s.SubmitRate, s.AcceptRate, s.RejectRate, formatRate(s.IncludeRate),
+			formatNodeValue(int(s.InFlight)), formatNodeValue(s.PoolSize), formatNodeValue(int(s.Dropped)),
+			marker, lagBar(s, scale))
+	}
+	if onset, ok := SheddingOnset(samples, threshold); ok {
+		fmt.Printf("Node started shedding at %.1f submissions/s (%s into the run): %.1f/s refused",
+			onset.SubmitRate, onset.Elapsed.Round(time.Second), onset.RejectRate)
+		if onset.Dropped > 0 {
+			fmt.Printf(", %d accepted then dropped", onset.Dropped)
+		}
+		fmt.Println()
+	} else {
+		fmt.Printf("Node kept up: it never shed more than %.0f%% of submissions\n", threshold*100)
+	}
+	fmt.Printf("==========================\n")
+}
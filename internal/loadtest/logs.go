@@ -0,0 +1,208 @@
+package loadtest
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"math/rand"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// logFixedSize approximates the JSON-RPC encoding of a log without its topics and data:
+// address, block number and hash, transaction hash and index, log index and removed
+const logFixedSize = 20 + 8 + 32 + 32 + 4 + 4 + 1
+
+// LogsConfig holds configuration for the eth_getLogs stress workload
+type LogsConfig struct {
+	Rate        float64          // Queries per second (0 = as fast as Concurrency allows)
+	Duration    time.Duration    // How long to send queries
+	Concurrency int              // Maximum queries in flight
+	Contracts   []common.Address // Contracts whose logs are queried (empty queries all)
+	Topics      []common.Hash    // Accepted topic0 values (empty matches any event)
+	Ranges      []uint64         // Block range widths, one picked at random per query
+}
+
+// LogRangeStats summarizes the queries of one block range width
+type LogRangeStats struct {
+	Range      uint64
+	Count      int
+	Errors     int
+	Logs       int   // Logs returned across all successful queries
+	Bytes      int64 // Approximate response payload across all successful queries
+	MaxLogs    int   // Largest single response
+	P50Latency time.Duration
+	P95Latency time.Duration
+	latencies  []time.Duration
+}
+
+// LogsReport summarizes an eth_getLogs stress run
+type LogsReport struct {
+	Duration time.Duration
+	Ranges   []*LogRangeStats // In the configured order
+}
+
+// ParseBlockRanges parses comma-separated block range widths such as "100,1000,10000"
+func ParseBlockRanges(s string) ([]uint64, error) {
+	var ranges []uint64
+	for _, entry := range splitComma(s) {
+		width, err := strconv.ParseUint(entry, 10, 64)
+		if err != nil || width == 0 {
+			return nil, fmt.Errorf("invalid block range %q: must be a positive number of blocks", entry)
+		}
+		ranges = append(ranges, width)
+	}
+	if len(ranges) == 0 {
+		return nil, fmt.Errorf("at least one block range is required")
+	}
+	return ranges, nil
+}
+
+// ParseTopics parses comma-separated topic0 filters, each either an event signature
+// such as "Transfer(address,address,uint256)" or a 32-byte hex topic
+func ParseTopics(s string) ([]common.Hash, error) {
+	var topics []common.Hash
+	for _, entry := range splitComma(s) {
+		switch {
+		case strings.HasPrefix(entry, "0x") && len(entry) == 66:
+			topics = append(topics, common.HexToHash(entry))
+		case strings.Contains(entry, "(") && strings.HasSuffix(entry, ")"):
+			topics = append(topics, crypto.Keccak256Hash([]byte(strings.ReplaceAll(entry, " ", ""))))
+		default:
+			return nil, fmt.Errorf("invalid topic %q: expected an event signature or 32-byte hex", entry)
+		}
+	}
+	return topics, nil
+}
+
+// splitComma splits a comma-separated list, dropping empty entries. Event signatures
+// contain commas inside parentheses, which are kept together.
+func splitComma(s string) []string {
+	var entries []string
+	depth, start := 0, 0
+	for i, r := range s {
+		switch r {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				entries = append(entries, s[start:i])
+				start = i + 1
+			}
+		}
+	}
+	entries = append(entries, s[start:])
+
+	result := entries[:0]
+	for _, entry := range entries {
+		if entry = strings.TrimSpace(entry); entry != "" {
+			result = append(result, entry)
+		}
+	}
+	return result
+}
+
+// RunLogs issues eth_getLogs queries over randomly placed windows of the configured
+// widths, filtered to the contracts and topics, to stress the node's log index
+func RunLogs(ctx context.Context, client *ethclient.Client, config *LogsConfig) (*LogsReport, error) {
+	head, err := client.BlockNumber(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get latest block: %w", err)
+	}
+	ctx, cancel := context.WithTimeout(ctx, config.Duration)
+	defer cancel()
+
+	report := &LogsReport{}
+	for _, width := range config.Ranges {
+		report.Ranges = append(report.Ranges, &LogRangeStats{Range: width})
+	}
+	query := ethereum.FilterQuery{Addresses: config.Contracts}
+	if len(config.Topics) > 0 {
+		query.Topics = [][]common.Hash{config.Topics}
+	}
+
+	var mu sync.Mutex
+	report.Duration = runQueries(ctx, config.Rate, config.Concurrency, func(rng *rand.Rand) func() {
+		stats := report.Ranges[rng.Intn(len(report.Ranges))]
+		from, to := logWindow(head, stats.Range, rng)
+		q := query
+		q.FromBlock, q.ToBlock = new(big.Int).SetUint64(from), new(big.Int).SetUint64(to)
+		return func() {
+			sentAt := time.Now()
+			logs, err := client.FilterLogs(ctx, q)
+			latency := time.Since(sentAt)
+			if ctx.Err() != nil {
+				return // Cut off by the end of the run, not a node failure
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			stats.Count++
+			if err != nil {
+				stats.Errors++
+				return
+			}
+			stats.latencies = append(stats.latencies, latency)
+			stats.Logs += len(logs)
+			stats.Bytes += logsSize(logs)
+			if len(logs) > stats.MaxLogs {
+				stats.MaxLogs = len(logs)
+			}
+		}
+	})
+	for _, stats := range report.Ranges {
+		stats.P50Latency = Percentile(stats.latencies, 50)
+		stats.P95Latency = Percentile(stats.latencies, 95)
+		stats.latencies = nil
+	}
+	return report, nil
+}
+
+// logWindow places a window of width blocks at a random point no later than head, so
+// successive queries do not hit the same cached range
+func logWindow(head, width uint64, rng *rand.Rand) (from, to uint64) {
+	if width > head {
+		return 0, head
+	}
+	to = width + uint64(rng.Int63n(int64(head-width+1)))
+	return to - width, to
+}
+
+// logsSize approximates the response payload of logs
+func logsSize(logs []types.Log) int64 {
+	var size int64
+	for _, l := range logs {
+		size += int64(logFixedSize + 32*len(l.Topics) + len(l.Data))
+	}
+	return size
+}
+
+// PrintLogsReport prints per-range query counts, response sizes and latencies
+func PrintLogsReport(report *LogsReport) {
+	fmt.Printf("\n=== eth_getLogs Stress Summary ===\n")
+	fmt.Printf("Duration: %s\n", report.Duration.Round(time.Second))
+	for _, stats := range report.Ranges {
+		if stats.Count == 0 {
+			continue
+		}
+		avgLogs, avgBytes := 0.0, 0.0
+		if ok := stats.Count - stats.Errors; ok > 0 {
+			avgLogs = float64(stats.Logs) / float64(ok)
+			avgBytes = float64(stats.Bytes) / float64(ok)
+		}
+		fmt.Printf("  %d blocks: %d queries, %d errors, %.1f logs (~%.0f bytes) per response, max %d logs, p50 %s, p95 %s\n",
+			stats.Range, stats.Count, stats.Errors, avgLogs, avgBytes, stats.MaxLogs,
+			stats.P50Latency.Round(time.Millisecond), stats.P95Latency.Round(time.Millisecond))
+	}
+	fmt.Printf("==========================\n")
+}
@@ -0,0 +1,52 @@
+package loadtest
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestParseLogsFilters(t *testing.T) {
+	t.Run("Ranges", func(t *testing.T) {
+		ranges, err := ParseBlockRanges("100, 1000,10000")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(ranges) != 3 || ranges[0] != 100 || ranges[2] != 10000 {
+			t.Errorf("unexpected ranges %v", ranges)
+		}
+		for _, s := range []string{"", "0", "-5", "abc"} {
+			if _, err := ParseBlockRanges(s); err == nil {
+				t.Errorf("%q: expected error", s)
+			}
+		}
+	})
+
+	t.Run("Topics", func(t *testing.T) {
+		topics, err := ParseTopics("Transfer(address,address,uint256), 0x" + "ab" + "00000000000000000000000000000000000000000000000000000000000000")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(topics) != 2 {
+			t.Errorf("expected 2 topics, got %d", len(topics))
+		}
+		if topics, err := ParseTopics(""); err != nil || len(topics) != 0 {
+			t.Errorf("expected no topics, got %v (%v)", topics, err)
+		}
+		if _, err := ParseTopics("Transfer"); err == nil {
+			t.Error("expected error for bare event name")
+		}
+	})
+}
+
+func TestLogWindow(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 1000; i++ {
+		from, to := logWindow(5000, 1000, rng)
+		if to-from != 1000 || to > 5000 {
+			t.Fatalf("invalid window [%d, %d]", from, to)
+		}
+	}
+	if from, to := logWindow(50, 1000, rng); from != 0 || to != 50 {
+		t.Errorf("expected window clamped to [0, 50], got [%d, %d]", from, to)
+	}
+}
@@ -0,0 +1,197 @@
+package loadtest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/aakash4dev/ethereum-transaction-simulator/internal/transaction"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// NodeSample is one snapshot of the node's network state during a run. Fields read
+// from a namespace the node does not expose are -1.
+type NodeSample struct {
+	Elapsed  time.Duration
+	SendRate float64 // Transactions sent per second since the previous sample
+	Peers    int     // net_peerCount, or the length of admin_peers
+	Pending  int     // txpool_status pending
+	Queued   int     // txpool_status queued
+	Syncing  bool
+	SyncLag  uint64 // Highest minus current block while syncing
+}
+
+// nodeStatsReader queries the node, remembering which methods it does not serve so
+// they are not retried every interval
+type nodeStatsReader struct {
+	client      *rpc.Client
+	unavailable map[string]bool
+}
+
+// call invokes method unless an earlier call showed the node does not serve it
+func (r *nodeStatsReader) call(ctx context.Context, result interface{}, method string) bool {
+	if r.unavailable[method] {
+		return false
+	}
+	if err := r.client.CallContext(ctx, result, method); err != nil {
+		if ctx.Err() == nil {
+			r.unavailable[method] = true
+		}
+		return false
+	}
+	return true
+}
+
+// peers returns the peer count from the net namespace, falling back to admin
+func (r *nodeStatsReader) peers(ctx context.Context) int {
+	var count hexutil.Uint64
+	if r.call(ctx, &count, "net_peerCount") {
+		return int(count)
+	}
+	var peers []json.RawMessage
+	if r.call(ctx, &peers, "admin_peers") {
+		return len(peers)
+	}
+	return -1
+}
+
+// sample reads one snapshot; send rate and elapsed time are filled in by the caller
+func (r *nodeStatsReader) sample(ctx context.Context) NodeSample {
+	s := NodeSample{Peers: r.peers(ctx), Pending: -1, Queued: -1}
+
+	var status struct {
+		Pending hexutil.Uint64 `json:"pending"`
+		Queued  hexutil.Uint64 `json:"queued"`
+	}
+	if r.call(ctx, &status, "txpool_status") {
+		s.Pending = int(status.Pending)
+		s.Queued = int(status.Queued)
+	}
+
+	var syncing json.RawMessage
+	if r.call(ctx, &syncing, "eth_syncing") && string(syncing) != "false" {
+		var progress struct {
+			CurrentBlock hexutil.Uint64 `json:"currentBlock"`
+			HighestBlock hexutil.Uint64 `json:"highestBlock"`
+		}
+		if json.Unmarshal(syncing, &progress) == nil {
+			s.Syncing = true
+			if progress.HighestBlock > progress.CurrentBlock {
+				s.SyncLag = uint64(progress.HighestBlock - progress.CurrentBlock)
+			}
+		}
+	}
+	return s
+}
+
+// MonitorNode samples the node's peer count, txpool size and sync status every
+// interval, together with the parallel sender's send rate, until ctx is cancelled.
+// Run it alongside the load so node-side context ends up in the run report; methods
+// the node does not expose (admin, txpool) are skipped after their first failure.
+func MonitorNode(ctx context.Context, client *rpc.Client, ps *transaction.ParallelSender, interval time.Duration) []NodeSample {
+	reader := &nodeStatsReader{client: client, unavailable: make(map[string]bool)}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	start := time.Now()
+	last := start
+	var lastSent int64
+	var samples []NodeSample
+	for {
+		select {
+		case <-ctx.Done():
+			return samples
+		case <-ticker.C:
+			sent, _, _, _ := ps.GetMetrics()
+			now := time.Now()
+			s := reader.sample(ctx)
+			if ctx.Err() != nil {
+				return samples
+			}
+			s.Elapsed = now.Sub(start)
+			s.SendRate = float64(sent-lastSent) / now.Sub(last).Seconds()
+			samples = append(samples, s)
+			last, lastSent = now, sent
+		}
+	}
+}
+
+// Correlation returns the Pearson correlation of xs and ys, or false when there are
+// fewer than two pairs or either series is constant
+func Correlation(xs, ys []float64) (float64, bool) {
+	n := len(xs)
+	if n < 2 || len(ys) != n {
+		return 0, false
+	}
+	var meanX, meanY float64
+	for i := range xs {
+		meanX += xs[i]
+		meanY += ys[i]
+	}
+	meanX /= float64(n)
+	meanY /= float64(n)
+
+	var cov, varX, varY float64
+	for i := range xs {
+		dx, dy := xs[i]-meanX, ys[i]-meanY
+		cov += dx * dy
+		varX += dx * dx
+		varY += dy * dy
+	}
+	if varX == 0 || varY == 0 {
+		return 0, false
+	}
+	return cov / math.Sqrt(varX*varY), true
+}
+
+// sendRateCorrelation correlates the send rate with the metric picked by value,
+// skipping samples where the metric was not available
+func sendRateCorrelation(samples []NodeSample, value func(NodeSample) int) (float64, bool) {
+	var rates, values []float64
+	for _, s := range samples {
+		if v := value(s); v >= 0 {
+			rates = append(rates, s.SendRate)
+			values = append(values, float64(v))
+		}
+	}
+	return Correlation(rates, values)
+}
+
+// formatNodeValue prints a metric, or n/a when the node did not expose it
+func formatNodeValue(v int) string {
+	if v < 0 {
+		return "n/a"
+	}
+	return fmt.Sprintf("%d", v)
+}
+
+// PrintNodeStats prints every sample and how peer count and txpool size moved with the send rate
+func PrintNodeStats(samples []NodeSample) {
+	fmt.Printf("\n=== Node Network Stats ===\n")
+	fmt.Printf("%10s %10s %6s %8s %8s %s\n", "elapsed", "send TPS", "peers", "pending", "queued", "sync")
+	for _, s := range samples {
+		sync := "synced"
+		if s.Syncing {
+			sync = fmt.Sprintf("syncing (%d behind)", s.SyncLag)
+		}
+		fmt.Printf("%10s %10.1f %6s %8s %8s %s\n", s.Elapsed.Round(time.Second), s.SendRate,
+			formatNodeValue(s.Peers), formatNodeValue(s.Pending), formatNodeValue(s.Queued), sync)
+	}
+	metrics := []struct {
+		name  string
+		value func(NodeSample) int
+	}{
+		{"txpool pending", func(s NodeSample) int { return s.Pending }},
+		{"txpool queued", func(s NodeSample) int { return s.Queued }},
+		{"peer count", func(s NodeSample) int { return s.Peers }},
+	}
+	for _, m := range metrics {
+		if r, ok := sendRateCorrelation(samples, m.value); ok {
+			fmt.Printf("Correlation of send rate with %s: %.2f\n", m.name, r)
+		}
+	}
+	fmt.Printf("==========================\n")
+}
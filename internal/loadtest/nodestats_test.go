@@ -0,0 +1,72 @@
+package loadtest
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCorrelation(t *testing.T) {
+	t.Run("perfectly correlated", func(t *testing.T) {
+		r, ok := Correlation([]float64{1, 2, 3}, []float64{10, 20, 30})
+		if !ok || math.Abs(r-1) > 1e-9 {
+			t.Errorf("expected 1, got %f (ok=%t)", r, ok)
+		}
+	})
+
+	t.Run("inversely correlated", func(t *testing.T) {
+		r, ok := Correlation([]float64{1, 2, 3}, []float64{30, 20, 10})
+		if !ok || math.Abs(r+1) > 1e-9 {
+			t.Errorf("expected -1, got %f (ok=%t)", r, ok)
+		}
+	})
+
+	t.Run("constant series is undefined", func(t *testing.T) {
+		if _, ok := Correlation([]float64{1, 2, 3}, []float64{5, 5, 5}); ok {
+			t.Error("expected no correlation for a constant series")
+		}
+	})
+
+	t.Run("unavailable samples are skipped", func(t *testing.T) {
+		samples := []NodeSample{
+			{SendRate: 10, Pending: 100},
+			{SendRate: 20, Pending: -1},
+			{SendRate: 30, Pending: 300},
+		}
+		r, ok := sendRateCorrelation(samples, func(s NodeSample) int { return s.Pending })
+		if !ok || math.Abs(r-1) > 1e-9 {
+			t.Errorf("expected 1 from the two available samples, got %f (ok=%t)", r, ok)
+		}
+	})
+}
+
+func TestSheddingOnset(t *testing.T) {
+	samples := []LagSample{
+		{SubmitRate: 100, AcceptRate: 100, InFlight: 50, Dropped: 0},
+		{SubmitRate: 200, AcceptRate: 198, RejectRate: 2, InFlight: 120, Dropped: 0},
+		{SubmitRate: 300, AcceptRate: 300, InFlight: 400, Dropped: 100},
+		{SubmitRate: 400, AcceptRate: 300, RejectRate: 100, InFlight: 500, Dropped: 100},
+	}
+
+	t.Run("dropped transactions count as shedding", func(t *testing.T) {
+		onset, ok := SheddingOnset(samples, 0.05)
+		if !ok || onset.SubmitRate != 300 {
+			t.Errorf("expected onset at 300/s, got %+v (ok=%t)", onset, ok)
+		}
+	})
+
+	t.Run("threshold is respected", func(t *testing.T) {
+		onset, ok := SheddingOnset(samples[:2], 0.005)
+		if !ok || onset.SubmitRate != 200 {
+			t.Errorf("expected onset at 200/s, got %+v (ok=%t)", onset, ok)
+		}
+		if _, ok := SheddingOnset(samples[:2], 0.05); ok {
+			t.Error("expected no shedding below the threshold")
+		}
+	})
+
+	t.Run("unknown drops are ignored", func(t *testing.T) {
+		if (LagSample{SubmitRate: 10, AcceptRate: 10, InFlight: -1, Dropped: -1}).Shedding(0.05) {
+			t.Error("expected no shedding without inclusion tracking")
+		}
+	})
+}
@@ -0,0 +1,248 @@
+package loadtest
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"math/rand"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// Read query kinds accepted in a read mix
+const (
+	ReadCall    = "call"    // eth_call
+	ReadBalance = "balance" // eth_getBalance
+	ReadLogs    = "logs"    // eth_getLogs
+	ReadStorage = "storage" // eth_getStorageAt
+)
+
+// readKinds lists the query kinds in report order
+var readKinds = []string{ReadCall, ReadBalance, ReadLogs, ReadStorage}
+
+// ReadConfig holds configuration for read (query) load
+type ReadConfig struct {
+	Rate          float64          // Queries per second (0 = as fast as Concurrency allows)
+	Duration      time.Duration    // How long to send queries
+	Concurrency   int              // Maximum queries in flight
+	Mix           map[string]int   // Relative weight of each query kind
+	Targets       []common.Address // Accounts and contracts queried
+	CallData      []byte           // Calldata for eth_call, e.g. a view function selector
+	LogBlockRange uint64           // Blocks covered by each eth_getLogs query, ending at the head
+}
+
+// ReadStats summarizes the queries of one kind
+type ReadStats struct {
+	Count      int
+	Errors     int
+	P50Latency time.Duration
+	P95Latency time.Duration
+	latencies  []time.Duration
+}
+
+// ReadReport summarizes a read load run
+type ReadReport struct {
+	Duration time.Duration
+	Kinds    map[string]*ReadStats
+}
+
+// ParseReadMix parses a weighted mix such as "call=40,balance=30,logs=10,storage=20"
+func ParseReadMix(s string) (map[string]int, error) {
+	mix := make(map[string]int)
+	total := 0
+	for _, entry := range strings.Split(s, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		kind, weight, ok := strings.Cut(entry, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid read mix entry %q: expected kind=weight", entry)
+		}
+		kind = strings.TrimSpace(kind)
+		if !isReadKind(kind) {
+			return nil, fmt.Errorf("unknown read kind %q (valid: %s)", kind, strings.Join(readKinds, ", "))
+		}
+		w, err := strconv.Atoi(strings.TrimSpace(weight))
+		if err != nil || w < 0 {
+			return nil, fmt.Errorf("invalid weight for %s: %s", kind, weight)
+		}
+		mix[kind] = w
+		total += w
+	}
+	if total == 0 {
+		return nil, fmt.Errorf("read mix must have at least one positive weight")
+	}
+	return mix, nil
+}
+
+// isReadKind reports whether kind is a supported query kind
+func isReadKind(kind string) bool {
+	for _, k := range readKinds {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// pickRead chooses a query kind with probability proportional to its weight
+func pickRead(mix map[string]int, rng *rand.Rand) string {
+	total := 0
+	for _, kind := range readKinds {
+		total += mix[kind]
+	}
+	n := rng.Intn(total)
+	for _, kind := range readKinds {
+		if n < mix[kind] {
+			return kind
+		}
+		n -= mix[kind]
+	}
+	return readKinds[len(readKinds)-1]
+}
+
+// RunReads floods the node with read queries in the configured mix until Duration
+// elapses or the context is cancelled. It can run alongside a write load to measure
+// RPC serving capacity under combined read/write pressure.
+func RunReads(ctx context.Context, client *ethclient.Client, config *ReadConfig) (*ReadReport, error) {
+	if len(config.Targets) == 0 {
+		return nil, fmt.Errorf("read load needs at least one target address")
+	}
+	ctx, cancel := context.WithTimeout(ctx, config.Duration)
+	defer cancel()
+
+	report := &ReadReport{Kinds: make(map[string]*ReadStats)}
+	for _, kind := range readKinds {
+		report.Kinds[kind] = &ReadStats{}
+	}
+
+	var mu sync.Mutex
+	report.Duration = runQueries(ctx, config.Rate, config.Concurrency, func(rng *rand.Rand) func() {
+		kind := pickRead(config.Mix, rng)
+		target := config.Targets[rng.Intn(len(config.Targets))]
+		return func() {
+			sentAt := time.Now()
+			err := runRead(ctx, client, config, kind, target)
+			latency := time.Since(sentAt)
+			if ctx.Err() != nil {
+				return // Cut off by the end of the run, not a node failure
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			stats := report.Kinds[kind]
+			stats.Count++
+			if err != nil {
+				stats.Errors++
+				return
+			}
+			stats.latencies = append(stats.latencies, latency)
+		}
+	})
+	for _, stats := range report.Kinds {
+		stats.P50Latency = Percentile(stats.latencies, 50)
+		stats.P95Latency = Percentile(stats.latencies, 95)
+		stats.latencies = nil
+	}
+	return report, nil
+}
+
+// runQueries calls next at rate per second (or as fast as concurrency allows when rate is
+// 0) until the context is done, running each returned query in its own goroutine with at
+// most concurrency in flight. It returns once every query has finished, with the elapsed time.
+func runQueries(ctx context.Context, rate float64, concurrency int, next func(rng *rand.Rand) func()) time.Duration {
+	var tick <-chan time.Time
+	if rate > 0 {
+		ticker := time.NewTicker(time.Duration(float64(time.Second) / rate))
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	var wg sync.WaitGroup
+	semaphore := make(chan struct{}, concurrency)
+	rng := rand.New(rand.NewSource(time.Now().UnixNano()))
+	start := time.Now()
+
+	for {
+		if tick != nil {
+			select {
+			case <-ctx.Done():
+				wg.Wait()
+				return time.Since(start)
+			case <-tick:
+			}
+		}
+		select {
+		case <-ctx.Done():
+			wg.Wait()
+			return time.Since(start)
+		case semaphore <- struct{}{}:
+		}
+
+		query := next(rng)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+			query()
+		}()
+	}
+}
+
+// runRead issues one query of the given kind against target
+func runRead(ctx context.Context, client *ethclient.Client, config *ReadConfig, kind string, target common.Address) error {
+	switch kind {
+	case ReadCall:
+		_, err := client.CallContract(ctx, ethereum.CallMsg{To: &target, Data: config.CallData}, nil)
+		return err
+	case ReadBalance:
+		_, err := client.BalanceAt(ctx, target, nil)
+		return err
+	case ReadStorage:
+		_, err := client.StorageAt(ctx, target, common.Hash{}, nil)
+		return err
+	case ReadLogs:
+		head, err := client.BlockNumber(ctx)
+		if err != nil {
+			return err
+		}
+		from := uint64(0)
+		if head > config.LogBlockRange {
+			from = head - config.LogBlockRange
+		}
+		_, err = client.FilterLogs(ctx, ethereum.FilterQuery{
+			FromBlock: new(big.Int).SetUint64(from),
+			ToBlock:   new(big.Int).SetUint64(head),
+			Addresses: []common.Address{target},
+		})
+		return err
+	default:
+		return fmt.Errorf("unknown read kind: %s", kind)
+	}
+}
+
+// PrintReadReport prints per-kind query counts, error counts and latencies
+func PrintReadReport(report *ReadReport) {
+	fmt.Printf("\n=== Read Load Summary ===\n")
+	total := 0
+	for _, stats := range report.Kinds {
+		total += stats.Count
+	}
+	fmt.Printf("Queries: %d in %s (%.1f/s)\n", total, report.Duration.Round(time.Second), float64(total)/report.Duration.Seconds())
+	for _, kind := range readKinds {
+		stats := report.Kinds[kind]
+		if stats.Count == 0 {
+			continue
+		}
+		fmt.Printf("  %-8s %d queries, %d errors, p50 %s, p95 %s\n", kind, stats.Count, stats.Errors,
+			stats.P50Latency.Round(time.Millisecond), stats.P95Latency.Round(time.Millisecond))
+	}
+	fmt.Printf("==========================\n")
+}
@@ -0,0 +1,41 @@
+package loadtest
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestParseReadMix(t *testing.T) {
+	t.Run("Valid", func(t *testing.T) {
+		mix, err := ParseReadMix("call=40, balance=30,logs=0,storage=30")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if mix[ReadCall] != 40 || mix[ReadBalance] != 30 || mix[ReadLogs] != 0 || mix[ReadStorage] != 30 {
+			t.Errorf("unexpected mix %v", mix)
+		}
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		for _, s := range []string{"", "call", "trace=10", "call=-1", "call=0,logs=0"} {
+			if _, err := ParseReadMix(s); err == nil {
+				t.Errorf("%q: expected error", s)
+			}
+		}
+	})
+
+	t.Run("PickFollowsWeights", func(t *testing.T) {
+		mix := map[string]int{ReadBalance: 1, ReadLogs: 3}
+		rng := rand.New(rand.NewSource(1))
+		counts := make(map[string]int)
+		for i := 0; i < 4000; i++ {
+			counts[pickRead(mix, rng)]++
+		}
+		if counts[ReadCall] != 0 || counts[ReadStorage] != 0 {
+			t.Errorf("picked zero-weight kinds: %v", counts)
+		}
+		if counts[ReadLogs] < 2700 || counts[ReadLogs] > 3300 {
+			t.Errorf("expected about 3000 logs queries, got %d", counts[ReadLogs])
+		}
+	})
+}
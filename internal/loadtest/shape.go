@@ -0,0 +1,201 @@
+package loadtest
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aakash4dev/ethereum-transaction-simulator/internal/transaction"
+)
+
+// Traffic shapes
+const (
+	ShapeSine    = "sine"    // Base rate plus a sinusoidal swing, e.g. a compressed day/night cycle
+	ShapeStep    = "step"    // Cycles through fixed rate levels
+	ShapePoisson = "poisson" // Poisson arrivals around the base rate, resampled every second
+	ShapeSpike   = "spike"   // Base rate with one flash-crowd burst
+)
+
+// shapeInterval is how often a shaped run updates the send rate
+const shapeInterval = time.Second
+
+// minShapeRate keeps a shape from reaching 0, which the sender treats as unlimited
+const minShapeRate = 0.1
+
+// Shape returns the target rate at a point in a run
+type Shape func(elapsed time.Duration, rng *rand.Rand) float64
+
+// ShapeConfig selects and parameterizes a traffic shape
+type ShapeConfig struct {
+	Kind          string
+	BaseTPS       float64
+	Amplitude     float64       // Sine: swing above and below BaseTPS
+	Period        time.Duration // Sine: length of one full cycle
+	Steps         []float64     // Step: rate levels, cycled in order
+	StepDuration  time.Duration // Step: time spent at each level
+	SpikeTPS      float64       // Spike: rate during the burst
+	SpikeAt       time.Duration // Spike: when the burst starts
+	SpikeDuration time.Duration // Spike: how long the burst lasts
+}
+
+// Phase returns the phase of the shape elapsed is in: the step level (counting
+// repeats) for step, before, during or after the burst for spike, and the cycle for
+// sine. Poisson has a single phase.
+func (c *ShapeConfig) Phase(elapsed time.Duration) int {
+	switch c.Kind {
+	case ShapeStep:
+		if c.StepDuration > 0 {
+			return int(elapsed / c.StepDuration)
+		}
+	case ShapeSpike:
+		switch {
+		case elapsed < c.SpikeAt:
+			return 0
+		case elapsed < c.SpikeAt+c.SpikeDuration:
+			return 1
+		}
+		return 2
+	case ShapeSine:
+		if c.Period > 0 {
+			return int(elapsed / c.Period)
+		}
+	}
+	return 0
+}
+
+// TimeAdvancer moves a development chain's clock forward
+type TimeAdvancer interface {
+	IncreaseTime(ctx context.Context, by time.Duration) error
+}
+
+// TimeWarp advances chain time between the phases of a shaped run, so contracts
+// whose behaviour depends on block time (vesting, auctions) move through their
+// stages while under load
+type TimeWarp struct {
+	Node  TimeAdvancer
+	Phase func(elapsed time.Duration) int // Usually ShapeConfig.Phase
+	Step  time.Duration                   // Chain time added at every phase change
+}
+
+// ParseSteps parses comma-separated step rates such as "20,50,100"
+func ParseSteps(s string) ([]float64, error) {
+	var steps []float64
+	for _, entry := range strings.Split(s, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		rate, err := strconv.ParseFloat(entry, 64)
+		if err != nil || rate <= 0 {
+			return nil, fmt.Errorf("invalid step rate %q: must be a positive number", entry)
+		}
+		steps = append(steps, rate)
+	}
+	return steps, nil
+}
+
+// NewShape builds the shape described by config
+func NewShape(config *ShapeConfig) (Shape, error) {
+	switch config.Kind {
+	case ShapeSine:
+		if config.Period <= 0 {
+			return nil, fmt.Errorf("sine shape needs a positive period")
+		}
+		return func(elapsed time.Duration, _ *rand.Rand) float64 {
+			phase := 2 * math.Pi * float64(elapsed) / float64(config.Period)
+			return clampRate(config.BaseTPS + config.Amplitude*math.Sin(phase))
+		}, nil
+	case ShapeStep:
+		if len(config.Steps) == 0 || config.StepDuration <= 0 {
+			return nil, fmt.Errorf("step shape needs at least one level and a positive step duration")
+		}
+		return func(elapsed time.Duration, _ *rand.Rand) float64 {
+			return config.Steps[int(elapsed/config.StepDuration)%len(config.Steps)]
+		}, nil
+	case ShapePoisson:
+		return func(_ time.Duration, rng *rand.Rand) float64 {
+			return clampRate(poisson(config.BaseTPS*shapeInterval.Seconds(), rng) / shapeInterval.Seconds())
+		}, nil
+	case ShapeSpike:
+		return func(elapsed time.Duration, _ *rand.Rand) float64 {
+			if elapsed >= config.SpikeAt && elapsed < config.SpikeAt+config.SpikeDuration {
+				return clampRate(config.SpikeTPS)
+			}
+			return clampRate(config.BaseTPS)
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown traffic shape: %s", config.Kind)
+	}
+}
+
+// clampRate keeps a shaped rate above minShapeRate
+func clampRate(rate float64) float64 {
+	if rate < minShapeRate {
+		return minShapeRate
+	}
+	return rate
+}
+
+// poisson samples a Poisson-distributed count with mean lambda, using Knuth's method
+// for small means and a normal approximation for large ones
+func poisson(lambda float64, rng *rand.Rand) float64 {
+	if lambda > 30 {
+		return math.Max(0, math.Round(lambda+math.Sqrt(lambda)*rng.NormFloat64()))
+	}
+	limit := math.Exp(-lambda)
+	count, product := 0.0, rng.Float64()
+	for product > limit {
+		count++
+		product *= rng.Float64()
+	}
+	return count
+}
+
+// RunShaped runs the parallel sender for duration, updating its rate every second to
+// follow shape so the load mimics production-like variability. A non-nil warp
+// advances chain time whenever the run enters a new phase.
+func RunShaped(ctx context.Context, ps *transaction.ParallelSender, shape Shape, duration time.Duration, warp *TimeWarp) error {
+	rng := rand.New(rand.NewSource(time.Now().UnixNano()))
+	ps.SetRate(shape(0, rng))
+
+	runCtx, cancel := context.WithTimeout(ctx, duration)
+	defer cancel()
+	errChan := make(chan error, 1)
+	go func() {
+		errChan <- ps.SendParallelTransactions(runCtx)
+	}()
+
+	start := time.Now()
+	ticker := time.NewTicker(shapeInterval)
+	defer ticker.Stop()
+	lastReport := start
+	phase := 0
+	for {
+		select {
+		case now := <-ticker.C:
+			rate := shape(now.Sub(start), rng)
+			ps.SetRate(rate)
+			if warp != nil {
+				if p := warp.Phase(now.Sub(start)); p != phase {
+					phase = p
+					if err := warp.Node.IncreaseTime(ctx, warp.Step); err != nil {
+						fmt.Printf("[%s] phase %d: failed to advance chain time: %v\n", now.Sub(start).Round(time.Second), phase, err)
+					} else {
+						fmt.Printf("[%s] phase %d: chain time advanced by %s\n", now.Sub(start).Round(time.Second), phase, warp.Step)
+					}
+				}
+			}
+			if now.Sub(lastReport) >= time.Minute {
+				sent, _, failed, _ := ps.GetMetrics()
+				fmt.Printf("[%s] target %.1f TPS, sent %d, failed %d\n", now.Sub(start).Round(time.Second), rate, sent, failed)
+				lastReport = now
+			}
+		case err := <-errChan:
+			return err
+		}
+	}
+}
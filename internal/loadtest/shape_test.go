@@ -0,0 +1,85 @@
+package loadtest
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+	"time"
+)
+
+func TestShapes(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+
+	t.Run("Sine", func(t *testing.T) {
+		shape, err := NewShape(&ShapeConfig{Kind: ShapeSine, BaseTPS: 50, Amplitude: 25, Period: 4 * time.Minute})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		for elapsed, want := range map[time.Duration]float64{0: 50, time.Minute: 75, 3 * time.Minute: 25} {
+			if got := shape(elapsed, rng); math.Abs(got-want) > 1e-9 {
+				t.Errorf("at %s: expected %.1f, got %.1f", elapsed, want, got)
+			}
+		}
+	})
+
+	t.Run("SineNeverUnlimited", func(t *testing.T) {
+		shape, _ := NewShape(&ShapeConfig{Kind: ShapeSine, BaseTPS: 10, Amplitude: 50, Period: 4 * time.Minute})
+		if got := shape(3*time.Minute, rng); got != minShapeRate {
+			t.Errorf("expected trough clamped to %.1f, got %.1f", minShapeRate, got)
+		}
+	})
+
+	t.Run("Step", func(t *testing.T) {
+		shape, err := NewShape(&ShapeConfig{Kind: ShapeStep, Steps: []float64{20, 50, 100}, StepDuration: time.Minute})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		for elapsed, want := range map[time.Duration]float64{0: 20, 90 * time.Second: 50, 2 * time.Minute: 100, 3 * time.Minute: 20} {
+			if got := shape(elapsed, rng); got != want {
+				t.Errorf("at %s: expected %.0f, got %.0f", elapsed, want, got)
+			}
+		}
+	})
+
+	t.Run("Spike", func(t *testing.T) {
+		shape, _ := NewShape(&ShapeConfig{Kind: ShapeSpike, BaseTPS: 10, SpikeTPS: 500, SpikeAt: time.Minute, SpikeDuration: 30 * time.Second})
+		if shape(59*time.Second, rng) != 10 || shape(time.Minute, rng) != 500 || shape(90*time.Second, rng) != 10 {
+			t.Error("expected the spike only between 60s and 90s")
+		}
+	})
+
+	t.Run("PoissonMean", func(t *testing.T) {
+		for _, base := range []float64{5, 200} {
+			shape, _ := NewShape(&ShapeConfig{Kind: ShapePoisson, BaseTPS: base})
+			sum := 0.0
+			for i := 0; i < 5000; i++ {
+				sum += shape(0, rng)
+			}
+			if mean := sum / 5000; math.Abs(mean-base)/base > 0.05 {
+				t.Errorf("base %.0f: mean rate %.2f too far from base", base, mean)
+			}
+		}
+	})
+
+	t.Run("Phases", func(t *testing.T) {
+		step := &ShapeConfig{Kind: ShapeStep, Steps: []float64{20, 50}, StepDuration: time.Minute}
+		if step.Phase(59*time.Second) != 0 || step.Phase(time.Minute) != 1 || step.Phase(150*time.Second) != 2 {
+			t.Error("expected one step phase per level, counting repeats")
+		}
+		spike := &ShapeConfig{Kind: ShapeSpike, SpikeAt: time.Minute, SpikeDuration: 30 * time.Second}
+		if spike.Phase(0) != 0 || spike.Phase(time.Minute) != 1 || spike.Phase(90*time.Second) != 2 {
+			t.Error("expected spike phases before, during and after the burst")
+		}
+		if (&ShapeConfig{Kind: ShapePoisson}).Phase(time.Hour) != 0 {
+			t.Error("expected poisson to have a single phase")
+		}
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		for _, config := range []*ShapeConfig{{Kind: "zigzag"}, {Kind: ShapeSine}, {Kind: ShapeStep, StepDuration: time.Minute}} {
+			if _, err := NewShape(config); err == nil {
+				t.Errorf("%+v: expected error", *config)
+			}
+		}
+	})
+}
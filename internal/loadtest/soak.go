@@ -0,0 +1,110 @@
+package loadtest
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aakash4dev/ethereum-transaction-simulator/internal/transaction"
+)
+
+// SoakConfig holds configuration for a soak test
+type SoakConfig struct {
+	TPS                float64       // Constant send rate
+	Duration           time.Duration // Total soak length
+	Window             time.Duration // Length of each measurement window
+	DegradationPercent int           // Change against the baseline window that counts as degradation
+}
+
+// SoakWindow is one measurement window and the degradations found in it
+type SoakWindow struct {
+	Index    int
+	Stats    WindowStats
+	Degraded []string
+}
+
+// RunSoak sends at a constant rate for the configured duration, closing a measurement
+// window every config.Window. The first window is the baseline; every later window is
+// compared with it and flagged when inclusion latency or failure rate rise, or blocks
+// carry fewer transactions, by more than DegradationPercent.
+func RunSoak(ctx context.Context, ps *transaction.ParallelSender, config *SoakConfig) ([]SoakWindow, error) {
+	c := &collector{}
+	ps.ObserveBlocks(c.observe)
+	ps.SetRate(config.TPS)
+
+	runCtx, cancel := context.WithTimeout(ctx, config.Duration)
+	defer cancel()
+	errChan := make(chan error, 1)
+	go func() {
+		errChan <- ps.SendParallelTransactions(runCtx)
+	}()
+
+	fmt.Printf("Soak test: %.1f TPS for %s, %s windows\n", config.TPS, config.Duration, config.Window)
+	ticker := time.NewTicker(config.Window)
+	defer ticker.Stop()
+
+	var windows []SoakWindow
+	for {
+		select {
+		case <-ticker.C:
+			sent, _, failed, _ := ps.GetMetrics()
+			window := SoakWindow{Index: len(windows), Stats: c.close(sent, failed)}
+			if len(windows) > 0 {
+				window.Degraded = Compare(windows[0].Stats, window.Stats, config.DegradationPercent)
+			}
+			windows = append(windows, window)
+			printSoakWindow(window)
+		case err := <-errChan:
+			PrintSoakSummary(windows)
+			return windows, err
+		}
+	}
+}
+
+// Compare returns a description of every metric in current that degraded by more
+// than percent against baseline
+func Compare(baseline, current WindowStats, percent int) []string {
+	var degraded []string
+	factor := 1 + float64(percent)/100
+
+	if baseline.P95Latency > 0 && float64(current.P95Latency) > float64(baseline.P95Latency)*factor {
+		degraded = append(degraded, fmt.Sprintf("p95 latency %s vs baseline %s",
+			current.P95Latency.Round(time.Millisecond), baseline.P95Latency.Round(time.Millisecond)))
+	}
+	// Failure rate is compared in percentage points so a zero baseline still alerts
+	if current.FailureRate > baseline.FailureRate+float64(percent)/100 {
+		degraded = append(degraded, fmt.Sprintf("failure rate %.1f%% vs baseline %.1f%%",
+			current.FailureRate*100, baseline.FailureRate*100))
+	}
+	if baseline.AvgBlockTxs > 0 && current.AvgBlockTxs*factor < baseline.AvgBlockTxs {
+		degraded = append(degraded, fmt.Sprintf("avg txs per block %.1f vs baseline %.1f",
+			current.AvgBlockTxs, baseline.AvgBlockTxs))
+	}
+	return degraded
+}
+
+// printSoakWindow prints one window as it closes
+func printSoakWindow(w SoakWindow) {
+	fmt.Printf("Window %d: sent %d, failed %d, mined %d, p95 latency %s, avg txs/block %.1f\n",
+		w.Index, w.Stats.Sent, w.Stats.Failed, w.Stats.Mined, w.Stats.P95Latency.Round(time.Millisecond), w.Stats.AvgBlockTxs)
+	for _, d := range w.Degraded {
+		fmt.Printf("  DEGRADED: %s\n", d)
+	}
+}
+
+// PrintSoakSummary prints the windows that showed degradation
+func PrintSoakSummary(windows []SoakWindow) {
+	fmt.Printf("\n=== Soak Summary ===\n")
+	fmt.Printf("Windows: %d\n", len(windows))
+	degraded := 0
+	for _, w := range windows {
+		if len(w.Degraded) > 0 {
+			degraded++
+			fmt.Printf("Window %d degraded: %v\n", w.Index, w.Degraded)
+		}
+	}
+	if degraded == 0 {
+		fmt.Printf("No degradation detected\n")
+	}
+	fmt.Printf("==========================\n")
+}
@@ -0,0 +1,78 @@
+package loadtest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aakash4dev/ethereum-transaction-simulator/internal/transaction"
+)
+
+func TestCompare(t *testing.T) {
+	baseline := WindowStats{P95Latency: 4 * time.Second, FailureRate: 0.01, AvgBlockTxs: 100}
+
+	t.Run("Stable", func(t *testing.T) {
+		current := WindowStats{P95Latency: 4500 * time.Millisecond, FailureRate: 0.02, AvgBlockTxs: 90}
+		if degraded := Compare(baseline, current, 25); len(degraded) != 0 {
+			t.Errorf("expected no degradation, got %v", degraded)
+		}
+	})
+
+	t.Run("Degraded", func(t *testing.T) {
+		current := WindowStats{P95Latency: 10 * time.Second, FailureRate: 0.5, AvgBlockTxs: 40}
+		if degraded := Compare(baseline, current, 25); len(degraded) != 3 {
+			t.Errorf("expected 3 degradations, got %v", degraded)
+		}
+	})
+}
+
+func TestPercentile(t *testing.T) {
+	t.Run("P95", func(t *testing.T) {
+		var durations []time.Duration
+		for i := 1; i <= 100; i++ {
+			durations = append(durations, time.Duration(i)*time.Millisecond)
+		}
+		if got := Percentile(durations, 95); got != 95*time.Millisecond {
+			t.Errorf("expected 95ms, got %s", got)
+		}
+		if got := Percentile(nil, 95); got != 0 {
+			t.Errorf("expected 0 for empty input, got %s", got)
+		}
+	})
+}
+
+func TestAdaptiveSearch(t *testing.T) {
+	t.Run("ConvergesBelowLimit", func(t *testing.T) {
+		// The chain sustains up to 300 TPS
+		s := &search{rate: 100, step: 50}
+		for i := 0; i < 100; i++ {
+			if s.next(s.rate <= 300) {
+				break
+			}
+		}
+		if s.lastGood > 300 || s.lastGood < 250 {
+			t.Errorf("expected to converge just under 300 TPS, got %.1f", s.lastGood)
+		}
+	})
+
+	t.Run("NothingMinedIsUnhealthy", func(t *testing.T) {
+		config := &AdaptiveConfig{MaxP95Latency: 10 * time.Second, MaxFailurePercent: 5}
+		if config.healthy(WindowStats{Sent: 100}) {
+			t.Error("expected a window with nothing mined to be unhealthy")
+		}
+		if !config.healthy(WindowStats{Sent: 100, Mined: 90, P95Latency: 4 * time.Second}) {
+			t.Error("expected a window within thresholds to be healthy")
+		}
+		if !config.healthy(WindowStats{}) {
+			t.Error("expected an idle window to be healthy")
+		}
+	})
+
+	t.Run("PendingCountsTowardsLatency", func(t *testing.T) {
+		c := &collector{}
+		c.observe(transaction.BlockStats{Ours: 1, Latencies: []time.Duration{time.Second}})
+		c.addPending([]time.Duration{30 * time.Second, 30 * time.Second})
+		if stats := c.close(3, 0); stats.P95Latency != 30*time.Second {
+			t.Errorf("expected pending transactions to set p95 to 30s, got %s", stats.P95Latency)
+		}
+	})
+}
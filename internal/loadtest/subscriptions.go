@@ -0,0 +1,297 @@
+package loadtest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// Subscription kinds, named as in eth_subscribe
+const (
+	SubscribeHeads   = "newHeads"
+	SubscribeLogs    = "logs"
+	SubscribePending = "newPendingTransactions"
+)
+
+// subscriptionKinds lists the subscription kinds in report order
+var subscriptionKinds = []string{SubscribeHeads, SubscribeLogs, SubscribePending}
+
+// fanoutRetention is how long the first arrival of a notification is remembered
+const fanoutRetention = time.Minute
+
+// SubscriptionConfig holds configuration for the WebSocket fan-out workload
+type SubscriptionConfig struct {
+	URL          string           // WebSocket endpoint
+	Connections  int              // Concurrent WebSocket connections
+	Kinds        []string         // Subscriptions opened on every connection
+	LogAddresses []common.Address // Log subscription filter (empty subscribes to all logs)
+	Duration     time.Duration    // How long to keep the subscriptions open
+}
+
+// SubscriptionStats summarizes the notifications of one subscription kind across connections
+type SubscriptionStats struct {
+	Subscribed    int // Subscriptions successfully opened
+	Refused       int // Subscriptions the node rejected
+	Dropped       int // Subscriptions the node closed before the end of the run
+	Notifications int
+	// Delay between the first connection receiving a notification and each other connection
+	P50FanoutDelay time.Duration
+	P95FanoutDelay time.Duration
+	delays         []time.Duration
+}
+
+// SubscriptionReport summarizes a WebSocket fan-out run
+type SubscriptionReport struct {
+	Duration       time.Duration
+	Connections    int // Connections successfully opened
+	FailedConnects int
+	Kinds          map[string]*SubscriptionStats
+	// Delay between a block's timestamp and its newHeads notification (second resolution)
+	P50HeadLag time.Duration
+	P95HeadLag time.Duration
+}
+
+// ParseSubscriptionKinds parses a comma-separated list of eth_subscribe kinds
+func ParseSubscriptionKinds(s string) ([]string, error) {
+	var kinds []string
+	for _, kind := range strings.Split(s, ",") {
+		kind = strings.TrimSpace(kind)
+		if kind == "" {
+			continue
+		}
+		if !isSubscriptionKind(kind) {
+			return nil, fmt.Errorf("unknown subscription %q (valid: %s)", kind, strings.Join(subscriptionKinds, ", "))
+		}
+		kinds = append(kinds, kind)
+	}
+	if len(kinds) == 0 {
+		return nil, fmt.Errorf("at least one subscription is required")
+	}
+	return kinds, nil
+}
+
+// isSubscriptionKind reports whether kind is a supported subscription
+func isSubscriptionKind(kind string) bool {
+	for _, k := range subscriptionKinds {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// fanout remembers when each notification first arrived on any connection
+type fanout struct {
+	mu    sync.Mutex
+	first map[string]time.Time
+}
+
+// record returns how long after its first arrival a notification reached this connection
+// and whether this was the first arrival
+func (f *fanout) record(key string, at time.Time) (time.Duration, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	first, seen := f.first[key]
+	if !seen {
+		f.first[key] = at
+		return 0, true
+	}
+	return at.Sub(first), false
+}
+
+// prune forgets notifications that first arrived before cutoff
+func (f *fanout) prune(cutoff time.Time) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for key, first := range f.first {
+		if first.Before(cutoff) {
+			delete(f.first, key)
+		}
+	}
+}
+
+// notification holds the fields used to identify heads, logs and pending transactions
+type notification struct {
+	Number    *hexutil.Uint64 `json:"number"`
+	Timestamp *hexutil.Uint64 `json:"timestamp"`
+	TxHash    string          `json:"transactionHash"`
+	LogIndex  *hexutil.Uint64 `json:"logIndex"`
+}
+
+// notificationKey identifies a notification so its arrivals can be matched across connections
+func notificationKey(kind string, raw json.RawMessage) (string, *notification, error) {
+	if kind == SubscribePending {
+		var hash string
+		if err := json.Unmarshal(raw, &hash); err != nil {
+			return "", nil, err
+		}
+		return hash, nil, nil
+	}
+
+	var n notification
+	if err := json.Unmarshal(raw, &n); err != nil {
+		return "", nil, err
+	}
+	switch {
+	case kind == SubscribeHeads && n.Number != nil:
+		return fmt.Sprintf("%d", uint64(*n.Number)), &n, nil
+	case kind == SubscribeLogs && n.LogIndex != nil:
+		return fmt.Sprintf("%s/%d", n.TxHash, uint64(*n.LogIndex)), &n, nil
+	default:
+		return "", nil, fmt.Errorf("unrecognized %s notification", kind)
+	}
+}
+
+// RunSubscriptions opens Connections WebSocket connections, each subscribing to every
+// configured kind, and measures how notifications fan out across them: the delay after
+// the first connection receives a notification, block head lag, and subscriptions the
+// node drops. Run it alongside the parallel sender so there is traffic to notify about.
+func RunSubscriptions(ctx context.Context, config *SubscriptionConfig) (*SubscriptionReport, error) {
+	ctx, cancel := context.WithTimeout(ctx, config.Duration)
+	defer cancel()
+
+	report := &SubscriptionReport{Kinds: make(map[string]*SubscriptionStats)}
+	fanouts := make(map[string]*fanout)
+	for _, kind := range config.Kinds {
+		report.Kinds[kind] = &SubscriptionStats{}
+		fanouts[kind] = &fanout{first: make(map[string]time.Time)}
+	}
+	var headLags []time.Duration
+	var mu sync.Mutex
+
+	go func() {
+		ticker := time.NewTicker(fanoutRetention / 2)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-ticker.C:
+				for _, f := range fanouts {
+					f.prune(now.Add(-fanoutRetention))
+				}
+			}
+		}
+	}()
+
+	var wg sync.WaitGroup
+	start := time.Now()
+	for i := 0; i < config.Connections; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			client, err := rpc.DialContext(ctx, config.URL)
+			mu.Lock()
+			if err != nil {
+				report.FailedConnects++
+				mu.Unlock()
+				return
+			}
+			report.Connections++
+			mu.Unlock()
+			defer client.Close()
+
+			var subWG sync.WaitGroup
+			for _, kind := range config.Kinds {
+				subWG.Add(1)
+				go func(kind string) {
+					defer subWG.Done()
+					ch := make(chan json.RawMessage, 256)
+					sub, err := client.EthSubscribe(ctx, ch, subscriptionArgs(kind, config.LogAddresses)...)
+					stats := report.Kinds[kind]
+					mu.Lock()
+					if err != nil {
+						stats.Refused++
+						mu.Unlock()
+						return
+					}
+					stats.Subscribed++
+					mu.Unlock()
+					defer sub.Unsubscribe()
+
+					for {
+						select {
+						case <-ctx.Done():
+							return
+						case <-sub.Err():
+							if ctx.Err() == nil {
+								mu.Lock()
+								stats.Dropped++
+								mu.Unlock()
+							}
+							return
+						case raw := <-ch:
+							at := time.Now()
+							key, n, err := notificationKey(kind, raw)
+							if err != nil {
+								continue
+							}
+							delay, first := fanouts[kind].record(key, at)
+
+							mu.Lock()
+							stats.Notifications++
+							if !first {
+								stats.delays = append(stats.delays, delay)
+							}
+							if first && kind == SubscribeHeads && n.Timestamp != nil {
+								headLags = append(headLags, at.Sub(time.Unix(int64(*n.Timestamp), 0)))
+							}
+							mu.Unlock()
+						}
+					}
+				}(kind)
+			}
+			subWG.Wait()
+		}()
+	}
+
+	wg.Wait()
+	report.Duration = time.Since(start)
+	for _, stats := range report.Kinds {
+		stats.P50FanoutDelay = Percentile(stats.delays, 50)
+		stats.P95FanoutDelay = Percentile(stats.delays, 95)
+		stats.delays = nil
+	}
+	report.P50HeadLag = Percentile(headLags, 50)
+	report.P95HeadLag = Percentile(headLags, 95)
+	return report, nil
+}
+
+// subscriptionArgs returns the eth_subscribe parameters for kind
+func subscriptionArgs(kind string, addresses []common.Address) []interface{} {
+	if kind != SubscribeLogs {
+		return []interface{}{kind}
+	}
+	filter := map[string]interface{}{}
+	if len(addresses) > 0 {
+		filter["address"] = addresses
+	}
+	return []interface{}{kind, filter}
+}
+
+// PrintSubscriptionReport prints per-subscription notification counts, drops and fan-out delays
+func PrintSubscriptionReport(report *SubscriptionReport) {
+	fmt.Printf("\n=== WebSocket Fan-out Summary ===\n")
+	fmt.Printf("Connections: %d open, %d failed, duration: %s\n", report.Connections, report.FailedConnects, report.Duration.Round(time.Second))
+	for _, kind := range subscriptionKinds {
+		stats, ok := report.Kinds[kind]
+		if !ok {
+			continue
+		}
+		fmt.Printf("  %s: %d subscribed, %d refused, %d dropped, %d notifications, fan-out delay p50 %s, p95 %s\n",
+			kind, stats.Subscribed, stats.Refused, stats.Dropped, stats.Notifications,
+			stats.P50FanoutDelay.Round(time.Millisecond), stats.P95FanoutDelay.Round(time.Millisecond))
+	}
+	if report.P95HeadLag > 0 {
+		fmt.Printf("Head lag (block timestamp to first notification): p50 %s, p95 %s\n",
+			report.P50HeadLag.Round(time.Millisecond), report.P95HeadLag.Round(time.Millisecond))
+	}
+	fmt.Printf("==========================\n")
+}
@@ -0,0 +1,57 @@
+package loadtest
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestSubscriptions(t *testing.T) {
+	t.Run("ParseKinds", func(t *testing.T) {
+		kinds, err := ParseSubscriptionKinds("newHeads, logs")
+		if err != nil || len(kinds) != 2 || kinds[1] != SubscribeLogs {
+			t.Errorf("unexpected kinds %v (%v)", kinds, err)
+		}
+		for _, s := range []string{"", "newBlocks"} {
+			if _, err := ParseSubscriptionKinds(s); err == nil {
+				t.Errorf("%q: expected error", s)
+			}
+		}
+	})
+
+	t.Run("NotificationKey", func(t *testing.T) {
+		cases := []struct {
+			kind string
+			raw  string
+			key  string
+		}{
+			{SubscribeHeads, `{"number":"0x10","timestamp":"0x5"}`, "16"},
+			{SubscribeLogs, `{"transactionHash":"0xabc","logIndex":"0x2"}`, "0xabc/2"},
+			{SubscribePending, `"0xdef"`, "0xdef"},
+		}
+		for _, c := range cases {
+			key, _, err := notificationKey(c.kind, json.RawMessage(c.raw))
+			if err != nil || key != c.key {
+				t.Errorf("%s: expected key %q, got %q (%v)", c.kind, c.key, key, err)
+			}
+		}
+		if _, _, err := notificationKey(SubscribeHeads, json.RawMessage(`{}`)); err == nil {
+			t.Error("expected error for head without number")
+		}
+	})
+
+	t.Run("Fanout", func(t *testing.T) {
+		f := &fanout{first: make(map[string]time.Time)}
+		start := time.Now()
+		if _, first := f.record("1", start); !first {
+			t.Error("expected first arrival")
+		}
+		if delay, first := f.record("1", start.Add(30*time.Millisecond)); first || delay != 30*time.Millisecond {
+			t.Errorf("expected 30ms fan-out delay, got %s (first %v)", delay, first)
+		}
+		f.prune(start.Add(time.Second))
+		if _, first := f.record("1", start.Add(2*time.Second)); !first {
+			t.Error("expected pruned notification to count as a first arrival")
+		}
+	})
+}
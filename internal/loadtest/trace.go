@@ -0,0 +1,222 @@
+package loadtest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// Traced RPC methods
+const (
+	TraceTransaction = "debug_traceTransaction"
+	TraceBlock       = "debug_traceBlockByNumber"
+)
+
+// TraceConfig holds configuration for the debug/trace workload
+type TraceConfig struct {
+	Rate         float64       // Trace calls per second (0 = as fast as Concurrency allows)
+	Duration     time.Duration // How long to send trace calls
+	Concurrency  int           // Maximum trace calls in flight
+	Tracer       string        // Tracer name such as "callTracer", empty uses the default struct logger
+	Timeout      time.Duration // Node-side tracer timeout passed with each call
+	BlockPercent int           // Share of calls tracing whole blocks instead of single transactions
+	RecentBlocks int           // Only blocks this close to the head are traced
+}
+
+// TraceStats summarizes the calls of one trace method
+type TraceStats struct {
+	ReadStats
+	Bytes int64 // Response payload across all successful calls
+}
+
+// TraceReport summarizes a trace run
+type TraceReport struct {
+	Duration time.Duration
+	Skipped  int // Calls not made because no recent block or transaction was available
+	Methods  map[string]*TraceStats
+}
+
+// tracedBlock is a recently mined block and its transactions
+type tracedBlock struct {
+	number uint64
+	txs    []common.Hash
+}
+
+// tracePool keeps the most recent blocks so traces target freshly mined traffic
+type tracePool struct {
+	mu     sync.Mutex
+	blocks []tracedBlock
+	limit  int
+	last   uint64
+}
+
+// add appends a block, dropping the oldest once more than limit are held
+func (p *tracePool) add(block tracedBlock) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.blocks = append(p.blocks, block)
+	if len(p.blocks) > p.limit {
+		p.blocks = p.blocks[len(p.blocks)-p.limit:]
+	}
+	p.last = block.number
+}
+
+// pick returns the method and parameter of the next trace, or false when the pool holds
+// nothing suitable. Transaction traces only consider blocks with transactions.
+func (p *tracePool) pick(rng *rand.Rand, blockPercent int) (string, interface{}, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if len(p.blocks) == 0 {
+		return "", nil, false
+	}
+	if rng.Intn(100) < blockPercent {
+		block := p.blocks[rng.Intn(len(p.blocks))]
+		return TraceBlock, hexutil.EncodeUint64(block.number), true
+	}
+
+	total := 0
+	for _, b := range p.blocks {
+		total += len(b.txs)
+	}
+	if total == 0 {
+		return "", nil, false
+	}
+	n := rng.Intn(total)
+	for _, b := range p.blocks {
+		if n < len(b.txs) {
+			return TraceTransaction, b.txs[n], true
+		}
+		n -= len(b.txs)
+	}
+	return "", nil, false
+}
+
+// refresh adds blocks mined since the last refresh, at most limit of them
+func (p *tracePool) refresh(ctx context.Context, client *ethclient.Client) {
+	head, err := client.BlockNumber(ctx)
+	if err != nil {
+		return // Retry on next refresh
+	}
+	from := p.last + 1
+	if head >= uint64(p.limit) && from < head-uint64(p.limit)+1 {
+		from = head - uint64(p.limit) + 1
+	}
+	for number := from; number <= head; number++ {
+		block, err := client.BlockByNumber(ctx, new(big.Int).SetUint64(number))
+		if err != nil {
+			return // Resume from this block on next refresh
+		}
+		traced := tracedBlock{number: number}
+		for _, tx := range block.Transactions() {
+			traced.txs = append(traced.txs, tx.Hash())
+		}
+		p.add(traced)
+	}
+}
+
+// RunTraces calls debug_traceTransaction and debug_traceBlockByNumber on recently mined
+// blocks and transactions, measuring tracer latency and response size. Tracing is
+// usually the first RPC facility to fall over, so run it alongside the parallel sender
+// to trace our own traffic under load.
+func RunTraces(ctx context.Context, client *rpc.Client, config *TraceConfig) (*TraceReport, error) {
+	ctx, cancel := context.WithTimeout(ctx, config.Duration)
+	defer cancel()
+
+	eth := ethclient.NewClient(client)
+	pool := &tracePool{limit: config.RecentBlocks}
+	pool.refresh(ctx, eth)
+	go func() {
+		ticker := time.NewTicker(time.Second)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				pool.refresh(ctx, eth)
+			}
+		}
+	}()
+
+	report := &TraceReport{Methods: map[string]*TraceStats{
+		TraceTransaction: {},
+		TraceBlock:       {},
+	}}
+	options := map[string]interface{}{}
+	if config.Tracer != "" {
+		options["tracer"] = config.Tracer
+	}
+	if config.Timeout > 0 {
+		options["timeout"] = config.Timeout.String()
+	}
+
+	var mu sync.Mutex
+	report.Duration = runQueries(ctx, config.Rate, config.Concurrency, func(rng *rand.Rand) func() {
+		method, param, ok := pool.pick(rng, config.BlockPercent)
+		if !ok {
+			return func() {
+				mu.Lock()
+				report.Skipped++
+				mu.Unlock()
+			}
+		}
+		return func() {
+			var result json.RawMessage
+			sentAt := time.Now()
+			err := client.CallContext(ctx, &result, method, param, options)
+			latency := time.Since(sentAt)
+			if ctx.Err() != nil {
+				return // Cut off by the end of the run, not a node failure
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			stats := report.Methods[method]
+			stats.Count++
+			if err != nil {
+				stats.Errors++
+				return
+			}
+			stats.latencies = append(stats.latencies, latency)
+			stats.Bytes += int64(len(result))
+		}
+	})
+
+	for _, stats := range report.Methods {
+		stats.P50Latency = Percentile(stats.latencies, 50)
+		stats.P95Latency = Percentile(stats.latencies, 95)
+		stats.latencies = nil
+	}
+	return report, nil
+}
+
+// PrintTraceReport prints per-method trace counts, errors, latencies and response sizes
+func PrintTraceReport(report *TraceReport) {
+	fmt.Printf("\n=== Trace RPC Summary ===\n")
+	fmt.Printf("Duration: %s\n", report.Duration.Round(time.Second))
+	for _, method := range []string{TraceTransaction, TraceBlock} {
+		stats := report.Methods[method]
+		if stats.Count == 0 {
+			continue
+		}
+		avgBytes := 0.0
+		if ok := stats.Count - stats.Errors; ok > 0 {
+			avgBytes = float64(stats.Bytes) / float64(ok)
+		}
+		fmt.Printf("  %s: %d calls, %d errors, p50 %s, p95 %s, ~%.0f bytes per response\n", method, stats.Count, stats.Errors,
+			stats.P50Latency.Round(time.Millisecond), stats.P95Latency.Round(time.Millisecond), avgBytes)
+	}
+	if report.Skipped > 0 {
+		fmt.Printf("Skipped (no recent blocks or transactions): %d\n", report.Skipped)
+	}
+	fmt.Printf("==========================\n")
+}
@@ -0,0 +1,45 @@
+package loadtest
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestTracePool(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+
+	t.Run("Empty", func(t *testing.T) {
+		pool := &tracePool{limit: 3}
+		if _, _, ok := pool.pick(rng, 50); ok {
+			t.Error("expected nothing to trace in an empty pool")
+		}
+		pool.add(tracedBlock{number: 1})
+		if _, _, ok := pool.pick(rng, 0); ok {
+			t.Error("expected no transaction to trace in empty blocks")
+		}
+		if method, _, ok := pool.pick(rng, 100); !ok || method != TraceBlock {
+			t.Errorf("expected a block trace, got %q (%v)", method, ok)
+		}
+	})
+
+	t.Run("KeepsRecentBlocks", func(t *testing.T) {
+		pool := &tracePool{limit: 2}
+		for i := uint64(1); i <= 5; i++ {
+			pool.add(tracedBlock{number: i, txs: []common.Hash{{byte(i)}}})
+		}
+		if len(pool.blocks) != 2 || pool.blocks[0].number != 4 || pool.last != 5 {
+			t.Fatalf("expected blocks 4 and 5, got %+v", pool.blocks)
+		}
+		for i := 0; i < 100; i++ {
+			method, param, ok := pool.pick(rng, 0)
+			if !ok || method != TraceTransaction {
+				t.Fatalf("expected a transaction trace, got %q (%v)", method, ok)
+			}
+			if hash := param.(common.Hash); hash[0] < 4 {
+				t.Fatalf("traced transaction from an evicted block: %x", hash[0])
+			}
+		}
+	})
+}
@@ -0,0 +1,89 @@
+package loadtest
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/aakash4dev/ethereum-transaction-simulator/internal/transaction"
+)
+
+// WindowStats summarizes one measurement window of a running load
+type WindowStats struct {
+	Sent        int64
+	Failed      int64
+	Mined       int
+	FailureRate float64       // Failed / (Sent + Failed)
+	P95Latency  time.Duration // 95th percentile send-to-inclusion latency
+	AvgBlockTxs float64       // Average transactions per block, ours and others
+	Blocks      int
+}
+
+// collector accumulates block observations and metric deltas for the current window
+type collector struct {
+	mu         sync.Mutex
+	latencies  []time.Duration
+	blockTxs   int
+	blocks     int
+	mined      int
+	lastSent   int64
+	lastFailed int64
+}
+
+// observe is registered as the parallel sender's block observer
+func (c *collector) observe(stats transaction.BlockStats) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.latencies = append(c.latencies, stats.Latencies...)
+	c.blockTxs += stats.TxCount
+	c.blocks++
+	c.mined += stats.Ours
+}
+
+// addPending counts transactions still waiting for inclusion in the current window's
+// latency sample at their age so far, so a stalled chain cannot look fast
+func (c *collector) addPending(ages []time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.latencies = append(c.latencies, ages...)
+}
+
+// close ends the current window and starts a new one
+func (c *collector) close(sent, failed int64) WindowStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	stats := WindowStats{
+		Sent:       sent - c.lastSent,
+		Failed:     failed - c.lastFailed,
+		Mined:      c.mined,
+		P95Latency: Percentile(c.latencies, 95),
+		Blocks:     c.blocks,
+	}
+	if attempts := stats.Sent + stats.Failed; attempts > 0 {
+		stats.FailureRate = float64(stats.Failed) / float64(attempts)
+	}
+	if c.blocks > 0 {
+		stats.AvgBlockTxs = float64(c.blockTxs) / float64(c.blocks)
+	}
+
+	c.latencies = nil
+	c.blockTxs, c.blocks, c.mined = 0, 0, 0
+	c.lastSent, c.lastFailed = sent, failed
+	return stats
+}
+
+// Percentile returns the p-th percentile of the durations (0 if empty)
+func Percentile(durations []time.Duration, p int) time.Duration {
+	if len(durations) == 0 {
+		return 0
+	}
+	sorted := make([]time.Duration, len(durations))
+	copy(sorted, durations)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	index := (len(sorted)*p + 99) / 100
+	if index > 0 {
+		index--
+	}
+	return sorted[index]
+}
@@ -0,0 +1,135 @@
+package probe
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math/big"
+	"net/http"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// canaryGasLimit is the gas limit for canary self-transfers
+const canaryGasLimit = 21000
+
+// CanaryConfig holds configuration for the canary health monitor
+type CanaryConfig struct {
+	Interval    time.Duration // Time between canary transactions
+	MaxLatency  time.Duration // Inclusion latency that triggers an alert
+	WebhookURL  string        // Receives a JSON POST for every alert, empty only logs
+	ExitOnAlert bool          // Stop the canary on the first alert
+}
+
+// Alert describes a canary transaction that failed or was included too slowly
+type Alert struct {
+	Reason         string    `json:"reason"`
+	TxHash         string    `json:"txHash,omitempty"`
+	LatencySeconds float64   `json:"latencySeconds,omitempty"`
+	Time           time.Time `json:"time"`
+}
+
+// RunCanary sends a zero-value self-transfer every Interval until the context is
+// cancelled and raises an alert when one is rejected, reverts, or is not included
+// within MaxLatency. With ExitOnAlert the first alert is returned as an error.
+func (p *Prober) RunCanary(ctx context.Context, config *CanaryConfig) error {
+	ticker := time.NewTicker(config.Interval)
+	defer ticker.Stop()
+
+	for sent := 1; ; sent++ {
+		latency, txHash, err := p.sendCanary(ctx, config.MaxLatency)
+		if ctx.Err() != nil {
+			return nil
+		}
+		if err != nil {
+			alert := &Alert{Reason: err.Error(), TxHash: txHash.Hex(), LatencySeconds: latency.Seconds(), Time: time.Now()}
+			p.raiseAlert(ctx, config.WebhookURL, alert)
+			if config.ExitOnAlert {
+				return fmt.Errorf("canary alert: %s", alert.Reason)
+			}
+		} else {
+			fmt.Printf("Canary %d included in %s: %s\n", sent, latency.Round(time.Millisecond), txHash.Hex())
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// sendCanary sends one canary transaction and waits up to maxLatency for its receipt
+func (p *Prober) sendCanary(ctx context.Context, maxLatency time.Duration) (time.Duration, common.Hash, error) {
+	nonce, err := p.client.PendingNonceAt(ctx, p.address)
+	if err != nil {
+		return 0, common.Hash{}, fmt.Errorf("failed to get nonce: %w", err)
+	}
+	gasPrice, err := p.client.SuggestGasPrice(ctx)
+	if err != nil {
+		return 0, common.Hash{}, fmt.Errorf("failed to get gas price: %w", err)
+	}
+	tx, err := p.sign(nonce, p.address, big.NewInt(0), canaryGasLimit, gasPrice, nil)
+	if err != nil {
+		return 0, common.Hash{}, err
+	}
+
+	sentAt := time.Now()
+	if err := p.submitter.SendTransaction(ctx, tx); err != nil {
+		return 0, tx.Hash(), fmt.Errorf("canary rejected: %w", err)
+	}
+
+	deadline := sentAt.Add(maxLatency)
+	for time.Now().Before(deadline) {
+		receipt, err := p.client.TransactionReceipt(ctx, tx.Hash())
+		if err == nil && receipt != nil {
+			latency := time.Since(sentAt)
+			if receipt.Status == types.ReceiptStatusFailed {
+				return latency, tx.Hash(), fmt.Errorf("canary reverted in block %d", receipt.BlockNumber.Uint64())
+			}
+			return latency, tx.Hash(), nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return 0, tx.Hash(), ctx.Err()
+		case <-time.After(500 * time.Millisecond):
+		}
+	}
+	return time.Since(sentAt), tx.Hash(), fmt.Errorf("canary not included within %s", maxLatency)
+}
+
+// raiseAlert logs an alert and posts it to the webhook when one is configured
+func (p *Prober) raiseAlert(ctx context.Context, webhookURL string, alert *Alert) {
+	log.Printf("CANARY ALERT: %s (tx %s)", alert.Reason, alert.TxHash)
+	if webhookURL == "" {
+		return
+	}
+
+	body, err := json.Marshal(alert)
+	if err != nil {
+		log.Printf("Failed to encode canary alert: %v", err)
+		return
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhookURL, bytes.NewReader(body))
+	if err != nil {
+		log.Printf("Failed to create canary webhook request: %v", err)
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		log.Printf("Failed to deliver canary alert: %v", err)
+		return
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		log.Printf("Canary webhook returned status %d", resp.StatusCode)
+	}
+}
@@ -0,0 +1,282 @@
+package probe
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/aakash4dev/ethereum-transaction-simulator/internal/contract"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// Differential case names
+const (
+	DiffDeployStorage = "deploy-storage"
+	DiffDeployGrief   = "deploy-gas-grief"
+	DiffTransfer      = "transfer"
+	DiffStorageCall   = "storage-set"
+	DiffRevert        = "revert"
+	DiffOutOfGas      = "out-of-gas"
+)
+
+// diffCallGas is the gas limit of the differential calls, enough for set() and a
+// meaningful amount of gas burned by the grief cases
+const diffCallGas = 100000
+
+// DiffConfig holds configuration for the differential test
+type DiffConfig struct {
+	Transactions int           // Transfers and calls sent after the two deployments
+	SettleTime   time.Duration // How long to wait for the transactions to be mined
+}
+
+// EndpointOutcome is how one endpoint handled one transaction
+type EndpointOutcome struct {
+	Accepted bool
+	Error    string
+	Mined    bool
+	Status   uint64
+	GasUsed  uint64
+	Logs     int
+	Block    uint64 // Blocks after the first block that included any of the set
+	Index    uint
+}
+
+// DiffEntry is one pre-signed transaction and how each endpoint handled it
+type DiffEntry struct {
+	Case        string
+	TxHash      common.Hash
+	A, B        EndpointOutcome
+	Divergences []string
+}
+
+// RunDifferential signs one transaction set and submits it unchanged to the prober's
+// endpoint (A) and to other (B), then compares acceptance, receipts and inclusion
+// order. A and B may be two clients on the same network or two separate devnets; the
+// key must have the same nonce and chain ID on both.
+func (p *Prober) RunDifferential(ctx context.Context, other *ethclient.Client, config *DiffConfig) ([]*DiffEntry, error) {
+	otherChainID, err := other.ChainID(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get chain ID from second endpoint: %w", err)
+	}
+	if otherChainID.Cmp(p.chainID) != 0 {
+		return nil, fmt.Errorf("chain IDs differ (first: %s, second: %s)", p.chainID, otherChainID)
+	}
+	nonce, err := p.client.PendingNonceAt(ctx, p.address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get nonce: %w", err)
+	}
+	otherNonce, err := other.PendingNonceAt(ctx, p.address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get nonce from second endpoint: %w", err)
+	}
+	if nonce != otherNonce {
+		return nil, fmt.Errorf("sender nonces differ (first: %d, second: %d)", nonce, otherNonce)
+	}
+	gasPrice, err := p.client.SuggestGasPrice(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get gas price: %w", err)
+	}
+	// Headroom so the set is priced in on both endpoints
+	gasPrice = bumpFee(gasPrice, 2)
+
+	txs, cases, err := p.buildDiffSet(nonce, gasPrice, config.Transactions)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]*DiffEntry, len(txs))
+	for i, tx := range txs {
+		entries[i] = &DiffEntry{Case: cases[i], TxHash: tx.Hash()}
+		if err := p.submitter.SendTransaction(ctx, tx); err != nil {
+			entries[i].A.Error = err.Error()
+		} else {
+			entries[i].A.Accepted = true
+		}
+		if err := other.SendTransaction(ctx, tx); err != nil {
+			entries[i].B.Error = err.Error()
+		} else {
+			entries[i].B.Accepted = true
+		}
+	}
+
+	p.collectReceipts(ctx, p.client, entries, func(e *DiffEntry) *EndpointOutcome { return &e.A }, config.SettleTime)
+	p.collectReceipts(ctx, other, entries, func(e *DiffEntry) *EndpointOutcome { return &e.B }, config.SettleTime)
+	for _, e := range entries {
+		e.Divergences = compareOutcomes(e.A, e.B)
+	}
+	return entries, nil
+}
+
+// buildDiffSet signs the differential set: a storage contract and a gas-grief contract
+// deployment, then transfers, set() calls, reverting calls and out-of-gas calls in turn
+func (p *Prober) buildDiffSet(nonce uint64, gasPrice *big.Int, count int) ([]*types.Transaction, []string, error) {
+	storageCode, err := contract.GetContractBytecode()
+	if err != nil {
+		return nil, nil, err
+	}
+	griefCode, err := contract.GetGasGriefBytecode()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var txs []*types.Transaction
+	var cases []string
+	storage := crypto.CreateAddress(p.address, nonce)
+	tx, err := p.signCreate(nonce, big.NewInt(0), 500000, gasPrice, storageCode)
+	if err != nil {
+		return nil, nil, err
+	}
+	txs, cases = append(txs, tx), append(cases, DiffDeployStorage)
+	nonce++
+
+	grief := crypto.CreateAddress(p.address, nonce)
+	tx, err = p.signCreate(nonce, big.NewInt(0), 200000, gasPrice, griefCode)
+	if err != nil {
+		return nil, nil, err
+	}
+	txs, cases = append(txs, tx), append(cases, DiffDeployGrief)
+	nonce++
+
+	for i := 0; i < count; i++ {
+		var name string
+		switch i % 4 {
+		case 0:
+			name = DiffTransfer
+			tx, err = p.sign(nonce, randomAddress(), big.NewInt(1), 21000, gasPrice, nil)
+		case 1:
+			name = DiffStorageCall
+			var data []byte
+			if data, err = contract.GetSetFunctionData(big.NewInt(int64(i))); err == nil {
+				tx, err = p.sign(nonce, storage, big.NewInt(0), diffCallGas, gasPrice, data)
+			}
+		case 2:
+			name = DiffRevert
+			tx, err = p.sign(nonce, grief, big.NewInt(0), diffCallGas, gasPrice, contract.GasGriefCalldata(diffCallGas/2, true))
+		case 3:
+			name = DiffOutOfGas
+			tx, err = p.sign(nonce, grief, big.NewInt(0), diffCallGas, gasPrice, contract.GasGriefCalldata(0, false))
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		txs, cases = append(txs, tx), append(cases, name)
+		nonce++
+	}
+	return txs, cases, nil
+}
+
+// collectReceipts waits up to timeout for the transactions an endpoint accepted to be
+// mined there, and records their receipts in the outcome picked by outcome
+func (p *Prober) collectReceipts(ctx context.Context, client *ethclient.Client, entries []*DiffEntry, outcome func(*DiffEntry) *EndpointOutcome, timeout time.Duration) {
+	deadline := time.Now().Add(timeout)
+	for {
+		outstanding := 0
+		for _, e := range entries {
+			o := outcome(e)
+			if !o.Accepted || o.Mined {
+				continue
+			}
+			receipt, err := client.TransactionReceipt(ctx, e.TxHash)
+			if err != nil || receipt == nil {
+				outstanding++
+				continue
+			}
+			o.Mined = true
+			o.Status = receipt.Status
+			o.GasUsed = receipt.GasUsed
+			o.Logs = len(receipt.Logs)
+			o.Block = receipt.BlockNumber.Uint64()
+			o.Index = receipt.TransactionIndex
+		}
+		if outstanding == 0 || !time.Now().Before(deadline) {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(time.Second):
+		}
+	}
+
+	// Express blocks relative to the first inclusion so separate chains compare
+	first := uint64(0)
+	for _, e := range entries {
+		if o := outcome(e); o.Mined && (first == 0 || o.Block < first) {
+			first = o.Block
+		}
+	}
+	for _, e := range entries {
+		if o := outcome(e); o.Mined {
+			o.Block -= first
+		}
+	}
+}
+
+// compareOutcomes describes every difference between how A and B handled a transaction
+func compareOutcomes(a, b EndpointOutcome) []string {
+	var divergences []string
+	if a.Accepted != b.Accepted {
+		divergences = append(divergences, fmt.Sprintf("acceptance: %s vs %s", describeAcceptance(a), describeAcceptance(b)))
+		return divergences
+	}
+	if a.Mined != b.Mined {
+		divergences = append(divergences, fmt.Sprintf("inclusion: mined %t vs %t", a.Mined, b.Mined))
+		return divergences
+	}
+	if !a.Mined {
+		return divergences
+	}
+	if a.Status != b.Status {
+		divergences = append(divergences, fmt.Sprintf("status: %d vs %d", a.Status, b.Status))
+	}
+	if a.GasUsed != b.GasUsed {
+		divergences = append(divergences, fmt.Sprintf("gas used: %d vs %d", a.GasUsed, b.GasUsed))
+	}
+	if a.Logs != b.Logs {
+		divergences = append(divergences, fmt.Sprintf("logs: %d vs %d", a.Logs, b.Logs))
+	}
+	if a.Block != b.Block || a.Index != b.Index {
+		divergences = append(divergences, fmt.Sprintf("ordering: block +%d index %d vs block +%d index %d", a.Block, a.Index, b.Block, b.Index))
+	}
+	return divergences
+}
+
+// describeAcceptance returns "accepted" or the rejection error
+func describeAcceptance(o EndpointOutcome) string {
+	if o.Accepted {
+		return "accepted"
+	}
+	return "rejected (" + o.Error + ")"
+}
+
+// PrintDiffReport prints every divergent transaction and a per-case divergence count
+func PrintDiffReport(entries []*DiffEntry) {
+	fmt.Printf("\n=== Differential Test ===\n")
+	divergent := 0
+	perCase := make(map[string]int)
+	var order []string
+	for _, e := range entries {
+		if _, seen := perCase[e.Case]; !seen {
+			order = append(order, e.Case)
+			perCase[e.Case] = 0
+		}
+		if len(e.Divergences) == 0 {
+			continue
+		}
+		divergent++
+		perCase[e.Case]++
+		fmt.Printf("%s %s\n", e.Case, e.TxHash.Hex())
+		for _, d := range e.Divergences {
+			fmt.Printf("  %s\n", d)
+		}
+	}
+	for _, name := range order {
+		fmt.Printf("%-18s divergent: %d\n", name, perCase[name])
+	}
+	fmt.Printf("Transactions: %d, divergent: %d\n", len(entries), divergent)
+	fmt.Printf("==========================\n")
+}
@@ -0,0 +1,48 @@
+package probe
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCompareOutcomes(t *testing.T) {
+	mined := EndpointOutcome{Accepted: true, Mined: true, Status: 1, GasUsed: 21000, Block: 0, Index: 3}
+
+	t.Run("identical outcomes do not diverge", func(t *testing.T) {
+		if d := compareOutcomes(mined, mined); len(d) != 0 {
+			t.Errorf("expected no divergences, got %v", d)
+		}
+	})
+
+	t.Run("rejection hides receipt differences", func(t *testing.T) {
+		rejected := EndpointOutcome{Error: "nonce too low"}
+		d := compareOutcomes(mined, rejected)
+		if len(d) != 1 || !strings.HasPrefix(d[0], "acceptance") || !strings.Contains(d[0], "nonce too low") {
+			t.Errorf("expected one acceptance divergence, got %v", d)
+		}
+	})
+
+	t.Run("mined on one endpoint only", func(t *testing.T) {
+		pending := EndpointOutcome{Accepted: true}
+		d := compareOutcomes(mined, pending)
+		if len(d) != 1 || !strings.HasPrefix(d[0], "inclusion") {
+			t.Errorf("expected one inclusion divergence, got %v", d)
+		}
+	})
+
+	t.Run("receipt and ordering differences are all reported", func(t *testing.T) {
+		other := mined
+		other.Status = 0
+		other.GasUsed = 30000
+		other.Index = 4
+		d := compareOutcomes(mined, other)
+		if len(d) != 3 {
+			t.Fatalf("expected 3 divergences, got %v", d)
+		}
+		for i, prefix := range []string{"status", "gas used", "ordering"} {
+			if !strings.HasPrefix(d[i], prefix) {
+				t.Errorf("expected divergence %d to be %s, got %s", i, prefix, d[i])
+			}
+		}
+	})
+}
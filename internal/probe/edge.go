@@ -0,0 +1,127 @@
+package probe
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// MaxTxSize is geth's txpool limit on the encoded size of a single transaction
+const MaxTxSize = 4 * 32 * 1024
+
+// edgeCase builds one boundary transaction at the given nonce
+type edgeCase struct {
+	name  string
+	build func(env *edgeEnv, nonce uint64) (*types.Transaction, error)
+}
+
+// edgeEnv holds chain values the edge cases are derived from
+type edgeEnv struct {
+	prober        *Prober
+	gasPrice      *big.Int
+	balance       *big.Int
+	blockGasLimit uint64
+}
+
+// edgeCases lists the boundary transactions in the order they are sent.
+// Zero gas price goes last since an accepted-but-unmineable tx would block later nonces.
+var edgeCases = []edgeCase{
+	{"max-calldata", func(env *edgeEnv, nonce uint64) (*types.Transaction, error) {
+		data := make([]byte, MaxTxSize-512) // Leave room for the rest of the encoding
+		return env.prober.sign(nonce, env.prober.address, big.NewInt(0), intrinsicGas(data), env.gasPrice, data)
+	}},
+	{"oversized-calldata", func(env *edgeEnv, nonce uint64) (*types.Transaction, error) {
+		data := make([]byte, MaxTxSize+1)
+		return env.prober.sign(nonce, env.prober.address, big.NewInt(0), intrinsicGas(data), env.gasPrice, data)
+	}},
+	{"gas-at-block-limit", func(env *edgeEnv, nonce uint64) (*types.Transaction, error) {
+		return env.prober.sign(nonce, env.prober.address, big.NewInt(0), env.blockGasLimit, env.gasPrice, nil)
+	}},
+	{"gas-above-block-limit", func(env *edgeEnv, nonce uint64) (*types.Transaction, error) {
+		return env.prober.sign(nonce, env.prober.address, big.NewInt(0), env.blockGasLimit+1, env.gasPrice, nil)
+	}},
+	{"gas-below-intrinsic", func(env *edgeEnv, nonce uint64) (*types.Transaction, error) {
+		return env.prober.sign(nonce, env.prober.address, big.NewInt(0), 20999, env.gasPrice, nil)
+	}},
+	{"value-equals-balance", func(env *edgeEnv, nonce uint64) (*types.Transaction, error) {
+		// The full balance leaves nothing for gas, so this should be rejected
+		return env.prober.sign(nonce, env.prober.address, env.balance, 21000, env.gasPrice, nil)
+	}},
+	{"zero-gas-price", func(env *edgeEnv, nonce uint64) (*types.Transaction, error) {
+		return env.prober.sign(nonce, env.prober.address, big.NewInt(0), 21000, big.NewInt(0), nil)
+	}},
+}
+
+// precompileCount is the number of precompiles at addresses 0x01..0x0a (through point evaluation)
+const precompileCount = 10
+
+// RunEdgeSuite sends boundary transactions (calldata at the size limit, gas at the
+// block gas limit, zero gas price, value equal to the full balance, calls to every
+// precompile) and reports how the node handled each
+func (p *Prober) RunEdgeSuite(ctx context.Context, settleTime time.Duration) ([]*Result, error) {
+	gasPrice, err := p.client.SuggestGasPrice(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get gas price: %w", err)
+	}
+	balance, err := p.client.BalanceAt(ctx, p.address, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get balance: %w", err)
+	}
+	header, err := p.client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get latest header: %w", err)
+	}
+	nonce, err := p.client.PendingNonceAt(ctx, p.address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get nonce: %w", err)
+	}
+
+	env := &edgeEnv{
+		prober:        p,
+		gasPrice:      gasPrice,
+		balance:       balance,
+		blockGasLimit: header.GasLimit,
+	}
+
+	cases := make([]edgeCase, 0, len(edgeCases)+precompileCount)
+	for i := 1; i <= precompileCount; i++ {
+		precompile := common.BigToAddress(big.NewInt(int64(i)))
+		cases = append(cases, edgeCase{fmt.Sprintf("precompile-0x%02x", i), func(env *edgeEnv, nonce uint64) (*types.Transaction, error) {
+			return env.prober.sign(nonce, precompile, big.NewInt(0), 100000, env.gasPrice, []byte{0x01})
+		}})
+	}
+	cases = append(cases, edgeCases...)
+
+	var results []*Result
+	for _, c := range cases {
+		tx, err := c.build(env, nonce)
+		if err != nil {
+			return results, fmt.Errorf("%s: %w", c.name, err)
+		}
+		result := p.submit(ctx, c.name, tx)
+		if result.Outcome == OutcomeAccepted {
+			nonce++
+		}
+		results = append(results, result)
+	}
+
+	p.settle(ctx, results, settleTime)
+	return results, nil
+}
+
+// intrinsicGas returns the intrinsic gas of a plain call carrying data
+func intrinsicGas(data []byte) uint64 {
+	gas := uint64(21000)
+	for _, b := range data {
+		if b == 0 {
+			gas += 4
+		} else {
+			gas += 16
+		}
+	}
+	return gas
+}
@@ -0,0 +1,177 @@
+package probe
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/aakash4dev/ethereum-transaction-simulator/internal/transaction"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// Verdicts of the invalid-transaction suite
+const (
+	VerdictRejected = "rejected" // Refused with one of the expected errors
+	VerdictMismatch = "mismatch" // Refused, but with an unexpected error
+	VerdictAccepted = "ACCEPTED" // Taken by the node although it is invalid
+	VerdictSkipped  = "skipped"  // The case cannot be built against this account
+)
+
+// invalidCase builds one transaction the node must refuse. expect lists phrases of
+// the refusal as geth, erigon, nethermind, reth and besu word it, compared after
+// normalizeError.
+type invalidCase struct {
+	name   string
+	expect []string
+	build  func(env *invalidEnv) (*types.Transaction, string, error) // A non-empty string skips the case with that reason
+}
+
+// invalidEnv holds the account state the invalid transactions are built from
+type invalidEnv struct {
+	prober   *Prober
+	gasPrice *big.Int
+	balance  *big.Int
+	nonce    uint64 // Pending nonce
+	mined    uint64 // Mined nonce
+}
+
+// InvalidResult records how the node answered one invalid transaction
+type InvalidResult struct {
+	Case     string
+	Verdict  string
+	Error    string   // The node's refusal, or why the case was skipped
+	Expected []string // Phrases one of which the refusal should contain
+}
+
+// invalidCases lists the invalid transactions in the order they are sent. Each is
+// built on the pending nonce, so one that is wrongly accepted only takes a nonce
+// that no later case relies on being free.
+var invalidCases = []invalidCase{
+	{"bad-signature", []string{"invalidsender", "invalidsignature", "invalidtransactionv,r,svalues", "signature"}, func(env *invalidEnv) (*types.Transaction, string, error) {
+		tx := types.NewTransaction(env.nonce, env.prober.address, big.NewInt(0), 21000, env.gasPrice, nil)
+		signed, err := tx.WithSignature(transaction.SignerFor(env.prober.chainID), make([]byte, 65))
+		return signed, "", err
+	}},
+	{"wrong-chain-id", []string{"invalidchainid", "chainid", "invalidsender"}, func(env *invalidEnv) (*types.Transaction, string, error) {
+		wrongChainID := new(big.Int).Add(env.prober.chainID, big.NewInt(1))
+		tx := types.NewTransaction(env.nonce, env.prober.address, big.NewInt(0), 21000, env.gasPrice, nil)
+		signed, err := types.SignTx(tx, types.NewEIP155Signer(wrongChainID), env.prober.privateKey)
+		return signed, "", err
+	}},
+	{"gas-below-intrinsic", []string{"intrinsicgas", "gaslimitbelow", "gastoolow"}, func(env *invalidEnv) (*types.Transaction, string, error) {
+		tx, err := env.prober.sign(env.nonce, env.prober.address, big.NewInt(0), 20999, env.gasPrice, nil)
+		return tx, "", err
+	}},
+	{"nonce-in-the-past", []string{"noncetoolow", "oldnonce", "nonceislowerthan", "lowernonce"}, func(env *invalidEnv) (*types.Transaction, string, error) {
+		if env.mined == 0 {
+			return nil, "the account has no mined transaction to reuse the nonce of", nil
+		}
+		tx, err := env.prober.sign(0, env.prober.address, big.NewInt(0), 21000, env.gasPrice, nil)
+		return tx, "", err
+	}},
+	{"value-above-balance", []string{"insufficientfunds", "insufficientbalance", "upfrontcost", "exceedsbalance"}, func(env *invalidEnv) (*types.Transaction, string, error) {
+		value := new(big.Int).Add(env.balance, big.NewInt(1))
+		tx, err := env.prober.sign(env.nonce, env.prober.address, value, 21000, env.gasPrice, nil)
+		return tx, "", err
+	}},
+}
+
+// RunInvalidSuite submits deliberately invalid transactions (bad signature, wrong
+// chain ID, gas below intrinsic, a nonce already used, value above the balance)
+// and checks that the node refuses each with the expected error
+func (p *Prober) RunInvalidSuite(ctx context.Context) ([]*InvalidResult, error) {
+	gasPrice, err := p.client.SuggestGasPrice(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get gas price: %w", err)
+	}
+	balance, err := p.client.BalanceAt(ctx, p.address, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get balance: %w", err)
+	}
+	nonce, err := p.client.PendingNonceAt(ctx, p.address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get nonce: %w", err)
+	}
+	mined, err := p.client.NonceAt(ctx, p.address, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get nonce: %w", err)
+	}
+	env := &invalidEnv{prober: p, gasPrice: gasPrice, balance: balance, nonce: nonce, mined: mined}
+
+	results := make([]*InvalidResult, 0, len(invalidCases))
+	for _, c := range invalidCases {
+		result := &InvalidResult{Case: c.name, Expected: c.expect}
+		tx, skip, err := c.build(env)
+		switch {
+		case err != nil:
+			return results, fmt.Errorf("%s: %w", c.name, err)
+		case skip != "":
+			result.Verdict, result.Error = VerdictSkipped, skip
+		default:
+			result.Verdict, result.Error = judgeRefusal(p.submitter.SendTransaction(ctx, tx), c.expect)
+			if result.Verdict == VerdictAccepted {
+				env.nonce++
+			}
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// judgeRefusal classifies the node's answer to an invalid transaction
+func judgeRefusal(err error, expect []string) (verdict, message string) {
+	if err == nil || transaction.IsAlreadyKnown(err) {
+		return VerdictAccepted, ""
+	}
+	normalized := normalizeError(err.Error())
+	for _, phrase := range expect {
+		if strings.Contains(normalized, phrase) {
+			return VerdictRejected, err.Error()
+		}
+	}
+	return VerdictMismatch, err.Error()
+}
+
+// normalizeError lowercases an error and drops spaces, underscores and dashes, so
+// "nonce too low", "NonceTooLow" and "NONCE_TOO_LOW" compare equal
+func normalizeError(message string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case ' ', '_', '-':
+			return -1
+		}
+		return r
+	}, strings.ToLower(message))
+}
+
+// InvalidFailures counts the cases the node accepted or refused with an
+// unexpected error
+func InvalidFailures(results []*InvalidResult) (accepted, mismatched int) {
+	for _, r := range results {
+		switch r.Verdict {
+		case VerdictAccepted:
+			accepted++
+		case VerdictMismatch:
+			mismatched++
+		}
+	}
+	return accepted, mismatched
+}
+
+// PrintInvalidResults prints the verdict of every invalid transaction
+func PrintInvalidResults(results []*InvalidResult) {
+	fmt.Printf("\n=== Invalid Transaction Rejection ===\n")
+	for _, r := range results {
+		fmt.Printf("%-24s %s\n", r.Case, r.Verdict)
+		switch r.Verdict {
+		case VerdictMismatch:
+			fmt.Printf("  error: %s\n  expected one of: %s\n", r.Error, strings.Join(r.Expected, ", "))
+		case VerdictRejected, VerdictSkipped:
+			fmt.Printf("  %s\n", r.Error)
+		}
+	}
+	accepted, mismatched := InvalidFailures(results)
+	fmt.Printf("Unexpectedly accepted: %d, unexpected errors: %d\n", accepted, mismatched)
+	fmt.Printf("==========================\n")
+}
@@ -0,0 +1,299 @@
+package probe
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// GapState is where a nonce-gapped transaction was last seen
+type GapState string
+
+const (
+	GapRejected GapState = "rejected" // Refused by RPC on submission
+	GapQueued   GapState = "queued"   // Held in the queued pool, waiting for the gap to close
+	GapPending  GapState = "pending"  // Promoted to the pending pool
+	GapInPool   GapState = "in-pool"  // In the pool; the node does not say which part
+	GapEvicted  GapState = "evicted"  // Accepted, then dropped without being mined
+	GapMined    GapState = "mined"    // Included in a block
+)
+
+// NonceGapConfig holds configuration for the nonce-gap probe
+type NonceGapConfig struct {
+	Offsets      []uint64      // Nonces to send, as offsets from the pending nonce; offset 0 is the first executable nonce
+	Fill         bool          // After observing, send the missing nonces so queued transactions can be promoted
+	GasLimit     uint64        // Gas limit for probe transactions
+	ObserveFor   time.Duration // How long to watch after sending, and again after filling
+	PollInterval time.Duration // Interval between pool status checks
+}
+
+// GapResult is the life of one nonce-gapped transaction
+type GapResult struct {
+	Offset     uint64
+	Nonce      uint64
+	Filler     bool // Sent to close a gap rather than to open one
+	State      GapState
+	Error      string
+	QueuedAt   time.Duration // Since the probe started; 0 if never seen queued
+	PromotedAt time.Duration // When first seen pending or mined after having been queued
+	EndedAt    time.Duration // When mined or evicted
+	txHash     common.Hash
+}
+
+// ParseNonceOffsets parses a comma-separated list of offsets and inclusive ranges,
+// e.g. 1-5,10,64-70
+func ParseNonceOffsets(spec string) ([]uint64, error) {
+	seen := make(map[uint64]bool)
+	var offsets []uint64
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		lo, hi, isRange := strings.Cut(part, "-")
+		first, err := strconv.ParseUint(strings.TrimSpace(lo), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid nonce offset %q", part)
+		}
+		last := first
+		if isRange {
+			if last, err = strconv.ParseUint(strings.TrimSpace(hi), 10, 64); err != nil || last < first {
+				return nil, fmt.Errorf("invalid nonce offset range %q", part)
+			}
+		}
+		if last-first >= 10000 {
+			return nil, fmt.Errorf("nonce offset range %q is too large (max 10000 nonces)", part)
+		}
+		for offset := first; offset <= last; offset++ {
+			if !seen[offset] {
+				seen[offset] = true
+				offsets = append(offsets, offset)
+			}
+		}
+	}
+	if len(offsets) == 0 {
+		return nil, errors.New("no nonce offsets given")
+	}
+	sort.Slice(offsets, func(i, j int) bool { return offsets[i] < offsets[j] })
+	return offsets, nil
+}
+
+// RunNonceGap sends transactions with future nonces to exercise the node's queued
+// pool and watches each one being held, evicted, promoted or mined. With Fill set,
+// the missing nonces are sent after the first observation window and the queued
+// transactions are watched again as the gaps close.
+func (p *Prober) RunNonceGap(ctx context.Context, config *NonceGapConfig) ([]*GapResult, error) {
+	base, err := p.client.PendingNonceAt(ctx, p.address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get nonce: %w", err)
+	}
+	gasPrice, err := p.client.SuggestGasPrice(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get gas price: %w", err)
+	}
+
+	start := time.Now()
+	results := make([]*GapResult, 0, len(config.Offsets))
+	for _, offset := range config.Offsets {
+		result, err := p.sendGapped(ctx, base, offset, false, gasPrice, config.GasLimit)
+		if err != nil {
+			return results, err
+		}
+		results = append(results, result)
+	}
+	p.observeGaps(ctx, results, start, config)
+
+	if config.Fill {
+		sent := make(map[uint64]bool, len(config.Offsets))
+		for _, offset := range config.Offsets {
+			sent[offset] = true
+		}
+		last := config.Offsets[len(config.Offsets)-1]
+		for offset := uint64(0); offset < last; offset++ {
+			if sent[offset] {
+				continue
+			}
+			result, err := p.sendGapped(ctx, base, offset, true, gasPrice, config.GasLimit)
+			if err != nil {
+				return results, err
+			}
+			results = append(results, result)
+		}
+		p.observeGaps(ctx, results, start, config)
+	}
+
+	sort.SliceStable(results, func(i, j int) bool { return results[i].Offset < results[j].Offset })
+	return results, nil
+}
+
+// sendGapped sends a zero-value self-transfer at base+offset
+func (p *Prober) sendGapped(ctx context.Context, base, offset uint64, filler bool, gasPrice *big.Int, gasLimit uint64) (*GapResult, error) {
+	tx, err := p.sign(base+offset, p.address, big.NewInt(0), gasLimit, gasPrice, nil)
+	if err != nil {
+		return nil, err
+	}
+	result := &GapResult{Offset: offset, Nonce: base + offset, Filler: filler, State: GapInPool, txHash: tx.Hash()}
+	if err := p.submitter.SendTransaction(ctx, tx); err != nil {
+		result.State = GapRejected
+		result.Error = err.Error()
+	}
+	return result, nil
+}
+
+// observeGaps polls the pool until every accepted transaction is mined or evicted,
+// or ObserveFor elapses
+func (p *Prober) observeGaps(ctx context.Context, results []*GapResult, start time.Time, config *NonceGapConfig) {
+	ticker := time.NewTicker(config.PollInterval)
+	defer ticker.Stop()
+	deadline := time.Now().Add(config.ObserveFor)
+
+	for time.Now().Before(deadline) {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		minedNonce, err := p.client.NonceAt(ctx, p.address, nil)
+		if err != nil {
+			continue
+		}
+		pending, queued, poolKnown := p.poolContent(ctx)
+		now := time.Since(start)
+		outstanding := 0
+		for _, r := range results {
+			if r.State == GapRejected || r.State == GapMined || r.State == GapEvicted {
+				continue
+			}
+			var state GapState
+			switch {
+			case r.Nonce < minedNonce:
+				state = GapMined
+			case poolKnown && queued[r.Nonce]:
+				state = GapQueued
+			case poolKnown && pending[r.Nonce]:
+				state = GapPending
+			case poolKnown:
+				state = GapEvicted
+			default:
+				state = p.lookupGapped(ctx, r)
+			}
+			r.advance(state, now)
+			if r.State != GapMined && r.State != GapEvicted {
+				outstanding++
+			}
+		}
+		if outstanding == 0 {
+			return
+		}
+	}
+}
+
+// advance records a newly observed state
+func (r *GapResult) advance(state GapState, now time.Duration) {
+	switch state {
+	case GapQueued:
+		if r.QueuedAt == 0 {
+			r.QueuedAt = now
+		}
+	case GapPending, GapMined:
+		if r.QueuedAt != 0 && r.PromotedAt == 0 {
+			r.PromotedAt = now
+		}
+	}
+	if state == GapMined || state == GapEvicted {
+		r.EndedAt = now
+	}
+	r.State = state
+}
+
+// lookupGapped asks for the transaction by hash, for nodes without txpool_contentFrom
+func (p *Prober) lookupGapped(ctx context.Context, r *GapResult) GapState {
+	_, isPending, err := p.client.TransactionByHash(ctx, r.txHash)
+	switch {
+	case errors.Is(err, ethereum.NotFound):
+		return GapEvicted
+	case err == nil && !isPending:
+		return GapMined
+	default:
+		return GapInPool
+	}
+}
+
+// poolContent returns the nonces of the prober's pending and queued transactions
+// through geth's txpool_contentFrom. ok is false when the node does not support it.
+func (p *Prober) poolContent(ctx context.Context) (pending, queued map[uint64]bool, ok bool) {
+	return poolContentFrom(ctx, p.client, p.address)
+}
+
+// poolContentFrom returns the nonces of address's pending and queued transactions
+func poolContentFrom(ctx context.Context, client *ethclient.Client, address common.Address) (pending, queued map[uint64]bool, ok bool) {
+	var content struct {
+		Pending map[string]json.RawMessage `json:"pending"`
+		Queued  map[string]json.RawMessage `json:"queued"`
+	}
+	if err := client.Client().CallContext(ctx, &content, "txpool_contentFrom", address); err != nil {
+		return nil, nil, false
+	}
+	nonces := func(txs map[string]json.RawMessage) map[uint64]bool {
+		set := make(map[uint64]bool, len(txs))
+		for key := range txs {
+			if nonce, err := strconv.ParseUint(key, 10, 64); err == nil {
+				set[nonce] = true
+			}
+		}
+		return set
+	}
+	return nonces(content.Pending), nonces(content.Queued), true
+}
+
+// PrintNonceGap prints the life of every nonce-gapped transaction and a summary
+// of how the node's queued pool treated them
+func PrintNonceGap(results []*GapResult) {
+	fmt.Printf("\n=== Nonce Gap Probe ===\n")
+	counts := make(map[GapState]int)
+	firstRejected := int64(-1)
+	for _, r := range results {
+		role := "gapped"
+		if r.Filler {
+			role = "filler"
+		}
+		line := fmt.Sprintf("+%-5d nonce %-8d %-7s %s", r.Offset, r.Nonce, role, r.State)
+		if r.QueuedAt > 0 {
+			line += fmt.Sprintf(", queued at %s", r.QueuedAt.Round(time.Second))
+		}
+		if r.PromotedAt > 0 {
+			line += fmt.Sprintf(", promoted at %s", r.PromotedAt.Round(time.Second))
+		}
+		if r.EndedAt > 0 {
+			line += fmt.Sprintf(", %s at %s", r.State, r.EndedAt.Round(time.Second))
+		}
+		if r.Error != "" {
+			line += ": " + r.Error
+		}
+		fmt.Println(line)
+		if r.Filler {
+			continue
+		}
+		counts[r.State]++
+		if r.State == GapRejected && firstRejected < 0 {
+			firstRejected = int64(r.Offset)
+		}
+	}
+	fmt.Printf("Gapped: %d rejected, %d still queued, %d evicted, %d promoted and pending, %d mined\n",
+		counts[GapRejected], counts[GapQueued]+counts[GapInPool], counts[GapEvicted], counts[GapPending], counts[GapMined])
+	if firstRejected >= 0 {
+		fmt.Printf("First rejected offset: +%d\n", firstRejected)
+	}
+	fmt.Printf("==========================\n")
+}
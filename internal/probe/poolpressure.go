@@ -0,0 +1,325 @@
+package probe
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/aakash4dev/ethereum-transaction-simulator/internal/transaction"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// pressureWorkers bounds the accounts sent from or polled at the same time
+const pressureWorkers = 32
+
+// PoolPressureConfig holds configuration for the pool-pressure probe
+type PoolPressureConfig struct {
+	TxsPerAccount int           // Consecutive nonces each account sends, e.g. past geth's 64 per-account slots
+	GasLimit      uint64        // Gas limit for probe transactions
+	ObserveFor    time.Duration // How long to watch the pool after sending
+	PollInterval  time.Duration // Interval between pool status checks
+}
+
+// PressureAccount is what the node did with one account's transactions
+type PressureAccount struct {
+	Address       common.Address
+	Base          uint64 // Pending nonce before sending
+	Accepted      int    // Transactions accepted by RPC; sending stops at the first rejection
+	FirstRejected int    // Position of the first rejected transaction, -1 if none was
+	Error         string // Reason given for the first rejection
+	txs           []*pressureTx
+}
+
+// pressureTx is one accepted transaction of an account
+type pressureTx struct {
+	hash  common.Hash
+	nonce uint64
+	state GapState
+}
+
+// PoolPressureReport is the outcome of a pool-pressure run
+type PoolPressureReport struct {
+	Accounts      []*PressureAccount
+	TxsPerAccount int
+	PeakPending   int           // Largest pool-wide pending count seen through txpool_status, -1 if unknown
+	PeakQueued    int           // Largest pool-wide queued count, -1 if unknown
+	FirstEviction time.Duration // Since sending started; 0 if nothing was evicted
+}
+
+// RunPoolPressure fills the node's pool from many accounts at once: each key sends
+// TxsPerAccount zero-value self-transfers with consecutive nonces, so every one is
+// executable and competes for pending slots. The pool is then watched to see which
+// transactions it keeps, evicts or mines. The keys must be funded for the gas.
+func (p *Prober) RunPoolPressure(ctx context.Context, keys []*ecdsa.PrivateKey, config *PoolPressureConfig) (*PoolPressureReport, error) {
+	if len(keys) == 0 {
+		return nil, errors.New("no accounts to send from")
+	}
+	gasPrice, err := p.client.SuggestGasPrice(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get gas price: %w", err)
+	}
+
+	report := &PoolPressureReport{
+		Accounts:      make([]*PressureAccount, len(keys)),
+		TxsPerAccount: config.TxsPerAccount,
+		PeakPending:   -1,
+		PeakQueued:    -1,
+	}
+	start := time.Now()
+	errs := make([]error, len(keys))
+	p.eachAccount(len(keys), func(i int) {
+		report.Accounts[i], errs[i] = p.fillAccount(ctx, keys[i], gasPrice, config)
+	})
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	p.observePressure(ctx, report, start, config)
+	return report, nil
+}
+
+// eachAccount calls f for every account index, pressureWorkers at a time
+func (p *Prober) eachAccount(n int, f func(i int)) {
+	sem := make(chan struct{}, pressureWorkers)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			f(i)
+		}(i)
+	}
+	wg.Wait()
+}
+
+// fillAccount sends one account's transactions in nonce order until the node
+// refuses one
+func (p *Prober) fillAccount(ctx context.Context, key *ecdsa.PrivateKey, gasPrice *big.Int, config *PoolPressureConfig) (*PressureAccount, error) {
+	address := crypto.PubkeyToAddress(key.PublicKey)
+	base, err := p.client.PendingNonceAt(ctx, address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get nonce of %s: %w", address.Hex(), err)
+	}
+	account := &PressureAccount{Address: address, Base: base, FirstRejected: -1}
+	for i := 0; i < config.TxsPerAccount; i++ {
+		if ctx.Err() != nil {
+			return account, nil
+		}
+		tx := types.NewTransaction(base+uint64(i), address, big.NewInt(0), config.GasLimit, gasPrice, nil)
+		signed, err := transaction.SignTx(tx, p.chainID, key)
+		if err != nil {
+			return nil, fmt.Errorf("failed to sign transaction: %w", err)
+		}
+		if err := p.submitter.SendTransaction(ctx, signed); err != nil {
+			account.FirstRejected = i
+			account.Error = err.Error()
+			break
+		}
+		account.Accepted++
+		account.txs = append(account.txs, &pressureTx{hash: signed.Hash(), nonce: signed.Nonce(), state: GapInPool})
+	}
+	return account, nil
+}
+
+// observePressure polls the pool until every accepted transaction is mined or
+// evicted, or ObserveFor elapses
+func (p *Prober) observePressure(ctx context.Context, report *PoolPressureReport, start time.Time, config *PoolPressureConfig) {
+	ticker := time.NewTicker(config.PollInterval)
+	defer ticker.Stop()
+	deadline := time.Now().Add(config.ObserveFor)
+	var mu sync.Mutex
+
+	for time.Now().Before(deadline) {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		var status struct {
+			Pending hexutil.Uint64 `json:"pending"`
+			Queued  hexutil.Uint64 `json:"queued"`
+		}
+		if err := p.client.Client().CallContext(ctx, &status, "txpool_status"); err == nil {
+			report.PeakPending = maxInt(report.PeakPending, int(status.Pending))
+			report.PeakQueued = maxInt(report.PeakQueued, int(status.Queued))
+		}
+
+		outstanding := 0
+		p.eachAccount(len(report.Accounts), func(i int) {
+			left, evicted := p.pollAccount(ctx, report.Accounts[i])
+			mu.Lock()
+			defer mu.Unlock()
+			outstanding += left
+			if evicted && report.FirstEviction == 0 {
+				report.FirstEviction = time.Since(start)
+			}
+		})
+		if outstanding == 0 {
+			return
+		}
+	}
+}
+
+// pollAccount updates the state of an account's transactions. It returns how
+// many are still in the pool and whether any was newly evicted.
+func (p *Prober) pollAccount(ctx context.Context, account *PressureAccount) (outstanding int, evicted bool) {
+	minedNonce, err := p.client.NonceAt(ctx, account.Address, nil)
+	if err != nil {
+		return len(account.txs), false
+	}
+	pending, queued, poolKnown := poolContentFrom(ctx, p.client, account.Address)
+	for _, tx := range account.txs {
+		if tx.state == GapMined || tx.state == GapEvicted {
+			continue
+		}
+		switch {
+		case tx.nonce < minedNonce:
+			tx.state = GapMined
+		case poolKnown && queued[tx.nonce]:
+			tx.state = GapQueued
+		case poolKnown && pending[tx.nonce]:
+			tx.state = GapPending
+		case poolKnown:
+			tx.state = GapEvicted
+		default:
+			_, isPending, err := p.client.TransactionByHash(ctx, tx.hash)
+			switch {
+			case errors.Is(err, ethereum.NotFound):
+				tx.state = GapEvicted
+			case err == nil && !isPending:
+				tx.state = GapMined
+			}
+		}
+		if tx.state == GapEvicted {
+			evicted = true
+		} else if tx.state != GapMined {
+			outstanding++
+		}
+	}
+	return outstanding, evicted
+}
+
+// States counts the account's accepted transactions by their last seen state
+func (a *PressureAccount) States() map[GapState]int {
+	counts := make(map[GapState]int)
+	for _, tx := range a.txs {
+		counts[tx.state]++
+	}
+	return counts
+}
+
+// PressureStats summarizes how many transactions the node took per account
+type PressureStats struct {
+	MinAccepted    int
+	MedianAccepted int
+	MaxAccepted    int
+	Limited        int            // Accounts that had a transaction rejected
+	AccountLimit   int            // Accepted count shared by every limited account, -1 if they differ or none was limited
+	TotalAccepted  int            // Across all accounts
+	Reasons        map[string]int // First rejection reason and the number of accounts that got it
+}
+
+// SummarizePressure derives the node's apparent limits from the accounts' outcomes.
+// When every limited account was cut off after the same number of transactions,
+// that number is the node's per-account limit; when they differ, the pool-wide
+// limit was hit first.
+func SummarizePressure(accounts []*PressureAccount) PressureStats {
+	stats := PressureStats{AccountLimit: -1, Reasons: make(map[string]int)}
+	if len(accounts) == 0 {
+		return stats
+	}
+	accepted := make([]int, len(accounts))
+	for i, a := range accounts {
+		accepted[i] = a.Accepted
+		stats.TotalAccepted += a.Accepted
+		if a.FirstRejected < 0 {
+			continue
+		}
+		stats.Reasons[a.Error]++
+		switch {
+		case stats.Limited == 0:
+			stats.AccountLimit = a.Accepted
+		case stats.AccountLimit != a.Accepted:
+			stats.AccountLimit = -1
+		}
+		stats.Limited++
+	}
+	if stats.Limited == 0 {
+		stats.AccountLimit = -1
+	}
+	sort.Ints(accepted)
+	stats.MinAccepted = accepted[0]
+	stats.MedianAccepted = accepted[len(accepted)/2]
+	stats.MaxAccepted = accepted[len(accepted)-1]
+	return stats
+}
+
+// maxInt returns the larger of a and b
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+// PrintPoolPressure prints per-account acceptance, what became of the accepted
+// transactions, and the limits they suggest
+func PrintPoolPressure(report *PoolPressureReport) {
+	fmt.Printf("\n=== Pool Pressure Probe ===\n")
+	stats := SummarizePressure(report.Accounts)
+	states := make(map[GapState]int)
+	for _, a := range report.Accounts {
+		for state, n := range a.States() {
+			states[state] += n
+		}
+	}
+	sent := len(report.Accounts) * report.TxsPerAccount
+	fmt.Printf("Accounts: %d, %d transactions each (%d total)\n", len(report.Accounts), report.TxsPerAccount, sent)
+	fmt.Printf("Accepted: %d (per account min %d, median %d, max %d)\n",
+		stats.TotalAccepted, stats.MinAccepted, stats.MedianAccepted, stats.MaxAccepted)
+	fmt.Printf("Accounts cut off by a rejection: %d\n", stats.Limited)
+
+	reasons := make([]string, 0, len(stats.Reasons))
+	for reason := range stats.Reasons {
+		reasons = append(reasons, reason)
+	}
+	sort.Slice(reasons, func(i, j int) bool { return stats.Reasons[reasons[i]] > stats.Reasons[reasons[j]] })
+	for _, reason := range reasons {
+		fmt.Printf("  %5d  %s\n", stats.Reasons[reason], reason)
+	}
+
+	fmt.Printf("Accepted transactions: %d pending, %d queued, %d in pool, %d evicted, %d mined\n",
+		states[GapPending], states[GapQueued], states[GapInPool], states[GapEvicted], states[GapMined])
+	if report.FirstEviction > 0 {
+		fmt.Printf("First eviction seen at: %s\n", report.FirstEviction.Round(time.Second))
+	}
+	if report.PeakPending >= 0 {
+		fmt.Printf("Pool peak: %d pending, %d queued\n", report.PeakPending, report.PeakQueued)
+	}
+
+	switch {
+	case stats.AccountLimit >= 0:
+		fmt.Printf("Per-account limit: %d transactions\n", stats.AccountLimit)
+	case stats.Limited > 0:
+		fmt.Printf("Accounts were cut off at different counts: the pool-wide limit was reached first\n")
+	case states[GapEvicted] > 0:
+		fmt.Printf("Every transaction was accepted, but the pool evicted some to stay within its limits\n")
+	default:
+		fmt.Printf("No limit reached; raise POOL_PRESSURE_ACCOUNTS or POOL_PRESSURE_TXS_PER_ACCOUNT\n")
+	}
+	fmt.Printf("==========================\n")
+}
@@ -0,0 +1,165 @@
+package probe
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aakash4dev/ethereum-transaction-simulator/internal/transaction"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// Outcome describes how the node handled a probe transaction
+type Outcome string
+
+const (
+	OutcomeAccepted Outcome = "accepted" // Accepted by RPC and still the live tx for its nonce
+	OutcomeRejected Outcome = "rejected" // Rejected by RPC on submission
+	OutcomeReplaced Outcome = "replaced" // Accepted, then superseded by another tx with the same nonce
+	OutcomeMined    Outcome = "mined"    // Included in a block
+)
+
+// Result records the node's handling of a single probe transaction
+type Result struct {
+	Case    string
+	TxHash  common.Hash
+	Nonce   uint64
+	Outcome Outcome
+	Error   string
+}
+
+// Prober sends deliberately unusual transactions to observe node mempool policy
+type Prober struct {
+	client     *ethclient.Client
+	privateKey *ecdsa.PrivateKey
+	address    common.Address
+	chainID    *big.Int
+	submitter  transaction.Submitter
+}
+
+// NewProber creates a new prober for the given funded key
+func NewProber(client *ethclient.Client, privateKeyHex string, chainID *big.Int) (*Prober, error) {
+	privateKey, err := crypto.HexToECDSA(strings.TrimPrefix(privateKeyHex, "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse private key: %w", err)
+	}
+
+	return &Prober{
+		client:     client,
+		privateKey: privateKey,
+		address:    crypto.PubkeyToAddress(privateKey.PublicKey),
+		chainID:    chainID,
+		submitter:  client,
+	}, nil
+}
+
+// SetSubmitter routes probe transactions to a separate write endpoint
+func (p *Prober) SetSubmitter(submitter transaction.Submitter) {
+	p.submitter = submitter
+}
+
+// sign signs a legacy transaction with the prober's key
+func (p *Prober) sign(nonce uint64, to common.Address, value *big.Int, gasLimit uint64, gasPrice *big.Int, data []byte) (*types.Transaction, error) {
+	tx := types.NewTransaction(nonce, to, value, gasLimit, gasPrice, data)
+	signedTx, err := transaction.SignTx(tx, p.chainID, p.privateKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign transaction: %w", err)
+	}
+	return signedTx, nil
+}
+
+// submit sends a transaction and records whether the RPC accepted it
+func (p *Prober) submit(ctx context.Context, name string, tx *types.Transaction) *Result {
+	result := &Result{
+		Case:    name,
+		TxHash:  tx.Hash(),
+		Nonce:   tx.Nonce(),
+		Outcome: OutcomeAccepted,
+	}
+	if err := p.submitter.SendTransaction(ctx, tx); err != nil {
+		result.Outcome = OutcomeRejected
+		result.Error = err.Error()
+	}
+	return result
+}
+
+// settle waits for the accepted results to be mined and marks the ones that
+// lost their nonce to another transaction as replaced
+func (p *Prober) settle(ctx context.Context, results []*Result, timeout time.Duration) {
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		outstanding := 0
+		for _, r := range results {
+			if r.Outcome != OutcomeAccepted {
+				continue
+			}
+			receipt, err := p.client.TransactionReceipt(ctx, r.TxHash)
+			if err == nil && receipt != nil {
+				r.Outcome = OutcomeMined
+				continue
+			}
+			outstanding++
+		}
+		if outstanding == 0 {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(time.Second):
+		}
+	}
+
+	// Any accepted tx whose nonce was mined by a different hash was replaced
+	mined := make(map[uint64]bool)
+	for _, r := range results {
+		if r.Outcome == OutcomeMined {
+			mined[r.Nonce] = true
+		}
+	}
+	for _, r := range results {
+		if r.Outcome == OutcomeAccepted && mined[r.Nonce] {
+			r.Outcome = OutcomeReplaced
+		}
+	}
+}
+
+// PrintResults prints a per-case summary of probe outcomes
+func PrintResults(title string, results []*Result) {
+	counts := make(map[string]map[Outcome]int)
+	errorSamples := make(map[string]string)
+	for _, r := range results {
+		if counts[r.Case] == nil {
+			counts[r.Case] = make(map[Outcome]int)
+		}
+		counts[r.Case][r.Outcome]++
+		if r.Error != "" {
+			errorSamples[r.Case] = r.Error
+		}
+	}
+
+	cases := make([]string, 0, len(counts))
+	for name := range counts {
+		cases = append(cases, name)
+	}
+	sort.Strings(cases)
+
+	fmt.Printf("\n=== %s ===\n", title)
+	for _, name := range cases {
+		c := counts[name]
+		fmt.Printf("%-28s mined: %d, accepted: %d, replaced: %d, rejected: %d\n",
+			name, c[OutcomeMined], c[OutcomeAccepted], c[OutcomeReplaced], c[OutcomeRejected])
+		if sample, ok := errorSamples[name]; ok {
+			fmt.Printf("  last error: %s\n", sample)
+		}
+	}
+	fmt.Printf("==========================\n")
+}
@@ -0,0 +1,146 @@
+package probe
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// PropagationConfig holds configuration for the mempool propagation measurement
+type PropagationConfig struct {
+	Samples      int           // Transactions sent through the prober's endpoint
+	SendInterval time.Duration // Pause between sends
+	PollInterval time.Duration // How often the observing endpoint is asked for each transaction
+	Timeout      time.Duration // Give up on a transaction not seen by the observer after this long
+}
+
+// PropagationSample is the observation of one transaction on the observing endpoint
+type PropagationSample struct {
+	TxHash  common.Hash
+	Latency time.Duration // From acceptance by the sending endpoint to first sighting
+	Seen    bool
+	Mined   bool // First seen already in a block, so the latency includes inclusion
+	Error   string
+}
+
+// RunPropagation sends transfers through the prober's endpoint (A) and polls observer
+// (B) for each one until it shows up, measuring how long the transaction took to
+// reach B's mempool
+func (p *Prober) RunPropagation(ctx context.Context, observer *ethclient.Client, config *PropagationConfig) ([]*PropagationSample, error) {
+	gasPrice, err := p.client.SuggestGasPrice(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get gas price: %w", err)
+	}
+	nonce, err := p.client.PendingNonceAt(ctx, p.address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get nonce: %w", err)
+	}
+
+	samples := make([]*PropagationSample, 0, config.Samples)
+	var wg sync.WaitGroup
+	for i := 0; i < config.Samples; i++ {
+		if i > 0 {
+			select {
+			case <-ctx.Done():
+				wg.Wait()
+				return samples, ctx.Err()
+			case <-time.After(config.SendInterval):
+			}
+		}
+		tx, err := p.sign(nonce, randomAddress(), big.NewInt(1), 21000, gasPrice, nil)
+		if err != nil {
+			wg.Wait()
+			return samples, err
+		}
+		sample := &PropagationSample{TxHash: tx.Hash()}
+		samples = append(samples, sample)
+		if err := p.submitter.SendTransaction(ctx, tx); err != nil {
+			sample.Error = err.Error()
+			continue
+		}
+		nonce++
+
+		wg.Add(1)
+		go func(sample *PropagationSample, sent time.Time) {
+			defer wg.Done()
+			watchPropagation(ctx, observer, sample, sent, config)
+		}(sample, time.Now())
+	}
+	wg.Wait()
+	return samples, nil
+}
+
+// watchPropagation polls the observer for one transaction until it is seen or the
+// timeout passes
+func watchPropagation(ctx context.Context, observer *ethclient.Client, sample *PropagationSample, sent time.Time, config *PropagationConfig) {
+	ticker := time.NewTicker(config.PollInterval)
+	defer ticker.Stop()
+	deadline := sent.Add(config.Timeout)
+	for {
+		if tx, isPending, err := observer.TransactionByHash(ctx, sample.TxHash); err == nil && tx != nil {
+			sample.Seen = true
+			sample.Mined = !isPending
+			sample.Latency = time.Since(sent)
+			return
+		}
+		if !time.Now().Before(deadline) {
+			return
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// PropagationLatencies returns the sorted latencies of transactions seen pending on
+// the observer. Transactions first seen in a block are left out, since their latency
+// includes inclusion rather than propagation alone.
+func PropagationLatencies(samples []*PropagationSample) []time.Duration {
+	var latencies []time.Duration
+	for _, s := range samples {
+		if s.Seen && !s.Mined {
+			latencies = append(latencies, s.Latency)
+		}
+	}
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+	return latencies
+}
+
+// PrintPropagationResults prints the propagation latency distribution
+func PrintPropagationResults(samples []*PropagationSample, pollInterval time.Duration) {
+	fmt.Printf("\n=== Mempool Propagation ===\n")
+	sent, missed, mined := 0, 0, 0
+	var lastError string
+	for _, s := range samples {
+		if s.Error != "" {
+			lastError = s.Error
+			continue
+		}
+		sent++
+		switch {
+		case !s.Seen:
+			missed++
+		case s.Mined:
+			mined++
+		}
+	}
+	latencies := PropagationLatencies(samples)
+	fmt.Printf("Sent: %d, seen pending: %d, first seen mined: %d, never seen: %d\n", sent, len(latencies), mined, missed)
+	if len(latencies) > 0 {
+		at := func(p int) time.Duration { return latencies[(len(latencies)-1)*p/100].Round(time.Millisecond) }
+		fmt.Printf("Latency min: %s, p50: %s, p90: %s, p99: %s, max: %s (resolution %s)\n",
+			at(0), at(50), at(90), at(99), at(100), pollInterval)
+	}
+	if lastError != "" {
+		fmt.Printf("Last send error: %s\n", lastError)
+	}
+	fmt.Printf("==========================\n")
+}
@@ -0,0 +1,23 @@
+package probe
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPropagationLatencies(t *testing.T) {
+	samples := []*PropagationSample{
+		{Seen: true, Latency: 300 * time.Millisecond},
+		{Seen: true, Latency: 100 * time.Millisecond},
+		{Seen: true, Mined: true, Latency: 12 * time.Second},
+		{Seen: false},
+		{Error: "nonce too low"},
+	}
+	latencies := PropagationLatencies(samples)
+	if len(latencies) != 2 {
+		t.Fatalf("expected only the 2 pending sightings, got %v", latencies)
+	}
+	if latencies[0] != 100*time.Millisecond || latencies[1] != 300*time.Millisecond {
+		t.Errorf("expected sorted latencies, got %v", latencies)
+	}
+}
@@ -0,0 +1,208 @@
+package probe
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/aakash4dev/ethereum-transaction-simulator/internal/transaction"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Selfdestruct cases
+const (
+	SelfdestructSameTx  = "same-tx"  // Destroyed by its own init code
+	SelfdestructLaterTx = "later-tx" // Deployed, then destroyed by a later call
+)
+
+var (
+	// selfdestructInitCode runs selfdestruct(caller()) during creation, so no code is stored
+	selfdestructInitCode = mustDecodeHex("33ff")
+	// selfdestructDeployCode stores the runtime selfdestruct(caller()), run by a later call
+	selfdestructDeployCode = mustDecodeHex("600280600b6000396000f3" + "33ff")
+)
+
+// selfdestructGas covers creation, the selfdestruct and the new-account charge
+const selfdestructGas = 100000
+
+// SelfdestructObservation is the state of one contract after its selfdestruct was mined
+type SelfdestructObservation struct {
+	Case        string
+	Contract    common.Address
+	Mined       bool
+	CodeRemoved bool
+	BalanceLeft bool // The contract still holds the wei it was created with
+	Error       string
+}
+
+// SelfdestructConfig holds configuration for the SELFDESTRUCT behavior suite
+type SelfdestructConfig struct {
+	Rounds     int           // Contracts per case
+	SettleTime time.Duration // How long to wait for the transactions to be mined
+}
+
+// mustDecodeHex decodes a hex constant, panicking on malformed input
+func mustDecodeHex(s string) []byte {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+// signCreate signs a legacy contract creation with the prober's key
+func (p *Prober) signCreate(nonce uint64, value *big.Int, gasLimit uint64, gasPrice *big.Int, code []byte) (*types.Transaction, error) {
+	tx := types.NewContractCreation(nonce, value, gasLimit, gasPrice, code)
+	signedTx, err := transaction.SignTx(tx, p.chainID, p.privateKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign transaction: %w", err)
+	}
+	return signedTx, nil
+}
+
+// RunSelfdestructSuite creates contracts funded with 1 wei that selfdestruct either
+// during creation or in a later transaction, then reads back their code and balance.
+// Since EIP-6780 (Cancun), only a contract destroyed in the transaction that created it
+// is removed; one destroyed later keeps its code and only loses its balance.
+func (p *Prober) RunSelfdestructSuite(ctx context.Context, config *SelfdestructConfig) ([]*SelfdestructObservation, error) {
+	gasPrice, err := p.client.SuggestGasPrice(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get gas price: %w", err)
+	}
+	nonce, err := p.client.PendingNonceAt(ctx, p.address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get nonce: %w", err)
+	}
+
+	var observations []*SelfdestructObservation
+	var results []*Result
+	txResults := make(map[*SelfdestructObservation][]*Result)
+	send := func(obs *SelfdestructObservation, tx *types.Transaction) bool {
+		result := p.submit(ctx, obs.Case, tx)
+		results = append(results, result)
+		txResults[obs] = append(txResults[obs], result)
+		if result.Outcome != OutcomeAccepted {
+			obs.Error = result.Error
+			return false
+		}
+		nonce++
+		return true
+	}
+
+	for i := 0; i < config.Rounds; i++ {
+		sameTx := &SelfdestructObservation{Case: SelfdestructSameTx, Contract: crypto.CreateAddress(p.address, nonce)}
+		observations = append(observations, sameTx)
+		tx, err := p.signCreate(nonce, big.NewInt(1), selfdestructGas, gasPrice, selfdestructInitCode)
+		if err != nil {
+			return observations, err
+		}
+		send(sameTx, tx)
+
+		laterTx := &SelfdestructObservation{Case: SelfdestructLaterTx, Contract: crypto.CreateAddress(p.address, nonce)}
+		observations = append(observations, laterTx)
+		tx, err = p.signCreate(nonce, big.NewInt(1), selfdestructGas, gasPrice, selfdestructDeployCode)
+		if err != nil {
+			return observations, err
+		}
+		if !send(laterTx, tx) {
+			continue
+		}
+		tx, err = p.sign(nonce, laterTx.Contract, big.NewInt(0), selfdestructGas, gasPrice, nil)
+		if err != nil {
+			return observations, err
+		}
+		send(laterTx, tx)
+	}
+
+	p.settle(ctx, results, config.SettleTime)
+	for _, obs := range observations {
+		obs.Mined = len(txResults[obs]) > 0
+		for _, r := range txResults[obs] {
+			if r.Outcome != OutcomeMined {
+				obs.Mined = false
+			}
+		}
+		if !obs.Mined {
+			continue
+		}
+		code, err := p.client.CodeAt(ctx, obs.Contract, nil)
+		if err != nil {
+			obs.Error = fmt.Sprintf("failed to get code: %v", err)
+			continue
+		}
+		balance, err := p.client.BalanceAt(ctx, obs.Contract, nil)
+		if err != nil {
+			obs.Error = fmt.Sprintf("failed to get balance: %v", err)
+			continue
+		}
+		obs.CodeRemoved = len(code) == 0
+		obs.BalanceLeft = balance.Sign() > 0
+	}
+	return observations, nil
+}
+
+// SelfdestructBehavior names the rule the observations match: "EIP-6780" when contracts
+// destroyed in a later transaction keep their code, "pre-Cancun" when they are removed,
+// "mixed" when both were seen, or "unknown" when none was mined
+func SelfdestructBehavior(observations []*SelfdestructObservation) string {
+	kept, removed := 0, 0
+	for _, obs := range observations {
+		if obs.Case != SelfdestructLaterTx || !obs.Mined || obs.Error != "" {
+			continue
+		}
+		if obs.CodeRemoved {
+			removed++
+		} else {
+			kept++
+		}
+	}
+	switch {
+	case kept > 0 && removed > 0:
+		return "mixed"
+	case kept > 0:
+		return "EIP-6780"
+	case removed > 0:
+		return "pre-Cancun"
+	default:
+		return "unknown"
+	}
+}
+
+// PrintSelfdestructResults prints per-case code and balance outcomes and the behavior they match
+func PrintSelfdestructResults(observations []*SelfdestructObservation) {
+	fmt.Printf("\n=== SELFDESTRUCT Behavior ===\n")
+	for _, name := range []string{SelfdestructSameTx, SelfdestructLaterTx} {
+		total, mined, removed, balanceLeft := 0, 0, 0, 0
+		var lastError string
+		for _, obs := range observations {
+			if obs.Case != name {
+				continue
+			}
+			total++
+			if obs.Error != "" {
+				lastError = obs.Error
+			}
+			if !obs.Mined || obs.Error != "" {
+				continue
+			}
+			mined++
+			if obs.CodeRemoved {
+				removed++
+			}
+			if obs.BalanceLeft {
+				balanceLeft++
+			}
+		}
+		fmt.Printf("%-10s contracts: %d, mined: %d, code removed: %d, code kept: %d, balance left: %d\n",
+			name, total, mined, removed, mined-removed, balanceLeft)
+		if lastError != "" {
+			fmt.Printf("  last error: %s\n", lastError)
+		}
+	}
+	fmt.Printf("Behavior: %s\n", SelfdestructBehavior(observations))
+	fmt.Printf("==========================\n")
+}
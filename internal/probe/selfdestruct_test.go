@@ -0,0 +1,24 @@
+package probe
+
+import (
+	"testing"
+)
+
+func TestSelfdestructBehavior(t *testing.T) {
+	sameTx := &SelfdestructObservation{Case: SelfdestructSameTx, Mined: true, CodeRemoved: true}
+	kept := &SelfdestructObservation{Case: SelfdestructLaterTx, Mined: true}
+	removed := &SelfdestructObservation{Case: SelfdestructLaterTx, Mined: true, CodeRemoved: true}
+	pending := &SelfdestructObservation{Case: SelfdestructLaterTx}
+
+	cases := map[string][]*SelfdestructObservation{
+		"EIP-6780":   {sameTx, kept, pending},
+		"pre-Cancun": {sameTx, removed},
+		"mixed":      {kept, removed},
+		"unknown":    {sameTx, pending},
+	}
+	for want, observations := range cases {
+		if got := SelfdestructBehavior(observations); got != want {
+			t.Errorf("expected %s, got %s", want, got)
+		}
+	}
+}
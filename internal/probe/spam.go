@@ -0,0 +1,105 @@
+package probe
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/aakash4dev/ethereum-transaction-simulator/internal/contract"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Spam probe case names
+const (
+	CaseDuplicate       = "duplicate-raw"
+	CaseConflictSameFee = "conflict-same-fee"
+	CaseConflictBumped  = "conflict-bumped-fee"
+	CaseRapidReplace    = "rapid-replacement"
+)
+
+// SpamConfig holds configuration for the spam-resistance probe
+type SpamConfig struct {
+	Rounds       int           // Number of times each case is exercised
+	Replacements int           // Replacements per nonce in the rapid-replacement case
+	GasLimit     uint64        // Gas limit for probe transactions
+	SettleTime   time.Duration // Time to wait for probe transactions to be mined
+}
+
+// RunSpamProbe sends duplicate, conflicting and rapidly replaced transactions and
+// reports how the node handled each of them
+func (p *Prober) RunSpamProbe(ctx context.Context, config *SpamConfig) ([]*Result, error) {
+	nonce, err := p.client.PendingNonceAt(ctx, p.address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get nonce: %w", err)
+	}
+	gasPrice, err := p.client.SuggestGasPrice(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get gas price: %w", err)
+	}
+
+	var results []*Result
+	for round := 0; round < config.Rounds; round++ {
+		select {
+		case <-ctx.Done():
+			return results, ctx.Err()
+		default:
+		}
+
+		// Duplicate: the exact same raw transaction twice
+		tx, err := p.sign(nonce, randomAddress(), big.NewInt(0), config.GasLimit, gasPrice, nil)
+		if err != nil {
+			return results, err
+		}
+		results = append(results, p.submit(ctx, CaseDuplicate, tx), p.submit(ctx, CaseDuplicate, tx))
+		nonce++
+
+		// Conflicting: same nonce and fee, different recipient
+		first, err := p.sign(nonce, randomAddress(), big.NewInt(0), config.GasLimit, gasPrice, nil)
+		if err != nil {
+			return results, err
+		}
+		second, err := p.sign(nonce, randomAddress(), big.NewInt(0), config.GasLimit, gasPrice, nil)
+		if err != nil {
+			return results, err
+		}
+		results = append(results, p.submit(ctx, CaseConflictSameFee, first), p.submit(ctx, CaseConflictSameFee, second))
+
+		// Conflicting: same nonce with a fee bumped past the usual 10% replacement threshold
+		bumped, err := p.sign(nonce, randomAddress(), big.NewInt(0), config.GasLimit, bumpFee(gasPrice, 1), nil)
+		if err != nil {
+			return results, err
+		}
+		results = append(results, p.submit(ctx, CaseConflictBumped, bumped))
+		nonce++
+
+		// Rapid replacement: a chain of fee bumps on one nonce sent back to back
+		for i := 0; i <= config.Replacements; i++ {
+			tx, err := p.sign(nonce, randomAddress(), big.NewInt(0), config.GasLimit, bumpFee(gasPrice, i), nil)
+			if err != nil {
+				return results, err
+			}
+			results = append(results, p.submit(ctx, CaseRapidReplace, tx))
+		}
+		nonce++
+	}
+
+	p.settle(ctx, results, config.SettleTime)
+	return results, nil
+}
+
+// bumpFee returns price increased by 12.5% compounded times times
+func bumpFee(price *big.Int, times int) *big.Int {
+	bumped := new(big.Int).Set(price)
+	for i := 0; i < times; i++ {
+		bumped.Mul(bumped, big.NewInt(9))
+		bumped.Div(bumped, big.NewInt(8))
+		bumped.Add(bumped, big.NewInt(1))
+	}
+	return bumped
+}
+
+// randomAddress returns a fresh random recipient
+func randomAddress() common.Address {
+	return contract.GenerateRandomAddresses(1)[0]
+}
@@ -0,0 +1,135 @@
+package probe
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+)
+
+func TestBumpFee(t *testing.T) {
+	t.Run("ClearsReplacementThreshold", func(t *testing.T) {
+		price := big.NewInt(1000000000)
+		bumped := bumpFee(price, 1)
+
+		// Geth requires at least a 10% bump to replace a pending transaction
+		minReplacement := new(big.Int).Div(new(big.Int).Mul(price, big.NewInt(110)), big.NewInt(100))
+		if bumped.Cmp(minReplacement) < 0 {
+			t.Errorf("bumped fee %s is below replacement threshold %s", bumped, minReplacement)
+		}
+	})
+
+	t.Run("ZeroBumpsKeepsPrice", func(t *testing.T) {
+		price := big.NewInt(7)
+		if bumpFee(price, 0).Cmp(price) != 0 {
+			t.Error("zero bumps should return the original price")
+		}
+	})
+}
+
+func TestIntrinsicGas(t *testing.T) {
+	t.Run("CountsZeroAndNonZeroBytes", func(t *testing.T) {
+		if got := intrinsicGas([]byte{0x00, 0x01}); got != 21000+4+16 {
+			t.Errorf("expected %d, got %d", 21000+4+16, got)
+		}
+	})
+}
+
+func TestParseNonceOffsets(t *testing.T) {
+	t.Run("ExpandsRanges", func(t *testing.T) {
+		offsets, err := ParseNonceOffsets("10, 1-3,2,64-65")
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected := []uint64{1, 2, 3, 10, 64, 65}
+		if len(offsets) != len(expected) {
+			t.Fatalf("expected %v, got %v", expected, offsets)
+		}
+		for i := range expected {
+			if offsets[i] != expected[i] {
+				t.Fatalf("expected %v, got %v", expected, offsets)
+			}
+		}
+	})
+
+	t.Run("RejectsBadSpecs", func(t *testing.T) {
+		for _, spec := range []string{"", "5-1", "a", "1-", "0-20000"} {
+			if _, err := ParseNonceOffsets(spec); err == nil {
+				t.Errorf("expected an error for %q", spec)
+			}
+		}
+	})
+}
+
+func TestGapResultAdvance(t *testing.T) {
+	r := &GapResult{State: GapInPool}
+	r.advance(GapQueued, time.Second)
+	r.advance(GapQueued, 2*time.Second)
+	r.advance(GapPending, 3*time.Second)
+	r.advance(GapMined, 4*time.Second)
+	if r.QueuedAt != time.Second || r.PromotedAt != 3*time.Second || r.EndedAt != 4*time.Second || r.State != GapMined {
+		t.Errorf("unexpected timeline: %+v", r)
+	}
+}
+
+func TestSummarizePressure(t *testing.T) {
+	t.Run("SharedLimitIsPerAccount", func(t *testing.T) {
+		accounts := []*PressureAccount{
+			{Accepted: 64, FirstRejected: 64, Error: "account limit exceeded"},
+			{Accepted: 64, FirstRejected: 64, Error: "account limit exceeded"},
+			{Accepted: 10, FirstRejected: -1},
+		}
+		stats := SummarizePressure(accounts)
+		if stats.AccountLimit != 64 || stats.Limited != 2 || stats.TotalAccepted != 138 {
+			t.Errorf("unexpected stats: %+v", stats)
+		}
+		if stats.MinAccepted != 10 || stats.MedianAccepted != 64 || stats.MaxAccepted != 64 {
+			t.Errorf("unexpected distribution: %+v", stats)
+		}
+		if stats.Reasons["account limit exceeded"] != 2 {
+			t.Errorf("unexpected reasons: %v", stats.Reasons)
+		}
+	})
+
+	t.Run("DifferingCutoffsHaveNoAccountLimit", func(t *testing.T) {
+		accounts := []*PressureAccount{
+			{Accepted: 40, FirstRejected: 40, Error: "txpool is full"},
+			{Accepted: 12, FirstRejected: 12, Error: "txpool is full"},
+		}
+		if stats := SummarizePressure(accounts); stats.AccountLimit != -1 || stats.Limited != 2 {
+			t.Errorf("unexpected stats: %+v", stats)
+		}
+	})
+
+	t.Run("NoRejections", func(t *testing.T) {
+		stats := SummarizePressure([]*PressureAccount{{Accepted: 5, FirstRejected: -1}})
+		if stats.AccountLimit != -1 || stats.Limited != 0 {
+			t.Errorf("unexpected stats: %+v", stats)
+		}
+	})
+}
+
+func TestJudgeRefusal(t *testing.T) {
+	expect := invalidCases[3].expect // nonce-in-the-past
+	cases := []struct {
+		err     error
+		verdict string
+	}{
+		{nil, VerdictAccepted},
+		{errors.New("already known"), VerdictAccepted},
+		{errors.New("nonce too low: next nonce 5, tx nonce 0"), VerdictRejected},
+		{errors.New("OldNonce, Current nonce: 5, nonce of rejected tx: 0"), VerdictRejected},
+		{errors.New("NONCE_TOO_LOW"), VerdictRejected},
+		{errors.New("insufficient funds for gas * price + value"), VerdictMismatch},
+	}
+	for _, c := range cases {
+		if verdict, _ := judgeRefusal(c.err, expect); verdict != c.verdict {
+			t.Errorf("%v: expected %s, got %s", c.err, c.verdict, verdict)
+		}
+	}
+
+	accepted, mismatched := InvalidFailures([]*InvalidResult{{Verdict: VerdictAccepted}, {Verdict: VerdictMismatch}, {Verdict: VerdictSkipped}, {Verdict: VerdictRejected}})
+	if accepted != 1 || mismatched != 1 {
+		t.Errorf("expected 1 accepted and 1 mismatched, got %d and %d", accepted, mismatched)
+	}
+}
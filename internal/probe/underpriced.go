@@ -0,0 +1,147 @@
+package probe
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// FeeCurveConfig holds configuration for the underpriced transaction probe
+type FeeCurveConfig struct {
+	MinPercent   int           // Lowest fee as a percentage of the suggested gas price
+	MaxPercent   int           // Highest fee as a percentage of the suggested gas price
+	Steps        int           // Number of fee levels between MinPercent and MaxPercent
+	GasLimit     uint64        // Gas limit for probe transactions
+	ObserveFor   time.Duration // How long to watch accepted transactions for eviction or inclusion
+	PollInterval time.Duration // Interval between pool status checks
+}
+
+// FeePoint is one sample on the fee-acceptance curve
+type FeePoint struct {
+	Percent      int
+	GasPrice     *big.Int
+	TxHash       common.Hash
+	Accepted     bool
+	Error        string
+	Mined        bool
+	MinedAfter   time.Duration
+	Evicted      bool
+	EvictedAfter time.Duration
+}
+
+// RunFeeCurve sends transactions with fees stepped from MaxPercent down to MinPercent
+// of the market price and watches each accepted one until it is mined, evicted, or
+// ObserveFor elapses. Fees descend with increasing nonces so every accepted
+// transaction stays executable; a rejected level reuses its nonce for the next one.
+func (p *Prober) RunFeeCurve(ctx context.Context, config *FeeCurveConfig) ([]*FeePoint, error) {
+	if config.Steps < 2 {
+		return nil, fmt.Errorf("fee curve needs at least 2 steps")
+	}
+
+	marketPrice, err := p.client.SuggestGasPrice(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get gas price: %w", err)
+	}
+	nonce, err := p.client.PendingNonceAt(ctx, p.address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get nonce: %w", err)
+	}
+
+	points := make([]*FeePoint, 0, config.Steps)
+	for i := 0; i < config.Steps; i++ {
+		percent := config.MaxPercent - (config.MaxPercent-config.MinPercent)*i/(config.Steps-1)
+		gasPrice := new(big.Int).Mul(marketPrice, big.NewInt(int64(percent)))
+		gasPrice.Div(gasPrice, big.NewInt(100))
+
+		tx, err := p.sign(nonce, p.address, big.NewInt(0), config.GasLimit, gasPrice, nil)
+		if err != nil {
+			return points, err
+		}
+		point := &FeePoint{Percent: percent, GasPrice: gasPrice, TxHash: tx.Hash()}
+		if err := p.submitter.SendTransaction(ctx, tx); err != nil {
+			point.Error = err.Error()
+		} else {
+			point.Accepted = true
+			nonce++
+		}
+		points = append(points, point)
+	}
+
+	p.observeFeePoints(ctx, points, config)
+	return points, nil
+}
+
+// observeFeePoints polls accepted transactions until each is mined or evicted
+func (p *Prober) observeFeePoints(ctx context.Context, points []*FeePoint, config *FeeCurveConfig) {
+	start := time.Now()
+	ticker := time.NewTicker(config.PollInterval)
+	defer ticker.Stop()
+
+	for time.Since(start) < config.ObserveFor {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		outstanding := 0
+		for _, point := range points {
+			if !point.Accepted || point.Mined || point.Evicted {
+				continue
+			}
+			_, isPending, err := p.client.TransactionByHash(ctx, point.TxHash)
+			switch {
+			case errors.Is(err, ethereum.NotFound):
+				point.Evicted = true
+				point.EvictedAfter = time.Since(start)
+			case err == nil && !isPending:
+				point.Mined = true
+				point.MinedAfter = time.Since(start)
+			default:
+				outstanding++
+			}
+		}
+		if outstanding == 0 {
+			return
+		}
+	}
+}
+
+// AcceptanceThreshold returns the lowest fee percentage the node accepted, or -1
+func AcceptanceThreshold(points []*FeePoint) int {
+	threshold := -1
+	for _, point := range points {
+		if point.Accepted && (threshold == -1 || point.Percent < threshold) {
+			threshold = point.Percent
+		}
+	}
+	return threshold
+}
+
+// PrintFeeCurve prints the fee-acceptance curve
+func PrintFeeCurve(points []*FeePoint) {
+	fmt.Printf("\n=== Fee Acceptance Curve ===\n")
+	for _, point := range points {
+		status := "pending"
+		switch {
+		case !point.Accepted:
+			status = "rejected: " + point.Error
+		case point.Mined:
+			status = fmt.Sprintf("mined after %s", point.MinedAfter.Round(time.Second))
+		case point.Evicted:
+			status = fmt.Sprintf("evicted after %s", point.EvictedAfter.Round(time.Second))
+		}
+		fmt.Printf("%4d%% (%s wei): %s\n", point.Percent, point.GasPrice.String(), status)
+	}
+	if threshold := AcceptanceThreshold(points); threshold >= 0 {
+		fmt.Printf("Acceptance threshold: %d%% of suggested gas price\n", threshold)
+	} else {
+		fmt.Printf("Acceptance threshold: no fee level was accepted\n")
+	}
+	fmt.Printf("==========================\n")
+}
@@ -0,0 +1,75 @@
+package remotesign
+
+import (
+	"fmt"
+	"net/rpc"
+
+	"github.com/aakash4dev/ethereum-transaction-simulator/internal/transaction"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// Client is the sender side of a split run. It holds no keys: every transaction is
+// signed by the signer process over the local socket.
+type Client struct {
+	client *rpc.Client
+}
+
+// Dial connects to the signer listening on the unix socket at path
+func Dial(path string) (*Client, error) {
+	client, err := rpc.Dial("unix", path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to signer: %w", err)
+	}
+	return &Client{client: client}, nil
+}
+
+// Addresses returns the wallets the signer can sign for
+func (c *Client) Addresses() ([]common.Address, error) {
+	var addresses []common.Address
+	if err := c.client.Call("Signer.Addresses", struct{}{}, &addresses); err != nil {
+		return nil, fmt.Errorf("failed to get signer addresses: %w", err)
+	}
+	return addresses, nil
+}
+
+// Wallets returns key-less parallel wallets for the signer's addresses, to be sent
+// from by a ParallelSender whose signer is set to c.SignTx
+func (c *Client) Wallets(client *ethclient.Client) ([]*transaction.ParallelWallet, error) {
+	addresses, err := c.Addresses()
+	if err != nil {
+		return nil, err
+	}
+	wallets := make([]*transaction.ParallelWallet, len(addresses))
+	for i, address := range addresses {
+		wallets[i] = &transaction.ParallelWallet{
+			Address:      address,
+			NonceManager: transaction.NewNonceManager(client, address),
+		}
+	}
+	return wallets, nil
+}
+
+// SignTx has the signer sign tx for from. Its signature matches
+// transaction.ParallelSender.SetSigner.
+func (c *Client) SignTx(tx *types.Transaction, from common.Address) (*types.Transaction, error) {
+	unsigned, err := tx.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	var raw []byte
+	if err := c.client.Call("Signer.Sign", SignArgs{From: from, Unsigned: unsigned}, &raw); err != nil {
+		return nil, fmt.Errorf("remote signing failed: %w", err)
+	}
+	signedTx := new(types.Transaction)
+	if err := signedTx.UnmarshalBinary(raw); err != nil {
+		return nil, fmt.Errorf("invalid signed transaction from signer: %w", err)
+	}
+	return signedTx, nil
+}
+
+// Close closes the connection to the signer
+func (c *Client) Close() {
+	c.client.Close()
+}
@@ -0,0 +1,152 @@
+package remotesign
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"net/rpc"
+	"os"
+	"path/filepath"
+
+	"github.com/aakash4dev/ethereum-transaction-simulator/internal/transaction"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// ErrRefused is returned for a transaction the signer's policy does not allow
+var ErrRefused = errors.New("refused by signer policy")
+
+// SignArgs asks the signer to sign one unsigned transaction
+type SignArgs struct {
+	From     common.Address
+	Unsigned []byte // Unsigned transaction in its binary encoding
+}
+
+// SignPolicy limits what the signer signs. The sender is less trusted than the
+// signer, so without limits it could have any held wallet send its whole balance
+// anywhere; with them it can only ask for transactions the run would send.
+type SignPolicy struct {
+	Recipients  *transaction.AddressPolicy // Recipients allowed besides the held wallets, nil allows any
+	MaxValue    *big.Int                   // Largest value per transaction, nil leaves it uncapped
+	MaxGasPrice *big.Int                   // Largest gas price or fee cap, nil leaves it uncapped
+}
+
+// Signer is the key-holding side of a split run. It signs transactions its policy
+// allows for the wallets it holds and nothing else, and never talks to the chain itself.
+type Signer struct {
+	chainID *big.Int
+	policy  SignPolicy
+	keys    map[common.Address]*ecdsa.PrivateKey
+	order   []common.Address
+}
+
+// NewSigner creates a signer for keys on chainID that signs what policy allows
+func NewSigner(chainID *big.Int, keys []*ecdsa.PrivateKey, policy SignPolicy) (*Signer, error) {
+	if len(keys) == 0 {
+		return nil, errors.New("signer needs at least one key")
+	}
+	s := &Signer{chainID: chainID, policy: policy, keys: make(map[common.Address]*ecdsa.PrivateKey, len(keys))}
+	for _, key := range keys {
+		address := crypto.PubkeyToAddress(key.PublicKey)
+		if _, ok := s.keys[address]; !ok {
+			s.order = append(s.order, address)
+		}
+		s.keys[address] = key
+	}
+	return s, nil
+}
+
+// Addresses returns the addresses the signer holds keys for, in the order given to NewSigner
+func (s *Signer) Addresses(_ struct{}, reply *[]common.Address) error {
+	*reply = append([]common.Address(nil), s.order...)
+	return nil
+}
+
+// Sign signs args.Unsigned with the key of args.From for the signer's chain ID, if
+// the policy allows it
+func (s *Signer) Sign(args SignArgs, reply *[]byte) error {
+	key, ok := s.keys[args.From]
+	if !ok {
+		return fmt.Errorf("no key for %s", args.From.Hex())
+	}
+	tx := new(types.Transaction)
+	if err := tx.UnmarshalBinary(args.Unsigned); err != nil {
+		return fmt.Errorf("invalid transaction: %w", err)
+	}
+	if err := s.check(tx); err != nil {
+		return err
+	}
+	signedTx, err := transaction.SignTx(tx, s.chainID, key)
+	if err != nil {
+		return err
+	}
+	raw, err := signedTx.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	*reply = raw
+	return nil
+}
+
+// check returns an error wrapping ErrRefused when the policy does not allow tx.
+// Legacy transactions carry no chain ID before signing; Sign binds them to the
+// signer's own.
+func (s *Signer) check(tx *types.Transaction) error {
+	if tx.Type() != types.LegacyTxType && tx.ChainId().Cmp(s.chainID) != 0 {
+		return fmt.Errorf("%w: chain ID %s, the signer is on %s", ErrRefused, tx.ChainId(), s.chainID)
+	}
+	if to := tx.To(); to != nil && s.policy.Recipients != nil && !s.policy.Recipients.Allowed(*to) {
+		if _, held := s.keys[*to]; !held || s.policy.Recipients.Denied(*to) {
+			return fmt.Errorf("%w: recipient %s", ErrRefused, to.Hex())
+		}
+	}
+	if s.policy.MaxValue != nil && tx.Value().Cmp(s.policy.MaxValue) > 0 {
+		return fmt.Errorf("%w: value %s exceeds %s", ErrRefused, tx.Value(), s.policy.MaxValue)
+	}
+	if s.policy.MaxGasPrice != nil && tx.GasFeeCap().Cmp(s.policy.MaxGasPrice) > 0 {
+		return fmt.Errorf("%w: gas price %s exceeds %s", ErrRefused, tx.GasFeeCap(), s.policy.MaxGasPrice)
+	}
+	return nil
+}
+
+// Listen opens the unix socket at path, replacing a stale socket left by an earlier
+// run. The socket's directory must be accessible to the current user only, and is
+// created that way if missing, so no other user can ever reach the socket.
+func Listen(path string) (net.Listener, error) {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create signer socket directory: %w", err)
+	}
+	info, err := os.Lstat(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check signer socket directory: %w", err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("signer socket directory %s is not a directory", dir)
+	}
+	if info.Mode().Perm() != 0700 {
+		return nil, fmt.Errorf("signer socket directory %s must have mode 0700 (has %o)", dir, info.Mode().Perm())
+	}
+
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("failed to remove stale signer socket: %w", err)
+	}
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on signer socket: %w", err)
+	}
+	return listener, nil
+}
+
+// Serve accepts sender connections on listener until it is closed
+func (s *Signer) Serve(listener net.Listener) error {
+	server := rpc.NewServer()
+	if err := server.RegisterName("Signer", s); err != nil {
+		return err
+	}
+	server.Accept(listener)
+	return nil
+}
@@ -0,0 +1,133 @@
+package remotesign
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/aakash4dev/ethereum-transaction-simulator/internal/transaction"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func TestSignerOverSocket(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	allowed := common.Address{0x02}
+	signer, err := NewSigner(big.NewInt(1337), []*ecdsa.PrivateKey{key}, SignPolicy{
+		Recipients:  transaction.NewAddressPolicy([]common.Address{allowed}, nil),
+		MaxValue:    big.NewInt(100),
+		MaxGasPrice: big.NewInt(10),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	listener, err := Listen(filepath.Join(t.TempDir(), "signer", "signer.sock"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	go signer.Serve(listener)
+
+	client, err := Dial(listener.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	t.Run("Addresses", func(t *testing.T) {
+		addresses, err := client.Addresses()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(addresses) != 1 || addresses[0] != crypto.PubkeyToAddress(key.PublicKey) {
+			t.Errorf("expected the signer's address, got %v", addresses)
+		}
+	})
+
+	t.Run("RefusesUnknownWallet", func(t *testing.T) {
+		tx := types.NewTransaction(0, common.Address{0x02}, big.NewInt(0), 21000, big.NewInt(1), nil)
+		if _, err := client.SignTx(tx, common.Address{0x01}); err == nil {
+			t.Error("expected an error for a wallet the signer holds no key for")
+		}
+	})
+	t.Run("SignsWhatPolicyAllows", func(t *testing.T) {
+		from := crypto.PubkeyToAddress(key.PublicKey)
+		for _, to := range []common.Address{allowed, from} {
+			tx := types.NewTransaction(0, to, big.NewInt(100), 21000, big.NewInt(10), nil)
+			signedTx, err := client.SignTx(tx, from)
+			if err != nil {
+				t.Fatalf("expected a transaction to %s to be signed: %v", to.Hex(), err)
+			}
+			if sender, err := types.Sender(types.LatestSignerForChainID(big.NewInt(1337)), signedTx); err != nil || sender != from {
+				t.Errorf("expected a signature by %s, got %s (%v)", from.Hex(), sender.Hex(), err)
+			}
+		}
+	})
+
+	t.Run("RefusesOutsidePolicy", func(t *testing.T) {
+		from := crypto.PubkeyToAddress(key.PublicKey)
+		refused := map[string]*types.Transaction{
+			"recipient": types.NewTransaction(0, common.Address{0x03}, big.NewInt(0), 21000, big.NewInt(1), nil),
+			"value":     types.NewTransaction(0, allowed, big.NewInt(101), 21000, big.NewInt(1), nil),
+			"gas price": types.NewTransaction(0, allowed, big.NewInt(0), 21000, big.NewInt(11), nil),
+			"chain ID": types.NewTx(&types.DynamicFeeTx{
+				ChainID: big.NewInt(1), To: &allowed, Gas: 21000, GasFeeCap: big.NewInt(1), GasTipCap: big.NewInt(1), Value: big.NewInt(0),
+			}),
+		}
+		for name, tx := range refused {
+			if _, err := client.SignTx(tx, from); err == nil {
+				t.Errorf("expected the %s to be refused", name)
+			}
+		}
+	})
+}
+
+func TestListen(t *testing.T) {
+	t.Run("CreatesPrivateDirectory", func(t *testing.T) {
+		dir := filepath.Join(t.TempDir(), "signer")
+		listener, err := Listen(filepath.Join(dir, "signer.sock"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer listener.Close()
+		info, err := os.Stat(dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info.Mode().Perm() != 0700 {
+			t.Errorf("expected mode 0700, got %o", info.Mode().Perm())
+		}
+	})
+
+	t.Run("RefusesSharedDirectory", func(t *testing.T) {
+		dir := t.TempDir()
+		if err := os.Chmod(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		if listener, err := Listen(filepath.Join(dir, "signer.sock")); err == nil {
+			listener.Close()
+			t.Error("expected a directory other users can reach to be refused")
+		}
+	})
+}
+
+func TestSignerPolicy(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	signer, err := NewSigner(big.NewInt(1337), []*ecdsa.PrivateKey{key}, SignPolicy{MaxValue: big.NewInt(1)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx := types.NewTransaction(0, common.Address{0x02}, big.NewInt(2), 21000, big.NewInt(1), nil)
+	unsigned, _ := tx.MarshalBinary()
+	var raw []byte
+	if err := signer.Sign(SignArgs{From: crypto.PubkeyToAddress(key.PublicKey), Unsigned: unsigned}, &raw); !errors.Is(err, ErrRefused) {
+		t.Errorf("expected ErrRefused, got %v", err)
+	}
+}
@@ -0,0 +1,96 @@
+package runs
+
+import (
+	"os"
+	"runtime"
+	"runtime/debug"
+	"time"
+)
+
+// gethModule is the go-ethereum module path, looked up in the build info
+const gethModule = "github.com/ethereum/go-ethereum"
+
+// Environment records what produced a run, so its results can be reproduced and
+// compared with later runs: the simulator build, the node it ran against, the
+// host and the resolved configuration
+type Environment struct {
+	SimulatorVersion string                 `json:"simulatorVersion"`
+	Commit           string                 `json:"commit,omitempty"`
+	CommitTime       string                 `json:"commitTime,omitempty"`
+	Modified         bool                   `json:"modified,omitempty"` // Built from a tree with uncommitted changes
+	GoVersion        string                 `json:"goVersion"`
+	GethVersion      string                 `json:"gethVersion,omitempty"`
+	ClientVersion    string                 `json:"clientVersion,omitempty"` // web3_clientVersion of the target node
+	ChainID          string                 `json:"chainId,omitempty"`
+	Host             Host                   `json:"host"`
+	Config           map[string]interface{} `json:"config,omitempty"` // Resolved settings, secrets redacted
+	RecordedAt       time.Time              `json:"recordedAt"`
+}
+
+// Host describes the machine the simulator ran on
+type Host struct {
+	Hostname string `json:"hostname"`
+	OS       string `json:"os"`
+	Arch     string `json:"arch"`
+	CPUs     int    `json:"cpus"`
+}
+
+// Report is the report.json file: the run's environment next to its final metrics
+type Report struct {
+	Environment *Environment `json:"environment,omitempty"`
+	Metrics     interface{}  `json:"metrics"`
+}
+
+// NewEnvironment describes the running binary and host. clientVersion and chainID
+// come from the target node and config from the caller's resolved settings.
+func NewEnvironment(clientVersion, chainID string, config map[string]interface{}) *Environment {
+	env := &Environment{
+		SimulatorVersion: "(devel)",
+		GoVersion:        runtime.Version(),
+		ClientVersion:    clientVersion,
+		ChainID:          chainID,
+		Host: Host{
+			OS:   runtime.GOOS,
+			Arch: runtime.GOARCH,
+			CPUs: runtime.NumCPU(),
+		},
+		Config:     config,
+		RecordedAt: time.Now().UTC(),
+	}
+	env.Host.Hostname, _ = os.Hostname()
+	if info, ok := debug.ReadBuildInfo(); ok {
+		env.fromBuildInfo(info)
+	}
+	return env
+}
+
+// fromBuildInfo fills in the versions Go embeds in the binary
+func (e *Environment) fromBuildInfo(info *debug.BuildInfo) {
+	if info.Main.Version != "" {
+		e.SimulatorVersion = info.Main.Version
+	}
+	for _, dep := range info.Deps {
+		if dep.Path != gethModule {
+			continue
+		}
+		e.GethVersion = dep.Version
+		if dep.Replace != nil {
+			e.GethVersion += " => " + dep.Replace.Path + " " + dep.Replace.Version
+		}
+	}
+	for _, setting := range info.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			e.Commit = setting.Value
+		case "vcs.time":
+			e.CommitTime = setting.Value
+		case "vcs.modified":
+			e.Modified = setting.Value == "true"
+		}
+	}
+}
+
+// WriteReport writes report.json with the run's environment and final metrics
+func (r *Run) WriteReport(env *Environment, metrics interface{}) error {
+	return r.WriteJSON(ReportFile, Report{Environment: env, Metrics: metrics})
+}
@@ -0,0 +1,233 @@
+package runs
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Artifact file names inside a run directory
+const (
+	MetadataFile  = "run.json"
+	OutputFile    = "output.log"
+	ReportFile    = "report.json"
+	WalletsFile   = "wallets.json"
+	ManifestFile  = "manifest.json"
+	ContractsFile = "contracts.json"
+	TraceFile     = "trace.json"
+)
+
+// Run statuses recorded in run.json
+const (
+	StatusRunning  = "running"
+	StatusFinished = "finished"
+	StatusFailed   = "failed"
+)
+
+// Metadata is the run.json file describing one run
+type Metadata struct {
+	ID         string    `json:"id"`
+	Mode       string    `json:"mode"`
+	StartedAt  time.Time `json:"startedAt"`
+	FinishedAt time.Time `json:"finishedAt,omitempty"`
+	Status     string    `json:"status"`
+	Error      string    `json:"error,omitempty"`
+	// Offset of the local clock from a reference such as NTP at run start, so
+	// timestamps from runs on different machines can be lined up
+	ClockSource   string  `json:"clockSource,omitempty"`
+	ClockOffsetMs float64 `json:"clockOffsetMs,omitempty"`
+}
+
+// Run is the artifact directory of one run, runs/<id>/
+type Run struct {
+	Dir      string
+	Metadata Metadata
+	restore  func()
+}
+
+// FormatID formats a run ID the way memos print it, so a directory can be matched
+// with the transactions its run sent
+func FormatID(id uint64) string {
+	return fmt.Sprintf("%016x", id)
+}
+
+// Create opens the directory root/<id> for a run, creating it if needed. Starting a
+// run again with the same ID reuses its directory, replacing the artifacts it writes
+// again, so a retried run does not leave a second, partial directory behind.
+func Create(root, id, mode string) (*Run, error) {
+	dir := filepath.Join(root, id)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create run directory: %w", err)
+	}
+	r := &Run{
+		Dir: dir,
+		Metadata: Metadata{
+			ID:        id,
+			Mode:      mode,
+			StartedAt: time.Now().UTC(),
+			Status:    StatusRunning,
+		},
+	}
+	if err := r.WriteJSON(MetadataFile, r.Metadata); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// RecordClockOffset records in run.json how far source's clock was ahead of the
+// local one at run start
+func (r *Run) RecordClockOffset(source string, offset time.Duration) error {
+	r.Metadata.ClockSource = source
+	r.Metadata.ClockOffsetMs = float64(offset) / float64(time.Millisecond)
+	return r.WriteJSON(MetadataFile, r.Metadata)
+}
+
+// Path returns the path of an artifact inside the run directory
+func (r *Run) Path(name string) string {
+	return filepath.Join(r.Dir, name)
+}
+
+// WriteJSON writes v to the named artifact as indented JSON
+func (r *Run) WriteJSON(name string, v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode %s: %w", name, err)
+	}
+	if err := os.WriteFile(r.Path(name), data, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	return nil
+}
+
+// CaptureOutput copies everything written to stdout into output.log for the rest of
+// the run, while still printing it. Finish stops the capture.
+func (r *Run) CaptureOutput() error {
+	file, err := os.Create(r.Path(OutputFile))
+	if err != nil {
+		return fmt.Errorf("failed to create output log: %w", err)
+	}
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("failed to capture output: %w", err)
+	}
+
+	stdout := os.Stdout
+	os.Stdout = writer
+	done := make(chan struct{})
+	go func() {
+		io.Copy(io.MultiWriter(stdout, file), reader)
+		close(done)
+	}()
+	r.restore = func() {
+		os.Stdout = stdout
+		writer.Close()
+		<-done
+		reader.Close()
+		file.Close()
+	}
+	return nil
+}
+
+// Finish stops output capture and records how the run ended in run.json
+func (r *Run) Finish(runErr error) error {
+	if r.restore != nil {
+		r.restore()
+		r.restore = nil
+	}
+	r.Metadata.FinishedAt = time.Now().UTC()
+	r.Metadata.Status = StatusFinished
+	if runErr != nil {
+		r.Metadata.Status = StatusFailed
+		r.Metadata.Error = runErr.Error()
+	}
+	return r.WriteJSON(MetadataFile, r.Metadata)
+}
+
+// RunsOptions holds the arguments of the runs subcommand
+type RunsOptions struct {
+	Dir string // Directory holding one subdirectory per run
+}
+
+// ParseRunsArgs parses `simulator runs list [--dir runs]`
+func ParseRunsArgs(args []string) (*RunsOptions, error) {
+	if len(args) == 0 || args[0] != "list" {
+		return nil, errors.New("usage: simulator runs list [--dir runs]")
+	}
+	fs := flag.NewFlagSet("runs list", flag.ContinueOnError)
+	dir := fs.String("dir", "runs", "directory holding the run directories")
+	if err := fs.Parse(args[1:]); err != nil {
+		return nil, err
+	}
+	return &RunsOptions{Dir: *dir}, nil
+}
+
+// Listing is one run found by List and the artifacts in its directory
+type Listing struct {
+	Metadata  Metadata
+	Artifacts []string
+}
+
+// List reads the run.json of every run under root, newest first. Directories
+// without a readable run.json are skipped.
+func List(root string) ([]Listing, error) {
+	entries, err := os.ReadDir(root)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read runs directory: %w", err)
+	}
+
+	var listings []Listing
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		dir := filepath.Join(root, entry.Name())
+		data, err := os.ReadFile(filepath.Join(dir, MetadataFile))
+		if err != nil {
+			continue
+		}
+		var listing Listing
+		if err := json.Unmarshal(data, &listing.Metadata); err != nil {
+			continue
+		}
+		files, _ := os.ReadDir(dir)
+		for _, f := range files {
+			if !f.IsDir() && f.Name() != MetadataFile {
+				listing.Artifacts = append(listing.Artifacts, f.Name())
+			}
+		}
+		listings = append(listings, listing)
+	}
+	sort.Slice(listings, func(i, j int) bool {
+		return listings[i].Metadata.StartedAt.After(listings[j].Metadata.StartedAt)
+	})
+	return listings, nil
+}
+
+// PrintRuns prints one line per run with its mode, start time, duration and status
+func PrintRuns(listings []Listing) {
+	fmt.Printf("\n=== Runs ===\n")
+	for _, l := range listings {
+		m := l.Metadata
+		duration := "-"
+		if !m.FinishedAt.IsZero() {
+			duration = m.FinishedAt.Sub(m.StartedAt).Round(time.Second).String()
+		}
+		fmt.Printf("%s  %-12s %s  %8s  %s\n", m.ID, m.Mode, m.StartedAt.Local().Format("2006-01-02 15:04:05"), duration, m.Status)
+		if len(l.Artifacts) > 0 {
+			fmt.Printf("  %s\n", strings.Join(l.Artifacts, ", "))
+		}
+	}
+	fmt.Printf("Runs: %d\n", len(listings))
+	fmt.Printf("==========================\n")
+}
@@ -0,0 +1,96 @@
+package runs
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"testing"
+)
+
+func TestRunLifecycle(t *testing.T) {
+	root := t.TempDir()
+
+	t.Run("CreateAndFinish", func(t *testing.T) {
+		r, err := Create(root, FormatID(0xabc), "parallel")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := r.WriteJSON(ReportFile, map[string]int{"sent": 10}); err != nil {
+			t.Fatal(err)
+		}
+		if err := r.Finish(errors.New("boom")); err != nil {
+			t.Fatal(err)
+		}
+
+		listings, err := List(root)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(listings) != 1 {
+			t.Fatalf("expected 1 run, got %d", len(listings))
+		}
+		m := listings[0].Metadata
+		if m.ID != "0000000000000abc" || m.Mode != "parallel" || m.Status != StatusFailed || m.Error != "boom" {
+			t.Errorf("unexpected metadata: %+v", m)
+		}
+		if len(listings[0].Artifacts) != 1 || listings[0].Artifacts[0] != ReportFile {
+			t.Errorf("expected only the report artifact, got %v", listings[0].Artifacts)
+		}
+	})
+
+	t.Run("SameIDReusesDirectory", func(t *testing.T) {
+		if _, err := Create(root, FormatID(0xabc), "parallel"); err != nil {
+			t.Fatal(err)
+		}
+		entries, _ := os.ReadDir(root)
+		if len(entries) != 1 {
+			t.Errorf("expected one run directory, got %d", len(entries))
+		}
+	})
+
+	t.Run("MissingRoot", func(t *testing.T) {
+		listings, err := List(root + "/missing")
+		if err != nil || len(listings) != 0 {
+			t.Errorf("expected no runs and no error, got %v, %v", listings, err)
+		}
+	})
+}
+
+func TestParseRunsArgs(t *testing.T) {
+	if _, err := ParseRunsArgs(nil); err == nil {
+		t.Error("missing list action should be rejected")
+	}
+	options, err := ParseRunsArgs([]string{"list", "--dir", "out"})
+	if err != nil || options.Dir != "out" {
+		t.Errorf("unexpected result: %+v, %v", options, err)
+	}
+}
+
+func TestEnvironment(t *testing.T) {
+	env := NewEnvironment("Geth/v1.13.0", "1337", map[string]interface{}{"Mode": "parallel"})
+	if env.GoVersion == "" || env.Host.OS == "" || env.Host.CPUs == 0 || env.SimulatorVersion == "" {
+		t.Errorf("expected build and host details, got %+v", env)
+	}
+
+	r, err := Create(t.TempDir(), FormatID(1), "parallel")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := r.WriteReport(env, map[string]int{"sent": 3}); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(r.Path(ReportFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var report struct {
+		Environment Environment    `json:"environment"`
+		Metrics     map[string]int `json:"metrics"`
+	}
+	if err := json.Unmarshal(data, &report); err != nil {
+		t.Fatal(err)
+	}
+	if report.Environment.ClientVersion != "Geth/v1.13.0" || report.Environment.Config["Mode"] != "parallel" || report.Metrics["sent"] != 3 {
+		t.Errorf("unexpected report: %s", data)
+	}
+}
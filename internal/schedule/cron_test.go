@@ -0,0 +1,46 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCron(t *testing.T) {
+	t.Run("NightlyNext", func(t *testing.T) {
+		cron, err := ParseCron("0 2 * * *")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		from := time.Date(2024, 5, 1, 3, 0, 0, 0, time.UTC)
+		next, err := cron.Next(from)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		want := time.Date(2024, 5, 2, 2, 0, 0, 0, time.UTC)
+		if !next.Equal(want) {
+			t.Errorf("expected %s, got %s", want, next)
+		}
+	})
+
+	t.Run("Steps", func(t *testing.T) {
+		cron, err := ParseCron("*/15 * * * 1-5")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		// Wednesday 10:30 matches, Sunday does not
+		if !cron.Matches(time.Date(2024, 5, 1, 10, 30, 0, 0, time.UTC)) {
+			t.Error("expected weekday quarter hour to match")
+		}
+		if cron.Matches(time.Date(2024, 5, 5, 10, 30, 0, 0, time.UTC)) {
+			t.Error("expected Sunday not to match")
+		}
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		for _, expr := range []string{"* * * *", "60 * * * *", "*/0 * * * *", "a * * * *"} {
+			if _, err := ParseCron(expr); err == nil {
+				t.Errorf("%q: expected error", expr)
+			}
+		}
+	})
+}
@@ -0,0 +1,83 @@
+package schedule
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// RunFunc runs one scheduled scenario and writes its report to w
+type RunFunc func(ctx context.Context, w io.Writer) error
+
+// Scheduler runs a scenario on a cron schedule, each run bounded by a maximum duration
+type Scheduler struct {
+	cron      *Cron
+	duration  time.Duration // Maximum length of one run (0 = until the run returns)
+	reportDir string        // Directory receiving one report file per run, empty disables
+	run       RunFunc
+}
+
+// NewScheduler creates a scheduler for the cron expression expr
+func NewScheduler(expr string, duration time.Duration, reportDir string, run RunFunc) (*Scheduler, error) {
+	cron, err := ParseCron(expr)
+	if err != nil {
+		return nil, err
+	}
+	if reportDir != "" {
+		if err := os.MkdirAll(reportDir, 0755); err != nil {
+			return nil, fmt.Errorf("failed to create report directory: %w", err)
+		}
+	}
+	return &Scheduler{cron: cron, duration: duration, reportDir: reportDir, run: run}, nil
+}
+
+// Run waits for each scheduled time and runs the scenario until the context is
+// cancelled. A run that is still going when the next slot arrives delays that slot;
+// failed runs are logged and do not stop the schedule.
+func (s *Scheduler) Run(ctx context.Context) error {
+	for {
+		next, err := s.cron.Next(time.Now())
+		if err != nil {
+			return err
+		}
+		log.Printf("Next scheduled run at %s", next.Format(time.RFC3339))
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(time.Until(next)):
+		}
+
+		if err := s.runOnce(ctx, next); err != nil {
+			log.Printf("Scheduled run at %s failed: %v", next.Format(time.RFC3339), err)
+		}
+	}
+}
+
+// runOnce runs the scenario once, archiving its report when a report directory is set
+func (s *Scheduler) runOnce(ctx context.Context, startedAt time.Time) error {
+	runCtx := ctx
+	if s.duration > 0 {
+		var cancel context.CancelFunc
+		runCtx, cancel = context.WithTimeout(ctx, s.duration)
+		defer cancel()
+	}
+
+	var report io.Writer = os.Stdout
+	if s.reportDir != "" {
+		path := filepath.Join(s.reportDir, fmt.Sprintf("run-%s.log", startedAt.Format("20060102-150405")))
+		file, err := os.Create(path)
+		if err != nil {
+			return fmt.Errorf("failed to create report file: %w", err)
+		}
+		defer file.Close()
+		report = io.MultiWriter(os.Stdout, file)
+		log.Printf("Archiving report to %s", path)
+	}
+
+	return s.run(runCtx, report)
+}
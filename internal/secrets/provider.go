@@ -0,0 +1,9 @@
+package secrets
+
+import "context"
+
+// Provider fetches the funding wallet's private key from an external secret store,
+// for setups where the key may not be kept on disk or in the environment
+type Provider interface {
+	PrivateKey(ctx context.Context) (string, error)
+}
@@ -0,0 +1,143 @@
+package secrets
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// VaultConfig holds configuration for reading the private key from HashiCorp Vault
+type VaultConfig struct {
+	Addr      string // Vault server, e.g. https://vault.example:8200
+	Path      string // Secret path, e.g. secret/data/simulator for a KV v2 mount
+	Field     string // Field of the secret holding the key (default: private_key)
+	Namespace string // Vault Enterprise namespace, empty for none
+	Token     string // Token auth; takes precedence over AppRole
+	RoleID    string // AppRole auth role_id
+	SecretID  string // AppRole auth secret_id
+}
+
+// Vault reads the private key from a Vault KV secret, authenticating with a token
+// or an AppRole
+type Vault struct {
+	config VaultConfig
+	client *http.Client
+}
+
+// NewVault creates a Vault provider
+func NewVault(config VaultConfig) (*Vault, error) {
+	if config.Addr == "" || config.Path == "" {
+		return nil, errors.New("vault needs an address and a secret path")
+	}
+	if config.Token == "" && (config.RoleID == "" || config.SecretID == "") {
+		return nil, errors.New("vault needs a token or an AppRole role_id and secret_id")
+	}
+	if config.Field == "" {
+		config.Field = "private_key"
+	}
+	return &Vault{config: config, client: &http.Client{Timeout: 30 * time.Second}}, nil
+}
+
+// PrivateKey logs in if needed and reads the key field of the configured secret
+func (v *Vault) PrivateKey(ctx context.Context) (string, error) {
+	token := v.config.Token
+	if token == "" {
+		var err error
+		if token, err = v.loginAppRole(ctx); err != nil {
+			return "", err
+		}
+	}
+
+	var response struct {
+		Data map[string]interface{} `json:"data"`
+	}
+	path := "/v1/" + strings.TrimPrefix(v.config.Path, "/")
+	if err := v.do(ctx, http.MethodGet, path, token, nil, &response); err != nil {
+		return "", fmt.Errorf("failed to read vault secret %s: %w", v.config.Path, err)
+	}
+	key, err := secretField(response.Data, v.config.Field)
+	if err != nil {
+		return "", fmt.Errorf("vault secret %s: %w", v.config.Path, err)
+	}
+	return key, nil
+}
+
+// secretField returns a string field of a secret. KV v2 nests the fields under a
+// second "data" object next to "metadata"; KV v1 returns them directly.
+func secretField(data map[string]interface{}, field string) (string, error) {
+	if nested, ok := data["data"].(map[string]interface{}); ok {
+		if _, v2 := data["metadata"]; v2 {
+			data = nested
+		}
+	}
+	value, ok := data[field]
+	if !ok {
+		return "", fmt.Errorf("no field %q", field)
+	}
+	s, ok := value.(string)
+	if !ok || s == "" {
+		return "", fmt.Errorf("field %q is not a non-empty string", field)
+	}
+	return s, nil
+}
+
+// loginAppRole exchanges the role_id and secret_id for a client token
+func (v *Vault) loginAppRole(ctx context.Context) (string, error) {
+	body, err := json.Marshal(map[string]string{"role_id": v.config.RoleID, "secret_id": v.config.SecretID})
+	if err != nil {
+		return "", err
+	}
+	var response struct {
+		Auth struct {
+			ClientToken string `json:"client_token"`
+		} `json:"auth"`
+	}
+	if err := v.do(ctx, http.MethodPost, "/v1/auth/approle/login", "", body, &response); err != nil {
+		return "", fmt.Errorf("vault approle login failed: %w", err)
+	}
+	if response.Auth.ClientToken == "" {
+		return "", errors.New("vault approle login returned no token")
+	}
+	return response.Auth.ClientToken, nil
+}
+
+// do sends a request to the Vault HTTP API and decodes a successful JSON response
+func (v *Vault) do(ctx context.Context, method, path, token string, body []byte, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, method, strings.TrimSuffix(v.config.Addr, "/")+path, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("X-Vault-Token", token)
+	}
+	if v.config.Namespace != "" {
+		req.Header.Set("X-Vault-Namespace", v.config.Namespace)
+	}
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		// Vault reports failures as {"errors": [...]}; never echo anything else back
+		var failure struct {
+			Errors []string `json:"errors"`
+		}
+		json.NewDecoder(resp.Body).Decode(&failure)
+		if len(failure.Errors) > 0 {
+			return fmt.Errorf("status %d: %s", resp.StatusCode, strings.Join(failure.Errors, "; "))
+		}
+		return fmt.Errorf("status %d", resp.StatusCode)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
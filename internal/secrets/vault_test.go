@@ -0,0 +1,73 @@
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestVault(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/auth/approle/login":
+			var body map[string]string
+			json.NewDecoder(r.Body).Decode(&body)
+			if body["role_id"] != "role" || body["secret_id"] != "secret" {
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte(`{"errors":["invalid role or secret ID"]}`))
+				return
+			}
+			w.Write([]byte(`{"auth":{"client_token":"approle-token"}}`))
+		case "/v1/secret/data/simulator":
+			if token := r.Header.Get("X-Vault-Token"); token != "root" && token != "approle-token" {
+				w.WriteHeader(http.StatusForbidden)
+				w.Write([]byte(`{"errors":["permission denied"]}`))
+				return
+			}
+			w.Write([]byte(`{"data":{"data":{"private_key":"0xabc"},"metadata":{"version":1}}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	t.Run("TokenAuth", func(t *testing.T) {
+		v, err := NewVault(VaultConfig{Addr: server.URL, Path: "secret/data/simulator", Token: "root"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		key, err := v.PrivateKey(context.Background())
+		if err != nil || key != "0xabc" {
+			t.Errorf("expected 0xabc, got %q (%v)", key, err)
+		}
+	})
+
+	t.Run("AppRoleAuth", func(t *testing.T) {
+		v, _ := NewVault(VaultConfig{Addr: server.URL, Path: "secret/data/simulator", RoleID: "role", SecretID: "secret"})
+		if key, err := v.PrivateKey(context.Background()); err != nil || key != "0xabc" {
+			t.Errorf("expected 0xabc, got %q (%v)", key, err)
+		}
+		v, _ = NewVault(VaultConfig{Addr: server.URL, Path: "secret/data/simulator", RoleID: "role", SecretID: "wrong"})
+		if _, err := v.PrivateKey(context.Background()); err == nil {
+			t.Error("expected a login error")
+		}
+	})
+
+	t.Run("RequiresAuth", func(t *testing.T) {
+		if _, err := NewVault(VaultConfig{Addr: server.URL, Path: "secret/data/simulator"}); err == nil {
+			t.Error("expected an error without a token or AppRole")
+		}
+	})
+}
+
+func TestSecretField(t *testing.T) {
+	v1 := map[string]interface{}{"private_key": "0x1", "data": map[string]interface{}{"private_key": "0x2"}}
+	if got, _ := secretField(v1, "private_key"); got != "0x1" {
+		t.Errorf("expected the KV v1 field, got %s", got)
+	}
+	if _, err := secretField(v1, "missing"); err == nil {
+		t.Error("expected an error for a missing field")
+	}
+}
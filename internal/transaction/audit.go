@@ -0,0 +1,231 @@
+package transaction
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// auditConcurrency bounds the RPC calls made while snapshotting and auditing wallets
+const auditConcurrency = 50
+
+// auditSettleTimeout is how long Audit waits for pending transactions to be mined
+const auditSettleTimeout = 60 * time.Second
+
+// walletAudit holds the state needed to reconcile a wallet with the chain after a run
+type walletAudit struct {
+	startNonce   uint64
+	startBalance *big.Int
+	sent         []common.Hash
+	mu           sync.Mutex
+}
+
+// WalletDiscrepancy describes a wallet whose on-chain state does not match what was sent
+type WalletDiscrepancy struct {
+	Address         common.Address
+	Sent            int      // Transactions we recorded as sent
+	Mined           int      // Of those, transactions with a receipt
+	NonceDelta      uint64   // Nonce increase observed on chain
+	ExpectedBalance *big.Int // Start balance less fees and values of mined transactions
+	ActualBalance   *big.Int
+}
+
+// AuditReport compares our run metrics with the chain's view of the wallets
+type AuditReport struct {
+	Wallets       int
+	Sent          int   // Transactions recorded per wallet
+	Mined         int   // Transactions with a receipt
+	ReportedSent  int64 // Sent count from the run metrics
+	Discrepancies []WalletDiscrepancy
+}
+
+// snapshotWallets records each wallet's nonce and balance before sending starts
+func (ps *ParallelSender) snapshotWallets(ctx context.Context) error {
+	var wg sync.WaitGroup
+	errChan := make(chan error, len(ps.wallets))
+	semaphore := make(chan struct{}, auditConcurrency)
+
+	for _, wallet := range ps.wallets {
+		wg.Add(1)
+		go func(w *ParallelWallet) {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			nonce, err := ps.client.NonceAt(ctx, w.Address, nil)
+			if err != nil {
+				errChan <- fmt.Errorf("wallet %s: failed to get nonce: %w", w.Address.Hex(), err)
+				return
+			}
+			balance, err := ps.client.BalanceAt(ctx, w.Address, nil)
+			if err != nil {
+				errChan <- fmt.Errorf("wallet %s: failed to get balance: %w", w.Address.Hex(), err)
+				return
+			}
+			w.audit = &walletAudit{startNonce: nonce, startBalance: balance}
+		}(wallet)
+	}
+
+	wg.Wait()
+	close(errChan)
+	if err, ok := <-errChan; ok {
+		return err
+	}
+	return nil
+}
+
+// recordSent remembers a sent transaction for the post-run audit
+func (w *ParallelWallet) recordSent(hash common.Hash) {
+	if w.audit == nil {
+		return
+	}
+	w.audit.mu.Lock()
+	w.audit.sent = append(w.audit.sent, hash)
+	w.audit.mu.Unlock()
+}
+
+// Audit reconciles every wallet with the chain once its pending transactions settle:
+// the nonce increase must equal the number of mined transactions, and the balance must
+// equal the start balance less the fees and values of those transactions. Wallets that
+// disagree point at silently dropped transactions or miscounted metrics.
+func (ps *ParallelSender) Audit(ctx context.Context) (*AuditReport, error) {
+	ps.waitForSettle(ctx, auditSettleTimeout)
+
+	report := &AuditReport{
+		Wallets:      len(ps.wallets),
+		ReportedSent: atomic.LoadInt64(&ps.totalSent),
+	}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	errChan := make(chan error, len(ps.wallets))
+	semaphore := make(chan struct{}, auditConcurrency)
+
+	for _, wallet := range ps.wallets {
+		if wallet.audit == nil {
+			continue
+		}
+		wg.Add(1)
+		go func(w *ParallelWallet) {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			result, err := ps.auditWallet(ctx, w)
+			if err != nil {
+				errChan <- fmt.Errorf("wallet %s: %w", w.Address.Hex(), err)
+				return
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			report.Sent += result.Sent
+			report.Mined += result.Mined
+			if result.NonceDelta != uint64(result.Mined) || result.ExpectedBalance.Cmp(result.ActualBalance) != 0 {
+				report.Discrepancies = append(report.Discrepancies, *result)
+			}
+		}(wallet)
+	}
+
+	wg.Wait()
+	close(errChan)
+	if err, ok := <-errChan; ok {
+		return nil, err
+	}
+	return report, nil
+}
+
+// auditWallet compares one wallet's receipts, nonce and balance with its start snapshot
+func (ps *ParallelSender) auditWallet(ctx context.Context, w *ParallelWallet) (*WalletDiscrepancy, error) {
+	w.audit.mu.Lock()
+	sent := make([]common.Hash, len(w.audit.sent))
+	copy(sent, w.audit.sent)
+	w.audit.mu.Unlock()
+
+	result := &WalletDiscrepancy{
+		Address:         w.Address,
+		Sent:            len(sent),
+		ExpectedBalance: new(big.Int).Set(w.audit.startBalance),
+	}
+	for _, hash := range sent {
+		receipt, err := ps.client.TransactionReceipt(ctx, hash)
+		if err != nil || receipt == nil {
+			continue // Dropped or still pending
+		}
+		result.Mined++
+
+		fee := new(big.Int).Mul(new(big.Int).SetUint64(receipt.GasUsed), receipt.EffectiveGasPrice)
+		result.ExpectedBalance.Sub(result.ExpectedBalance, fee)
+		// Reverted calls do not transfer value
+		if receipt.Status == types.ReceiptStatusSuccessful {
+			result.ExpectedBalance.Sub(result.ExpectedBalance, ps.value())
+		}
+	}
+
+	nonce, err := ps.client.NonceAt(ctx, w.Address, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get nonce: %w", err)
+	}
+	result.NonceDelta = nonce - w.audit.startNonce
+
+	balance, err := ps.client.BalanceAt(ctx, w.Address, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get balance: %w", err)
+	}
+	result.ActualBalance = balance
+	return result, nil
+}
+
+// waitForSettle waits until no wallet has pending transactions, or timeout elapses
+func (ps *ParallelSender) waitForSettle(ctx context.Context, timeout time.Duration) {
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		settled := true
+		for _, w := range ps.wallets {
+			pending, err := ps.client.PendingNonceAt(ctx, w.Address)
+			if err != nil {
+				continue
+			}
+			mined, err := ps.client.NonceAt(ctx, w.Address, nil)
+			if err != nil || pending > mined {
+				settled = false
+				break
+			}
+		}
+		if settled {
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(2 * time.Second):
+		}
+	}
+}
+
+// PrintAudit prints the post-run chain state audit
+func PrintAudit(report *AuditReport) {
+	fmt.Printf("\n=== Chain State Audit ===\n")
+	fmt.Printf("Wallets audited: %d\n", report.Wallets)
+	fmt.Printf("Sent (per wallet records): %d, reported by metrics: %d\n", report.Sent, report.ReportedSent)
+	if int64(report.Sent) != report.ReportedSent {
+		fmt.Printf("  WARNING: sent counts disagree, metrics may be double-counting\n")
+	}
+	fmt.Printf("Mined: %d, missing (dropped or pending): %d\n", report.Mined, report.Sent-report.Mined)
+	fmt.Printf("Wallets with discrepancies: %d\n", len(report.Discrepancies))
+	for i, d := range report.Discrepancies {
+		if i == 10 {
+			fmt.Printf("  ... %d more\n", len(report.Discrepancies)-10)
+			break
+		}
+		fmt.Printf("  - %s: sent %d, mined %d, nonce +%d, balance %s (expected %s)\n",
+			d.Address.Hex(), d.Sent, d.Mined, d.NonceDelta, d.ActualBalance.String(), d.ExpectedBalance.String())
+	}
+	fmt.Printf("==========================\n")
+}
@@ -0,0 +1,153 @@
+package transaction
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// BroadcastEndpoint is one endpoint a BroadcastSubmitter sends to
+type BroadcastEndpoint struct {
+	Name      string // Shown in the report, usually the endpoint URL
+	Submitter Submitter
+}
+
+// EndpointStats summarizes how one broadcast endpoint answered
+type EndpointStats struct {
+	Name       string
+	Accepted   int
+	Rejected   int
+	Wins       int // Times this endpoint accepted first
+	P50Latency time.Duration
+	P95Latency time.Duration
+	LastError  string
+}
+
+// endpointRecord accumulates the answers of one endpoint
+type endpointRecord struct {
+	latencies []time.Duration
+	rejected  int
+	wins      int
+	lastError string
+}
+
+// BroadcastSubmitter sends each signed transaction to every endpoint at once and
+// returns as soon as one accepts it, so a slow or failing provider does not hold up
+// the run. Sends to the remaining endpoints finish in the background and are still
+// timed, which makes acceptance latency comparable across providers.
+type BroadcastSubmitter struct {
+	endpoints []BroadcastEndpoint
+	mu        sync.Mutex
+	records   []endpointRecord
+}
+
+// NewBroadcastSubmitter creates a submitter that broadcasts to every endpoint
+func NewBroadcastSubmitter(endpoints []BroadcastEndpoint) (*BroadcastSubmitter, error) {
+	if len(endpoints) == 0 {
+		return nil, fmt.Errorf("broadcast needs at least one endpoint")
+	}
+	return &BroadcastSubmitter{
+		endpoints: endpoints,
+		records:   make([]endpointRecord, len(endpoints)),
+	}, nil
+}
+
+// SendTransaction submits tx to every endpoint and returns nil once any accepts it,
+// or the last error when all of them reject it
+func (b *BroadcastSubmitter) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	errs := make(chan error, len(b.endpoints))
+	accepted := false
+	start := time.Now()
+	for i, endpoint := range b.endpoints {
+		go func(i int, endpoint BroadcastEndpoint) {
+			err := endpoint.Submitter.SendTransaction(ctx, tx)
+			latency := time.Since(start)
+			if IsAlreadyKnown(err) {
+				// Gossip from another endpoint got there first; the node has the transaction
+				err = nil
+			}
+
+			b.mu.Lock()
+			record := &b.records[i]
+			if err != nil {
+				record.rejected++
+				record.lastError = err.Error()
+			} else {
+				record.latencies = append(record.latencies, latency)
+				if !accepted {
+					accepted = true
+					record.wins++
+				}
+			}
+			b.mu.Unlock()
+			errs <- err
+		}(i, endpoint)
+	}
+
+	var lastErr error
+	for range b.endpoints {
+		err := <-errs
+		if err == nil {
+			return nil
+		}
+		lastErr = err
+	}
+	return fmt.Errorf("rejected by all %d endpoints: %w", len(b.endpoints), lastErr)
+}
+
+// Stats returns the acceptance counts and latencies of every endpoint so far
+func (b *BroadcastSubmitter) Stats() []EndpointStats {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	stats := make([]EndpointStats, len(b.endpoints))
+	for i, endpoint := range b.endpoints {
+		record := b.records[i]
+		latencies := append([]time.Duration(nil), record.latencies...)
+		sort.Slice(latencies, func(a, b int) bool { return latencies[a] < latencies[b] })
+		stats[i] = EndpointStats{
+			Name:       endpoint.Name,
+			Accepted:   len(latencies),
+			Rejected:   record.rejected,
+			Wins:       record.wins,
+			P50Latency: latencyPercentile(latencies, 50),
+			P95Latency: latencyPercentile(latencies, 95),
+			LastError:  record.lastError,
+		}
+	}
+	return stats
+}
+
+// latencyPercentile returns the p-th percentile of sorted latencies
+func latencyPercentile(sorted []time.Duration, p int) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	return sorted[(len(sorted)-1)*p/100]
+}
+
+// PrintBroadcastReport prints the acceptance latency of every broadcast endpoint
+func PrintBroadcastReport(stats []EndpointStats) {
+	fmt.Printf("\n=== Broadcast Endpoints ===\n")
+	for _, s := range stats {
+		fmt.Printf("%s\n  accepted: %d, rejected: %d, first to accept: %d, p50 latency: %s, p95 latency: %s\n",
+			s.Name, s.Accepted, s.Rejected, s.Wins,
+			s.P50Latency.Round(time.Millisecond), s.P95Latency.Round(time.Millisecond))
+		if s.LastError != "" {
+			fmt.Printf("  last error: %s\n", s.LastError)
+		}
+	}
+	fmt.Printf("==========================\n")
+}
+
+// Close closes every endpoint that holds a connection
+func (b *BroadcastSubmitter) Close() {
+	for _, endpoint := range b.endpoints {
+		if closer, ok := endpoint.Submitter.(interface{ Close() }); ok {
+			closer.Close()
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package transaction
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// fakeSubmitter answers after delay with err
+type fakeSubmitter struct {
+	delay time.Duration
+	err   error
+}
+
+func (f *fakeSubmitter) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	time.Sleep(f.delay)
+	return f.err
+}
+
+func TestBroadcastSubmitter(t *testing.T) {
+	t.Run("FirstAcceptWins", func(t *testing.T) {
+		b, err := NewBroadcastSubmitter([]BroadcastEndpoint{
+			{Name: "slow", Submitter: &fakeSubmitter{delay: 200 * time.Millisecond}},
+			{Name: "fast", Submitter: &fakeSubmitter{}},
+			{Name: "failing", Submitter: &fakeSubmitter{err: errors.New("boom")}},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		start := time.Now()
+		if err := b.SendTransaction(context.Background(), nil); err != nil {
+			t.Fatalf("expected the send to be accepted, got %v", err)
+		}
+		if time.Since(start) >= 200*time.Millisecond {
+			t.Error("SendTransaction should not wait for the slow endpoint")
+		}
+
+		time.Sleep(300 * time.Millisecond)
+		stats := b.Stats()
+		if stats[0].Accepted != 1 || stats[0].Wins != 0 {
+			t.Errorf("slow endpoint: expected 1 accepted and no wins, got %+v", stats[0])
+		}
+		if stats[1].Accepted != 1 || stats[1].Wins != 1 {
+			t.Errorf("fast endpoint: expected 1 accepted and 1 win, got %+v", stats[1])
+		}
+		if stats[2].Rejected != 1 || stats[2].LastError != "boom" {
+			t.Errorf("failing endpoint: expected 1 rejection, got %+v", stats[2])
+		}
+	})
+
+	t.Run("AllRejected", func(t *testing.T) {
+		b, _ := NewBroadcastSubmitter([]BroadcastEndpoint{
+			{Name: "a", Submitter: &fakeSubmitter{err: errors.New("nonce too low")}},
+			{Name: "b", Submitter: &fakeSubmitter{err: errors.New("nonce too low")}},
+		})
+		err := b.SendTransaction(context.Background(), nil)
+		if !IsNonceTooLow(err) {
+			t.Errorf("expected the endpoint error to be wrapped, got %v", err)
+		}
+	})
+
+	t.Run("AlreadyKnownCountsAsAccepted", func(t *testing.T) {
+		b, _ := NewBroadcastSubmitter([]BroadcastEndpoint{
+			{Name: "a", Submitter: &fakeSubmitter{err: errors.New("already known")}},
+		})
+		if err := b.SendTransaction(context.Background(), nil); err != nil {
+			t.Errorf("expected already known to be accepted, got %v", err)
+		}
+	})
+}
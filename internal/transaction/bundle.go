@@ -0,0 +1,328 @@
+package transaction
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"math/rand"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// BundleClient talks to a Flashbots-compatible relay (eth_callBundle / eth_sendBundle)
+type BundleClient struct {
+	relayURL   string
+	authKey    *ecdsa.PrivateKey
+	httpClient *http.Client
+}
+
+// CallBundleResult is the relay's simulation result for a bundle
+type CallBundleResult struct {
+	BundleHash string `json:"bundleHash"`
+	Results    []struct {
+		TxHash string `json:"txHash"`
+		Error  string `json:"error,omitempty"`
+		Revert string `json:"revert,omitempty"`
+	} `json:"results"`
+}
+
+// NewBundleClient creates a relay client. authKey signs the X-Flashbots-Signature
+// header and only identifies the searcher; it does not need to hold funds.
+func NewBundleClient(relayURL string, authKey *ecdsa.PrivateKey) *BundleClient {
+	return &BundleClient{
+		relayURL:   relayURL,
+		authKey:    authKey,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// CallBundle simulates the bundle on top of stateBlock as if it were mined in blockNumber
+func (bc *BundleClient) CallBundle(ctx context.Context, txs []*types.Transaction, blockNumber, stateBlock uint64) (*CallBundleResult, error) {
+	rawTxs, err := encodeBundle(txs)
+	if err != nil {
+		return nil, err
+	}
+
+	params := map[string]interface{}{
+		"txs":              rawTxs,
+		"blockNumber":      hexutil.EncodeUint64(blockNumber),
+		"stateBlockNumber": hexutil.EncodeUint64(stateBlock),
+	}
+	var result CallBundleResult
+	if err := bc.call(ctx, "eth_callBundle", params, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// SendBundle submits the bundle for inclusion in blockNumber and returns the bundle hash
+func (bc *BundleClient) SendBundle(ctx context.Context, txs []*types.Transaction, blockNumber uint64) (string, error) {
+	rawTxs, err := encodeBundle(txs)
+	if err != nil {
+		return "", err
+	}
+
+	params := map[string]interface{}{
+		"txs":         rawTxs,
+		"blockNumber": hexutil.EncodeUint64(blockNumber),
+	}
+	var result struct {
+		BundleHash string `json:"bundleHash"`
+	}
+	if err := bc.call(ctx, "eth_sendBundle", params, &result); err != nil {
+		return "", err
+	}
+	return result.BundleHash, nil
+}
+
+// call performs a signed JSON-RPC request against the relay
+func (bc *BundleClient) call(ctx context.Context, method string, params interface{}, result interface{}) error {
+	body, err := json.Marshal(map[string]interface{}{
+		"jsonrpc": "2.0",
+		"id":      1,
+		"method":  method,
+		"params":  []interface{}{params},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode request: %w", err)
+	}
+
+	// X-Flashbots-Signature: <address>:<personal_sign(keccak256(body))>
+	digest := hexutil.Encode(crypto.Keccak256(body))
+	signature, err := crypto.Sign(accounts.TextHash([]byte(digest)), bc.authKey)
+	if err != nil {
+		return fmt.Errorf("failed to sign relay request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, bc.relayURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Flashbots-Signature", crypto.PubkeyToAddress(bc.authKey.PublicKey).Hex()+":"+hexutil.Encode(signature))
+
+	resp, err := bc.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("relay request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var response struct {
+		Result json.RawMessage `json:"result"`
+		Error  *struct {
+			Code    int    `json:"code"`
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return fmt.Errorf("failed to decode relay response (status %d): %w", resp.StatusCode, err)
+	}
+	if response.Error != nil {
+		return fmt.Errorf("%s: %s", method, response.Error.Message)
+	}
+	return json.Unmarshal(response.Result, result)
+}
+
+// encodeBundle returns the raw hex encoding of each transaction
+func encodeBundle(txs []*types.Transaction) ([]string, error) {
+	rawTxs := make([]string, len(txs))
+	for i, tx := range txs {
+		raw, err := tx.MarshalBinary()
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode transaction: %w", err)
+		}
+		rawTxs[i] = hexutil.Encode(raw)
+	}
+	return rawTxs, nil
+}
+
+// maxSimulationFailures is the number of consecutive bundles failing simulation after
+// which SendBundles gives up, as the failure is unlikely to clear by itself
+const maxSimulationFailures = 10
+
+// BundleSender groups signed transactions into bundles targeted at the next block
+type BundleSender struct {
+	client     *ethclient.Client
+	relay      *BundleClient
+	privateKey *ecdsa.PrivateKey
+	chainID    *big.Int
+	config     *BundleConfig
+}
+
+// BundleConfig holds configuration for bundle submission
+type BundleConfig struct {
+	RandomAddresses []common.Address
+	Value           *big.Int
+	GasLimit        uint64
+	Data            []byte
+	BundleSize      int            // Transactions per bundle
+	MaxTransactions int            // Total transactions to submit across all bundles (0 = unlimited)
+	Policy          *AddressPolicy // Recipients allowed besides the sender, nil allows any
+}
+
+// NewBundleSender creates a new bundle sender
+func NewBundleSender(client *ethclient.Client, relay *BundleClient, privateKeyHex string, chainID *big.Int, config *BundleConfig) (*BundleSender, error) {
+	privateKey, err := crypto.HexToECDSA(strings.TrimPrefix(privateKeyHex, "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse private key: %w", err)
+	}
+	if config.BundleSize <= 0 {
+		config.BundleSize = 5
+	}
+
+	return &BundleSender{
+		client:     client,
+		relay:      relay,
+		privateKey: privateKey,
+		chainID:    chainID,
+		config:     config,
+	}, nil
+}
+
+// SendBundles simulates and submits bundles until MaxTransactions have been bundled
+func (bs *BundleSender) SendBundles(ctx context.Context) error {
+	rng := rand.New(rand.NewSource(time.Now().UnixNano()))
+	fromAddress := crypto.PubkeyToAddress(bs.privateKey.PublicKey)
+	bundles := (bs.config.MaxTransactions + bs.config.BundleSize - 1) / bs.config.BundleSize
+	simFailures := 0
+	consecutiveFailures := 0
+	submitted := 0
+
+	for i := 0; WithinLimit(i, bundles); i++ {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+
+		head, err := bs.client.BlockNumber(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get block number: %w", err)
+		}
+
+		// Bundles that were not included leave the nonce unchanged, so always
+		// start from the mined nonce rather than a local counter
+		nonce, err := bs.client.NonceAt(ctx, fromAddress, nil)
+		if err != nil {
+			return fmt.Errorf("failed to get nonce: %w", err)
+		}
+
+		gasPrice, err := bs.client.SuggestGasPrice(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get gas price: %w", err)
+		}
+
+		size := bs.bundleSize(i)
+		txs := make([]*types.Transaction, 0, size)
+		for j := 0; j < size; j++ {
+			recipient := bs.config.RandomAddresses[rng.Intn(len(bs.config.RandomAddresses))]
+			if err := bs.checkRecipient(fromAddress, recipient); err != nil {
+				return err
+			}
+			tx := types.NewTransaction(nonce+uint64(j), recipient, bs.config.Value, bs.config.GasLimit, gasPrice, bs.config.Data)
+			signedTx, err := SignTx(tx, bs.chainID, bs.privateKey)
+			if err != nil {
+				return fmt.Errorf("failed to sign transaction: %w", err)
+			}
+			txs = append(txs, signedTx)
+		}
+
+		target := head + 1
+		sim, err := bs.relay.CallBundle(ctx, txs, target, head)
+		if err != nil {
+			return fmt.Errorf("bundle simulation failed: %w", err)
+		}
+		if failed := bundleSimulationError(sim); failed != "" {
+			simFailures++
+			consecutiveFailures++
+			fmt.Printf("Bundle %s failed simulation: %s\n", FormatProgress(i, bundles), failed)
+			if consecutiveFailures >= maxSimulationFailures {
+				return fmt.Errorf("%d bundles in a row failed simulation, last: %s", consecutiveFailures, failed)
+			}
+			// Retry on the next block's state rather than hammering the relay
+			if err := bs.waitForBlock(ctx, target); err != nil {
+				return err
+			}
+			continue
+		}
+		consecutiveFailures = 0
+
+		bundleHash, err := bs.relay.SendBundle(ctx, txs, target)
+		if err != nil {
+			return fmt.Errorf("failed to send bundle: %w", err)
+		}
+		submitted++
+		fmt.Printf("Bundle %s (%d txs) targeting block %d: %s\n", FormatProgress(i, bundles), len(txs), target, bundleHash)
+
+		// The next bundle reuses the nonces of this one unless it was included, so it
+		// can only be built once the target block is out
+		if WithinLimit(i+1, bundles) {
+			if err := bs.waitForBlock(ctx, target); err != nil {
+				return err
+			}
+		}
+	}
+
+	fmt.Printf("Bundles submitted: %d, failed simulation: %d\n", submitted, simFailures)
+	return nil
+}
+
+// checkRecipient applies the address policy to a bundle transaction from from to to,
+// as PolicySubmitter does for transactions sent through the node: the relay bypasses it
+func (bs *BundleSender) checkRecipient(from, to common.Address) error {
+	policy := bs.config.Policy
+	if policy == nil || policy.Allowed(to) || (to == from && !policy.Denied(to)) {
+		return nil
+	}
+	return fmt.Errorf("%w: %s", ErrAddressNotAllowed, to.Hex())
+}
+
+// bundleSize returns the number of transactions in the 0-based bundle i: BundleSize,
+// except for a last bundle holding the rest of MaxTransactions
+func (bs *BundleSender) bundleSize(i int) int {
+	if bs.config.MaxTransactions == Unlimited {
+		return bs.config.BundleSize
+	}
+	if rest := bs.config.MaxTransactions - i*bs.config.BundleSize; rest < bs.config.BundleSize {
+		return rest
+	}
+	return bs.config.BundleSize
+}
+
+// waitForBlock waits until the chain head reaches number
+func (bs *BundleSender) waitForBlock(ctx context.Context, number uint64) error {
+	ticker := time.NewTicker(500 * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		head, err := bs.client.BlockNumber(ctx)
+		if err == nil && head >= number {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// bundleSimulationError returns the first transaction error in a simulation result
+func bundleSimulationError(result *CallBundleResult) string {
+	for _, r := range result.Results {
+		if r.Error != "" {
+			return fmt.Sprintf("tx %s: %s", r.TxHash, r.Error)
+		}
+	}
+	return ""
+}
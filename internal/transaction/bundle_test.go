@@ -0,0 +1,106 @@
+package transaction
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+func TestBundleSize(t *testing.T) {
+	t.Run("LastBundleHoldsTheRest", func(t *testing.T) {
+		bs := &BundleSender{config: &BundleConfig{BundleSize: 5, MaxTransactions: 12}}
+		var sizes []int
+		for i := 0; WithinLimit(i, 3); i++ {
+			sizes = append(sizes, bs.bundleSize(i))
+		}
+		if len(sizes) != 3 || sizes[0] != 5 || sizes[1] != 5 || sizes[2] != 2 {
+			t.Errorf("expected bundles of 5, 5 and 2, got %v", sizes)
+		}
+	})
+
+	t.Run("Unlimited", func(t *testing.T) {
+		bs := &BundleSender{config: &BundleConfig{BundleSize: 5}}
+		if size := bs.bundleSize(100); size != 5 {
+			t.Errorf("expected full bundles without a cap, got %d", size)
+		}
+	})
+}
+
+// advancingEth is a fakeEth whose head moves one block on every eth_blockNumber
+type advancingEth struct {
+	*fakeEth
+	head uint64
+}
+
+func (f *advancingEth) BlockNumber() hexutil.Uint64 {
+	return hexutil.Uint64(atomic.AddUint64(&f.head, 1))
+}
+
+func TestSendBundlesSimulationFailures(t *testing.T) {
+	var simulations int64
+	relay := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt64(&simulations, 1)
+		w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":{"results":[{"txHash":"0x01","error":"execution reverted"}]}}`))
+	}))
+	defer relay.Close()
+
+	server := rpc.NewServer()
+	if err := server.RegisterName("eth", &advancingEth{fakeEth: &fakeEth{}}); err != nil {
+		t.Fatal(err)
+	}
+	defer server.Stop()
+	client := ethclient.NewClient(rpc.DialInProc(server))
+	defer client.Close()
+
+	key, _ := crypto.GenerateKey()
+	bs, err := NewBundleSender(client, NewBundleClient(relay.URL, key), hexutil.Encode(crypto.FromECDSA(key)), big.NewInt(1337), &BundleConfig{
+		RandomAddresses: []common.Address{common.HexToAddress("0x02")},
+		Value:           big.NewInt(0),
+		GasLimit:        21000,
+		MaxTransactions: 1000,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = bs.SendBundles(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "failed simulation") {
+		t.Errorf("expected the run to stop on repeated simulation failures, got %v", err)
+	}
+	if got := atomic.LoadInt64(&simulations); got != maxSimulationFailures {
+		t.Errorf("expected %d simulations, got %d", maxSimulationFailures, got)
+	}
+}
+
+func TestBundleRecipientPolicy(t *testing.T) {
+	from := common.HexToAddress("0x01")
+	allowed := common.HexToAddress("0x02")
+	denied := common.HexToAddress("0x03")
+	bs := &BundleSender{config: &BundleConfig{Policy: NewAddressPolicy([]common.Address{allowed}, []common.Address{denied})}}
+
+	if err := bs.checkRecipient(from, allowed); err != nil {
+		t.Errorf("expected an allowlisted recipient to pass, got %v", err)
+	}
+	if err := bs.checkRecipient(from, from); err != nil {
+		t.Errorf("expected the sender itself to pass, got %v", err)
+	}
+	if err := bs.checkRecipient(from, denied); !errors.Is(err, ErrAddressNotAllowed) {
+		t.Errorf("expected a denied recipient to be refused, got %v", err)
+	}
+	if err := bs.checkRecipient(from, common.HexToAddress("0x04")); !errors.Is(err, ErrAddressNotAllowed) {
+		t.Errorf("expected a recipient off the allowlist to be refused, got %v", err)
+	}
+	if err := (&BundleSender{config: &BundleConfig{}}).checkRecipient(from, denied); err != nil {
+		t.Errorf("expected no policy to allow any recipient, got %v", err)
+	}
+}
@@ -0,0 +1,135 @@
+package transaction
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"math/rand"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// ErrChaosDropped is returned for sends the fault injector drops before they reach
+// the node, standing in for a lost request
+var ErrChaosDropped = errors.New("chaos: send dropped before reaching the node")
+
+// ChaosConfig injects faults into the simulator's own send pipeline, to exercise
+// the node's rejection paths and our retry logic under controlled conditions
+type ChaosConfig struct {
+	DropPercent    float64       // Share of sends failed with ErrChaosDropped instead of being sent
+	CorruptPercent float64       // Share of sends whose signature is replaced by an invalid one
+	SignDelay      time.Duration // Signing is delayed by a random time up to this, 0 disables
+}
+
+// ChaosReport counts the faults injected during a run
+type ChaosReport struct {
+	Sends     int64 // Sends that passed through the injector
+	Dropped   int64
+	Corrupted int64
+	Delayed   int64 // Signings that were delayed
+}
+
+// chaos injects the faults of a ChaosConfig and counts them
+type chaos struct {
+	config    *ChaosConfig
+	chainID   *big.Int
+	mu        sync.Mutex
+	rng       *rand.Rand
+	sends     int64
+	dropped   int64
+	corrupted int64
+	delayed   int64
+}
+
+// newChaos creates a fault injector for transactions signed for chainID
+func newChaos(config *ChaosConfig, chainID *big.Int) *chaos {
+	return &chaos{config: config, chainID: chainID, rng: rand.New(rand.NewSource(time.Now().UnixNano()))}
+}
+
+// roll reports whether an event with the given percent chance happens
+func (c *chaos) roll(percent float64) bool {
+	if percent <= 0 {
+		return false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.rng.Float64()*100 < percent
+}
+
+// delaySigning waits a random time up to SignDelay, or until ctx is cancelled
+func (c *chaos) delaySigning(ctx context.Context) {
+	if c.config.SignDelay <= 0 {
+		return
+	}
+	c.mu.Lock()
+	delay := time.Duration(c.rng.Int63n(int64(c.config.SignDelay) + 1))
+	c.mu.Unlock()
+	atomic.AddInt64(&c.delayed, 1)
+	select {
+	case <-ctx.Done():
+	case <-time.After(delay):
+	}
+}
+
+// corrupt returns tx with its signature replaced by r = s = 0, which no sender
+// recovers from, so the node must refuse it
+func (c *chaos) corrupt(tx *types.Transaction) (*types.Transaction, error) {
+	return tx.WithSignature(SignerFor(c.chainID), make([]byte, 65))
+}
+
+// report returns the faults injected so far
+func (c *chaos) report() *ChaosReport {
+	return &ChaosReport{
+		Sends:     atomic.LoadInt64(&c.sends),
+		Dropped:   atomic.LoadInt64(&c.dropped),
+		Corrupted: atomic.LoadInt64(&c.corrupted),
+		Delayed:   atomic.LoadInt64(&c.delayed),
+	}
+}
+
+// chaosSubmitter drops and corrupts sends before passing them to next
+type chaosSubmitter struct {
+	next  Submitter
+	chaos *chaos
+}
+
+// SendTransaction drops tx, sends it with a corrupted signature, or sends it as is
+func (s *chaosSubmitter) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	atomic.AddInt64(&s.chaos.sends, 1)
+	if s.chaos.roll(s.chaos.config.DropPercent) {
+		atomic.AddInt64(&s.chaos.dropped, 1)
+		return ErrChaosDropped
+	}
+	if s.chaos.roll(s.chaos.config.CorruptPercent) {
+		corrupted, err := s.chaos.corrupt(tx)
+		if err != nil {
+			return fmt.Errorf("chaos: failed to corrupt signature: %w", err)
+		}
+		atomic.AddInt64(&s.chaos.corrupted, 1)
+		tx = corrupted
+	}
+	return s.next.SendTransaction(ctx, tx)
+}
+
+// PrintChaosReport prints the faults injected into the run, to compare with the
+// rejections and failures in the transaction summary
+func PrintChaosReport(r *ChaosReport) {
+	fmt.Printf("\n=== Injected Faults ===\n")
+	fmt.Printf("Sends through the injector: %d\n", r.Sends)
+	share := func(n int64) float64 {
+		if r.Sends == 0 {
+			return 0
+		}
+		return float64(n) * 100 / float64(r.Sends)
+	}
+	fmt.Printf("Dropped: %d (%.2f%%)\n", r.Dropped, share(r.Dropped))
+	fmt.Printf("Corrupted signatures: %d (%.2f%%)\n", r.Corrupted, share(r.Corrupted))
+	if r.Delayed > 0 {
+		fmt.Printf("Delayed signings: %d\n", r.Delayed)
+	}
+	fmt.Printf("==========================\n")
+}
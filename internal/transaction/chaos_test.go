@@ -0,0 +1,142 @@
+package transaction
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// senderSubmitter recovers the sender of every transaction it is given
+type senderSubmitter struct {
+	chainID *big.Int
+	errs    []error
+}
+
+func (s *senderSubmitter) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	_, err := types.Sender(SignerFor(s.chainID), tx)
+	s.errs = append(s.errs, err)
+	return nil
+}
+
+func TestChaos(t *testing.T) {
+	chainID := big.NewInt(1337)
+	key, _ := crypto.GenerateKey()
+	tx, err := SignTx(types.NewTransaction(0, common.Address{0x01}, big.NewInt(0), 21000, big.NewInt(1), nil), chainID, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("Drops", func(t *testing.T) {
+		next := &senderSubmitter{chainID: chainID}
+		c := newChaos(&ChaosConfig{DropPercent: 100}, chainID)
+		if err := (&chaosSubmitter{next: next, chaos: c}).SendTransaction(context.Background(), tx); !errors.Is(err, ErrChaosDropped) {
+			t.Errorf("expected ErrChaosDropped, got %v", err)
+		}
+		if len(next.errs) != 0 {
+			t.Error("expected a dropped send not to reach the submitter")
+		}
+		if r := c.report(); r.Sends != 1 || r.Dropped != 1 {
+			t.Errorf("unexpected report: %+v", r)
+		}
+	})
+
+	t.Run("CorruptsSignatures", func(t *testing.T) {
+		next := &senderSubmitter{chainID: chainID}
+		c := newChaos(&ChaosConfig{CorruptPercent: 100}, chainID)
+		if err := (&chaosSubmitter{next: next, chaos: c}).SendTransaction(context.Background(), tx); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(next.errs) != 1 || next.errs[0] == nil {
+			t.Errorf("expected the sender of a corrupted transaction not to be recoverable, got %v", next.errs)
+		}
+		if c.report().Corrupted != 1 {
+			t.Errorf("unexpected report: %+v", c.report())
+		}
+	})
+
+	t.Run("PassesThrough", func(t *testing.T) {
+		next := &senderSubmitter{chainID: chainID}
+		c := newChaos(&ChaosConfig{SignDelay: time.Millisecond}, chainID)
+		c.delaySigning(context.Background())
+		if err := (&chaosSubmitter{next: next, chaos: c}).SendTransaction(context.Background(), tx); err != nil || len(next.errs) != 1 || next.errs[0] != nil {
+			t.Errorf("expected the transaction to pass unchanged, got %v, %v", err, next.errs)
+		}
+		if r := c.report(); r.Delayed != 1 || r.Dropped != 0 || r.Corrupted != 0 {
+			t.Errorf("unexpected report: %+v", r)
+		}
+	})
+}
+
+// scriptedSubmitter answers the i-th send with errs[i], accepting once they run out,
+// and records the nonce of every send
+type scriptedSubmitter struct {
+	errs   []error
+	nonces []uint64
+}
+
+func (s *scriptedSubmitter) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	s.nonces = append(s.nonces, tx.Nonce())
+	if len(s.nonces) <= len(s.errs) {
+		return s.errs[len(s.nonces)-1]
+	}
+	return nil
+}
+
+func TestRetryKeepsNonce(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	newSender := func(t *testing.T, eth *fakeEth, submitter Submitter) (*ParallelSender, *ParallelWallet) {
+		client := newFakeClient(t, eth)
+		w := &ParallelWallet{PrivateKey: key, Address: crypto.PubkeyToAddress(key.PublicKey), NonceManager: NewNonceManager(client, crypto.PubkeyToAddress(key.PublicKey))}
+		ps := NewParallelSender(client, big.NewInt(1337), []*ParallelWallet{w}, []common.Address{{0x02}}, &ParallelConfig{
+			Value:      big.NewInt(0),
+			GasLimit:   21000,
+			RetryDelay: time.Millisecond,
+		})
+		ps.SetSubmitter(submitter)
+		return ps, w
+	}
+	rng := rand.New(rand.NewSource(1))
+
+	t.Run("InjectedFaults", func(t *testing.T) {
+		submitter := &scriptedSubmitter{errs: []error{ErrChaosDropped, errors.New("invalid sender"), errors.New("txpool is full")}}
+		ps, w := newSender(t, &fakeEth{pending: 5}, submitter)
+		ps.sendTransactionWithRetry(context.Background(), w, rng)
+		if len(submitter.nonces) != 4 {
+			t.Fatalf("expected 4 sends, got %d", len(submitter.nonces))
+		}
+		for i, nonce := range submitter.nonces {
+			if nonce != 5 {
+				t.Errorf("send %d: expected nonce 5 to be reused, got %d", i, nonce)
+			}
+		}
+		if ps.totalSent != 1 || w.NonceManager.currentNonce != 6 {
+			t.Errorf("expected 1 sent and next nonce 6, got %d and %d", ps.totalSent, w.NonceManager.currentNonce)
+		}
+	})
+
+	t.Run("NonceConflictTakesFreshNonce", func(t *testing.T) {
+		eth := &fakeEth{pending: 5}
+		submitter := &scriptedSubmitter{errs: []error{errors.New("replacement transaction underpriced")}}
+		ps, w := newSender(t, eth, submitter)
+		ps.sendTransactionWithRetry(context.Background(), w, rng)
+		if len(submitter.nonces) != 2 || submitter.nonces[0] != 5 || submitter.nonces[1] != 6 {
+			t.Errorf("expected nonces 5 then 6, got %v", submitter.nonces)
+		}
+	})
+
+	t.Run("GivingUpHandsBackNonce", func(t *testing.T) {
+		submitter := &scriptedSubmitter{errs: []error{ErrChaosDropped, ErrChaosDropped, ErrChaosDropped, ErrChaosDropped}}
+		ps, w := newSender(t, &fakeEth{pending: 5}, submitter)
+		ps.sendTransactionWithRetry(context.Background(), w, rng)
+		if ps.totalFailed != 1 || w.NonceManager.currentNonce != 5 {
+			t.Errorf("expected 1 failure and nonce 5 handed back, got %d and %d", ps.totalFailed, w.NonceManager.currentNonce)
+		}
+	})
+}
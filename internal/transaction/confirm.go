@@ -0,0 +1,147 @@
+package transaction
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// Confirmation is the point at which a sent transaction counts as successful
+type Confirmation string
+
+const (
+	ConfirmNone      Confirmation = "none"      // Accepted by the RPC endpoint
+	ConfirmMempool   Confirmation = "mempool"   // Returned by eth_getTransactionByHash, pending or mined
+	ConfirmMined     Confirmation = "mined"     // A receipt exists
+	ConfirmBlocks    Confirmation = "blocks"    // Mined and buried under Blocks further blocks
+	ConfirmFinalized Confirmation = "finalized" // Mined in a block at or below the "finalized" block
+)
+
+// ParseConfirmation parses a confirmation level name
+func ParseConfirmation(s string) (Confirmation, error) {
+	switch c := Confirmation(strings.ToLower(strings.TrimSpace(s))); c {
+	case ConfirmNone, ConfirmMempool, ConfirmMined, ConfirmBlocks, ConfirmFinalized:
+		return c, nil
+	}
+	return "", fmt.Errorf("unknown confirmation level %q (expected none, mempool, mined, blocks, or finalized)", s)
+}
+
+// ErrReverted is returned by ConfirmationStrategy.Wait for a transaction that was
+// mined but reverted, which no confirmation level counts as successful
+var ErrReverted = errors.New("transaction reverted")
+
+// ConfirmationStrategy decides when a sent transaction counts as successful
+type ConfirmationStrategy struct {
+	Level        Confirmation
+	Blocks       uint64        // Confirmations required by ConfirmBlocks
+	Timeout      time.Duration // Give up waiting after this long (0 = until ctx is cancelled)
+	PollInterval time.Duration // Defaults to 500ms
+}
+
+// DefaultConfirmation counts a transaction once the node returns it, pending or mined
+var DefaultConfirmation = &ConfirmationStrategy{Level: ConfirmMempool, Timeout: 30 * time.Second}
+
+// defaultVerifyTimeout bounds the wait of a strategy without a timeout when the
+// parallel sender verifies a transaction, so the run's end is not held up forever
+const defaultVerifyTimeout = 2 * time.Minute
+
+// String describes the strategy, e.g. "mined+6 blocks"
+func (c *ConfirmationStrategy) String() string {
+	if c.Level == ConfirmBlocks {
+		return fmt.Sprintf("mined+%d blocks", c.Blocks)
+	}
+	return string(c.Level)
+}
+
+// Wait blocks until txHash reaches the strategy's confirmation level, returning an
+// error when the timeout passes first, or ErrReverted as soon as a receipt shows it
+// failed. A transaction that was mined and then reorged out is waited for again.
+func (c *ConfirmationStrategy) Wait(ctx context.Context, client *ethclient.Client, txHash common.Hash) error {
+	if c.Level == ConfirmNone {
+		return nil
+	}
+	if c.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.Timeout)
+		defer cancel()
+	}
+	interval := c.PollInterval
+	if interval <= 0 {
+		interval = 500 * time.Millisecond
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		done, err := c.reached(ctx, client, txHash)
+		if done {
+			return nil
+		}
+		if errors.Is(err, ErrReverted) {
+			return fmt.Errorf("transaction %s not %s: %w", txHash.Hex(), c, err)
+		}
+		select {
+		case <-ctx.Done():
+			if err != nil {
+				return fmt.Errorf("transaction %s not %s: %w", txHash.Hex(), c, err)
+			}
+			return fmt.Errorf("transaction %s not %s: %w", txHash.Hex(), c, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// reached reports whether txHash has reached the confirmation level; the error is the
+// last RPC failure, kept for the timeout message, or ErrReverted. Levels from mined
+// up need a successful receipt.
+func (c *ConfirmationStrategy) reached(ctx context.Context, client *ethclient.Client, txHash common.Hash) (bool, error) {
+	if c.Level == ConfirmMempool {
+		tx, _, err := client.TransactionByHash(ctx, txHash)
+		return err == nil && tx != nil, err
+	}
+
+	receipt, err := client.TransactionReceipt(ctx, txHash)
+	if err != nil || receipt == nil {
+		return false, err
+	}
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		return false, fmt.Errorf("%w in block %d", ErrReverted, receipt.BlockNumber)
+	}
+	switch c.Level {
+	case ConfirmBlocks:
+		head, err := client.BlockNumber(ctx)
+		if err != nil {
+			return false, err
+		}
+		return head >= receipt.BlockNumber.Uint64()+c.Blocks, nil
+	case ConfirmFinalized:
+		finalized, err := client.HeaderByNumber(ctx, big.NewInt(int64(rpc.FinalizedBlockNumber)))
+		if err != nil {
+			return false, err
+		}
+		if finalized.Number.Cmp(receipt.BlockNumber) < 0 {
+			return false, nil
+		}
+		return c.stillCanonical(ctx, client, receipt)
+	default:
+		return true, nil
+	}
+}
+
+// stillCanonical checks that the receipt's block is the canonical block at its height,
+// so a receipt fetched just before a reorg is not taken as final
+func (c *ConfirmationStrategy) stillCanonical(ctx context.Context, client *ethclient.Client, receipt *types.Receipt) (bool, error) {
+	header, err := client.HeaderByNumber(ctx, receipt.BlockNumber)
+	if err != nil {
+		return false, err
+	}
+	return header.Hash() == receipt.BlockHash, nil
+}
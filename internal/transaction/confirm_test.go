@@ -0,0 +1,61 @@
+package transaction
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+func TestParseConfirmation(t *testing.T) {
+	t.Run("Levels", func(t *testing.T) {
+		for _, name := range []string{"none", "mempool", "mined", "blocks", "finalized", " Finalized "} {
+			if _, err := ParseConfirmation(name); err != nil {
+				t.Errorf("%q should parse: %v", name, err)
+			}
+		}
+	})
+
+	t.Run("Unknown", func(t *testing.T) {
+		if _, err := ParseConfirmation("safe"); err == nil {
+			t.Error("unknown levels should be rejected")
+		}
+	})
+
+	t.Run("String", func(t *testing.T) {
+		strategy := &ConfirmationStrategy{Level: ConfirmBlocks, Blocks: 6}
+		if strategy.String() != "mined+6 blocks" {
+			t.Errorf("unexpected description: %s", strategy)
+		}
+	})
+}
+
+func TestConfirmationStatus(t *testing.T) {
+	receipt := func(status uint64) *types.Receipt {
+		return &types.Receipt{Status: status, TxHash: common.Hash{0x01}, BlockNumber: big.NewInt(3), Logs: []*types.Log{}}
+	}
+	strategy := &ConfirmationStrategy{Level: ConfirmMined, Timeout: time.Second, PollInterval: 10 * time.Millisecond}
+
+	t.Run("Successful", func(t *testing.T) {
+		client := newFakeClient(t, &fakeEth{receipt: receipt(types.ReceiptStatusSuccessful)})
+		if err := strategy.Wait(context.Background(), client, common.Hash{0x01}); err != nil {
+			t.Errorf("expected a successful receipt to confirm, got %v", err)
+		}
+	})
+
+	t.Run("Reverted", func(t *testing.T) {
+		client := newFakeClient(t, &fakeEth{receipt: receipt(types.ReceiptStatusFailed)})
+		start := time.Now()
+		err := strategy.Wait(context.Background(), client, common.Hash{0x01})
+		if !errors.Is(err, ErrReverted) {
+			t.Errorf("expected ErrReverted, got %v", err)
+		}
+		if time.Since(start) >= strategy.Timeout {
+			t.Error("a reverted transaction should not be waited on until the timeout")
+		}
+	})
+}
@@ -0,0 +1,102 @@
+package transaction
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// DropReport counts the transactions that were accepted but not mined within the
+// drop timeout, by sending wallet and by gas price
+type DropReport struct {
+	Dropped    int
+	Verified   int         // Dropped after the mempool check had counted them as succeeded
+	ByWallet   []DropCount // Most dropped first
+	ByGasPrice []DropCount // Lowest gas price first
+}
+
+// DropCount is the number of dropped transactions in one group
+type DropCount struct {
+	Key   string // Wallet address, or gas price in gwei
+	Count int
+}
+
+// dropCounter accumulates dropped transactions. Its caller holds the tracker lock.
+type dropCounter struct {
+	dropped   int
+	verified  int
+	perWallet map[string]int
+	perPrice  map[float64]int // Keyed by gwei
+}
+
+// add counts one dropped transaction
+func (d *dropCounter) add(tracked *TrackedTx) {
+	if d.perWallet == nil {
+		d.perWallet, d.perPrice = make(map[string]int), make(map[float64]int)
+	}
+	d.dropped++
+	if tracked.verified {
+		d.verified++
+	}
+	d.perWallet[tracked.From.Hex()]++
+	gwei := 0.0
+	if tracked.GasPrice != nil {
+		gwei, _ = new(big.Float).Quo(new(big.Float).SetInt(tracked.GasPrice), big.NewFloat(params.GWei)).Float64()
+	}
+	d.perPrice[gwei]++
+}
+
+// report returns the counts with the groups sorted
+func (d *dropCounter) report() *DropReport {
+	r := &DropReport{Dropped: d.dropped, Verified: d.verified}
+	for address, count := range d.perWallet {
+		r.ByWallet = append(r.ByWallet, DropCount{Key: address, Count: count})
+	}
+	sort.Slice(r.ByWallet, func(i, j int) bool {
+		if r.ByWallet[i].Count != r.ByWallet[j].Count {
+			return r.ByWallet[i].Count > r.ByWallet[j].Count
+		}
+		return r.ByWallet[i].Key < r.ByWallet[j].Key
+	})
+	prices := make([]float64, 0, len(d.perPrice))
+	for gwei := range d.perPrice {
+		prices = append(prices, gwei)
+	}
+	sort.Float64s(prices)
+	for _, gwei := range prices {
+		r.ByGasPrice = append(r.ByGasPrice, DropCount{Key: fmt.Sprintf("%.2f gwei", gwei), Count: d.perPrice[gwei]})
+	}
+	return r
+}
+
+// PrintDropReport prints the transactions that were never mined within the timeout
+func PrintDropReport(r *DropReport, sent int64) {
+	fmt.Printf("\n=== Dropped Transactions ===\n")
+	share := 0.0
+	if sent > 0 {
+		share = float64(r.Dropped) * 100 / float64(sent)
+	}
+	fmt.Printf("Dropped: %d of %d sent (%.2f%%)\n", r.Dropped, sent, share)
+	if r.Dropped == 0 {
+		fmt.Printf("==========================\n")
+		return
+	}
+	if r.Verified > 0 {
+		fmt.Printf("Seen in the mempool first, no longer counted as succeeded: %d\n", r.Verified)
+	}
+	fmt.Printf("By gas price:\n")
+	for _, c := range r.ByGasPrice {
+		fmt.Printf("  %s: %d\n", c.Key, c.Count)
+	}
+	fmt.Printf("By wallet (%d wallets):\n", len(r.ByWallet))
+	for i, c := range r.ByWallet {
+		if i == 10 {
+			fmt.Printf("  ... %d more\n", len(r.ByWallet)-10)
+			break
+		}
+		fmt.Printf("  %s: %d\n", c.Key, c.Count)
+	}
+	fmt.Printf("==========================\n")
+}
@@ -0,0 +1,50 @@
+package transaction
+
+import "strings"
+
+// Send errors returned by geth-compatible nodes. They arrive as JSON-RPC error
+// strings, so they are matched by message rather than with errors.Is.
+const (
+	errMsgNonceTooLow            = "nonce too low"
+	errMsgReplacementUnderpriced = "replacement transaction underpriced"
+	errMsgAlreadyKnown           = "already known"
+)
+
+// poolPressureMessages are the refusals of a node whose pool, or the sender's
+// share of it, is full. Retrying later with the same nonce can succeed.
+var poolPressureMessages = []string{
+	"txpool is full",
+	"account limit exceeded",
+	"transaction underpriced", // geth's answer when the pool is full and the tx is among the cheapest
+	"txpoolfull",              // Nethermind
+}
+
+// IsNonceTooLow reports whether the node rejected a transaction because its nonce was already used
+func IsNonceTooLow(err error) bool {
+	return err != nil && strings.Contains(strings.ToLower(err.Error()), errMsgNonceTooLow)
+}
+
+// IsReplacementUnderpriced reports whether another transaction already occupies the nonce in the pool
+func IsReplacementUnderpriced(err error) bool {
+	return err != nil && strings.Contains(strings.ToLower(err.Error()), errMsgReplacementUnderpriced)
+}
+
+// IsAlreadyKnown reports whether the node already has this exact transaction
+func IsAlreadyKnown(err error) bool {
+	return err != nil && strings.Contains(strings.ToLower(err.Error()), errMsgAlreadyKnown)
+}
+
+// IsPoolPressure reports whether the node refused a transaction because its pool
+// is full or the sender holds too many slots. Replacement refusals are not included.
+func IsPoolPressure(err error) bool {
+	if err == nil || IsReplacementUnderpriced(err) {
+		return false
+	}
+	msg := strings.ToLower(err.Error())
+	for _, pressure := range poolPressureMessages {
+		if strings.Contains(msg, pressure) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,157 @@
+package transaction
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// maxAssertionSamples bounds how many assertion failures are kept for the summary
+const maxAssertionSamples = 10
+
+// expectedEvent is an event a contract call must emit
+type expectedEvent struct {
+	signature string
+	topic     common.Hash
+}
+
+// EventAssertions checks that contract calls emit the events declared for them, so
+// interaction runs verify correctness and not just delivery
+type EventAssertions struct {
+	expected map[[4]byte][]expectedEvent // Keyed by function selector
+
+	checked int64
+	failed  int64
+	samples []string
+	mu      sync.Mutex
+}
+
+// NewEventAssertions parses declarations of the form
+// "set(uint256)=ValueSet(uint256);transfer(address,uint256)=Transfer(address,address,uint256)".
+// Several events for one call are joined with "+".
+func NewEventAssertions(spec string) (*EventAssertions, error) {
+	a := &EventAssertions{expected: make(map[[4]byte][]expectedEvent)}
+	for _, entry := range strings.Split(spec, ";") {
+		entry = strings.ReplaceAll(entry, " ", "")
+		if entry == "" {
+			continue
+		}
+		call, events, ok := strings.Cut(entry, "=")
+		if !ok || !isSignature(call) || events == "" {
+			return nil, fmt.Errorf("invalid event assertion %q: expected call(args)=Event(args)", entry)
+		}
+
+		var selector [4]byte
+		copy(selector[:], crypto.Keccak256([]byte(call))[:4])
+		for _, event := range strings.Split(events, "+") {
+			if !isSignature(event) {
+				return nil, fmt.Errorf("invalid event signature %q in %q", event, entry)
+			}
+			a.expected[selector] = append(a.expected[selector], expectedEvent{
+				signature: event,
+				topic:     crypto.Keccak256Hash([]byte(event)),
+			})
+		}
+	}
+	if len(a.expected) == 0 {
+		return nil, fmt.Errorf("no event assertions declared")
+	}
+	return a, nil
+}
+
+// isSignature reports whether s looks like name(types)
+func isSignature(s string) bool {
+	open := strings.Index(s, "(")
+	return open > 0 && strings.HasSuffix(s, ")")
+}
+
+// expects reports whether a call with this calldata has declared events
+func (a *EventAssertions) expects(data []byte) bool {
+	if len(data) < 4 {
+		return false
+	}
+	var selector [4]byte
+	copy(selector[:], data[:4])
+	_, ok := a.expected[selector]
+	return ok
+}
+
+// check asserts that the receipt of tx holds every event declared for its call
+func (a *EventAssertions) check(tx *types.Transaction, receipt *types.Receipt) {
+	atomic.AddInt64(&a.checked, 1)
+	if reason := a.violation(tx.Data(), *tx.To(), receipt); reason != "" {
+		a.fail(fmt.Sprintf("%s: %s", tx.Hash().Hex(), reason))
+	}
+}
+
+// violation describes why a receipt fails the assertions for a call with this calldata
+// to contract, or returns "" when every declared event was emitted by the contract
+func (a *EventAssertions) violation(data []byte, contract common.Address, receipt *types.Receipt) string {
+	if receipt.Status == types.ReceiptStatusFailed {
+		return "call reverted"
+	}
+	var selector [4]byte
+	copy(selector[:], data[:4])
+	for _, event := range a.expected[selector] {
+		if !hasEvent(receipt, contract, event.topic) {
+			return "missing " + event.signature
+		}
+	}
+	return ""
+}
+
+// hasEvent reports whether the receipt holds a log from contract with topic0 == topic
+func hasEvent(receipt *types.Receipt, contract common.Address, topic common.Hash) bool {
+	for _, l := range receipt.Logs {
+		if l.Address == contract && len(l.Topics) > 0 && l.Topics[0] == topic {
+			return true
+		}
+	}
+	return false
+}
+
+// fail counts an assertion failure and keeps the first few for the summary
+func (a *EventAssertions) fail(reason string) {
+	atomic.AddInt64(&a.failed, 1)
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if len(a.samples) < maxAssertionSamples {
+		a.samples = append(a.samples, reason)
+	}
+}
+
+// verify fetches the receipts of txs and checks their events
+func (a *EventAssertions) verify(ctx context.Context, t *Tracker, txs []*types.Transaction) {
+	var wg sync.WaitGroup
+	semaphore := make(chan struct{}, 50) // Limit concurrent operations
+	for _, tx := range txs {
+		wg.Add(1)
+		go func(tx *types.Transaction) {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			receipt, err := t.client.TransactionReceipt(ctx, tx.Hash())
+			if err != nil {
+				return // The transaction was included, so this is an RPC failure, not an assertion failure
+			}
+			a.check(tx, receipt)
+		}(tx)
+	}
+	wg.Wait()
+}
+
+// Results returns how many calls were checked, how many failed, and sample failures
+func (a *EventAssertions) Results() (checked, failed int64, samples []string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	samples = make([]string, len(a.samples))
+	copy(samples, a.samples)
+	return atomic.LoadInt64(&a.checked), atomic.LoadInt64(&a.failed), samples
+}
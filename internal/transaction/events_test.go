@@ -0,0 +1,67 @@
+package transaction
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func TestEventAssertions(t *testing.T) {
+	contract := common.Address{0xc0, 0xff, 0xee}
+	setCall := append(crypto.Keccak256([]byte("set(uint256)"))[:4], make([]byte, 32)...)
+	valueSet := crypto.Keccak256Hash([]byte("ValueSet(uint256)"))
+
+	t.Run("Parse", func(t *testing.T) {
+		a, err := NewEventAssertions("set(uint256) = ValueSet(uint256); transfer(address,uint256)=Transfer(address,address,uint256)+Sent()")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !a.expects(setCall) {
+			t.Error("expected set(uint256) to have assertions")
+		}
+		if a.expects([]byte{0x01, 0x02, 0x03, 0x04}) || a.expects(nil) {
+			t.Error("unexpected assertions for unknown selector")
+		}
+		for _, spec := range []string{"", "set(uint256)", "set=ValueSet(uint256)", "set(uint256)=ValueSet"} {
+			if _, err := NewEventAssertions(spec); err == nil {
+				t.Errorf("%q: expected error", spec)
+			}
+		}
+	})
+
+	t.Run("Check", func(t *testing.T) {
+		a, err := NewEventAssertions("set(uint256)=ValueSet(uint256)")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		ok := &types.Receipt{Status: types.ReceiptStatusSuccessful, Logs: []*types.Log{{Address: contract, Topics: []common.Hash{valueSet}}}}
+		otherEmitter := &types.Receipt{Status: types.ReceiptStatusSuccessful, Logs: []*types.Log{{Address: common.Address{0x01}, Topics: []common.Hash{valueSet}}}}
+		noLogs := &types.Receipt{Status: types.ReceiptStatusSuccessful}
+		reverted := &types.Receipt{Status: types.ReceiptStatusFailed}
+		if reason := a.violation(setCall, contract, ok); reason != "" {
+			t.Errorf("expected no violation, got %q", reason)
+		}
+		for _, receipt := range []*types.Receipt{otherEmitter, noLogs} {
+			if reason := a.violation(setCall, contract, receipt); reason != "missing ValueSet(uint256)" {
+				t.Errorf("expected missing event, got %q", reason)
+			}
+		}
+		if reason := a.violation(setCall, contract, reverted); reason != "call reverted" {
+			t.Errorf("expected reverted call, got %q", reason)
+		}
+	})
+
+	t.Run("Samples", func(t *testing.T) {
+		a := &EventAssertions{}
+		for i := 0; i < maxAssertionSamples+5; i++ {
+			a.fail("missing event")
+		}
+		_, failed, samples := a.Results()
+		if failed != maxAssertionSamples+5 || len(samples) != maxAssertionSamples {
+			t.Errorf("expected %d failures and %d samples, got %d and %d", maxAssertionSamples+5, maxAssertionSamples, failed, len(samples))
+		}
+	})
+}
@@ -0,0 +1,64 @@
+package transaction
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// ExportWriter is a Submitter that writes each signed transaction to a file as a
+// raw hex line instead of sending it. The file is in the format ReadRawTransactions
+// reads, so replay mode or another tool can submit it later.
+type ExportWriter struct {
+	mu     sync.Mutex
+	file   *os.File
+	writer *bufio.Writer
+	count  int
+}
+
+// NewExportWriter creates the export file at path, replacing an existing one
+func NewExportWriter(path string) (*ExportWriter, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create export file: %w", err)
+	}
+	return &ExportWriter{file: file, writer: bufio.NewWriter(file)}, nil
+}
+
+// SendTransaction appends the signed transaction to the export file
+func (e *ExportWriter) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	raw, err := tx.MarshalBinary()
+	if err != nil {
+		return fmt.Errorf("failed to encode transaction: %w", err)
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if _, err := fmt.Fprintln(e.writer, hexutil.Encode(raw)); err != nil {
+		return fmt.Errorf("failed to write transaction: %w", err)
+	}
+	e.count++
+	return nil
+}
+
+// Count returns how many transactions were written
+func (e *ExportWriter) Count() int {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.count
+}
+
+// Close flushes and closes the export file
+func (e *ExportWriter) Close() error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if err := e.writer.Flush(); err != nil {
+		e.file.Close()
+		return fmt.Errorf("failed to flush export file: %w", err)
+	}
+	return e.file.Close()
+}
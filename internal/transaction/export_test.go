@@ -0,0 +1,48 @@
+package transaction
+
+import (
+	"context"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+func TestExportWriter(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "txs.hex")
+	e, err := NewExportWriter(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 3; i++ {
+		tx := types.NewTransaction(uint64(i), common.Address{0x01}, big.NewInt(0), 21000, big.NewInt(1), nil)
+		if err := e.SendTransaction(context.Background(), tx); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := e.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if e.Count() != 3 {
+		t.Errorf("expected 3 transactions written, got %d", e.Count())
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lines := strings.Count(string(data), "\n"); lines != 3 {
+		t.Errorf("expected one line per transaction, got %d", lines)
+	}
+	txs, err := ReadRawTransactions(strings.NewReader(string(data)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(txs) != 3 {
+		t.Errorf("expected the export to read back as 3 transactions, got %d", len(txs))
+	}
+}
@@ -0,0 +1,59 @@
+package transaction
+
+import (
+	"errors"
+	"sync"
+)
+
+// ErrFailureRateExceeded is returned when a run is aborted because too many of its
+// most recent transactions failed
+var ErrFailureRateExceeded = errors.New("run aborted: failure rate exceeded MAX_FAILURE_RATE")
+
+// failureGuard keeps the outcome of the last window transactions and trips once the
+// share of them that failed exceeds maxRate. It judges full windows only, so a few
+// failures at the start of a run do not abort it.
+type failureGuard struct {
+	mu       sync.Mutex
+	maxRate  float64
+	outcomes []bool // Ring buffer of recent outcomes, true for a failure
+	next     int
+	filled   bool
+	failed   int // Failures in outcomes
+	aborted  bool
+}
+
+// newFailureGuard creates a guard over the last window transactions
+func newFailureGuard(maxRate float64, window int) *failureGuard {
+	return &failureGuard{maxRate: maxRate, outcomes: make([]bool, window)}
+}
+
+// record adds one outcome and reports whether it tripped the guard, along with the
+// failure rate of the window. The guard trips once; later outcomes return false.
+func (g *failureGuard) record(failed bool) (tripped bool, rate float64) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.outcomes[g.next] {
+		g.failed--
+	}
+	g.outcomes[g.next] = failed
+	if failed {
+		g.failed++
+	}
+	g.next++
+	if g.next == len(g.outcomes) {
+		g.next, g.filled = 0, true
+	}
+	rate = float64(g.failed) / float64(len(g.outcomes))
+	if g.aborted || !g.filled || rate <= g.maxRate {
+		return false, rate
+	}
+	g.aborted = true
+	return true, rate
+}
+
+// tripped reports whether the guard has aborted the run
+func (g *failureGuard) tripped() bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.aborted
+}
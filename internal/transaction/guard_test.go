@@ -0,0 +1,28 @@
+package transaction
+
+import "testing"
+
+func TestFailureGuard(t *testing.T) {
+	guard := newFailureGuard(0.5, 4)
+
+	// Failures before the window is full are not judged
+	for i := 0; i < 3; i++ {
+		if tripped, _ := guard.record(true); tripped {
+			t.Fatalf("expected no abort before the window is full (outcome %d)", i)
+		}
+	}
+	// 3 of 4 failed
+	if tripped, rate := guard.record(false); !tripped || rate != 0.75 {
+		t.Fatalf("expected a 75%% failure rate to trip the guard, got tripped=%v rate=%v", tripped, rate)
+	}
+	if tripped, _ := guard.record(true); tripped || !guard.tripped() {
+		t.Error("expected the guard to trip only once and stay tripped")
+	}
+
+	healthy := newFailureGuard(0.5, 4)
+	for i, failed := range []bool{true, true, false, false, true, false, false, true} {
+		if tripped, _ := healthy.record(failed); tripped {
+			t.Fatalf("expected a window at or below the limit not to trip (outcome %d)", i)
+		}
+	}
+}
@@ -0,0 +1,25 @@
+package transaction
+
+import "fmt"
+
+// Unlimited is the MaxTransactions value that disables the transaction cap.
+// Every mode treats it the same way: keep sending until stopped or out of funds.
+const Unlimited = 0
+
+// WithinLimit reports whether the 0-based transaction i may be sent under max
+func WithinLimit(i, max int) bool {
+	return max == Unlimited || i < max
+}
+
+// IsLast reports whether the 0-based transaction i is the final one under max
+func IsLast(i, max int) bool {
+	return max != Unlimited && i >= max-1
+}
+
+// FormatProgress formats the 1-based position of transaction i against max
+func FormatProgress(i, max int) string {
+	if max == Unlimited {
+		return fmt.Sprintf("%d", i+1)
+	}
+	return fmt.Sprintf("%d/%d", i+1, max)
+}
@@ -0,0 +1,47 @@
+package transaction
+
+import (
+	"math/big"
+)
+
+// LiveSettings are the parallel-mode settings that can change while a run is going,
+// e.g. on a configuration reload during a soak test
+type LiveSettings struct {
+	RateLimit   float64   // Global send rate in transactions per second (0 = unlimited)
+	MaxGasPrice *big.Int  // Gas price cap, nil removes the cap
+	Builder     TxBuilder // Replaces the running builder, e.g. templates with new weights; nil keeps it
+}
+
+// Apply switches a running sender to s. Transactions already being built finish
+// with the previous settings. A run on the built-in workload keeps it: a builder only
+// replaces another builder. The rate only changes when RateLimit does and no
+// controller, such as a soak or adaptive run, has taken it over with SetRate.
+func (ps *ParallelSender) Apply(s *LiveSettings) {
+	ps.liveMu.Lock()
+	defer ps.liveMu.Unlock()
+	if !ps.controlled && s.RateLimit != ps.rateLimit {
+		ps.pacer.setRate(s.RateLimit)
+	}
+	ps.rateLimit = s.RateLimit
+	ps.maxGasPrice = s.MaxGasPrice
+	if s.Builder != nil && ps.builder != nil {
+		ps.builder = s.Builder
+	}
+}
+
+// currentBuilder returns the builder in use, nil for the built-in workload
+func (ps *ParallelSender) currentBuilder() TxBuilder {
+	ps.liveMu.RLock()
+	defer ps.liveMu.RUnlock()
+	return ps.builder
+}
+
+// capGasPrice lowers price to the gas price cap, if one is set
+func (ps *ParallelSender) capGasPrice(price *big.Int) *big.Int {
+	ps.liveMu.RLock()
+	defer ps.liveMu.RUnlock()
+	if ps.maxGasPrice != nil && price.Cmp(ps.maxGasPrice) > 0 {
+		return new(big.Int).Set(ps.maxGasPrice)
+	}
+	return price
+}
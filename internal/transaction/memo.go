@@ -0,0 +1,126 @@
+package transaction
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// memoMagic marks calldata that starts with a simulator memo; the trailing byte is the
+// encoding version
+var memoMagic = []byte{'E', 'T', 'S', 1}
+
+// MemoLength is the size of an encoded memo: magic, run ID, wallet index and sequence
+const MemoLength = 4 + 8 + 4 + 8
+
+// Memo attributes a transaction to a simulator run, worker wallet and sequence number
+type Memo struct {
+	RunID       uint64
+	WalletIndex uint32
+	Sequence    uint64
+}
+
+// ErrNoMemo is returned when calldata does not start with a simulator memo
+var ErrNoMemo = errors.New("no simulator memo in calldata")
+
+// NewRunID returns a random run ID
+func NewRunID() (uint64, error) {
+	var b [8]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return 0, fmt.Errorf("failed to generate run ID: %w", err)
+	}
+	return binary.BigEndian.Uint64(b[:]), nil
+}
+
+// ParseRunID parses a run ID given as up to 16 hex digits, with or without 0x
+func ParseRunID(s string) (uint64, error) {
+	id, err := strconv.ParseUint(strings.TrimPrefix(s, "0x"), 16, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid run ID %q: must be up to 16 hex digits", s)
+	}
+	return id, nil
+}
+
+// Encode returns the memo's 24-byte calldata prefix:
+// "ETS" 0x01 | run ID (8 bytes) | wallet index (4 bytes) | sequence (8 bytes), big-endian
+func (m Memo) Encode() []byte {
+	b := make([]byte, MemoLength)
+	copy(b, memoMagic)
+	binary.BigEndian.PutUint64(b[4:12], m.RunID)
+	binary.BigEndian.PutUint32(b[12:16], m.WalletIndex)
+	binary.BigEndian.PutUint64(b[16:24], m.Sequence)
+	return b
+}
+
+// DecodeMemo reads the memo at the start of data and returns it with the remaining payload
+func DecodeMemo(data []byte) (*Memo, []byte, error) {
+	if len(data) < MemoLength || !bytes.Equal(data[:len(memoMagic)], memoMagic) {
+		return nil, nil, ErrNoMemo
+	}
+	memo := &Memo{
+		RunID:       binary.BigEndian.Uint64(data[4:12]),
+		WalletIndex: binary.BigEndian.Uint32(data[12:16]),
+		Sequence:    binary.BigEndian.Uint64(data[16:24]),
+	}
+	return memo, data[MemoLength:], nil
+}
+
+// DecodeOptions holds the arguments of the decode subcommand
+type DecodeOptions struct {
+	TxHash string // Transaction to fetch and decode
+	Data   string // Raw calldata to decode instead of fetching a transaction
+}
+
+// ParseDecodeArgs parses `simulator decode --tx 0x...` or `simulator decode --data 0x...`
+func ParseDecodeArgs(args []string) (*DecodeOptions, error) {
+	fs := flag.NewFlagSet("decode", flag.ContinueOnError)
+	txHash := fs.String("tx", "", "hash of the transaction to decode")
+	data := fs.String("data", "", "raw calldata to decode (hex)")
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+	if (*txHash == "") == (*data == "") {
+		return nil, errors.New("exactly one of --tx or --data is required")
+	}
+	return &DecodeOptions{TxHash: *txHash, Data: *data}, nil
+}
+
+// DecodeCommand decodes the memo of the transaction or calldata named by opts,
+// fetching the transaction through client when a hash is given
+func DecodeCommand(ctx context.Context, client *ethclient.Client, opts *DecodeOptions) (*Memo, []byte, error) {
+	if opts.Data != "" {
+		data, err := hexutil.Decode(opts.Data)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid calldata: %w", err)
+		}
+		return DecodeMemo(data)
+	}
+
+	tx, _, err := client.TransactionByHash(ctx, common.HexToHash(opts.TxHash))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get transaction: %w", err)
+	}
+	return DecodeMemo(tx.Data())
+}
+
+// PrintMemo prints a decoded memo and its remaining payload
+func PrintMemo(memo *Memo, payload []byte) {
+	fmt.Printf("\n=== Transaction Memo ===\n")
+	fmt.Printf("Run ID: %016x\n", memo.RunID)
+	fmt.Printf("Wallet index: %d\n", memo.WalletIndex)
+	fmt.Printf("Sequence: %d\n", memo.Sequence)
+	if len(payload) > 0 {
+		fmt.Printf("Payload: %q\n", payload)
+	}
+	fmt.Printf("==========================\n")
+}
@@ -0,0 +1,54 @@
+package transaction
+
+import (
+	"testing"
+)
+
+func TestMemo(t *testing.T) {
+	t.Run("RoundTrip", func(t *testing.T) {
+		memo := Memo{RunID: 0xdeadbeef01020304, WalletIndex: 42, Sequence: 7}
+		data := append(memo.Encode(), []byte("hello")...)
+
+		decoded, payload, err := DecodeMemo(data)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if *decoded != memo {
+			t.Errorf("expected %+v, got %+v", memo, *decoded)
+		}
+		if string(payload) != "hello" {
+			t.Errorf("expected payload hello, got %q", payload)
+		}
+	})
+
+	t.Run("NoMemo", func(t *testing.T) {
+		for _, data := range [][]byte{nil, []byte("ETS"), []byte("lets bomb the network with transactions!")} {
+			if _, _, err := DecodeMemo(data); err != ErrNoMemo {
+				t.Errorf("%q: expected ErrNoMemo, got %v", data, err)
+			}
+		}
+	})
+
+	t.Run("PrefixesTransferData", func(t *testing.T) {
+		ps := &ParallelSender{config: &ParallelConfig{Data: []byte("x"), Memo: true, RunID: 9}}
+		w := &ParallelWallet{Index: 3}
+		ps.payload(w)
+		memo, payload, err := DecodeMemo(ps.payload(w))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if memo.RunID != 9 || memo.WalletIndex != 3 || memo.Sequence != 2 || string(payload) != "x" {
+			t.Errorf("unexpected memo %+v with payload %q", memo, payload)
+		}
+	})
+
+	t.Run("ParseRunID", func(t *testing.T) {
+		id, err := ParseRunID("0x00ff")
+		if err != nil || id != 255 {
+			t.Errorf("expected 255, got %d (%v)", id, err)
+		}
+		if _, err := ParseRunID("not-hex"); err == nil {
+			t.Error("expected error for invalid run ID")
+		}
+	})
+}
@@ -2,6 +2,8 @@ package transaction
 
 import (
 	"context"
+	"fmt"
+	"log"
 	"sync"
 	"time"
 
@@ -16,6 +18,8 @@ type NonceManager struct {
 	currentNonce uint64
 	mu          sync.Mutex
 	initialized bool
+	// Number of times the network nonce moved past ours without us sending
+	externalActivity uint64
 }
 
 // NewNonceManager creates a new nonce manager
@@ -43,6 +47,9 @@ func (nm *NonceManager) GetNextNonce(ctx context.Context) (uint64, error) {
 	
 	// If we haven't initialized or network nonce is higher, use network value
 	if !nm.initialized || pendingNonce > nm.currentNonce {
+		if nm.initialized {
+			nm.recordExternalActivity(pendingNonce)
+		}
 		nm.currentNonce = pendingNonce
 		nm.initialized = true
 	}
@@ -55,7 +62,74 @@ func (nm *NonceManager) GetNextNonce(ctx context.Context) (uint64, error) {
 	return nonce, nil
 }
 
-// Reset re-initializes the nonce from the network
+// NonceReservation is a contiguous block of nonces handed out by ReserveNonces
+type NonceReservation struct {
+	Start    uint64 // First reserved nonce
+	Count    int    // Number of reserved nonces
+	nm       *NonceManager
+	released bool
+}
+
+// ReserveNonces atomically reserves n contiguous nonces with a single PendingNonceAt
+// round-trip, for pre-signing pipelines and batched sends. Nonces that end up unused
+// must be handed back with Release, otherwise they leave a gap that blocks later
+// transactions from this address.
+func (nm *NonceManager) ReserveNonces(ctx context.Context, n int) (*NonceReservation, error) {
+	if n <= 0 {
+		return nil, fmt.Errorf("nonce reservation size must be greater than 0 (got: %d)", n)
+	}
+
+	nm.mu.Lock()
+	defer nm.mu.Unlock()
+
+	pendingNonce, err := nm.client.PendingNonceAt(ctx, nm.address)
+	if err != nil {
+		return nil, err
+	}
+	if !nm.initialized || pendingNonce > nm.currentNonce {
+		if nm.initialized {
+			nm.recordExternalActivity(pendingNonce)
+		}
+		nm.currentNonce = pendingNonce
+		nm.initialized = true
+	}
+
+	reservation := &NonceReservation{Start: nm.currentNonce, Count: n, nm: nm}
+	nm.currentNonce += uint64(n)
+	return reservation, nil
+}
+
+// Nonce returns the i-th nonce of the reservation
+func (r *NonceReservation) Nonce(i int) uint64 {
+	return r.Start + uint64(i)
+}
+
+// Release hands back the nonces after the first used ones. Rollback is only possible
+// while no later nonce has been handed out; otherwise an error is returned and the
+// caller must fill the gap, e.g. with self-transfers. Releasing twice is a no-op.
+func (r *NonceReservation) Release(used int) error {
+	if used < 0 || used > r.Count {
+		return fmt.Errorf("used nonces must be between 0 and %d (got: %d)", r.Count, used)
+	}
+
+	r.nm.mu.Lock()
+	defer r.nm.mu.Unlock()
+	if r.released || used == r.Count {
+		r.released = true
+		return nil
+	}
+	end := r.Start + uint64(r.Count)
+	if r.nm.currentNonce != end {
+		return fmt.Errorf("cannot release nonces %d-%d: nonce %d has already been handed out", r.Start+uint64(used), end-1, r.nm.currentNonce-1)
+	}
+	r.nm.currentNonce = r.Start + uint64(used)
+	r.released = true
+	return nil
+}
+
+// Reset resyncs the nonce from the network. The counter only moves forward, to the
+// larger of the pending nonce and our own: nonces handed out but not yet in the
+// node's pool are still ours, and rewinding would hand them out twice.
 func (nm *NonceManager) Reset(ctx context.Context) error {
 	nm.mu.Lock()
 	defer nm.mu.Unlock()
@@ -64,11 +138,85 @@ func (nm *NonceManager) Reset(ctx context.Context) error {
 	if err != nil {
 		return err
 	}
-	nm.currentNonce = nonce
+	if !nm.initialized || nonce > nm.currentNonce {
+		nm.currentNonce = nonce
+	}
 	nm.initialized = true
 	return nil
 }
 
+// MarkRejected updates the local counter after the node rejected a transaction sent
+// with nonce. It returns true when the rejection is a recoverable nonce conflict and
+// the transaction should be rebuilt with a fresh nonce rather than retried as is:
+// "nonce too low" resyncs from the network, and "replacement transaction underpriced"
+// skips past the nonce that is already taken in the pool. Any other error leaves
+// the counter alone; the caller still holds nonce and should retry with it, or hand
+// it back with Rollback when giving up.
+func (nm *NonceManager) MarkRejected(ctx context.Context, nonce uint64, err error) (bool, error) {
+	switch {
+	case IsNonceTooLow(err):
+		if resyncErr := nm.Reset(ctx); resyncErr != nil {
+			return false, resyncErr
+		}
+		return true, nil
+	case IsReplacementUnderpriced(err):
+		nm.mu.Lock()
+		defer nm.mu.Unlock()
+		if nm.currentNonce <= nonce {
+			nm.currentNonce = nonce + 1
+		}
+		return true, nil
+	default:
+		return false, nil
+	}
+}
+
+// Rollback hands back nonce after its transaction was given up on. Like
+// NonceReservation.Release it only succeeds while nonce is the last one handed out;
+// otherwise it returns false and the nonce is left as a gap the caller must fill.
+func (nm *NonceManager) Rollback(nonce uint64) bool {
+	nm.mu.Lock()
+	defer nm.mu.Unlock()
+	if nm.currentNonce != nonce+1 {
+		return false
+	}
+	nm.currentNonce = nonce
+	return true
+}
+
+// CheckExternalActivity compares the mined nonce with our local counter and resyncs
+// if transactions we did not send have been mined from this address (another process
+// using the same key, or a stale run still alive). It returns true if activity was found.
+func (nm *NonceManager) CheckExternalActivity(ctx context.Context) (bool, error) {
+	minedNonce, err := nm.client.NonceAt(ctx, nm.address, nil)
+	if err != nil {
+		return false, err
+	}
+
+	nm.mu.Lock()
+	defer nm.mu.Unlock()
+	if !nm.initialized || minedNonce <= nm.currentNonce {
+		return false, nil
+	}
+	nm.recordExternalActivity(minedNonce)
+	nm.currentNonce = minedNonce
+	return true, nil
+}
+
+// ExternalActivityCount returns how many times external nonce activity was detected
+func (nm *NonceManager) ExternalActivityCount() uint64 {
+	nm.mu.Lock()
+	defer nm.mu.Unlock()
+	return nm.externalActivity
+}
+
+// recordExternalActivity warns that the network nonce moved past ours (caller holds mu)
+func (nm *NonceManager) recordExternalActivity(networkNonce uint64) {
+	nm.externalActivity++
+	log.Printf("Warning: nonce for %s advanced externally (expected %d, network has %d) - is another process using this key? Resyncing",
+		nm.address.Hex(), nm.currentNonce, networkNonce)
+}
+
 // WaitForNonceUpdate waits for the pending nonce to reflect a transaction we just sent
 // This ensures the node has accepted the transaction into its mempool before we proceed
 func (nm *NonceManager) WaitForNonceUpdate(ctx context.Context, expectedNonce uint64, maxWait time.Duration) error {
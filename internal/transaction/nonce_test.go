@@ -0,0 +1,158 @@
+package transaction
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"sync"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// fakeEth serves the eth_ methods the senders read, over an in-process RPC server
+type fakeEth struct {
+	mu      sync.Mutex
+	pending uint64
+	mined   uint64
+	head    uint64
+	receipt *types.Receipt
+}
+
+func (f *fakeEth) GetTransactionCount(address common.Address, block string) hexutil.Uint64 {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if block == "pending" {
+		return hexutil.Uint64(f.pending)
+	}
+	return hexutil.Uint64(f.mined)
+}
+
+func (f *fakeEth) BlockNumber() hexutil.Uint64 {
+	return hexutil.Uint64(f.head)
+}
+
+func (f *fakeEth) GetTransactionReceipt(hash common.Hash) *types.Receipt {
+	return f.receipt
+}
+
+func (f *fakeEth) GasPrice() *hexutil.Big {
+	return (*hexutil.Big)(big.NewInt(1e9))
+}
+
+func (f *fakeEth) ChainId() *hexutil.Big {
+	return (*hexutil.Big)(big.NewInt(1337))
+}
+
+// newFakeClient returns a client of an in-process node answering from eth
+func newFakeClient(t *testing.T, eth *fakeEth) *ethclient.Client {
+	server := rpc.NewServer()
+	if err := server.RegisterName("eth", eth); err != nil {
+		t.Fatal(err)
+	}
+	client := ethclient.NewClient(rpc.DialInProc(server))
+	t.Cleanup(func() {
+		client.Close()
+		server.Stop()
+	})
+	return client
+}
+
+func TestNonceReservationRelease(t *testing.T) {
+	t.Run("RollsBackUnusedTail", func(t *testing.T) {
+		nm := &NonceManager{currentNonce: 15, initialized: true}
+		r := &NonceReservation{Start: 10, Count: 5, nm: nm}
+		if err := r.Release(2); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if nm.currentNonce != 12 {
+			t.Errorf("expected next nonce 12, got %d", nm.currentNonce)
+		}
+	})
+
+	t.Run("RefusesAfterLaterReservation", func(t *testing.T) {
+		nm := &NonceManager{currentNonce: 20, initialized: true}
+		r := &NonceReservation{Start: 10, Count: 5, nm: nm}
+		if err := r.Release(2); err == nil {
+			t.Error("expected error when later nonces were handed out")
+		}
+		if nm.currentNonce != 20 {
+			t.Errorf("expected next nonce to stay 20, got %d", nm.currentNonce)
+		}
+	})
+}
+
+func TestSendErrorClassification(t *testing.T) {
+	t.Run("NonceTooLow", func(t *testing.T) {
+		if !IsNonceTooLow(errors.New("nonce too low: next nonce 5, tx nonce 3")) {
+			t.Error("expected nonce too low to be detected")
+		}
+		if IsNonceTooLow(nil) {
+			t.Error("nil error should not match")
+		}
+	})
+
+	t.Run("ReplacementUnderpriced", func(t *testing.T) {
+		nm := &NonceManager{currentNonce: 3, initialized: true}
+		recoverable, err := nm.MarkRejected(context.Background(), 4, errors.New("replacement transaction underpriced"))
+		if err != nil || !recoverable {
+			t.Fatalf("expected recoverable rejection, got %v, %v", recoverable, err)
+		}
+		if nm.currentNonce != 5 {
+			t.Errorf("expected next nonce 5, got %d", nm.currentNonce)
+		}
+	})
+
+	t.Run("NonceTooLowMovesForwardOnly", func(t *testing.T) {
+		eth := &fakeEth{pending: 7}
+		nm := &NonceManager{client: newFakeClient(t, eth), currentNonce: 5, initialized: true}
+		recoverable, err := nm.MarkRejected(context.Background(), 4, errors.New("nonce too low: next nonce 7, tx nonce 4"))
+		if err != nil || !recoverable {
+			t.Fatalf("expected recoverable rejection, got %v, %v", recoverable, err)
+		}
+		if nm.currentNonce != 7 {
+			t.Errorf("expected the counter to resync to the pending nonce 7, got %d", nm.currentNonce)
+		}
+
+		// Nonces handed out but not yet in the pool must not be handed out again
+		nm.currentNonce = 12
+		if _, err := nm.MarkRejected(context.Background(), 6, errors.New("nonce too low")); err != nil {
+			t.Fatal(err)
+		}
+		if nm.currentNonce != 12 {
+			t.Errorf("expected the counter to stay at 12, got %d", nm.currentNonce)
+		}
+	})
+
+	t.Run("OtherErrorsKeepNonce", func(t *testing.T) {
+		nm := &NonceManager{currentNonce: 4, initialized: true}
+		if recoverable, _ := nm.MarkRejected(context.Background(), 3, errors.New("insufficient funds for gas * price + value")); recoverable {
+			t.Error("insufficient funds should not be recoverable")
+		}
+		if nm.currentNonce != 4 {
+			t.Errorf("expected the counter to stay at 4, got %d", nm.currentNonce)
+		}
+		if !nm.Rollback(3) || nm.currentNonce != 3 {
+			t.Errorf("expected nonce 3 to be handed back, next nonce is %d", nm.currentNonce)
+		}
+		nm.currentNonce = 6
+		if nm.Rollback(3) {
+			t.Error("expected no rollback once later nonces were handed out")
+		}
+	})
+
+	t.Run("PoolPressure", func(t *testing.T) {
+		for _, msg := range []string{"txpool is full", "account limit exceeded", "transaction underpriced"} {
+			if !IsPoolPressure(errors.New(msg)) {
+				t.Errorf("expected %q to be pool pressure", msg)
+			}
+		}
+		if IsPoolPressure(errors.New("replacement transaction underpriced")) {
+			t.Error("replacement refusals are not pool pressure")
+		}
+	})
+}
@@ -0,0 +1,87 @@
+package transaction
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// pacer hands out send tokens at a global rate. A single feeder issues tokens, so a
+// rate change takes effect within one interval no matter how many wallets are waiting,
+// which lets controllers steer a running load.
+type pacer struct {
+	mu       sync.Mutex
+	interval time.Duration // 0 = unlimited
+	tokens   chan struct{}
+	once     sync.Once
+}
+
+// setRate sets the rate in transactions per second (0 = unlimited)
+func (p *pacer) setRate(tps float64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if tps <= 0 {
+		p.interval = 0
+		return
+	}
+	p.interval = time.Duration(float64(time.Second) / tps)
+}
+
+// rate returns the current rate in transactions per second (0 = unlimited)
+func (p *pacer) rate() float64 {
+	interval := p.currentInterval()
+	if interval == 0 {
+		return 0
+	}
+	return float64(time.Second) / float64(interval)
+}
+
+// currentInterval returns the time between tokens
+func (p *pacer) currentInterval() time.Duration {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.interval
+}
+
+// run issues tokens until the context is cancelled
+func (p *pacer) run(ctx context.Context) {
+	p.once.Do(func() { p.tokens = make(chan struct{}) })
+	for {
+		interval := p.currentInterval()
+		if interval == 0 {
+			interval = 100 * time.Millisecond // Unlimited: just watch for a rate change
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(interval):
+		}
+		if p.currentInterval() == 0 {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case p.tokens <- struct{}{}:
+		}
+	}
+}
+
+// wait blocks until a send token is available; it returns immediately when unlimited
+func (p *pacer) wait(ctx context.Context) error {
+	p.once.Do(func() { p.tokens = make(chan struct{}) })
+	for {
+		if p.currentInterval() == 0 {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-p.tokens:
+			return nil
+		case <-time.After(100 * time.Millisecond):
+			// Re-check in case the rate was lifted to unlimited
+		}
+	}
+}
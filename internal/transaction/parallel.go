@@ -3,6 +3,7 @@ package transaction
 import (
 	"context"
 	"crypto/ecdsa"
+	"errors"
 	"fmt"
 	"math/big"
 	"math/rand"
@@ -13,6 +14,7 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/aakash4dev/ethereum-transaction-simulator/internal/diagnostics"
 )
 
 // ParallelSender handles parallel transactions from multiple wallets
@@ -22,35 +24,94 @@ type ParallelSender struct {
 	wallets    []*ParallelWallet
 	recipients []common.Address
 	config     *ParallelConfig
+	tracker    *Tracker
+	submitter  Submitter
+	pacer      pacer
+	observer   func(BlockStats)
+	stream     *EventStream
+	contracts  ContractObserver
+	guard      *failureGuard      // Set when MaxFailureRate is configured
+	abort      context.CancelFunc // Stops the run when the guard trips
+	chaos      *chaos             // Set when Chaos is configured
+	signer     func(*types.Transaction, common.Address) (*types.Transaction, error)
+	// Zipf generators for recipients and contracts, set when ZipfExponent is configured.
+	// Their source is locked, so all wallets share them.
+	recipientZipf *rand.Zipf
+	contractZipf  *rand.Zipf
+	// Settings that can change mid-run, see Apply
+	liveMu      sync.RWMutex
+	builder     TxBuilder
+	maxGasPrice *big.Int
+	rateLimit   float64 // RateLimit as last configured
+	controlled  bool    // Set once SetRate hands the rate to a controller, which reloads then leave alone
 	// Metrics
+	totalReserved  int64 // Transactions claimed against MaxTransactions
 	totalSent      int64
 	totalFailed    int64
 	totalSucceeded int64
+	totalSubmitted int64 // SendTransaction calls, including retries
+	totalRejected  int64 // SendTransaction calls the node refused
+	trackInclusion bool  // Set by TrackInclusion
 	errors         []error
 	mu             sync.Mutex
+	sending        sync.WaitGroup // sendTransactionWithRetry goroutines
+	verifying      sync.WaitGroup // verifyTransaction goroutines, waited for before the summary
 }
 
 // ParallelWallet represents a wallet for parallel sending
 type ParallelWallet struct {
-	PrivateKey   *ecdsa.PrivateKey
+	PrivateKey   *ecdsa.PrivateKey // Nil when a remote signer set by SetSigner holds the key
 	Address      common.Address
 	NonceManager *NonceManager
+	Index        int    // Position in the wallet pool, set by NewParallelSender
+	sequence     uint64 // Transactions built by this wallet, used for DataTemplate and Memo
+	audit        *walletAudit // Start snapshot and sent hashes, set when Audit is enabled
+	persona      *Persona     // Behavior profile, set by NewParallelSender when Personas is configured
 	// Cached balance to reduce RPC calls
 	lastBalance     *big.Int
 	lastBalanceTime time.Time
 	balanceMu       sync.RWMutex
 }
 
+// CalldataGenerator builds the calldata for one contract call
+type CalldataGenerator func(rng *rand.Rand) ([]byte, error)
+
 // ParallelConfig holds configuration for parallel transactions
 type ParallelConfig struct {
 	Value                *big.Int
 	GasLimit             uint64
 	Data                 []byte
-	MaxTransactions      int
+	MaxTransactions      int    // Global cap shared by all wallets (0 = until balances run out)
 	MaxConcurrentRequests int    // Maximum concurrent RPC requests
 	BalanceCheckInterval int    // Check balance every N transactions
 	MaxRetries           int    // Maximum retries for failed transactions
 	RetryDelay           time.Duration // Delay between retries
+	VerifySampleRate     int    // Verify 1 in N sent transactions (0 disables verification)
+	MaxInFlight          int    // Pause sending when this many txs are unmined globally (0 = unlimited)
+	MaxInFlightPerWallet int    // Pause a wallet when this many of its txs are unmined (0 = unlimited)
+	Contracts            []common.Address // Contracts to call instead of transferring to recipients
+	Calldata             CalldataGenerator // Calldata for each contract call, nil uses Data
+	DataTemplate         string // Per-transaction data with {wallet}, {address}, {seq} placeholders, empty uses Data
+	Memo                 bool   // Prefix transfer data with a run ID, wallet index and sequence memo
+	RunID                uint64 // Run ID encoded in each memo
+	GasOnly              bool   // Send zero-value transactions to the sending wallet itself, burning only gas
+	Audit                bool   // Reconcile wallet nonces and balances with the chain after the run
+	RateLimit            float64 // Global send rate in transactions per second (0 = unlimited)
+	ExpectedEvents       *EventAssertions // Events each contract call must emit, checked on inclusion
+	InitCode             CalldataGenerator // Init code for contract creations sent instead of transfers or calls, nil disables
+	Confirmation         *ConfirmationStrategy // When a verified transaction counts as succeeded, nil uses DefaultConfirmation
+	Builder              TxBuilder // User-defined transactions (templates, a script or a plugin) sent instead of transfers or calls, nil disables
+	FeeStrategy          FeeStrategy // Adjusts the node's suggested gas price, nil uses it as is
+	MaxGasPrice          *big.Int // Gas prices above this are capped to it, nil leaves them uncapped
+	ThinkTime            *ThinkTime // Pause each wallet takes after every transaction, nil sends in a tight loop
+	Personas             *PersonaSet // Behavior profiles for shares of the wallet pool, nil gives every wallet the settings above
+	ZipfExponent         float64 // Pick recipients and contracts by Zipf popularity with this exponent (> 1), 0 picks uniformly
+	SlowStart            *SlowStart // Activate wallets gradually instead of all at once, nil starts them together
+	ReportPositions      bool    // Track inclusion and report where transactions landed within their blocks
+	DropTimeout          time.Duration // Count transactions not mined this long after sending as dropped, 0 disables
+	MaxFailureRate       float64 // Abort when more than this share of the last FailureRateWindow transactions failed (0 disables)
+	FailureRateWindow    int     // Transactions the failure rate is measured over (default: 100)
+	Chaos                *ChaosConfig // Faults injected into our own sends, nil disables
 }
 
 // NewParallelSender creates a new parallel transaction sender
@@ -68,32 +129,205 @@ func NewParallelSender(client *ethclient.Client, chainID *big.Int, wallets []*Pa
 	if config.RetryDelay == 0 {
 		config.RetryDelay = 100 * time.Millisecond
 	}
+	if config.Confirmation == nil {
+		config.Confirmation = DefaultConfirmation
+	}
+	if config.FailureRateWindow == 0 {
+		config.FailureRateWindow = 100
+	}
+	for i, w := range wallets {
+		w.Index = i
+	}
+	if config.Personas != nil {
+		for i, persona := range config.Personas.assign(len(wallets)) {
+			wallets[i].persona = persona
+		}
+	}
 
-	return &ParallelSender{
-		client:     client,
-		chainID:    chainID,
-		wallets:    wallets,
-		recipients: recipients,
-		config:     config,
-		errors:     make([]error, 0),
+	ps := &ParallelSender{
+		client:      client,
+		chainID:     chainID,
+		wallets:     wallets,
+		recipients:  recipients,
+		config:      config,
+		submitter:   client,
+		errors:      make([]error, 0),
+		builder:     config.Builder,
+		maxGasPrice: config.MaxGasPrice,
+		rateLimit:   config.RateLimit,
 	}
+	if config.ZipfExponent > 1 {
+		zipfRng := rand.New(newLockedSource(rand.Int63()))
+		ps.recipientZipf = newZipf(zipfRng, config.ZipfExponent, len(recipients))
+		ps.contractZipf = newZipf(zipfRng, config.ZipfExponent, len(config.Contracts))
+	}
+	ps.pacer.setRate(config.RateLimit)
+	return ps
+}
+
+// SetRate changes the global send rate in transactions per second (0 = unlimited).
+// It may be called while SendParallelTransactions is running. From then on the
+// caller owns the rate, and Apply no longer changes it.
+func (ps *ParallelSender) SetRate(tps float64) {
+	ps.liveMu.Lock()
+	defer ps.liveMu.Unlock()
+	ps.controlled = true
+	ps.pacer.setRate(tps)
+}
+
+// Rate returns the current global send rate (0 = unlimited)
+func (ps *ParallelSender) Rate() float64 {
+	return ps.pacer.rate()
+}
+
+// ObserveBlocks registers fn to receive inclusion stats for every new block.
+// It enables inclusion tracking and must be called before SendParallelTransactions.
+func (ps *ParallelSender) ObserveBlocks(fn func(BlockStats)) {
+	ps.observer = fn
+}
+
+// TrackInclusion counts sent transactions as they are included in blocks, for
+// AcceptanceCounts. It must be called before SendParallelTransactions.
+func (ps *ParallelSender) TrackInclusion() {
+	ps.trackInclusion = true
+}
+
+// StreamEvents writes an event for every transaction sent, refused, given up on and
+// included to stream. It enables inclusion tracking and must be called before
+// SendParallelTransactions.
+func (ps *ParallelSender) StreamEvents(stream *EventStream) {
+	ps.stream = stream
+}
+
+// ObserveContracts passes the outcome of every call to a contract o watches to o:
+// accepted, given up on, and the receipt once included. It enables inclusion
+// tracking and must be called before SendParallelTransactions.
+func (ps *ParallelSender) ObserveContracts(o ContractObserver) {
+	ps.contracts = o
+}
+
+// emit writes event to the event stream, if one is set
+func (ps *ParallelSender) emit(event TxEvent) {
+	if ps.stream != nil {
+		ps.stream.Emit(event)
+	}
+}
+
+// SetSubmitter routes signed transactions to a separate write endpoint
+func (ps *ParallelSender) SetSubmitter(submitter Submitter) {
+	ps.submitter = submitter
+}
+
+// SetSigner signs every transaction with sign instead of the wallet's private key,
+// so a process without keys can send for wallets held by a separate signer
+func (ps *ParallelSender) SetSigner(sign func(tx *types.Transaction, from common.Address) (*types.Transaction, error)) {
+	ps.signer = sign
+}
+
+// sign signs tx for w, locally or through the signer set by SetSigner
+func (ps *ParallelSender) sign(tx *types.Transaction, w *ParallelWallet) (*types.Transaction, error) {
+	if ps.signer != nil {
+		return ps.signer(tx, w.Address)
+	}
+	return SignTx(tx, ps.chainID, w.PrivateKey)
 }
 
 // SendParallelTransactions sends transactions continuously from all wallets until balance runs out
+// or MaxTransactions have been attempted across all wallets (0 = no cap)
 // It respects context cancellation and properly handles errors
 func (ps *ParallelSender) SendParallelTransactions(ctx context.Context) error {
 	var wg sync.WaitGroup
 	semaphore := make(chan struct{}, ps.config.MaxConcurrentRequests)
 
+	diagnostics.Publish("parallel", func() interface{} { return ps.Stats() })
+
+	// Stop the whole run, not just the failing sends, once too many recent transactions fail
+	if ps.config.MaxFailureRate > 0 {
+		ps.guard = newFailureGuard(ps.config.MaxFailureRate, ps.config.FailureRateWindow)
+		ctx, ps.abort = context.WithCancel(ctx)
+		defer ps.abort()
+	}
+
+	// Inject faults between signing and the submitter, whichever submitter is set
+	if ps.config.Chaos != nil {
+		ps.chaos = newChaos(ps.config.Chaos, ps.chainID)
+		submitter := ps.submitter
+		ps.submitter = &chaosSubmitter{next: submitter, chaos: ps.chaos}
+		defer func() { ps.submitter = submitter }()
+	}
+
+	// Snapshot wallets so the chain state can be reconciled after the run
+	if ps.config.Audit {
+		if err := ps.snapshotWallets(ctx); err != nil {
+			return fmt.Errorf("failed to snapshot wallets for audit: %w", err)
+		}
+	}
+
+	// Track inclusion of sent transactions when an in-flight cap, block observer, event stream, contract observer, event assertions, position report or drop timeout are configured
+	if ps.config.MaxInFlight > 0 || ps.config.MaxInFlightPerWallet > 0 || ps.observer != nil || ps.stream != nil || ps.contracts != nil || ps.config.ExpectedEvents != nil || ps.config.ReportPositions || ps.config.DropTimeout > 0 || ps.trackInclusion {
+		ps.tracker = NewTracker(ps.client)
+		ps.tracker.SetBlockObserver(ps.observer)
+		if ps.stream != nil {
+			ps.tracker.SetInclusionObserver(func(tracked *TrackedTx, block uint64, at time.Time) {
+				ps.stream.Emit(includedEvent(tracked, block, at))
+			})
+		}
+		if ps.config.DropTimeout > 0 {
+			ps.tracker.SetDropTimeout(ps.config.DropTimeout, ps.onDropped)
+		}
+		if ps.contracts != nil {
+			ps.tracker.SetContractObserver(ps.contracts)
+		}
+		ps.tracker.SetAssertions(ps.config.ExpectedEvents)
+		// Scan from the current head before any wallet sends, so no inclusion is missed
+		if err := ps.tracker.Start(ctx); err != nil {
+			return fmt.Errorf("failed to start inclusion tracking: %w", err)
+		}
+		trackerCtx, stopTracker := context.WithCancel(ctx)
+		defer stopTracker()
+		go func() {
+			if err := ps.tracker.Run(trackerCtx, time.Second); err != nil {
+				ps.recordError(fmt.Errorf("tracker: %w", err))
+			}
+		}()
+	}
+
+	pacerCtx, stopPacer := context.WithCancel(ctx)
+	defer stopPacer()
+	go ps.pacer.run(pacerCtx)
+
+	if slow := ps.config.SlowStart; slow != nil && slow.Initial < len(ps.wallets) {
+		fmt.Printf("Slow start: %d wallets active, doubling every %s, all %d active after %s\n",
+			slow.Initial, slow.Interval, len(ps.wallets), slow.Duration(len(ps.wallets)))
+	}
+
 	// Launch continuous transaction sending from each wallet
 	for _, wallet := range ps.wallets {
 		wg.Add(1)
 		go func(w *ParallelWallet) {
 			defer wg.Done()
 
-			rng := rand.New(rand.NewSource(rand.Int63()))
+			// Wait for this wallet's turn when wallets are activated gradually
+			if delay := ps.config.SlowStart.delay(w.Index); delay > 0 {
+				select {
+				case <-ctx.Done():
+					return
+				case <-time.After(delay):
+				}
+			}
+
+			// The wallet's sends run concurrently, so they share a locked source
+			rng := rand.New(newLockedSource(rand.Int63()))
 			balanceCheckCounter := 0
 
+			// Start thinking wallets at staggered times instead of all at once. Think
+			// times get their own source, as rng is used by this wallet's sends.
+			thinkRng := rand.New(rand.NewSource(rng.Int63()))
+			thinkTime := w.persona.thinkTime(ps.config.ThinkTime)
+			if thinkTime != nil && !thinkTime.think(ctx, thinkRng) {
+				return
+			}
+
 			// Continuous loop - send transactions until balance runs out or context is cancelled
 			for {
 				// Check context cancellation
@@ -114,16 +348,44 @@ func (ps *ParallelSender) SendParallelTransactions(ctx context.Context) error {
 					if !hasBalance {
 						return // Wallet out of balance
 					}
+					if _, err := w.NonceManager.CheckExternalActivity(ctx); err != nil {
+						ps.recordError(fmt.Errorf("wallet %s: nonce check failed: %w", w.Address.Hex(), err))
+					}
+				}
+
+				// Hold the configured global send rate
+				if err := ps.pacer.wait(ctx); err != nil {
+					return
 				}
 
 				// Acquire semaphore (non-blocking)
 				select {
 				case semaphore <- struct{}{}:
+					// Claim a slot against the global cap, shared by all wallets
+					if !ps.reserveTransaction() {
+						<-semaphore
+						return
+					}
+					// Take an in-flight slot, pausing while too many transactions are waiting to be mined
+					if ps.tracker != nil {
+						if err := ps.tracker.Acquire(ctx, w.Address, ps.config.MaxInFlight, ps.config.MaxInFlightPerWallet); err != nil {
+							<-semaphore
+							if errors.Is(err, ErrTrackerStopped) {
+								ps.recordError(fmt.Errorf("wallet %s: %w", w.Address.Hex(), err))
+							}
+							return
+						}
+					}
 					// Send transaction immediately
+					ps.sending.Add(1)
 					go func() {
+						defer ps.sending.Done()
 						defer func() { <-semaphore }()
 						ps.sendTransactionWithRetry(ctx, w, rng)
 					}()
+					if thinkTime != nil && !thinkTime.think(ctx, thinkRng) {
+						return
+					}
 				case <-ctx.Done():
 					return
 				default:
@@ -139,12 +401,45 @@ func (ps *ParallelSender) SendParallelTransactions(ctx context.Context) error {
 	}
 
 	wg.Wait()
+	ps.sending.Wait()
+	// Verifications run on their own context bounded by the confirmation timeout, so
+	// even an interrupted run counts the successes of what it sent
+	ps.verifying.Wait()
 
 	// Print summary
 	ps.printSummary()
+	if ps.config.ReportPositions {
+		PrintPositionReport(ps.tracker.Positions())
+	}
+	if ps.config.DropTimeout > 0 {
+		PrintDropReport(ps.tracker.Drops(), atomic.LoadInt64(&ps.totalSent))
+	}
+	if ps.chaos != nil {
+		PrintChaosReport(ps.chaos.report())
+	}
+
+	if ps.config.Audit {
+		// The run context may already be cancelled (Ctrl+C), so audit on a fresh one
+		report, err := ps.Audit(context.Background())
+		if err != nil {
+			return fmt.Errorf("audit failed: %w", err)
+		}
+		PrintAudit(report)
+	}
+	if ps.guard != nil && ps.guard.tripped() {
+		return ErrFailureRateExceeded
+	}
 	return nil
 }
 
+// reserveTransaction claims one transaction against MaxTransactions
+func (ps *ParallelSender) reserveTransaction() bool {
+	if ps.config.MaxTransactions == Unlimited {
+		return true
+	}
+	return atomic.AddInt64(&ps.totalReserved, 1) <= int64(ps.config.MaxTransactions)
+}
+
 // checkWalletBalance checks if wallet has sufficient balance, using cache when possible
 func (ps *ParallelSender) checkWalletBalance(ctx context.Context, w *ParallelWallet) (bool, error) {
 	// Check cache first (balance is valid for 1 second)
@@ -159,7 +454,7 @@ func (ps *ParallelSender) checkWalletBalance(ctx context.Context, w *ParallelWal
 		}
 
 		minRequired := new(big.Int).Mul(gasPrice, big.NewInt(int64(ps.config.GasLimit)))
-		minRequired.Add(minRequired, ps.config.Value)
+		minRequired.Add(minRequired, ps.value())
 
 		return balance.Cmp(minRequired) >= 0, nil
 	}
@@ -177,7 +472,7 @@ func (ps *ParallelSender) checkWalletBalance(ctx context.Context, w *ParallelWal
 	}
 
 	minRequired := new(big.Int).Mul(gasPrice, big.NewInt(int64(ps.config.GasLimit)))
-	minRequired.Add(minRequired, ps.config.Value)
+	minRequired.Add(minRequired, ps.value())
 
 	// Update cache
 	w.balanceMu.Lock()
@@ -188,100 +483,359 @@ func (ps *ParallelSender) checkWalletBalance(ctx context.Context, w *ParallelWal
 	return balance.Cmp(minRequired) >= 0, nil
 }
 
-// sendTransactionWithRetry sends a transaction with retry logic
+// sendTransactionWithRetry sends a transaction with retry logic. With inclusion
+// tracking, the caller has taken an in-flight slot, which is freed here unless the
+// transaction is accepted.
 func (ps *ParallelSender) sendTransactionWithRetry(ctx context.Context, w *ParallelWallet, rng *rand.Rand) {
-	recipient := ps.recipients[rng.Intn(len(ps.recipients))]
+	accepted := false
+	if ps.tracker != nil {
+		defer func() {
+			if !accepted {
+				ps.tracker.Release(w.Address)
+			}
+		}()
+	}
+	value, gasLimit := ps.value(), ps.config.GasLimit
+	if !ps.config.GasOnly {
+		value = w.persona.value(rng, value)
+	}
+	var recipient common.Address
+	var data []byte
+	var err error
+	builder := ps.currentBuilder()
+	if builder != nil {
+		var built *TemplateTx
+		built, err = builder.Build(rng, w.Index, w.Address, atomic.AddUint64(&w.sequence, 1))
+		if err == nil {
+			recipient, data, value = built.To, built.Data, built.Value
+			if built.GasLimit > 0 {
+				gasLimit = built.GasLimit
+			}
+		}
+	} else {
+		recipient, data, err = ps.nextTarget(w, rng)
+	}
+	if err != nil {
+		ps.recordError(fmt.Errorf("wallet %s: failed to build calldata: %w", w.Address.Hex(), err))
+		ps.emit(TxEvent{Event: EventFailed, Wallet: &w.Index, From: w.Address, Error: err.Error()})
+		ps.countFailure(recipient)
+		return
+	}
 
+	// The nonce is kept across retries until the node reports a nonce conflict: a
+	// send that failed for any other reason (a dropped request, a bad signature, a
+	// busy pool) leaves it unused, and taking a fresh one would leave a gap
 	var lastErr error
+	var nonce uint64
+	haveNonce := false
 	for attempt := 0; attempt <= ps.config.MaxRetries; attempt++ {
 		// Check context cancellation
 		select {
 		case <-ctx.Done():
+			if haveNonce {
+				ps.releaseNonce(w, nonce)
+			}
 			return
 		default:
 		}
 
 		// Get nonce
-		nonce, err := w.NonceManager.GetNextNonce(ctx)
-		if err != nil {
-			lastErr = fmt.Errorf("failed to get nonce: %w", err)
-			ps.recordError(fmt.Errorf("wallet %s: %w", w.Address.Hex(), lastErr))
-			atomic.AddInt64(&ps.totalFailed, 1)
-			return
+		if !haveNonce {
+			nonce, err = w.NonceManager.GetNextNonce(ctx)
+			if err != nil {
+				lastErr = fmt.Errorf("failed to get nonce: %w", err)
+				ps.recordError(fmt.Errorf("wallet %s: %w", w.Address.Hex(), lastErr))
+				ps.emit(TxEvent{Event: EventFailed, Wallet: &w.Index, From: w.Address, Error: lastErr.Error()})
+				ps.countFailure(recipient)
+				return
+			}
+			haveNonce = true
 		}
 
 		// Get gas price
 		gasPrice, err := ps.client.SuggestGasPrice(ctx)
+		if err == nil && ps.config.FeeStrategy != nil {
+			gasPrice, err = ps.config.FeeStrategy.GasPrice(gasPrice)
+		}
+		if err == nil {
+			gasPrice = ps.capGasPrice(w.persona.gasPrice(gasPrice))
+		}
 		if err != nil {
 			lastErr = fmt.Errorf("failed to get gas price: %w", err)
 			if attempt < ps.config.MaxRetries {
 				time.Sleep(ps.config.RetryDelay * time.Duration(attempt+1))
 				continue
 			}
+			ps.releaseNonce(w, nonce)
 			ps.recordError(fmt.Errorf("wallet %s: %w", w.Address.Hex(), lastErr))
-			atomic.AddInt64(&ps.totalFailed, 1)
+			ps.emit(TxEvent{Event: EventFailed, Wallet: &w.Index, From: w.Address, Error: lastErr.Error()})
+			ps.countFailure(recipient)
 			return
 		}
 
 		// Create transaction
-		tx := types.NewTransaction(
-			nonce,
-			recipient,
-			ps.config.Value,
-			ps.config.GasLimit,
-			gasPrice,
-			ps.config.Data,
-		)
+		var tx *types.Transaction
+		if ps.config.InitCode != nil && builder == nil {
+			tx = types.NewContractCreation(nonce, value, gasLimit, gasPrice, data)
+		} else {
+			tx = types.NewTransaction(
+				nonce,
+				recipient,
+				value,
+				gasLimit,
+				gasPrice,
+				data,
+			)
+		}
 
 		// Sign transaction
-		signedTx, err := types.SignTx(tx, types.NewEIP155Signer(ps.chainID), w.PrivateKey)
+		if ps.chaos != nil {
+			ps.chaos.delaySigning(ctx)
+		}
+		signedTx, err := ps.sign(tx, w)
 		if err != nil {
 			lastErr = fmt.Errorf("failed to sign transaction: %w", err)
+			ps.releaseNonce(w, nonce)
 			ps.recordError(fmt.Errorf("wallet %s: %w", w.Address.Hex(), lastErr))
-			atomic.AddInt64(&ps.totalFailed, 1)
+			ps.emit(TxEvent{Event: EventFailed, Wallet: &w.Index, From: w.Address, Error: lastErr.Error()})
+			ps.countFailure(recipient)
 			return
 		}
 
-		// Send transaction
-		err = ps.client.SendTransaction(ctx, signedTx)
+		// Send transaction, tracked first so that it is seen even if mined right away
+		if ps.tracker != nil {
+			ps.tracker.Track(signedTx, w.Address)
+		}
+		atomic.AddInt64(&ps.totalSubmitted, 1)
+		err = ps.submitter.SendTransaction(ctx, signedTx)
+		if IsAlreadyKnown(err) {
+			err = nil // The node already holds this exact transaction
+		}
 		if err != nil {
+			if ps.tracker != nil {
+				ps.tracker.Untrack(signedTx.Hash())
+			}
+			atomic.AddInt64(&ps.totalRejected, 1)
+			if ps.stream != nil {
+				event := txEvent(EventRejected, w, signedTx, attempt+1)
+				event.Error = err.Error()
+				ps.stream.Emit(event)
+			}
 			lastErr = fmt.Errorf("failed to send transaction: %w", err)
-			if attempt < ps.config.MaxRetries {
+			// Nonce conflicts are fixed by rebuilding with a fresh nonce, no backoff needed.
+			// Anything else is retried on the same nonce after a backoff.
+			recoverable, resyncErr := w.NonceManager.MarkRejected(ctx, nonce, err)
+			if resyncErr != nil {
+				lastErr = fmt.Errorf("%w (nonce resync failed: %v)", lastErr, resyncErr)
+			}
+			if recoverable {
+				haveNonce = false
+				if attempt < ps.config.MaxRetries {
+					continue
+				}
+			} else if attempt < ps.config.MaxRetries {
 				// Retry with exponential backoff
 				time.Sleep(ps.config.RetryDelay * time.Duration(attempt+1))
 				continue
+			} else {
+				ps.releaseNonce(w, nonce)
 			}
 			ps.recordError(fmt.Errorf("wallet %s: %w", w.Address.Hex(), lastErr))
-			atomic.AddInt64(&ps.totalFailed, 1)
+			ps.emit(TxEvent{Event: EventFailed, Wallet: &w.Index, From: w.Address, Error: lastErr.Error()})
+			ps.countFailure(recipient)
 			return
 		}
 
-		// Success - verify transaction was accepted (optional, non-blocking)
-		atomic.AddInt64(&ps.totalSent, 1)
-		go ps.verifyTransaction(ctx, signedTx.Hash(), w.Address)
+		// Success - verify a sample of transactions were accepted (optional, non-blocking)
+		accepted = true
+		sent := atomic.AddInt64(&ps.totalSent, 1)
+		ps.checkFailureRate(false)
+		if ps.contracts != nil && ps.contracts.Watches(recipient) {
+			ps.contracts.RecordSent(recipient)
+		}
+		if w.persona != nil {
+			atomic.AddInt64(&w.persona.sent, 1)
+		}
+		if ps.stream != nil {
+			ps.stream.Emit(txEvent(EventSent, w, signedTx, attempt+1))
+		}
+		w.recordSent(signedTx.Hash())
+		if ps.config.VerifySampleRate > 0 && sent%int64(ps.config.VerifySampleRate) == 0 {
+			ps.verifying.Add(1)
+			go ps.verifyTransaction(signedTx.Hash(), w.Address)
+		}
 		return
 	}
 
 	// All retries failed
 	ps.recordError(fmt.Errorf("wallet %s: transaction failed after %d retries: %w", w.Address.Hex(), ps.config.MaxRetries, lastErr))
+	ps.emit(TxEvent{Event: EventFailed, Wallet: &w.Index, From: w.Address, Error: lastErr.Error()})
+	ps.countFailure(recipient)
+}
+
+// releaseNonce hands back the nonce of a transaction that was given up on. When
+// later nonces are already out, it stays a gap that holds back this wallet's later
+// transactions, and is recorded as an error.
+func (ps *ParallelSender) releaseNonce(w *ParallelWallet, nonce uint64) {
+	if !w.NonceManager.Rollback(nonce) {
+		ps.recordError(fmt.Errorf("wallet %s: nonce %d was given up on after later nonces were handed out, leaving a gap", w.Address.Hex(), nonce))
+	}
+}
+
+// countFailure counts a transaction to recipient that was given up on
+func (ps *ParallelSender) countFailure(recipient common.Address) {
 	atomic.AddInt64(&ps.totalFailed, 1)
+	ps.checkFailureRate(true)
+	if ps.contracts != nil && ps.contracts.Watches(recipient) {
+		ps.contracts.RecordSendFailure(recipient)
+	}
+}
+
+// checkFailureRate records the outcome of a transaction with the failure guard and
+// aborts the run when it trips
+func (ps *ParallelSender) checkFailureRate(failed bool) {
+	if ps.guard == nil {
+		return
+	}
+	if tripped, rate := ps.guard.record(failed); tripped {
+		fmt.Printf("Aborting: %.1f%% of the last %d transactions failed, above MAX_FAILURE_RATE %.1f%%\n",
+			rate*100, ps.config.FailureRateWindow, ps.config.MaxFailureRate*100)
+		ps.abort()
+	}
 }
 
-// verifyTransaction verifies that a transaction was accepted into the mempool
-func (ps *ParallelSender) verifyTransaction(ctx context.Context, txHash common.Hash, walletAddr common.Address) {
-	// Wait a bit for transaction to be accepted
-	time.Sleep(500 * time.Millisecond)
+// nextTarget picks the destination and calldata for the next transaction: init code
+// when InitCode is set, a random contract with generated calldata when Contracts is set,
+// otherwise a random recipient
+func (ps *ParallelSender) nextTarget(w *ParallelWallet, rng *rand.Rand) (common.Address, []byte, error) {
+	if ps.config.InitCode != nil {
+		code, err := ps.config.InitCode(rng)
+		return common.Address{}, code, err
+	}
+	if len(ps.config.Contracts) == 0 || (len(ps.recipients) > 0 && !w.persona.call(rng)) {
+		if ps.config.GasOnly {
+			return w.Address, ps.payload(w), nil
+		}
+		return ps.recipients[pick(rng, ps.recipientZipf, len(ps.recipients))], ps.payload(w), nil
+	}
 
-	// Check if transaction is pending
-	_, isPending, err := ps.client.TransactionByHash(ctx, txHash)
-	if err == nil && !isPending {
-		// Transaction was mined
-		atomic.AddInt64(&ps.totalSucceeded, 1)
-	} else if err == nil && isPending {
-		// Transaction is pending - consider it successful
+	target := ps.config.Contracts[pick(rng, ps.contractZipf, len(ps.config.Contracts))]
+	if ps.config.Calldata == nil {
+		return target, ps.payload(w), nil
+	}
+	data, err := ps.config.Calldata(rng)
+	if err != nil {
+		return common.Address{}, nil, err
+	}
+	return target, data, nil
+}
+
+// pick returns a target index below n: uniformly, or by Zipf popularity so that
+// index 0 is the hottest target and the tail is rarely touched
+func pick(rng *rand.Rand, zipf *rand.Zipf, n int) int {
+	if zipf == nil {
+		return rng.Intn(n)
+	}
+	return int(zipf.Uint64())
+}
+
+// newZipf returns a Zipf generator of indexes below n, nil when there is no choice to make
+func newZipf(rng *rand.Rand, exponent float64, n int) *rand.Zipf {
+	if n <= 1 {
+		return nil
+	}
+	return rand.NewZipf(rng, exponent, 1, uint64(n-1))
+}
+
+// lockedSource is a rand.Source safe for concurrent use, for a *rand.Rand shared by goroutines
+type lockedSource struct {
+	mu  sync.Mutex
+	src rand.Source64
+}
+
+// newLockedSource returns a locked source seeded with seed
+func newLockedSource(seed int64) *lockedSource {
+	return &lockedSource{src: rand.NewSource(seed).(rand.Source64)}
+}
+
+func (s *lockedSource) Int63() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.src.Int63()
+}
+
+func (s *lockedSource) Uint64() uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.src.Uint64()
+}
+
+func (s *lockedSource) Seed(seed int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.src.Seed(seed)
+}
+
+// value returns the value sent with each transaction, zero in gas-only mode
+func (ps *ParallelSender) value() *big.Int {
+	if ps.config.GasOnly {
+		return big.NewInt(0)
+	}
+	return ps.config.Value
+}
+
+// payload returns the data for a wallet's next transfer, tagged when DataTemplate is set
+// and prefixed with a memo when Memo is set
+func (ps *ParallelSender) payload(w *ParallelWallet) []byte {
+	if ps.config.DataTemplate == "" && !ps.config.Memo {
+		return ps.config.Data
+	}
+	seq := atomic.AddUint64(&w.sequence, 1)
+
+	data := ps.config.Data
+	if ps.config.DataTemplate != "" {
+		data = RenderData(ps.config.DataTemplate, w.Index, w.Address, seq)
+	}
+	if !ps.config.Memo {
+		return data
+	}
+	memo := Memo{RunID: ps.config.RunID, WalletIndex: uint32(w.Index), Sequence: seq}
+	return append(memo.Encode(), data...)
+}
+
+// verifyTransaction counts a transaction as succeeded once it reaches the configured
+// confirmation level. A transaction that does not get there in time is not counted but
+// not failed either, since it may still be processing; one that reverted is recorded
+// as an error. It waits at most the confirmation timeout, or defaultVerifyTimeout
+// when the strategy has none, independently of the run's context.
+func (ps *ParallelSender) verifyTransaction(txHash common.Hash, walletAddr common.Address) {
+	defer ps.verifying.Done()
+	ctx := context.Background()
+	if ps.config.Confirmation.Timeout <= 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, defaultVerifyTimeout)
+		defer cancel()
+	}
+	err := ps.config.Confirmation.Wait(ctx, ps.client, txHash)
+	switch {
+	case err == nil:
 		atomic.AddInt64(&ps.totalSucceeded, 1)
+		if ps.config.DropTimeout > 0 {
+			ps.tracker.MarkVerified(txHash)
+		}
+	case errors.Is(err, ErrReverted):
+		ps.recordError(fmt.Errorf("wallet %s: %w", walletAddr.Hex(), err))
 	}
-	// If error, we don't increment succeeded but also don't fail - transaction might still be processing
+}
+
+// onDropped takes back the success of a dropped transaction the confirmation check
+// had already counted, such as one seen in the mempool that later vanished
+func (ps *ParallelSender) onDropped(tracked *TrackedTx) {
+	if tracked.verified {
+		atomic.AddInt64(&ps.totalSucceeded, -1)
+	}
+	hash, nonce := tracked.Hash, tracked.Nonce
+	ps.emit(TxEvent{Event: EventDropped, From: tracked.From, Hash: &hash, Nonce: &nonce})
 }
 
 // recordError records an error (thread-safe)
@@ -303,21 +857,97 @@ func (ps *ParallelSender) GetMetrics() (sent, succeeded, failed int64, errors []
 	return atomic.LoadInt64(&ps.totalSent), atomic.LoadInt64(&ps.totalSucceeded), atomic.LoadInt64(&ps.totalFailed), errorCopy
 }
 
+// AcceptanceCounts returns how many transactions were submitted to the node,
+// including retries, how many it accepted and refused, and how many of the
+// accepted ones were included. included is -1 when inclusion is not tracked.
+func (ps *ParallelSender) AcceptanceCounts() (submitted, accepted, rejected, included int64) {
+	included = -1
+	if ps.tracker != nil {
+		included = ps.tracker.Mined()
+	}
+	return atomic.LoadInt64(&ps.totalSubmitted), atomic.LoadInt64(&ps.totalSent), atomic.LoadInt64(&ps.totalRejected), included
+}
+
+// PendingAges returns how long each transaction not yet included has been waiting,
+// or nil when inclusion is not tracked
+func (ps *ParallelSender) PendingAges() []time.Duration {
+	if ps.tracker == nil {
+		return nil
+	}
+	return ps.tracker.PendingAges(time.Now())
+}
+
+// Positions returns where the run's transactions landed within their blocks, or nil
+// when inclusion is not tracked
+func (ps *ParallelSender) Positions() *PositionReport {
+	if ps.tracker == nil {
+		return nil
+	}
+	return ps.tracker.Positions()
+}
+
+// Stats returns a snapshot of the run counters, suitable for publishing through expvar
+func (ps *ParallelSender) Stats() map[string]int64 {
+	stats := map[string]int64{
+		"sent":      atomic.LoadInt64(&ps.totalSent),
+		"succeeded": atomic.LoadInt64(&ps.totalSucceeded),
+		"failed":    atomic.LoadInt64(&ps.totalFailed),
+		"reserved":  atomic.LoadInt64(&ps.totalReserved),
+		"submitted": atomic.LoadInt64(&ps.totalSubmitted),
+		"rejected":  atomic.LoadInt64(&ps.totalRejected),
+	}
+	if ps.tracker != nil {
+		stats["inFlight"] = int64(ps.tracker.InFlight())
+	}
+	if ps.config.ExpectedEvents != nil {
+		checked, failed, _ := ps.config.ExpectedEvents.Results()
+		stats["eventsChecked"] = checked
+		stats["eventAssertionsFailed"] = failed
+	}
+	return stats
+}
+
 // printSummary prints a summary of transactions sent
 func (ps *ParallelSender) printSummary() {
 	sent, succeeded, failed, errors := ps.GetMetrics()
 	fmt.Printf("\n=== Transaction Summary ===\n")
 	fmt.Printf("Total sent: %d\n", sent)
-	fmt.Printf("Succeeded: %d\n", succeeded)
+	if ps.config.VerifySampleRate > 0 {
+		fmt.Printf("Succeeded: %d (verified 1 in %d, %s)\n", succeeded, ps.config.VerifySampleRate, ps.config.Confirmation)
+	} else {
+		fmt.Printf("Succeeded: verification disabled\n")
+	}
 	fmt.Printf("Failed: %d\n", failed)
-	if len(errors) > 0 && len(errors) <= 10 {
-		fmt.Printf("\nRecent errors:\n")
-		for _, err := range errors[len(errors)-10:] {
-			fmt.Printf("  - %s\n", err.Error())
+	if ps.config.Personas != nil {
+		for _, count := range ps.config.Personas.PersonaCounts() {
+			fmt.Printf("  %s: %d sent\n", count.Name, count.Sent)
+		}
+	}
+	externalWallets := 0
+	for _, w := range ps.wallets {
+		if w.NonceManager.ExternalActivityCount() > 0 {
+			externalWallets++
+		}
+	}
+	if externalWallets > 0 {
+		fmt.Printf("Wallets with external nonce activity: %d\n", externalWallets)
+	}
+	if ps.config.ExpectedEvents != nil {
+		checked, failed, samples := ps.config.ExpectedEvents.Results()
+		fmt.Printf("Event assertions: %d checked, %d failed\n", checked, failed)
+		for _, sample := range samples {
+			fmt.Printf("  - %s\n", sample)
+		}
+	}
+	if len(errors) > 0 {
+		start := 0
+		if len(errors) > 10 {
+			start = len(errors) - 10
+			fmt.Printf("\nShowing last 10 of %d errors:\n", len(errors))
+		} else {
+			fmt.Printf("\nRecent errors:\n")
 		}
-	} else if len(errors) > 10 {
-		fmt.Printf("\nShowing last 10 of %d errors:\n", len(errors))
-		for _, err := range errors[len(errors)-10:] {
+		for _, err := range errors[start:] {
 			fmt.Printf("  - %s\n", err.Error())
 		}
 	}
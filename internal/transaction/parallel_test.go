@@ -1,8 +1,17 @@
 package transaction
 
 import (
+	"context"
+	"errors"
 	"math/big"
+	"math/rand"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
 )
 
 func TestParallelConfig(t *testing.T) {
@@ -28,3 +37,352 @@ func TestParallelConfig(t *testing.T) {
 	})
 }
 
+func TestPrintSummaryFewErrors(t *testing.T) {
+	// Fewer than 10 recorded errors must not slice before the start of the list
+	ps := &ParallelSender{config: &ParallelConfig{}}
+	for i := 0; i < 3; i++ {
+		ps.recordError(errors.New("send failed"))
+	}
+	ps.printSummary()
+}
+
+func TestTransactionLimit(t *testing.T) {
+	t.Run("Capped", func(t *testing.T) {
+		if !WithinLimit(9, 10) || WithinLimit(10, 10) {
+			t.Error("WithinLimit should allow exactly 10 transactions")
+		}
+		if !IsLast(9, 10) || IsLast(8, 10) {
+			t.Error("IsLast should only be true for the 10th transaction")
+		}
+	})
+
+	t.Run("Unlimited", func(t *testing.T) {
+		if !WithinLimit(1000000, Unlimited) {
+			t.Error("Unlimited should never stop")
+		}
+		if IsLast(1000000, Unlimited) {
+			t.Error("Unlimited should have no last transaction")
+		}
+	})
+}
+
+func TestRenderData(t *testing.T) {
+	t.Run("Placeholders", func(t *testing.T) {
+		got := string(RenderData("w{wallet}-s{seq}", 7, common.Address{}, 42))
+		if got != "w7-s42" {
+			t.Errorf("expected w7-s42, got %s", got)
+		}
+	})
+
+	t.Run("PlainText", func(t *testing.T) {
+		got := string(RenderData("hello", 1, common.Address{}, 1))
+		if got != "hello" {
+			t.Errorf("expected hello, got %s", got)
+		}
+	})
+}
+
+func TestLockedSource(t *testing.T) {
+	rng := rand.New(newLockedSource(1))
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 1000; i++ {
+				rng.Intn(100)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestTrackerSlots(t *testing.T) {
+	from := common.Address{0x01}
+	newTx := func(nonce uint64) *types.Transaction {
+		return types.NewTransaction(nonce, common.Address{0x02}, big.NewInt(0), 21000, big.NewInt(1e9), nil)
+	}
+
+	t.Run("CapsAreNotOvershot", func(t *testing.T) {
+		tracker := NewTracker(nil)
+		var wg sync.WaitGroup
+		var taken int64
+		ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+		defer cancel()
+		for i := 0; i < 20; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if tracker.Acquire(ctx, from, 5, 0) == nil {
+					atomic.AddInt64(&taken, 1)
+				}
+			}()
+		}
+		wg.Wait()
+		if taken != 5 || tracker.InFlight() != 5 {
+			t.Errorf("expected 5 slots taken, got %d with %d in flight", taken, tracker.InFlight())
+		}
+	})
+
+	t.Run("RefusedSendsKeepTheirSlot", func(t *testing.T) {
+		tracker := NewTracker(nil)
+		tracker.Acquire(context.Background(), from, 0, 1)
+		tx := newTx(0)
+		tracker.Track(tx, from)
+		tracker.Untrack(tx.Hash())
+		if tracker.InFlightFor(from) != 1 || len(tracker.pending) != 0 {
+			t.Fatalf("expected the slot to be held with nothing pending, got %d and %d", tracker.InFlightFor(from), len(tracker.pending))
+		}
+		tracker.Release(from)
+		if tracker.InFlightFor(from) != 0 {
+			t.Errorf("expected the slot to be freed, got %d in flight", tracker.InFlightFor(from))
+		}
+	})
+
+	t.Run("SupersededByMinedNonce", func(t *testing.T) {
+		tracker := NewTracker(newFakeClient(t, &fakeEth{mined: 2}))
+		for nonce := uint64(0); nonce < 3; nonce++ {
+			tracker.Acquire(context.Background(), from, 0, 0)
+			tracker.Track(newTx(nonce), from)
+		}
+		tracker.settle(context.Background(), time.Now())
+		if tracker.InFlight() != 3 {
+			t.Fatalf("expected recent transactions to be left alone, got %d in flight", tracker.InFlight())
+		}
+		tracker.settle(context.Background(), time.Now().Add(settleAge))
+		if tracker.InFlight() != 1 || tracker.Superseded() != 2 {
+			t.Errorf("expected nonces 0 and 1 to be superseded, got %d in flight and %d superseded", tracker.InFlight(), tracker.Superseded())
+		}
+	})
+
+	t.Run("StoppedTrackerRefusesSlots", func(t *testing.T) {
+		tracker := NewTracker(newFakeClient(t, &fakeEth{head: 7}))
+		if err := tracker.Start(context.Background()); err != nil || tracker.lastBlock != 7 {
+			t.Fatalf("expected the scan to start at head 7, got %d, %v", tracker.lastBlock, err)
+		}
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		tracker.Run(ctx, time.Millisecond)
+		if err := tracker.Acquire(context.Background(), from, 0, 0); !errors.Is(err, ErrTrackerStopped) {
+			t.Errorf("expected ErrTrackerStopped, got %v", err)
+		}
+	})
+}
+
+func TestLiveSettings(t *testing.T) {
+	templates, err := NewTemplateSet([]TxTemplate{{To: "0x0000000000000000000000000000000000000001"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ps := NewParallelSender(nil, big.NewInt(1337), nil, nil, &ParallelConfig{RateLimit: 10, Builder: templates})
+
+	if price := ps.capGasPrice(big.NewInt(500)); price.Int64() != 500 {
+		t.Errorf("expected an uncapped gas price, got %s", price)
+	}
+
+	reweighted, _ := NewTemplateSet([]TxTemplate{{To: "0x0000000000000000000000000000000000000002", Weight: 3}})
+	ps.Apply(&LiveSettings{RateLimit: 25, MaxGasPrice: big.NewInt(100), Builder: reweighted})
+	if ps.Rate() != 25 {
+		t.Errorf("expected rate 25, got %v", ps.Rate())
+	}
+	if price := ps.capGasPrice(big.NewInt(500)); price.Int64() != 100 {
+		t.Errorf("expected the gas price capped to 100, got %s", price)
+	}
+	if ps.currentBuilder() != TxBuilder(reweighted) {
+		t.Error("expected the reloaded templates to replace the running ones")
+	}
+
+	builtin := NewParallelSender(nil, big.NewInt(1337), nil, nil, &ParallelConfig{})
+	builtin.Apply(&LiveSettings{Builder: reweighted})
+	if builtin.currentBuilder() != nil {
+		t.Error("expected a run on the built-in workload to keep it")
+	}
+
+	controlled := NewParallelSender(nil, big.NewInt(1337), nil, nil, &ParallelConfig{})
+	controlled.SetRate(40)
+	controlled.Apply(&LiveSettings{})
+	controlled.Apply(&LiveSettings{RateLimit: 5})
+	if controlled.Rate() != 40 {
+		t.Errorf("expected the controller's rate 40 to survive reloads, got %v", controlled.Rate())
+	}
+}
+
+func TestThinkTime(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+
+	t.Run("Distributions", func(t *testing.T) {
+		fixed, err := ParseThinkTime("fixed:250ms")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if d := fixed.Sample(rng); d != 250*time.Millisecond {
+			t.Errorf("expected 250ms, got %s", d)
+		}
+
+		uniform, err := ParseThinkTime("uniform:100ms-200ms")
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i := 0; i < 100; i++ {
+			if d := uniform.Sample(rng); d < 100*time.Millisecond || d > 200*time.Millisecond {
+				t.Fatalf("uniform sample %s outside [100ms, 200ms]", d)
+			}
+		}
+
+		exponential, err := ParseThinkTime("exponential:1s")
+		if err != nil {
+			t.Fatal(err)
+		}
+		var total time.Duration
+		for i := 0; i < 10000; i++ {
+			total += exponential.Sample(rng)
+		}
+		if mean := total / 10000; mean < 900*time.Millisecond || mean > 1100*time.Millisecond {
+			t.Errorf("expected a mean near 1s, got %s", mean)
+		}
+	})
+
+	t.Run("RejectsBadSpecs", func(t *testing.T) {
+		for _, spec := range []string{"500ms", "poisson:1s", "uniform:2s-1s", "uniform:1s", "fixed:-1s", "exponential:soon"} {
+			if _, err := ParseThinkTime(spec); err == nil {
+				t.Errorf("expected an error for %q", spec)
+			}
+		}
+	})
+}
+
+func TestPersonas(t *testing.T) {
+	calls := 0
+	set, err := NewPersonaSet([]*Persona{
+		{Name: "whale", Percent: 10, Value: "random(1000,2000)", GasPercent: 150},
+		{Name: "spammer", Percent: 20, ThinkTime: "fixed:0s", CallPercent: &calls},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("AssignsShares", func(t *testing.T) {
+		counts := map[string]int{}
+		for _, p := range set.assign(100) {
+			name := "default"
+			if p != nil {
+				name = p.Name
+			}
+			counts[name]++
+		}
+		if counts["whale"] != 10 || counts["spammer"] != 20 || counts["default"] != 70 {
+			t.Errorf("expected 10/20/70 wallets, got %v", counts)
+		}
+	})
+
+	t.Run("AppliesBehavior", func(t *testing.T) {
+		rng := rand.New(rand.NewSource(1))
+		whale, spammer := set.personas[0], set.personas[1]
+		if v := whale.value(rng, big.NewInt(1)); v.Int64() < 1000 || v.Int64() > 2000 {
+			t.Errorf("expected a whale value in [1000, 2000], got %s", v)
+		}
+		if p := whale.gasPrice(big.NewInt(100)); p.Int64() != 150 {
+			t.Errorf("expected gas price 150, got %s", p)
+		}
+		if spammer.call(rng) {
+			t.Error("expected a persona with callPercent 0 to never call contracts")
+		}
+		var none *Persona
+		if v := none.value(rng, big.NewInt(7)); v.Int64() != 7 || !none.call(rng) {
+			t.Error("expected wallets without a persona to keep the run's settings")
+		}
+	})
+
+	t.Run("RejectsBadPersonas", func(t *testing.T) {
+		if _, err := NewPersonaSet([]*Persona{{Percent: 60}, {Percent: 50}}); err == nil {
+			t.Error("expected an error when percentages exceed 100")
+		}
+		if _, err := NewPersonaSet([]*Persona{{Percent: 10, ThinkTime: "sometimes"}}); err == nil {
+			t.Error("expected an error for an invalid think time")
+		}
+	})
+}
+
+func TestZipfTargets(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	counts := make([]int, 100)
+	recipients := make([]common.Address, len(counts))
+	ps := NewParallelSender(nil, big.NewInt(1337), nil, recipients, &ParallelConfig{ZipfExponent: 1.2})
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	for g := 0; g < 4; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 2500; i++ {
+				index := pick(rng, ps.recipientZipf, len(counts))
+				mu.Lock()
+				counts[index]++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	if counts[0] <= counts[1] || counts[1] <= counts[10] || counts[10] <= counts[99] {
+		t.Errorf("expected popularity to fall with rank, got %d, %d, %d, %d", counts[0], counts[1], counts[10], counts[99])
+	}
+	if counts[0] < 2000 {
+		t.Errorf("expected the hottest target to take a large share, got %d of 10000", counts[0])
+	}
+
+	single := NewParallelSender(nil, big.NewInt(1337), nil, recipients[:1], &ParallelConfig{ZipfExponent: 1.2})
+	if single.recipientZipf != nil || pick(rng, single.recipientZipf, 1) != 0 {
+		t.Error("expected index 0 without a generator for a single target")
+	}
+}
+
+func TestPositionHistogram(t *testing.T) {
+	var h positionHistogram
+	h.add([]int{0, 1}, 2) // A block of only ours
+	h.add([]int{9}, 10)   // Last in a block of 10
+	h.add(nil, 50)        // A block without ours is not counted
+	r := h.report()
+	if r.Transactions != 3 || r.Blocks != 2 || r.OnlyOurs != 1 {
+		t.Fatalf("expected 3 transactions in 2 blocks, 1 entirely ours, got %+v", r)
+	}
+	if r.Deciles[0] != 1 || r.Deciles[5] != 1 || r.Deciles[9] != 1 {
+		t.Errorf("unexpected position deciles: %v", r.Deciles)
+	}
+	if r.MedianIndex != 1 || r.MaxIndex != 9 || r.OurShare != 0.25 {
+		t.Errorf("expected median 1, max 9 and a 25%% share, got %+v", r)
+	}
+}
+
+func TestTrackerDrops(t *testing.T) {
+	tracker := NewTracker(nil)
+	var dropped []*TrackedTx
+	tracker.SetDropTimeout(time.Minute, func(tracked *TrackedTx) { dropped = append(dropped, tracked) })
+
+	from := common.Address{0x01}
+	cheap := types.NewTransaction(0, common.Address{0x02}, big.NewInt(0), 21000, big.NewInt(1e9), nil)
+	verified := types.NewTransaction(1, common.Address{0x02}, big.NewInt(0), 21000, big.NewInt(1e9), nil)
+	recent := types.NewTransaction(2, common.Address{0x02}, big.NewInt(0), 21000, big.NewInt(2e9), nil)
+	for _, tx := range []*types.Transaction{cheap, verified, recent} {
+		if err := tracker.Acquire(context.Background(), from, 0, 0); err != nil {
+			t.Fatal(err)
+		}
+		tracker.Track(tx, from)
+	}
+	tracker.MarkVerified(verified.Hash())
+	tracker.pending[recent.Hash()].SentAt = time.Now().Add(2 * time.Minute)
+
+	tracker.expire(time.Now().Add(time.Minute))
+	if len(dropped) != 2 || tracker.InFlight() != 1 || tracker.InFlightFor(from) != 1 {
+		t.Fatalf("expected 2 dropped and 1 still in flight, got %d and %d", len(dropped), tracker.InFlight())
+	}
+	r := tracker.Drops()
+	if r.Dropped != 2 || r.Verified != 1 {
+		t.Errorf("expected 2 drops, 1 of them verified, got %+v", r)
+	}
+	if len(r.ByGasPrice) != 1 || r.ByGasPrice[0].Key != "1.00 gwei" || r.ByGasPrice[0].Count != 2 {
+		t.Errorf("unexpected drops by gas price: %v", r.ByGasPrice)
+	}
+	if len(r.ByWallet) != 1 || r.ByWallet[0].Key != from.Hex() {
+		t.Errorf("unexpected drops by wallet: %v", r.ByWallet)
+	}
+}
@@ -0,0 +1,20 @@
+package transaction
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// RenderData expands a data template for one transaction so it can be attributed to
+// its worker from a block explorer. Supported placeholders are {wallet} (pool index),
+// {address} (sender address) and {seq} (per-wallet sequence number).
+func RenderData(template string, walletIndex int, address common.Address, seq uint64) []byte {
+	replacer := strings.NewReplacer(
+		"{wallet}", strconv.Itoa(walletIndex),
+		"{address}", address.Hex(),
+		"{seq}", strconv.FormatUint(seq, 10),
+	)
+	return []byte(replacer.Replace(template))
+}
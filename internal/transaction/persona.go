@@ -0,0 +1,165 @@
+package transaction
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"math/rand"
+	"os"
+	"sync/atomic"
+)
+
+// Persona is a behavior profile given to a share of the wallet pool, e.g. a few
+// whales moving large values, many regular users and a handful of spammers.
+// Empty fields keep the run's own settings.
+type Persona struct {
+	Name        string `json:"name"`
+	Percent     int    `json:"percent"`     // Share of the wallet pool
+	ThinkTime   string `json:"thinkTime"`   // Pause after each transaction, see ParseThinkTime
+	Value       string `json:"value"`       // Wei amount or random(min,max)
+	GasPercent  int    `json:"gasPercent"`  // Gas price as a percentage of the suggested one, 0 = 100
+	CallPercent *int   `json:"callPercent"` // Share of transactions calling the run's contracts instead of transferring
+
+	think *ThinkTime
+	sent  int64
+}
+
+// PersonaSet assigns personas to wallets by their index in the pool
+type PersonaSet struct {
+	personas []*Persona
+}
+
+// LoadPersonas reads a JSON array of personas from path
+func LoadPersonas(path string) (*PersonaSet, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read persona file: %w", err)
+	}
+	var personas []*Persona
+	if err := json.Unmarshal(data, &personas); err != nil {
+		return nil, fmt.Errorf("failed to decode persona file: %w", err)
+	}
+	return NewPersonaSet(personas)
+}
+
+// NewPersonaSet validates personas. Their percentages may add up to less than 100;
+// the remaining wallets keep the run's own settings.
+func NewPersonaSet(personas []*Persona) (*PersonaSet, error) {
+	if len(personas) == 0 {
+		return nil, errors.New("no personas defined")
+	}
+	total := 0
+	for i, p := range personas {
+		if p.Name == "" {
+			p.Name = fmt.Sprintf("persona %d", i)
+		}
+		if p.Percent <= 0 {
+			return nil, fmt.Errorf("%s: percent must be greater than 0", p.Name)
+		}
+		total += p.Percent
+		if p.ThinkTime != "" {
+			think, err := ParseThinkTime(p.ThinkTime)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", p.Name, err)
+			}
+			p.think = think
+		}
+		if p.Value != "" {
+			probe := &templateContext{rng: rand.New(rand.NewSource(1))}
+			if _, err := probe.value(p.Value); err != nil {
+				return nil, fmt.Errorf("%s: invalid value: %w", p.Name, err)
+			}
+		}
+		if p.GasPercent < 0 {
+			return nil, fmt.Errorf("%s: gasPercent cannot be negative", p.Name)
+		}
+		if p.CallPercent != nil && (*p.CallPercent < 0 || *p.CallPercent > 100) {
+			return nil, fmt.Errorf("%s: callPercent must be between 0 and 100", p.Name)
+		}
+	}
+	if total > 100 {
+		return nil, fmt.Errorf("persona percentages add up to %d, more than 100", total)
+	}
+	return &PersonaSet{personas: personas}, nil
+}
+
+// UsesCalls reports whether any persona sets callPercent
+func (s *PersonaSet) UsesCalls() bool {
+	for _, p := range s.personas {
+		if p.CallPercent != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// assign returns the persona of each of n wallets, nil for wallets without one.
+// Personas get consecutive blocks of the pool in the order they are defined.
+func (s *PersonaSet) assign(n int) []*Persona {
+	assigned := make([]*Persona, n)
+	start, cumulative := 0, 0
+	for _, p := range s.personas {
+		cumulative += p.Percent
+		end := cumulative * n / 100
+		for i := start; i < end; i++ {
+			assigned[i] = p
+		}
+		start = end
+	}
+	return assigned
+}
+
+// thinkTime returns the wallet's pause distribution, falling back to the run's
+func (p *Persona) thinkTime(fallback *ThinkTime) *ThinkTime {
+	if p == nil || p.think == nil {
+		return fallback
+	}
+	return p.think
+}
+
+// value returns the wallet's next transfer value, falling back to the run's
+func (p *Persona) value(rng *rand.Rand, fallback *big.Int) *big.Int {
+	if p == nil || p.Value == "" {
+		return fallback
+	}
+	value, err := (&templateContext{rng: rng}).value(p.Value)
+	if err != nil {
+		return fallback // Validated by NewPersonaSet
+	}
+	return value
+}
+
+// gasPrice scales the gas price by the persona's gasPercent
+func (p *Persona) gasPrice(price *big.Int) *big.Int {
+	if p == nil || p.GasPercent == 0 || p.GasPercent == 100 {
+		return price
+	}
+	scaled := new(big.Int).Mul(price, big.NewInt(int64(p.GasPercent)))
+	return scaled.Div(scaled, big.NewInt(100))
+}
+
+// call reports whether the wallet's next transaction calls a contract rather
+// than transferring, when the run has contracts to call
+func (p *Persona) call(rng *rand.Rand) bool {
+	if p == nil || p.CallPercent == nil {
+		return true
+	}
+	return rng.Intn(100) < *p.CallPercent
+}
+
+// PersonaCounts returns how many transactions each persona's wallets sent, in the
+// order the personas are defined
+func (s *PersonaSet) PersonaCounts() []PersonaCount {
+	counts := make([]PersonaCount, len(s.personas))
+	for i, p := range s.personas {
+		counts[i] = PersonaCount{Name: p.Name, Sent: atomic.LoadInt64(&p.sent)}
+	}
+	return counts
+}
+
+// PersonaCount is the number of transactions sent by one persona's wallets
+type PersonaCount struct {
+	Name string
+	Sent int64
+}
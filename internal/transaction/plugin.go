@@ -0,0 +1,39 @@
+package transaction
+
+import (
+	"math/big"
+	"math/rand"
+
+	"github.com/aakash4dev/ethereum-transaction-simulator/pkg/plugin"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// FeeStrategy picks the gas price of each parallel-mode transaction from the node's
+// suggestion. plugin.FeeStrategy satisfies it.
+type FeeStrategy interface {
+	GasPrice(suggested *big.Int) (*big.Int, error)
+}
+
+// pluginWorkload adapts a plugin workload to TxBuilder
+type pluginWorkload struct {
+	name     string
+	workload plugin.Workload
+}
+
+// NewPluginWorkload returns a TxBuilder that asks the named plugin workload for every transaction
+func NewPluginWorkload(name string, workload plugin.Workload) TxBuilder {
+	return &pluginWorkload{name: name, workload: workload}
+}
+
+// Build asks the plugin for the next transaction of a wallet
+func (p *pluginWorkload) Build(rng *rand.Rand, walletIndex int, address common.Address, seq uint64) (*TemplateTx, error) {
+	tx, err := p.workload.Build(plugin.TxRequest{Wallet: walletIndex, Address: address, Seq: seq, Seed: rng.Int63()})
+	if err != nil {
+		return nil, err
+	}
+	value := tx.Value
+	if value == nil {
+		value = new(big.Int)
+	}
+	return &TemplateTx{Template: p.name, To: tx.To, Value: value, Data: tx.Data, GasLimit: tx.GasLimit}, nil
+}
@@ -0,0 +1,114 @@
+package transaction
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// ErrAddressNotAllowed is returned for a transaction to an address the policy forbids
+var ErrAddressNotAllowed = errors.New("recipient not allowed by address policy")
+
+// AddressPolicy decides which addresses transactions may be sent to. The denylist
+// always wins; a non-empty allowlist permits only the addresses on it.
+type AddressPolicy struct {
+	allow map[common.Address]bool
+	deny  map[common.Address]bool
+}
+
+// NewAddressPolicy creates a policy from an allowlist and a denylist, either of which may be empty
+func NewAddressPolicy(allow, deny []common.Address) *AddressPolicy {
+	p := &AddressPolicy{allow: make(map[common.Address]bool), deny: make(map[common.Address]bool)}
+	for _, address := range allow {
+		p.allow[address] = true
+	}
+	for _, address := range deny {
+		p.deny[address] = true
+	}
+	return p
+}
+
+// Denied reports whether address is on the denylist
+func (p *AddressPolicy) Denied(address common.Address) bool {
+	return p.deny[address]
+}
+
+// Allowed reports whether transactions may be sent to address
+func (p *AddressPolicy) Allowed(address common.Address) bool {
+	if p.deny[address] {
+		return false
+	}
+	return len(p.allow) == 0 || p.allow[address]
+}
+
+// Check returns an error wrapping ErrAddressNotAllowed when address is not allowed
+func (p *AddressPolicy) Check(address common.Address) error {
+	if !p.Allowed(address) {
+		return fmt.Errorf("%w: %s", ErrAddressNotAllowed, address.Hex())
+	}
+	return nil
+}
+
+// PolicySubmitter refuses to submit transactions whose recipient the policy forbids.
+// Besides the allowlist it permits the run's own addresses, as they touch nothing
+// outside the run: the sender itself, contracts this submitter deployed and wallets
+// registered with AllowOwn. Denied addresses are refused regardless.
+type PolicySubmitter struct {
+	next    Submitter
+	policy  *AddressPolicy
+	chainID *big.Int
+
+	mu  sync.Mutex
+	own map[common.Address]bool // Deployed contracts and registered wallets
+}
+
+// NewPolicySubmitter wraps next with policy
+func NewPolicySubmitter(next Submitter, policy *AddressPolicy, chainID *big.Int) *PolicySubmitter {
+	return &PolicySubmitter{next: next, policy: policy, chainID: chainID, own: make(map[common.Address]bool)}
+}
+
+// AllowOwn registers the run's own wallets, e.g. the worker wallets it funds
+func (s *PolicySubmitter) AllowOwn(addresses ...common.Address) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, address := range addresses {
+		s.own[address] = true
+	}
+}
+
+// SendTransaction submits tx if its recipient is allowed
+func (s *PolicySubmitter) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	to := tx.To()
+	if to != nil && !s.policy.Allowed(*to) && (s.policy.Denied(*to) || !s.ownAddress(tx, *to)) {
+		return fmt.Errorf("%w: %s", ErrAddressNotAllowed, to.Hex())
+	}
+	if err := s.next.SendTransaction(ctx, tx); err != nil {
+		return err
+	}
+	if to == nil {
+		if from, err := types.Sender(SignerFor(s.chainID), tx); err == nil {
+			s.mu.Lock()
+			s.own[crypto.CreateAddress(from, tx.Nonce())] = true
+			s.mu.Unlock()
+		}
+	}
+	return nil
+}
+
+// ownAddress reports whether to is the sender of tx or one of the run's own addresses
+func (s *PolicySubmitter) ownAddress(tx *types.Transaction, to common.Address) bool {
+	s.mu.Lock()
+	own := s.own[to]
+	s.mu.Unlock()
+	if own {
+		return true
+	}
+	from, err := types.Sender(SignerFor(s.chainID), tx)
+	return err == nil && from == to
+}
@@ -0,0 +1,32 @@
+package transaction
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestAddressPolicy(t *testing.T) {
+	allowed, denied, other := common.Address{0x01}, common.Address{0x02}, common.Address{0x03}
+
+	t.Run("DenylistOnly", func(t *testing.T) {
+		p := NewAddressPolicy(nil, []common.Address{denied})
+		if !p.Allowed(other) || p.Allowed(denied) {
+			t.Error("expected only the denied address to be refused")
+		}
+	})
+
+	t.Run("AllowlistAndDenylist", func(t *testing.T) {
+		p := NewAddressPolicy([]common.Address{allowed, denied}, []common.Address{denied})
+		if !p.Allowed(allowed) {
+			t.Error("expected the allowlisted address to be allowed")
+		}
+		if p.Allowed(other) {
+			t.Error("expected an address off the allowlist to be refused")
+		}
+		if err := p.Check(denied); !errors.Is(err, ErrAddressNotAllowed) {
+			t.Errorf("expected the denylist to win, got %v", err)
+		}
+	})
+}
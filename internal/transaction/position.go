@@ -0,0 +1,88 @@
+package transaction
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// PositionReport describes where the run's transactions landed within their blocks.
+// Transactions spread through blocks full of other traffic mean the benchmark
+// competed for block space; blocks that are entirely ours mean it ran alone.
+type PositionReport struct {
+	Transactions int     // Included transactions
+	Blocks       int     // Blocks holding at least one of them
+	OnlyOurs     int     // Of those, blocks holding nothing else
+	OurShare     float64 // Our share of all transactions in those blocks
+	MedianIndex  int     // Median transaction index within the block
+	MaxIndex     int
+	Deciles      [10]int // Transactions by relative position, [0] the first tenth of the block
+}
+
+// positionHistogram accumulates inclusion positions as blocks are scanned. Its
+// caller holds the tracker lock.
+type positionHistogram struct {
+	indexes  []int
+	deciles  [10]int
+	blocks   int
+	onlyOurs int
+	ours     int
+	blockTxs int
+}
+
+// add records the indexes of our transactions in a block of txCount transactions
+func (h *positionHistogram) add(indexes []int, txCount int) {
+	if len(indexes) == 0 {
+		return
+	}
+	h.blocks++
+	if len(indexes) == txCount {
+		h.onlyOurs++
+	}
+	h.ours += len(indexes)
+	h.blockTxs += txCount
+	for _, index := range indexes {
+		h.indexes = append(h.indexes, index)
+		h.deciles[index*10/txCount]++
+	}
+}
+
+// report summarizes the positions recorded so far
+func (h *positionHistogram) report() *PositionReport {
+	r := &PositionReport{
+		Transactions: len(h.indexes),
+		Blocks:       h.blocks,
+		OnlyOurs:     h.onlyOurs,
+		Deciles:      h.deciles,
+	}
+	if len(h.indexes) == 0 {
+		return r
+	}
+	sorted := make([]int, len(h.indexes))
+	copy(sorted, h.indexes)
+	sort.Ints(sorted)
+	r.MedianIndex = sorted[(len(sorted)-1)/2]
+	r.MaxIndex = sorted[len(sorted)-1]
+	r.OurShare = float64(h.ours) / float64(h.blockTxs)
+	return r
+}
+
+// PrintPositionReport prints where the run's transactions landed within their blocks
+func PrintPositionReport(r *PositionReport) {
+	fmt.Printf("\n=== Inclusion Positions ===\n")
+	if r.Transactions == 0 {
+		fmt.Printf("No transactions were seen in a block\n")
+		fmt.Printf("==========================\n")
+		return
+	}
+	fmt.Printf("Transactions: %d in %d blocks, %d of them (%.1f%%) entirely ours\n",
+		r.Transactions, r.Blocks, r.OnlyOurs, float64(r.OnlyOurs)*100/float64(r.Blocks))
+	fmt.Printf("Our share of those blocks: %.1f%%\n", r.OurShare*100)
+	fmt.Printf("Index in block: median %d, max %d\n", r.MedianIndex, r.MaxIndex)
+	fmt.Printf("Position within block:\n")
+	for i, count := range r.Deciles {
+		share := float64(count) / float64(r.Transactions)
+		fmt.Printf("  %3d-%3d%%  %5.1f%%  %s\n", i*10, (i+1)*10, share*100, strings.Repeat("#", int(share*50+0.5)))
+	}
+	fmt.Printf("==========================\n")
+}
@@ -0,0 +1,128 @@
+package transaction
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// ReadRawTransactions reads signed transactions encoded as raw hex (RLP or typed
+// envelope, with or without 0x), one per line. Blank lines and lines starting with
+// # are skipped.
+func ReadRawTransactions(r io.Reader) ([]*types.Transaction, error) {
+	var txs []*types.Transaction
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 4*1024*1024) // Blob-sized lines
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if !strings.HasPrefix(line, "0x") && !strings.HasPrefix(line, "0X") {
+			line = "0x" + line
+		}
+		raw, err := hexutil.Decode(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid hex: %w", lineNo, err)
+		}
+		tx := new(types.Transaction)
+		if err := tx.UnmarshalBinary(raw); err != nil {
+			return nil, fmt.Errorf("line %d: invalid transaction: %w", lineNo, err)
+		}
+		txs = append(txs, tx)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read transactions: %w", err)
+	}
+	return txs, nil
+}
+
+// ReplayConfig holds configuration for submitting pre-signed transactions
+type ReplayConfig struct {
+	Rate        float64 // Transactions per second (0 = as fast as Concurrency allows)
+	Concurrency int     // Submissions in flight at once (default: 100)
+}
+
+// ReplayResult counts how the node answered each replayed transaction
+type ReplayResult struct {
+	Total        int
+	Accepted     int
+	AlreadyKnown int // Already in the node's pool, e.g. from an earlier replay
+	Failed       int
+	Duration     time.Duration
+	Errors       []error // The first errors, for the report
+}
+
+// maxReplayErrors caps the errors kept in a ReplayResult
+const maxReplayErrors = 10
+
+// Replay submits txs in file order at config.Rate. The transactions are sent as they
+// are, so no key is needed; nonce gaps from concurrent submission are queued by the
+// node until the earlier nonce arrives.
+func Replay(ctx context.Context, submitter Submitter, txs []*types.Transaction, config *ReplayConfig) *ReplayResult {
+	concurrency := config.Concurrency
+	if concurrency <= 0 {
+		concurrency = 100
+	}
+	var p pacer
+	p.setRate(config.Rate)
+	pacerCtx, stopPacer := context.WithCancel(ctx)
+	defer stopPacer()
+	go p.run(pacerCtx)
+
+	result := &ReplayResult{}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	semaphore := make(chan struct{}, concurrency)
+	start := time.Now()
+	for _, tx := range txs {
+		if err := p.wait(ctx); err != nil {
+			break
+		}
+		semaphore <- struct{}{}
+		result.Total++
+		wg.Add(1)
+		go func(tx *types.Transaction) {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+			err := submitter.SendTransaction(ctx, tx)
+
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case err == nil:
+				result.Accepted++
+			case IsAlreadyKnown(err):
+				result.AlreadyKnown++
+			default:
+				result.Failed++
+				if len(result.Errors) < maxReplayErrors {
+					result.Errors = append(result.Errors, fmt.Errorf("%s: %w", tx.Hash().Hex(), err))
+				}
+			}
+		}(tx)
+	}
+	wg.Wait()
+	result.Duration = time.Since(start)
+	return result
+}
+
+// PrintReplayResults prints the replay counts, achieved rate and first errors
+func PrintReplayResults(r *ReplayResult) {
+	fmt.Printf("\n=== Replay Results ===\n")
+	fmt.Printf("Submitted: %d, accepted: %d, already known: %d, failed: %d\n", r.Total, r.Accepted, r.AlreadyKnown, r.Failed)
+	if seconds := r.Duration.Seconds(); seconds > 0 {
+		fmt.Printf("Duration: %s, rate: %.1f tx/s\n", r.Duration.Round(time.Millisecond), float64(r.Total)/seconds)
+	}
+	for _, err := range r.Errors {
+		fmt.Printf("  error: %v\n", err)
+	}
+	fmt.Printf("==========================\n")
+}
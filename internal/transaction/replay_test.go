@@ -0,0 +1,49 @@
+package transaction
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// countingSubmitter answers the n-th send with errs[n % len(errs)]
+type countingSubmitter struct {
+	calls int64
+	errs  []error
+}
+
+func (c *countingSubmitter) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	n := atomic.AddInt64(&c.calls, 1) - 1
+	return c.errs[n%int64(len(c.errs))]
+}
+
+func TestReplay(t *testing.T) {
+	txs := make([]*types.Transaction, 6)
+	for i := range txs {
+		txs[i] = types.NewTransaction(uint64(i), common.Address{0x01}, big.NewInt(0), 21000, big.NewInt(1), nil)
+	}
+
+	t.Run("CountsOutcomes", func(t *testing.T) {
+		submitter := &countingSubmitter{errs: []error{nil, errors.New("already known"), errors.New("nonce too low")}}
+		r := Replay(context.Background(), submitter, txs, &ReplayConfig{Concurrency: 1})
+		if r.Total != 6 || r.Accepted != 2 || r.AlreadyKnown != 2 || r.Failed != 2 {
+			t.Errorf("unexpected counts: %+v", r)
+		}
+		if len(r.Errors) != 2 {
+			t.Errorf("expected 2 errors kept, got %d", len(r.Errors))
+		}
+	})
+
+	t.Run("Paces", func(t *testing.T) {
+		r := Replay(context.Background(), &countingSubmitter{errs: []error{nil}}, txs[:3], &ReplayConfig{Rate: 20})
+		if r.Duration < 100*time.Millisecond {
+			t.Errorf("3 transactions at 20 tx/s took only %s", r.Duration)
+		}
+	})
+}
@@ -0,0 +1,198 @@
+package transaction
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"math/rand"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"go.starlark.net/starlark"
+	"go.starlark.net/starlarkstruct"
+)
+
+// maxScriptSteps bounds the Starlark steps one build() call may take, so a script
+// that loops forever fails its transaction instead of stalling its wallet
+const maxScriptSteps = 1_000_000
+
+// ScriptHook builds every transaction by calling the build() function of a Starlark
+// script. build receives a struct with wallet (pool index), address (hex), and seq,
+// and returns a dict with to (hex address), and optionally value (wei), data (hex),
+// and gas_limit (0 uses the workload's gas limit). The script may call
+// random(n), random_address() and abi_word(x), which draw from the wallet's random
+// source and encode an int or address as one 32-byte ABI word in hex. A script
+// that calls fail() or raises an error fails that transaction only.
+type ScriptHook struct {
+	path  string
+	build starlark.Callable
+}
+
+// NewScriptHook loads the Starlark script at path. The script runs once, and must
+// define build(tx).
+func NewScriptHook(path string) (*ScriptHook, error) {
+	thread := &starlark.Thread{Name: path}
+	globals, err := starlark.ExecFile(thread, path, nil, scriptBuiltins)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load script %s: %w", path, err)
+	}
+	build, ok := globals["build"].(starlark.Callable)
+	if !ok {
+		return nil, fmt.Errorf("script %s does not define a build(tx) function", path)
+	}
+	// Globals are frozen once the script has run, so build can be called from every
+	// wallet at once
+	return &ScriptHook{path: path, build: build}, nil
+}
+
+// Build implements TxBuilder
+func (h *ScriptHook) Build(rng *rand.Rand, walletIndex int, address common.Address, seq uint64) (*TemplateTx, error) {
+	thread := &starlark.Thread{Name: h.path}
+	thread.SetLocal("rng", rng)
+	thread.SetMaxExecutionSteps(maxScriptSteps)
+
+	tx := starlarkstruct.FromStringDict(starlarkstruct.Default, starlark.StringDict{
+		"wallet":  starlark.MakeInt(walletIndex),
+		"address": starlark.String(address.Hex()),
+		"seq":     starlark.MakeUint64(seq),
+	})
+	result, err := starlark.Call(thread, h.build, starlark.Tuple{tx}, nil)
+	if err != nil {
+		var evalErr *starlark.EvalError
+		if errors.As(err, &evalErr) {
+			return nil, fmt.Errorf("script: %s", evalErr.Msg)
+		}
+		return nil, fmt.Errorf("script: %w", err)
+	}
+	dict, ok := result.(*starlark.Dict)
+	if !ok {
+		return nil, fmt.Errorf("script: build returned %s, expected a dict", result.Type())
+	}
+	return scriptTx(dict)
+}
+
+// scriptTx converts the dict returned by build into a transaction
+func scriptTx(dict *starlark.Dict) (*TemplateTx, error) {
+	tx := &TemplateTx{Template: "script", Value: new(big.Int)}
+	hasTo := false
+	for _, item := range dict.Items() {
+		key, ok := starlark.AsString(item[0])
+		if !ok {
+			return nil, fmt.Errorf("script: build returned a non-string key %s", item[0])
+		}
+		value := item[1]
+		switch key {
+		case "to":
+			s, ok := starlark.AsString(value)
+			if !ok || !common.IsHexAddress(s) {
+				return nil, fmt.Errorf("script: to %s is not an address", value)
+			}
+			tx.To = common.HexToAddress(s)
+			hasTo = true
+		case "value":
+			n, ok := value.(starlark.Int)
+			if !ok || n.Sign() < 0 {
+				return nil, fmt.Errorf("script: value %s is not a wei amount", value)
+			}
+			tx.Value = n.BigInt()
+		case "data":
+			s, ok := starlark.AsString(value)
+			if !ok {
+				return nil, fmt.Errorf("script: data %s is not a hex string", value)
+			}
+			data, err := hexutil.Decode(s)
+			if err != nil {
+				return nil, fmt.Errorf("script: data: %w", err)
+			}
+			tx.Data = data
+		case "gas_limit":
+			n, ok := value.(starlark.Int)
+			if !ok {
+				return nil, fmt.Errorf("script: gas_limit %s is not an int", value)
+			}
+			gas, ok := n.Uint64()
+			if !ok {
+				return nil, fmt.Errorf("script: gas_limit %s is out of range", value)
+			}
+			tx.GasLimit = gas
+		default:
+			return nil, fmt.Errorf("script: build returned unknown key %q (expected to, value, data, gas_limit)", key)
+		}
+	}
+	if !hasTo {
+		return nil, errors.New("script: build returned no to address")
+	}
+	return tx, nil
+}
+
+// scriptBuiltins are the functions scripts may call besides Starlark's own
+var scriptBuiltins = starlark.StringDict{
+	"random":         starlark.NewBuiltin("random", scriptRandom),
+	"random_address": starlark.NewBuiltin("random_address", scriptRandomAddress),
+	"abi_word":       starlark.NewBuiltin("abi_word", scriptABIWord),
+}
+
+// scriptRNG returns the random source of the transaction being built
+func scriptRNG(thread *starlark.Thread, name string) (*rand.Rand, error) {
+	rng, ok := thread.Local("rng").(*rand.Rand)
+	if !ok {
+		return nil, fmt.Errorf("%s: only available inside build", name)
+	}
+	return rng, nil
+}
+
+// scriptRandom implements random(n), a uniform int in [0, n)
+func scriptRandom(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var n starlark.Int
+	if err := starlark.UnpackPositionalArgs(b.Name(), args, kwargs, 1, &n); err != nil {
+		return nil, err
+	}
+	if n.Sign() <= 0 {
+		return nil, fmt.Errorf("%s: n must be positive", b.Name())
+	}
+	rng, err := scriptRNG(thread, b.Name())
+	if err != nil {
+		return nil, err
+	}
+	return starlark.MakeBigInt(new(big.Int).Rand(rng, n.BigInt())), nil
+}
+
+// scriptRandomAddress implements random_address()
+func scriptRandomAddress(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	if err := starlark.UnpackPositionalArgs(b.Name(), args, kwargs, 0); err != nil {
+		return nil, err
+	}
+	rng, err := scriptRNG(thread, b.Name())
+	if err != nil {
+		return nil, err
+	}
+	var a common.Address
+	rng.Read(a[:])
+	return starlark.String(a.Hex()), nil
+}
+
+// scriptABIWord implements abi_word(x), the unprefixed hex ABI word of an unsigned
+// int or an address, for appending to calldata
+func scriptABIWord(_ *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var x starlark.Value
+	if err := starlark.UnpackPositionalArgs(b.Name(), args, kwargs, 1, &x); err != nil {
+		return nil, err
+	}
+	var word common.Hash
+	switch x := x.(type) {
+	case starlark.Int:
+		n := x.BigInt()
+		if n.Sign() < 0 || n.BitLen() > 256 {
+			return nil, fmt.Errorf("%s: %s does not fit a uint256", b.Name(), x)
+		}
+		word = common.BigToHash(n)
+	case starlark.String:
+		if !common.IsHexAddress(string(x)) {
+			return nil, fmt.Errorf("%s: %s is not an address", b.Name(), x)
+		}
+		word = common.BytesToHash(common.HexToAddress(string(x)).Bytes())
+	default:
+		return nil, fmt.Errorf("%s: expected an int or address, got %s", b.Name(), x.Type())
+	}
+	return starlark.String(hexutil.Encode(word[:])[2:]), nil
+}
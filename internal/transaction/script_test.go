@@ -0,0 +1,130 @@
+package transaction
+
+import (
+	"bytes"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// writeScript writes a Starlark script to a temporary file and loads it
+func writeScript(t *testing.T, src string) (*ScriptHook, error) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "workload.star")
+	if err := os.WriteFile(path, []byte(src), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return NewScriptHook(path)
+}
+
+func TestScriptHook(t *testing.T) {
+	wallet := common.Address{0x01, 0x02}
+
+	t.Run("BuildsTransactions", func(t *testing.T) {
+		hook, err := writeScript(t, `
+TOKEN = "0x00000000000000000000000000000000000000aa"
+
+def build(tx):
+    if tx.seq % 2 == 0:
+        return {"to": tx.address, "value": 1000 + tx.wallet}
+    return {
+        "to": TOKEN,
+        "data": "0xa9059cbb" + abi_word(tx.address) + abi_word(random(100)),
+        "gas_limit": 60000,
+    }
+`)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rng := rand.New(rand.NewSource(1))
+
+		tx, err := hook.Build(rng, 3, wallet, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if tx.To != wallet || tx.Value.Int64() != 1003 || len(tx.Data) != 0 {
+			t.Errorf("expected a 1003 wei self-transfer, got %+v", tx)
+		}
+
+		tx, err = hook.Build(rng, 3, wallet, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if tx.To != common.HexToAddress("0xaa") || tx.GasLimit != 60000 || tx.Value.Sign() != 0 {
+			t.Errorf("expected a token call with a 60000 gas limit, got %+v", tx)
+		}
+		if len(tx.Data) != 4+2*32 || !bytes.Equal(tx.Data[4+12:4+32], wallet.Bytes()) {
+			t.Errorf("expected a selector, the wallet address and an amount, got %x", tx.Data)
+		}
+	})
+
+	t.Run("ConcurrentBuilds", func(t *testing.T) {
+		hook, err := writeScript(t, `
+def build(tx):
+    return {"to": random_address(), "value": random(10)}
+`)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var wg sync.WaitGroup
+		for i := 0; i < 8; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				rng := rand.New(rand.NewSource(int64(i)))
+				for seq := uint64(0); seq < 100; seq++ {
+					if _, err := hook.Build(rng, i, wallet, seq); err != nil {
+						t.Error(err)
+						return
+					}
+				}
+			}(i)
+		}
+		wg.Wait()
+	})
+
+	t.Run("ErrorsFailTheTransaction", func(t *testing.T) {
+		hook, err := writeScript(t, `
+def build(tx):
+    if tx.seq == 0:
+        fail("no transaction for seq 0")
+    if tx.seq == 1:
+        return {"to": "not an address"}
+    if tx.seq == 2:
+        return {"value": 1}
+    if tx.seq == 3:
+        return {"to": tx.address, "nonce": 1}
+    if tx.seq == 4:
+        for i in range(1000000000):
+            pass
+    return {"to": tx.address}
+`)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rng := rand.New(rand.NewSource(1))
+		for seq, want := range []string{"no transaction for seq 0", "not an address", "no to address", "unknown key", "too many steps"} {
+			_, err := hook.Build(rng, 0, wallet, uint64(seq))
+			if err == nil || !strings.Contains(err.Error(), want) {
+				t.Errorf("seq %d: expected an error containing %q, got %v", seq, want, err)
+			}
+		}
+		if _, err := hook.Build(rng, 0, wallet, 5); err != nil {
+			t.Errorf("expected a failed transaction not to affect the next, got %v", err)
+		}
+	})
+
+	t.Run("RequiresBuild", func(t *testing.T) {
+		if _, err := writeScript(t, "x = 1\n"); err == nil || !strings.Contains(err.Error(), "build(tx)") {
+			t.Errorf("expected a script without build to be rejected, got %v", err)
+		}
+		if _, err := writeScript(t, "def build(tx)\n"); err == nil {
+			t.Error("expected a syntax error to be rejected")
+		}
+	})
+}
@@ -22,6 +22,8 @@ type Sender struct {
 	chainID     *big.Int
 	config      *SenderConfig
 	nonceManager *NonceManager
+	submitter   Submitter
+	ownsClient  bool // Close the client on Close (false for injected clients)
 }
 
 // SenderConfig holds configuration for transaction sending
@@ -30,54 +32,52 @@ type SenderConfig struct {
 	Value            *big.Int
 	GasLimit         uint64
 	Data             []byte
-	MaxTransactions  int
+	MaxTransactions  int // 0 = unlimited
 	DelaySeconds     int
+	GasOnly          bool // Send zero-value transactions to the sender itself, burning only gas
+	Confirmation     *ConfirmationStrategy // Wait for each transaction to reach this level, nil keeps the DelaySeconds behavior
 }
 
 // NewSender creates a new transaction sender
 func NewSender(rpcURL, privateKeyHex string, config *SenderConfig) (*Sender, error) {
+	return NewSenderWithNonceManager(rpcURL, privateKeyHex, config, nil)
+}
+
+// NewSenderWithNonceManager creates a new transaction sender with a shared nonce manager
+//
+// Deprecated: dials a new client and fetches the chain ID again; callers that already
+// hold both should use NewSenderWithClient.
+func NewSenderWithNonceManager(rpcURL, privateKeyHex string, config *SenderConfig, nonceManager *NonceManager) (*Sender, error) {
 	client, err := ethclient.Dial(rpcURL)
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to RPC: %w", err)
 	}
 
-	privateKey, err := crypto.HexToECDSA(strings.TrimPrefix(privateKeyHex, "0x"))
-	if err != nil {
-		return nil, fmt.Errorf("failed to parse private key: %w", err)
-	}
-
 	chainID, err := client.ChainID(context.Background())
 	if err != nil {
+		client.Close()
 		return nil, fmt.Errorf("failed to get chain ID: %w", err)
 	}
 
-	fromAddress := crypto.PubkeyToAddress(privateKey.PublicKey)
-	nonceManager := NewNonceManager(client, fromAddress)
-
-	return &Sender{
-		client:       client,
-		privateKey:   privateKey,
-		chainID:      chainID,
-		config:       config,
-		nonceManager: nonceManager,
-	}, nil
-}
-
-// NewSenderWithNonceManager creates a new transaction sender with a shared nonce manager
-func NewSenderWithNonceManager(rpcURL, privateKeyHex string, config *SenderConfig, nonceManager *NonceManager) (*Sender, error) {
-	client, err := ethclient.Dial(rpcURL)
+	sender, err := NewSenderWithClient(client, chainID, privateKeyHex, config, nonceManager)
 	if err != nil {
-		return nil, fmt.Errorf("failed to connect to RPC: %w", err)
+		client.Close()
+		return nil, err
 	}
+	sender.ownsClient = true
+	return sender, nil
+}
 
+// NewSenderWithClient creates a new transaction sender on an existing client and chain ID.
+// A nil nonceManager creates one for the key's address. The client is not closed by Close.
+func NewSenderWithClient(client *ethclient.Client, chainID *big.Int, privateKeyHex string, config *SenderConfig, nonceManager *NonceManager) (*Sender, error) {
 	privateKey, err := crypto.HexToECDSA(strings.TrimPrefix(privateKeyHex, "0x"))
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse private key: %w", err)
 	}
 
-	chainID, err := client.ChainID(context.Background())
-	if err != nil {
-		return nil, fmt.Errorf("failed to get chain ID: %w", err)
+	if nonceManager == nil {
+		nonceManager = NewNonceManager(client, crypto.PubkeyToAddress(privateKey.PublicKey))
 	}
 
 	return &Sender{
@@ -86,20 +86,33 @@ func NewSenderWithNonceManager(rpcURL, privateKeyHex string, config *SenderConfi
 		chainID:      chainID,
 		config:       config,
 		nonceManager: nonceManager,
+		submitter:    client,
 	}, nil
 }
 
+// SetSubmitter routes signed transactions to a separate write endpoint
+func (s *Sender) SetSubmitter(submitter Submitter) {
+	s.submitter = submitter
+}
+
 // SendTransactions sends multiple transactions to random addresses
 func (s *Sender) SendTransactions() error {
 	rng := rand.New(rand.NewSource(time.Now().UnixNano()))
 	ctx := context.Background()
+	value := s.config.Value
+	if s.config.GasOnly {
+		value = big.NewInt(0)
+	}
 
-	for i := 0; i < s.config.MaxTransactions; i++ {
+	for i := 0; WithinLimit(i, s.config.MaxTransactions); i++ {
 		// Select random address from the array
 		randomIndex := rng.Intn(len(s.config.RandomAddresses))
 		recipient := s.config.RandomAddresses[randomIndex]
+		if s.config.GasOnly {
+			recipient = crypto.PubkeyToAddress(s.privateKey.PublicKey)
+		}
 
-		fmt.Printf("Sending transaction %d/%d to %s\n", i+1, s.config.MaxTransactions, recipient.Hex())
+		fmt.Printf("Sending transaction %s to %s\n", FormatProgress(i, s.config.MaxTransactions), recipient.Hex())
 
 		nonce, err := s.nonceManager.GetNextNonce(ctx)
 		if err != nil {
@@ -126,26 +139,35 @@ func (s *Sender) SendTransactions() error {
 		tx := types.NewTransaction(
 			nonce,
 			recipient,
-			s.config.Value,
+			value,
 			s.config.GasLimit,
 			gasPrice,
 			s.config.Data,
 		)
 
-		signedTx, err := types.SignTx(tx, types.NewEIP155Signer(s.chainID), s.privateKey)
+		signedTx, err := SignTx(tx, s.chainID, s.privateKey)
 		if err != nil {
 			return fmt.Errorf("failed to sign transaction: %w", err)
 		}
 
-		if err := s.client.SendTransaction(context.Background(), signedTx); err != nil {
+		if err := s.submitter.SendTransaction(context.Background(), signedTx); err != nil {
 			return fmt.Errorf("failed to send transaction: %w", err)
 		}
 
 		fmt.Printf("Transaction hash: %s\n", signedTx.Hash().Hex())
 
+		if s.config.Confirmation != nil {
+			if err := s.config.Confirmation.Wait(ctx, s.client, signedTx.Hash()); err != nil {
+				fmt.Printf("Not confirmed: %v\n", err)
+			} else {
+				fmt.Printf("Transaction confirmed (%s)\n", s.config.Confirmation)
+			}
+			continue
+		}
+
 		// Wait for transaction to be accepted into mempool before sending next
 		// This prevents nonce conflicts when sending transactions rapidly
-		if i < s.config.MaxTransactions-1 {
+		if !IsLast(i, s.config.MaxTransactions) {
 			if s.config.DelaySeconds > 0 {
 				// Wait for transaction receipt or use delay as fallback
 				receipt, err := s.waitForTransaction(ctx, signedTx.Hash())
@@ -187,9 +209,9 @@ func (s *Sender) waitForTransaction(ctx context.Context, txHash common.Hash) (*t
 	}
 }
 
-// Close closes the Ethereum client connection
+// Close closes the Ethereum client connection if the sender dialed it
 func (s *Sender) Close() {
-	if s.client != nil {
+	if s.client != nil && s.ownsClient {
 		s.client.Close()
 	}
 }
@@ -0,0 +1,62 @@
+package transaction
+
+import (
+	"crypto/ecdsa"
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// Supported signer types
+const (
+	SignerAuto      = "auto"      // Latest signer for the chain ID (all tx types), homestead without one
+	SignerEIP155    = "eip155"    // Replay-protected legacy transactions only
+	SignerHomestead = "homestead" // Unprotected transactions for pre-EIP-155 chains
+)
+
+var (
+	signerType = SignerAuto
+	signerMu   sync.RWMutex
+)
+
+// SetSignerType selects the signer used by every component. It should be called
+// once at startup, before any transactions are signed.
+func SetSignerType(kind string) error {
+	switch kind {
+	case SignerAuto, SignerEIP155, SignerHomestead:
+	default:
+		return fmt.Errorf("unsupported signer type: %s", kind)
+	}
+	signerMu.Lock()
+	defer signerMu.Unlock()
+	signerType = kind
+	return nil
+}
+
+// SignerFor returns the configured signer for chainID
+func SignerFor(chainID *big.Int) types.Signer {
+	signerMu.RLock()
+	kind := signerType
+	signerMu.RUnlock()
+
+	switch kind {
+	case SignerHomestead:
+		return types.HomesteadSigner{}
+	case SignerEIP155:
+		return types.NewEIP155Signer(chainID)
+	default:
+		if chainID == nil || chainID.Sign() == 0 {
+			return types.HomesteadSigner{}
+		}
+		// Accepts legacy, access list, dynamic fee and blob transactions
+		return types.LatestSignerForChainID(chainID)
+	}
+}
+
+// SignTx signs tx for chainID with the configured signer. All components sign
+// through here so typed transactions work everywhere once the signer supports them.
+func SignTx(tx *types.Transaction, chainID *big.Int, privateKey *ecdsa.PrivateKey) (*types.Transaction, error) {
+	return types.SignTx(tx, SignerFor(chainID), privateKey)
+}
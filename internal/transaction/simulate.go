@@ -0,0 +1,302 @@
+package transaction
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"flag"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// Selectors of the revert payloads the compiler emits for require/revert and for panics
+var (
+	errorSelector = []byte{0x08, 0xc3, 0x79, 0xa0} // Error(string)
+	panicSelector = []byte{0x4e, 0x48, 0x7b, 0x71} // Panic(uint256)
+)
+
+// panicReasons describes the Solidity panic codes
+var panicReasons = map[uint64]string{
+	0x00: "generic compiler panic",
+	0x01: "assertion failed",
+	0x11: "arithmetic overflow or underflow",
+	0x12: "division or modulo by zero",
+	0x21: "invalid enum value",
+	0x22: "invalid storage byte array encoding",
+	0x31: "pop on empty array",
+	0x32: "array index out of bounds",
+	0x41: "out of memory",
+	0x51: "call to zero-initialized function",
+}
+
+// SimulateOptions describes the transaction of `simulator simulate-tx`
+type SimulateOptions struct {
+	To    string   // Empty simulates a contract creation
+	Value *big.Int // In wei
+	Data  []byte
+	Key   string // Private key of the sender, hex; the caller falls back to PRIVATE_KEY
+	Gas   uint64 // Gas limit of the call, 0 leaves it to the node
+	Trace bool   // Also run debug_traceCall with the call tracer
+}
+
+// ParseSimulateArgs parses `simulator simulate-tx --to 0x... [--value wei] [--data 0x...] [--key 0x...] [--gas n] [--trace]`
+func ParseSimulateArgs(args []string) (*SimulateOptions, error) {
+	fs := flag.NewFlagSet("simulate-tx", flag.ContinueOnError)
+	to := fs.String("to", "", "recipient address, empty for a contract creation")
+	value := fs.String("value", "0", "value in wei")
+	data := fs.String("data", "", "calldata (hex)")
+	key := fs.String("key", "", "private key of the sender (hex), defaults to PRIVATE_KEY")
+	gas := fs.Uint64("gas", 0, "gas limit, 0 leaves it to the node")
+	trace := fs.Bool("trace", false, "also trace the call with debug_traceCall")
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	opts := &SimulateOptions{To: *to, Key: *key, Gas: *gas, Trace: *trace}
+	if opts.To != "" && !common.IsHexAddress(opts.To) {
+		return nil, fmt.Errorf("invalid --to address: %s", opts.To)
+	}
+	var ok bool
+	if opts.Value, ok = new(big.Int).SetString(*value, 10); !ok || opts.Value.Sign() < 0 {
+		return nil, fmt.Errorf("invalid --value: %s (expected wei)", *value)
+	}
+	if *data != "" {
+		decoded, err := hexutil.Decode(*data)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --data: %w", err)
+		}
+		opts.Data = decoded
+	}
+	if opts.To == "" && len(opts.Data) == 0 {
+		return nil, errors.New("--to or --data (init code of a contract creation) is required")
+	}
+	return opts, nil
+}
+
+// Simulation is the outcome of simulating one transaction against pending state
+type Simulation struct {
+	From         common.Address
+	To           *common.Address // Nil for a contract creation
+	Value        *big.Int
+	ReturnData   []byte
+	Reverted     bool
+	RevertReason string // Decoded revert payload, empty when there is none
+	CallError    string // Failure of eth_call other than a revert
+	GasEstimate  uint64
+	GasError     string     // Why eth_estimateGas failed, empty when it did not
+	Trace        *CallFrame // Nil unless tracing was requested and succeeded
+	TraceError   string
+}
+
+// CallFrame is one call of a callTracer result
+type CallFrame struct {
+	Type    string         `json:"type"`
+	From    common.Address `json:"from"`
+	To      common.Address `json:"to"`
+	Value   *hexutil.Big   `json:"value"`
+	GasUsed hexutil.Uint64 `json:"gasUsed"`
+	Input   hexutil.Bytes  `json:"input"`
+	Output  hexutil.Bytes  `json:"output"`
+	Error   string         `json:"error"`
+	Calls   []CallFrame    `json:"calls"`
+}
+
+// SimulateCommand runs eth_call, eth_estimateGas and, when opts.Trace is set,
+// debug_traceCall for the transaction in opts against the pending block. Failures of
+// the individual calls are part of the result; only an unusable key is an error.
+func SimulateCommand(ctx context.Context, client *rpc.Client, opts *SimulateOptions) (*Simulation, error) {
+	if opts.Key == "" {
+		return nil, errors.New("a sender key is required (--key or PRIVATE_KEY)")
+	}
+	privateKey, err := crypto.HexToECDSA(strings.TrimPrefix(opts.Key, "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid private key: %w", err)
+	}
+
+	sim := &Simulation{From: crypto.PubkeyToAddress(privateKey.PublicKey), Value: opts.Value}
+	if sim.Value == nil {
+		sim.Value = new(big.Int)
+	}
+	args := map[string]interface{}{
+		"from":  sim.From,
+		"value": (*hexutil.Big)(sim.Value),
+		"data":  hexutil.Bytes(opts.Data),
+	}
+	if opts.To != "" {
+		to := common.HexToAddress(opts.To)
+		sim.To = &to
+		args["to"] = to
+	}
+	if opts.Gas > 0 {
+		args["gas"] = hexutil.Uint64(opts.Gas)
+	}
+
+	var result hexutil.Bytes
+	if err := client.CallContext(ctx, &result, "eth_call", args, "pending"); err != nil {
+		if data, ok := revertData(err); ok {
+			sim.Reverted = true
+			sim.ReturnData = data
+			sim.RevertReason = DecodeRevert(data)
+		} else {
+			sim.CallError = err.Error()
+		}
+	} else {
+		sim.ReturnData = result
+	}
+
+	var gas hexutil.Uint64
+	if err := client.CallContext(ctx, &gas, "eth_estimateGas", args, "pending"); err != nil {
+		sim.GasError = err.Error()
+	} else {
+		sim.GasEstimate = uint64(gas)
+	}
+
+	if opts.Trace {
+		var frame CallFrame
+		if err := client.CallContext(ctx, &frame, "debug_traceCall", args, "pending", map[string]string{"tracer": "callTracer"}); err != nil {
+			sim.TraceError = err.Error()
+		} else {
+			sim.Trace = &frame
+		}
+	}
+	return sim, nil
+}
+
+// revertData extracts the revert payload the node attaches to a failed eth_call.
+// A revert without a payload is still a revert, unlike transport or argument errors.
+func revertData(err error) ([]byte, bool) {
+	var dataErr rpc.DataError
+	if errors.As(err, &dataErr) {
+		if s, ok := dataErr.ErrorData().(string); ok {
+			if data, err := hexutil.Decode(s); err == nil {
+				return data, true
+			}
+		}
+	}
+	if strings.Contains(err.Error(), "execution reverted") {
+		return nil, true
+	}
+	return nil, false
+}
+
+// DecodeRevert describes a revert payload: the message of Error(string), the meaning
+// of a Panic(uint256) code, or the selector of a custom error. It returns an empty
+// string for an empty payload.
+func DecodeRevert(data []byte) string {
+	if len(data) == 0 {
+		return ""
+	}
+	if len(data) < 4 {
+		return fmt.Sprintf("malformed revert data %s", hexutil.Encode(data))
+	}
+	selector, body := data[:4], data[4:]
+	switch {
+	case string(selector) == string(errorSelector) && len(body) >= 64:
+		offset := new(big.Int).SetBytes(body[:32])
+		if offset.IsUint64() && offset.Uint64()+32 <= uint64(len(body)) {
+			start := offset.Uint64()
+			length := new(big.Int).SetBytes(body[start : start+32])
+			if length.IsUint64() && start+32+length.Uint64() <= uint64(len(body)) {
+				return fmt.Sprintf("Error(%q)", body[start+32:start+32+length.Uint64()])
+			}
+		}
+	case string(selector) == string(panicSelector) && len(body) == 32:
+		code := new(big.Int).SetBytes(body)
+		reason, ok := panicReasons[code.Uint64()]
+		if !code.IsUint64() || !ok {
+			reason = "unknown panic code"
+		}
+		return fmt.Sprintf("Panic(0x%x): %s", code, reason)
+	}
+	return fmt.Sprintf("custom error %s", hexutil.Encode(selector))
+}
+
+// PrintSimulation prints the call result, gas estimate and call trace of a simulation
+func PrintSimulation(sim *Simulation) {
+	fmt.Printf("\n=== Transaction Simulation ===\n")
+	fmt.Printf("From: %s\n", sim.From.Hex())
+	if sim.To != nil {
+		fmt.Printf("To: %s\n", sim.To.Hex())
+	} else {
+		fmt.Printf("To: contract creation\n")
+	}
+	fmt.Printf("Value: %s wei\n", sim.Value)
+
+	switch {
+	case sim.CallError != "":
+		fmt.Printf("eth_call: failed: %s\n", sim.CallError)
+	case sim.Reverted:
+		reason := sim.RevertReason
+		if reason == "" {
+			reason = "no reason given"
+		}
+		fmt.Printf("eth_call: reverted: %s\n", reason)
+	default:
+		fmt.Printf("eth_call: success\n")
+		printReturnData(sim.ReturnData)
+	}
+
+	if sim.GasError != "" {
+		fmt.Printf("Gas estimate: failed: %s\n", sim.GasError)
+	} else {
+		fmt.Printf("Gas estimate: %d\n", sim.GasEstimate)
+	}
+
+	if sim.TraceError != "" {
+		fmt.Printf("Trace: failed: %s\n", sim.TraceError)
+	} else if sim.Trace != nil {
+		fmt.Printf("Call trace:\n")
+		printCallFrame(sim.Trace, 1)
+	}
+	fmt.Printf("==========================\n")
+}
+
+// printReturnData prints return data as hex and, when it is a sequence of ABI words,
+// each word as an unsigned integer
+func printReturnData(data []byte) {
+	fmt.Printf("Return data (%d bytes): %s\n", len(data), hexutil.Encode(data))
+	if len(data) == 0 || len(data)%32 != 0 {
+		return
+	}
+	for i := 0; i < len(data); i += 32 {
+		word := data[i : i+32]
+		fmt.Printf("  [%d] %s\n", i/32, describeWord(word))
+	}
+}
+
+// describeWord renders a 32-byte ABI word as an integer, adding the address it may
+// hold when only its low 20 bytes are set
+func describeWord(word []byte) string {
+	value := new(big.Int).SetBytes(word)
+	if value.IsUint64() {
+		return fmt.Sprintf("%d", binary.BigEndian.Uint64(word[24:]))
+	}
+	if value.BitLen() <= 160 && value.BitLen() > 128 {
+		return fmt.Sprintf("%s (address %s)", value, common.BytesToAddress(word).Hex())
+	}
+	return value.String()
+}
+
+// printCallFrame prints a call and its subcalls, one line each, indented by depth
+func printCallFrame(frame *CallFrame, depth int) {
+	line := fmt.Sprintf("%s%s %s -> %s, gas used %d", strings.Repeat("  ", depth), frame.Type, frame.From.Hex(), frame.To.Hex(), uint64(frame.GasUsed))
+	if frame.Value != nil && frame.Value.ToInt().Sign() > 0 {
+		line += fmt.Sprintf(", value %s wei", frame.Value.ToInt())
+	}
+	if frame.Error != "" {
+		line += fmt.Sprintf(", error: %s", frame.Error)
+		if reason := DecodeRevert(frame.Output); reason != "" {
+			line += fmt.Sprintf(" (%s)", reason)
+		}
+	}
+	fmt.Println(line)
+	for i := range frame.Calls {
+		printCallFrame(&frame.Calls[i], depth+1)
+	}
+}
@@ -0,0 +1,50 @@
+package transaction
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+func TestSimulate(t *testing.T) {
+	t.Run("ParseArgs", func(t *testing.T) {
+		opts, err := ParseSimulateArgs([]string{"--to", "0x000000000000000000000000000000000000dEaD", "--value", "1000", "--data", "0x01ff", "--trace"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if opts.Value.Int64() != 1000 || len(opts.Data) != 2 || !opts.Trace {
+			t.Errorf("unexpected options %+v", opts)
+		}
+
+		for _, args := range [][]string{
+			nil,
+			{"--to", "0xnothex"},
+			{"--to", "0x000000000000000000000000000000000000dEaD", "--value", "-1"},
+			{"--to", "0x000000000000000000000000000000000000dEaD", "--value", "1.5"},
+			{"--data", "zz"},
+		} {
+			if _, err := ParseSimulateArgs(args); err == nil {
+				t.Errorf("%v: expected an error", args)
+			}
+		}
+	})
+
+	t.Run("DecodeRevert", func(t *testing.T) {
+		for data, want := range map[string]string{
+			"0x": "",
+			// require(false, "not owner")
+			"0x08c379a0" +
+				"0000000000000000000000000000000000000000000000000000000000000020" +
+				"0000000000000000000000000000000000000000000000000000000000000009" +
+				"6e6f74206f776e65720000000000000000000000000000000000000000000000": `Error("not owner")`,
+			"0x4e487b710000000000000000000000000000000000000000000000000000000000000011": "Panic(0x11): arithmetic overflow or underflow",
+			"0x4e487b7100000000000000000000000000000000000000000000000000000000000000ff": "Panic(0xff): unknown panic code",
+			"0x82b42900": "custom error 0x82b42900",
+			"0x0102":     "malformed revert data 0x0102",
+		} {
+			if got := DecodeRevert(hexutil.MustDecode(data)); got != want {
+				t.Errorf("%s: expected %q, got %q", data, want, got)
+			}
+		}
+	})
+}
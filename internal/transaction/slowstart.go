@@ -0,0 +1,36 @@
+package transaction
+
+import "time"
+
+// SlowStart activates a run's wallets gradually: Initial wallets start at once and
+// the number of active wallets doubles every Interval until the whole pool sends.
+// Starting a thousand wallets together floods the node with balance, nonce and send
+// calls at the same instant and pollutes the first minutes of the run.
+type SlowStart struct {
+	Initial  int           // Wallets active from the start
+	Interval time.Duration // Time between doublings
+}
+
+// delay returns how long the wallet at index waits before it starts sending
+func (s *SlowStart) delay(index int) time.Duration {
+	if s == nil || s.Interval <= 0 || index < s.Initial {
+		return 0
+	}
+	steps, active := 0, s.Initial
+	if active < 1 {
+		active = 1
+	}
+	for active <= index {
+		active *= 2
+		steps++
+	}
+	return time.Duration(steps) * s.Interval
+}
+
+// Duration returns how long it takes until all of wallets are active
+func (s *SlowStart) Duration(wallets int) time.Duration {
+	if wallets == 0 {
+		return 0
+	}
+	return s.delay(wallets - 1)
+}
@@ -0,0 +1,31 @@
+package transaction
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSlowStartDelay(t *testing.T) {
+	slow := &SlowStart{Initial: 10, Interval: 5 * time.Second}
+	cases := map[int]time.Duration{
+		0:   0,
+		9:   0,
+		10:  5 * time.Second, // 20 active after one doubling
+		19:  5 * time.Second,
+		20:  10 * time.Second, // 40 after two
+		999: 35 * time.Second, // 1280 after seven
+	}
+	for index, want := range cases {
+		if got := slow.delay(index); got != want {
+			t.Errorf("wallet %d: expected delay %s, got %s", index, want, got)
+		}
+	}
+	if got := slow.Duration(1000); got != 35*time.Second {
+		t.Errorf("expected 1000 wallets to be active after 35s, got %s", got)
+	}
+
+	var disabled *SlowStart
+	if disabled.delay(500) != 0 {
+		t.Error("expected no delay without a slow start")
+	}
+}
@@ -0,0 +1,148 @@
+package transaction
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// Event types written to an EventStream
+const (
+	EventSent     = "sent"     // The node accepted the transaction
+	EventRejected = "rejected" // The node refused one attempt; it may be retried
+	EventFailed   = "failed"   // The transaction was given up on
+	EventIncluded = "included" // The transaction was seen in a block
+	EventDropped  = "dropped"  // The transaction was not seen in a block within DropTimeout
+)
+
+// TxEvent is one line of the event stream. Fields that do not apply to the event
+// are left out of the JSON.
+type TxEvent struct {
+	Time      time.Time       `json:"time"`
+	Event     string          `json:"event"`
+	Wallet    *int            `json:"wallet,omitempty"` // Index in the wallet pool
+	From      common.Address  `json:"from"`
+	Hash      *common.Hash    `json:"hash,omitempty"`
+	Nonce     *uint64         `json:"nonce,omitempty"`
+	To        *common.Address `json:"to,omitempty"` // Absent for contract creations
+	GasPrice  *big.Int        `json:"gasPrice,omitempty"`
+	Attempt   int             `json:"attempt,omitempty"` // 1-based send attempt, for sent and rejected
+	Error     string          `json:"error,omitempty"`
+	Block     uint64          `json:"block,omitempty"`
+	LatencyMs float64         `json:"latencyMs,omitempty"` // Send to inclusion
+}
+
+// EventStream writes transaction events as newline-delimited JSON, one object per
+// line, as they happen, so they can be piped into jq, vector or fluent-bit while the
+// run is going
+type EventStream struct {
+	mu      sync.Mutex
+	encoder *json.Encoder
+	closer  io.Closer
+	count   int64
+	err     error // First write error; later events are dropped
+}
+
+// NewEventStream streams events to w
+func NewEventStream(w io.Writer) *EventStream {
+	return &EventStream{encoder: json.NewEncoder(w)}
+}
+
+// OpenEventStream streams events to stdout when target is "stdout", otherwise to the
+// file at target, replacing an existing one. Streaming to stdout moves everything
+// else the simulator prints to stderr, so stdout carries only events.
+func OpenEventStream(target string) (*EventStream, error) {
+	if target == "stdout" {
+		// Go exits on a write to a closed stdout pipe unless SIGPIPE is handled;
+		// ignoring it turns the write into an error, so the run outlives its reader
+		signal.Ignore(syscall.SIGPIPE)
+		stream := NewEventStream(os.Stdout)
+		os.Stdout = os.Stderr
+		return stream, nil
+	}
+	file, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create event stream file: %w", err)
+	}
+	stream := NewEventStream(file)
+	stream.closer = file
+	return stream, nil
+}
+
+// Emit writes one event. A failed write, such as a closed pipe, stops the stream
+// without affecting the run; Err reports it.
+func (s *EventStream) Emit(event TxEvent) {
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return
+	}
+	if err := s.encoder.Encode(event); err != nil {
+		s.err = err
+		return
+	}
+	s.count++
+}
+
+// Count returns how many events were written
+func (s *EventStream) Count() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.count
+}
+
+// Err returns the write error that stopped the stream, if any
+func (s *EventStream) Err() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.err
+}
+
+// Close closes the stream's file. Streams to stdout or a caller's writer are left open.
+func (s *EventStream) Close() error {
+	if s.closer == nil {
+		return nil
+	}
+	return s.closer.Close()
+}
+
+// txEvent describes a signed transaction sent by wallet w
+func txEvent(event string, w *ParallelWallet, tx *types.Transaction, attempt int) TxEvent {
+	hash, nonce, index := tx.Hash(), tx.Nonce(), w.Index
+	return TxEvent{
+		Event:    event,
+		Wallet:   &index,
+		From:     w.Address,
+		Hash:     &hash,
+		Nonce:    &nonce,
+		To:       tx.To(),
+		GasPrice: tx.GasPrice(),
+		Attempt:  attempt,
+	}
+}
+
+// includedEvent describes a tracked transaction seen in block
+func includedEvent(tracked *TrackedTx, block uint64, at time.Time) TxEvent {
+	hash, nonce := tracked.Hash, tracked.Nonce
+	return TxEvent{
+		Time:      at,
+		Event:     EventIncluded,
+		From:      tracked.From,
+		Hash:      &hash,
+		Nonce:     &nonce,
+		Block:     block,
+		LatencyMs: float64(at.Sub(tracked.SentAt)) / float64(time.Millisecond),
+	}
+}
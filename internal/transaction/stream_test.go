@@ -0,0 +1,59 @@
+package transaction
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// failingWriter fails every write, like a pipe whose reader has exited
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) { return 0, errors.New("broken pipe") }
+
+func TestEventStream(t *testing.T) {
+	var buf bytes.Buffer
+	stream := NewEventStream(&buf)
+	w := &ParallelWallet{Address: common.Address{0x01}, Index: 3}
+	tx := types.NewTransaction(7, common.Address{0x02}, big.NewInt(0), 21000, big.NewInt(5), nil)
+
+	stream.Emit(txEvent(EventSent, w, tx, 1))
+	sentAt := time.Now()
+	stream.Emit(includedEvent(&TrackedTx{Hash: tx.Hash(), From: w.Address, Nonce: 7, SentAt: sentAt}, 42, sentAt.Add(1500*time.Millisecond)))
+
+	scanner := bufio.NewScanner(&buf)
+	var lines []map[string]interface{}
+	for scanner.Scan() {
+		var line map[string]interface{}
+		if err := json.Unmarshal(scanner.Bytes(), &line); err != nil {
+			t.Fatalf("expected one JSON object per line, got %q: %v", scanner.Text(), err)
+		}
+		lines = append(lines, line)
+	}
+	if len(lines) != 2 || stream.Count() != 2 {
+		t.Fatalf("expected 2 events, got %d lines and a count of %d", len(lines), stream.Count())
+	}
+	if lines[0]["event"] != EventSent || lines[0]["wallet"] != 3.0 || lines[0]["nonce"] != 7.0 || lines[0]["hash"] != tx.Hash().Hex() {
+		t.Errorf("unexpected sent event: %v", lines[0])
+	}
+	if _, ok := lines[0]["block"]; ok {
+		t.Errorf("expected fields that do not apply to be left out, got %v", lines[0])
+	}
+	if lines[1]["event"] != EventIncluded || lines[1]["block"] != 42.0 || lines[1]["latencyMs"] != 1500.0 {
+		t.Errorf("unexpected included event: %v", lines[1])
+	}
+
+	broken := NewEventStream(failingWriter{})
+	broken.Emit(TxEvent{Event: EventFailed})
+	broken.Emit(TxEvent{Event: EventFailed})
+	if broken.Err() == nil || broken.Count() != 0 {
+		t.Error("expected a failed write to stop the stream")
+	}
+}
@@ -0,0 +1,69 @@
+package transaction
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// Supported submission methods
+const (
+	SendMethodRaw     = "eth_sendRawTransaction"
+	SendMethodPrivate = "eth_sendPrivateTransaction"
+)
+
+// Submitter submits signed transactions to the network.
+// *ethclient.Client satisfies it, so the read endpoint is used when no
+// separate write endpoint is configured.
+type Submitter interface {
+	SendTransaction(ctx context.Context, tx *types.Transaction) error
+}
+
+// RPCSubmitter submits transactions to a dedicated write endpoint such as a
+// rollup sequencer or a private mempool relay
+type RPCSubmitter struct {
+	client *rpc.Client
+	method string
+}
+
+// NewRPCSubmitter connects to a write endpoint using the given send method
+func NewRPCSubmitter(ctx context.Context, rpcURL, method string) (*RPCSubmitter, error) {
+	if method != SendMethodRaw && method != SendMethodPrivate {
+		return nil, fmt.Errorf("unsupported send method: %s", method)
+	}
+
+	client, err := rpc.DialContext(ctx, rpcURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to write RPC: %w", err)
+	}
+
+	return &RPCSubmitter{
+		client: client,
+		method: method,
+	}, nil
+}
+
+// SendTransaction submits a signed transaction using the configured method
+func (s *RPCSubmitter) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	raw, err := tx.MarshalBinary()
+	if err != nil {
+		return fmt.Errorf("failed to encode transaction: %w", err)
+	}
+
+	if s.method == SendMethodPrivate {
+		// Flashbots Protect style: {"tx": "0x..."}
+		params := map[string]interface{}{"tx": hexutil.Encode(raw)}
+		return s.client.CallContext(ctx, nil, s.method, params)
+	}
+	return s.client.CallContext(ctx, nil, s.method, hexutil.Encode(raw))
+}
+
+// Close closes the write endpoint connection
+func (s *RPCSubmitter) Close() {
+	if s.client != nil {
+		s.client.Close()
+	}
+}
@@ -0,0 +1,249 @@
+package transaction
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"math/rand"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// TxTemplate is a user-defined transaction in a template file. To and Data may
+// contain placeholders:
+//
+//	${wallet_address}  sending wallet's address
+//	${wallet}          sending wallet's pool index
+//	${seq}             per-wallet sequence number
+//	${random_uint256}  random 256-bit number
+//	${random_address}  random address
+//
+// In To a placeholder expands to an address. In Data, which is hex, each expands to
+// one 32-byte ABI word, so calldata can be written as a selector followed by
+// arguments, e.g. 0xa9059cbb${random_address}${random_uint256}.
+// Value is a wei amount or random(min,max) for a uniform random amount.
+type TxTemplate struct {
+	Name     string `json:"name"`
+	To       string `json:"to"`
+	Value    string `json:"value"`
+	Data     string `json:"data"`
+	GasLimit uint64 `json:"gasLimit"` // 0 uses the workload's gas limit
+	Weight   int    `json:"weight"`   // Relative frequency, default 1
+}
+
+// TemplateTx is one transaction built from a template
+type TemplateTx struct {
+	Template string
+	To       common.Address
+	Value    *big.Int
+	Data     []byte
+	GasLimit uint64
+}
+
+// TxBuilder builds parallel-mode transactions from user-defined logic instead of
+// the built-in transfers and contract calls
+type TxBuilder interface {
+	Build(rng *rand.Rand, walletIndex int, address common.Address, seq uint64) (*TemplateTx, error)
+}
+
+// TemplateSet picks and expands templates by weight
+type TemplateSet struct {
+	templates   []TxTemplate
+	totalWeight int
+}
+
+// templatePlaceholders are the placeholders templates may use
+var templatePlaceholders = []string{"wallet_address", "wallet", "seq", "random_uint256", "random_address"}
+
+// LoadTemplates reads a JSON array of templates from path
+func LoadTemplates(path string) (*TemplateSet, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read template file: %w", err)
+	}
+	var templates []TxTemplate
+	if err := json.Unmarshal(data, &templates); err != nil {
+		return nil, fmt.Errorf("failed to decode template file: %w", err)
+	}
+	return NewTemplateSet(templates)
+}
+
+// NewTemplateSet validates templates and returns a set to build transactions from
+func NewTemplateSet(templates []TxTemplate) (*TemplateSet, error) {
+	if len(templates) == 0 {
+		return nil, errors.New("no transaction templates defined")
+	}
+	set := &TemplateSet{}
+	for i, t := range templates {
+		if t.Name == "" {
+			t.Name = fmt.Sprintf("template %d", i)
+		}
+		if t.Weight == 0 {
+			t.Weight = 1
+		}
+		if t.Weight < 0 {
+			return nil, fmt.Errorf("%s: weight cannot be negative", t.Name)
+		}
+		if t.To == "" {
+			return nil, fmt.Errorf("%s: to is required", t.Name)
+		}
+		// Expand once with fixed values to catch unknown placeholders and bad hex early
+		probe := &templateContext{rng: rand.New(rand.NewSource(1))}
+		if _, err := probe.to(t.To); err != nil {
+			return nil, fmt.Errorf("%s: invalid to: %w", t.Name, err)
+		}
+		if _, err := probe.data(t.Data); err != nil {
+			return nil, fmt.Errorf("%s: invalid data: %w", t.Name, err)
+		}
+		if _, err := probe.value(t.Value); err != nil {
+			return nil, fmt.Errorf("%s: invalid value: %w", t.Name, err)
+		}
+		set.templates = append(set.templates, t)
+		set.totalWeight += t.Weight
+	}
+	return set, nil
+}
+
+// Build picks a template by weight and expands it for one transaction
+func (s *TemplateSet) Build(rng *rand.Rand, walletIndex int, address common.Address, seq uint64) (*TemplateTx, error) {
+	pick := rng.Intn(s.totalWeight)
+	t := s.templates[len(s.templates)-1]
+	for _, candidate := range s.templates {
+		if pick < candidate.Weight {
+			t = candidate
+			break
+		}
+		pick -= candidate.Weight
+	}
+
+	ctx := &templateContext{rng: rng, walletIndex: walletIndex, wallet: address, seq: seq}
+	to, err := ctx.to(t.To)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", t.Name, err)
+	}
+	data, err := ctx.data(t.Data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", t.Name, err)
+	}
+	value, err := ctx.value(t.Value)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", t.Name, err)
+	}
+	return &TemplateTx{Template: t.Name, To: to, Value: value, Data: data, GasLimit: t.GasLimit}, nil
+}
+
+// templateContext holds the values placeholders expand to for one transaction
+type templateContext struct {
+	rng         *rand.Rand
+	walletIndex int
+	wallet      common.Address
+	seq         uint64
+}
+
+// word returns the 32-byte ABI word for a placeholder
+func (c *templateContext) word(name string) (common.Hash, error) {
+	switch name {
+	case "wallet_address":
+		return common.BytesToHash(c.wallet.Bytes()), nil
+	case "wallet":
+		return common.BigToHash(big.NewInt(int64(c.walletIndex))), nil
+	case "seq":
+		return common.BigToHash(new(big.Int).SetUint64(c.seq)), nil
+	case "random_uint256":
+		var h common.Hash
+		c.rng.Read(h[:])
+		return h, nil
+	case "random_address":
+		var a common.Address
+		c.rng.Read(a[:])
+		return common.BytesToHash(a.Bytes()), nil
+	}
+	return common.Hash{}, fmt.Errorf("unknown placeholder ${%s} (expected one of %s)", name, strings.Join(templatePlaceholders, ", "))
+}
+
+// expand replaces every ${name} in s with render(word)
+func (c *templateContext) expand(s string, render func(common.Hash) string) (string, error) {
+	var b strings.Builder
+	for {
+		start := strings.Index(s, "${")
+		if start < 0 {
+			b.WriteString(s)
+			return b.String(), nil
+		}
+		end := strings.IndexByte(s[start:], '}')
+		if end < 0 {
+			return "", fmt.Errorf("unterminated placeholder in %q", s)
+		}
+		word, err := c.word(s[start+2 : start+end])
+		if err != nil {
+			return "", err
+		}
+		b.WriteString(s[:start])
+		b.WriteString(render(word))
+		s = s[start+end+1:]
+	}
+}
+
+// to expands a To template into an address
+func (c *templateContext) to(s string) (common.Address, error) {
+	expanded, err := c.expand(s, func(word common.Hash) string {
+		return common.BytesToAddress(word.Bytes()).Hex()
+	})
+	if err != nil {
+		return common.Address{}, err
+	}
+	if !common.IsHexAddress(expanded) {
+		return common.Address{}, fmt.Errorf("%q is not an address", expanded)
+	}
+	return common.HexToAddress(expanded), nil
+}
+
+// data expands a Data template into calldata
+func (c *templateContext) data(s string) ([]byte, error) {
+	if s == "" {
+		return nil, nil
+	}
+	expanded, err := c.expand(s, func(word common.Hash) string {
+		return strings.TrimPrefix(hexutil.Encode(word.Bytes()), "0x")
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !strings.HasPrefix(expanded, "0x") {
+		expanded = "0x" + expanded
+	}
+	return hexutil.Decode(expanded)
+}
+
+// value evaluates a Value expression: empty (zero), a wei amount or random(min,max)
+func (c *templateContext) value(s string) (*big.Int, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return big.NewInt(0), nil
+	}
+	if args, ok := strings.CutPrefix(s, "random("); ok && strings.HasSuffix(args, ")") {
+		lo, hi, found := strings.Cut(strings.TrimSuffix(args, ")"), ",")
+		if !found {
+			return nil, fmt.Errorf("random needs a minimum and a maximum: %q", s)
+		}
+		min, err := strconv.ParseInt(strings.TrimSpace(lo), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid minimum in %q", s)
+		}
+		max, err := strconv.ParseInt(strings.TrimSpace(hi), 10, 64)
+		if err != nil || max < min || min < 0 {
+			return nil, fmt.Errorf("invalid maximum in %q", s)
+		}
+		return big.NewInt(min + c.rng.Int63n(max-min+1)), nil
+	}
+	value, ok := new(big.Int).SetString(s, 10)
+	if !ok || value.Sign() < 0 {
+		return nil, fmt.Errorf("%q is not a wei amount or random(min,max)", s)
+	}
+	return value, nil
+}
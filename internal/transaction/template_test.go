@@ -0,0 +1,58 @@
+package transaction
+
+import (
+	"bytes"
+	"math/rand"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestTemplates(t *testing.T) {
+	wallet := common.Address{0x01, 0x02}
+
+	t.Run("DataPlaceholdersAreWords", func(t *testing.T) {
+		c := &templateContext{rng: rand.New(rand.NewSource(1)), wallet: wallet, seq: 7}
+		data, err := c.data("0xa9059cbb${wallet_address}${random_uint256}")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(data) != 4+2*32 {
+			t.Fatalf("expected a selector and two words, got %d bytes", len(data))
+		}
+		if !bytes.Equal(data[4+12:4+32], wallet.Bytes()) {
+			t.Errorf("expected the wallet address left-padded in the first word, got %x", data[4:36])
+		}
+	})
+
+	t.Run("ValueExpressions", func(t *testing.T) {
+		c := &templateContext{rng: rand.New(rand.NewSource(1))}
+		for i := 0; i < 100; i++ {
+			v, err := c.value("random(10, 20)")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if v.Int64() < 10 || v.Int64() > 20 {
+				t.Fatalf("random value %s outside [10, 20]", v)
+			}
+		}
+		if v, _ := c.value("1000"); v.Int64() != 1000 {
+			t.Errorf("expected 1000, got %s", v)
+		}
+		for _, bad := range []string{"-1", "random(5)", "random(9,1)", "lots"} {
+			if _, err := c.value(bad); err == nil {
+				t.Errorf("expected an error for %q", bad)
+			}
+		}
+	})
+
+	t.Run("RejectsUnknownPlaceholders", func(t *testing.T) {
+		_, err := NewTemplateSet([]TxTemplate{{To: "0x0000000000000000000000000000000000000001", Data: "0x${nonce}"}})
+		if err == nil {
+			t.Error("expected an error for an unknown placeholder")
+		}
+		if _, err := NewTemplateSet(nil); err == nil {
+			t.Error("expected an error for an empty template list")
+		}
+	})
+}
@@ -0,0 +1,89 @@
+package transaction
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"strings"
+	"time"
+)
+
+// ThinkTime is the pause a wallet takes after each transaction, drawn from a
+// distribution so wallets behave like users rather than tight loops
+type ThinkTime struct {
+	Distribution string        // "fixed", "uniform" or "exponential"
+	Min          time.Duration // Fixed: the pause; uniform: the lower bound
+	Max          time.Duration // Uniform: the upper bound
+	Mean         time.Duration // Exponential: the mean pause
+}
+
+// ParseThinkTime parses fixed:DURATION, uniform:MIN-MAX or exponential:MEAN,
+// e.g. uniform:500ms-3s. Durations use Go syntax.
+func ParseThinkTime(spec string) (*ThinkTime, error) {
+	distribution, args, ok := strings.Cut(strings.TrimSpace(spec), ":")
+	if !ok {
+		return nil, fmt.Errorf("think time %q must be fixed:D, uniform:MIN-MAX or exponential:MEAN", spec)
+	}
+	t := &ThinkTime{Distribution: strings.ToLower(distribution)}
+	parse := func(s string) (time.Duration, error) {
+		d, err := time.ParseDuration(strings.TrimSpace(s))
+		if err != nil {
+			return 0, fmt.Errorf("invalid think time duration %q", s)
+		}
+		if d < 0 {
+			return 0, fmt.Errorf("think time duration cannot be negative: %q", s)
+		}
+		return d, nil
+	}
+	var err error
+	switch t.Distribution {
+	case "fixed":
+		t.Min, err = parse(args)
+	case "uniform":
+		lo, hi, found := strings.Cut(args, "-")
+		if !found {
+			return nil, fmt.Errorf("uniform think time needs MIN-MAX (got: %s)", args)
+		}
+		if t.Min, err = parse(lo); err != nil {
+			return nil, err
+		}
+		if t.Max, err = parse(hi); err != nil {
+			return nil, err
+		}
+		if t.Max < t.Min {
+			return nil, fmt.Errorf("uniform think time maximum is below its minimum (got: %s)", args)
+		}
+	case "exponential":
+		t.Mean, err = parse(args)
+	default:
+		return nil, fmt.Errorf("unknown think time distribution %q (expected fixed, uniform or exponential)", distribution)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// Sample draws one pause
+func (t *ThinkTime) Sample(rng *rand.Rand) time.Duration {
+	switch t.Distribution {
+	case "uniform":
+		return t.Min + time.Duration(rng.Int63n(int64(t.Max-t.Min)+1))
+	case "exponential":
+		return time.Duration(rng.ExpFloat64() * float64(t.Mean))
+	default:
+		return t.Min
+	}
+}
+
+// think pauses for one sample, returning false when ctx is cancelled first
+func (t *ThinkTime) think(ctx context.Context, rng *rand.Rand) bool {
+	timer := time.NewTimer(t.Sample(rng))
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
@@ -0,0 +1,459 @@
+package transaction
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// TrackedTx is a sent transaction that has not been seen in a block yet
+type TrackedTx struct {
+	Hash     common.Hash
+	From     common.Address
+	Nonce    uint64
+	GasPrice *big.Int
+	SentAt   time.Time
+	verified bool // Counted as succeeded by the confirmation check, see MarkVerified
+}
+
+// BlockStats summarizes one scanned block for observers such as soak and adaptive controllers
+type BlockStats struct {
+	Number    uint64
+	SeenAt    time.Time       // When the block was scanned, the inclusion time of its tracked transactions
+	TxCount   int             // All transactions in the block
+	Ours      int             // Tracked transactions included in the block
+	Latencies []time.Duration // Send-to-inclusion latency of each tracked transaction
+	Positions []int           // Index within the block of each tracked transaction
+}
+
+// ContractObserver follows the calls a run sends to the contracts it watches, such as
+// a registry of the run's contracts
+type ContractObserver interface {
+	Watches(address common.Address) bool
+	RecordSent(address common.Address)        // The node accepted a call
+	RecordSendFailure(address common.Address) // A call was given up on before the node accepted it
+	RecordReceipt(address common.Address, receipt *types.Receipt)
+}
+
+// ErrTrackerStopped is returned by Acquire once the tracker has stopped scanning
+// blocks, since in-flight caps can no longer be enforced
+var ErrTrackerStopped = errors.New("inclusion tracker stopped, in-flight caps cannot be enforced")
+
+// Tracker scan tuning
+const (
+	maxScanFailures = 30               // Consecutive failed block scans after which Run gives up
+	settleAge       = 30 * time.Second // Age after which pending transactions are checked against the mined nonce
+)
+
+// Tracker follows sent transactions until they are included in a block.
+// It scans each new block once instead of polling receipts per transaction,
+// so the RPC cost is one call per block regardless of the send rate.
+//
+// In-flight slots are taken with Acquire before a transaction is built and held
+// until it is included, dropped, superseded by another transaction with its nonce,
+// or given up on with Release. Track registers the transaction of a slot before it
+// is sent, so a transaction mined in the very next block is still seen.
+type Tracker struct {
+	client     *ethclient.Client
+	pending    map[common.Hash]*TrackedTx
+	inFlight   int                    // Slots taken by Acquire and not yet freed
+	perWallet  map[common.Address]int // inFlight by wallet
+	lastBlock  uint64
+	started    bool
+	stopped    chan struct{} // Closed when Run returns
+	observer   func(BlockStats)
+	included   func(tracked *TrackedTx, block uint64, at time.Time)
+	dropped    func(tracked *TrackedTx)
+	contracts  ContractObserver
+	dropAfter  time.Duration
+	assertions *EventAssertions
+	positions  positionHistogram
+	drops      dropCounter
+	mu         sync.Mutex
+	// Metrics
+	totalTracked    int64
+	totalMined      int64
+	totalSuperseded int64 // Pending transactions whose nonce was mined in another transaction
+}
+
+// NewTracker creates a new transaction tracker
+func NewTracker(client *ethclient.Client) *Tracker {
+	return &Tracker{
+		client:    client,
+		pending:   make(map[common.Hash]*TrackedTx),
+		perWallet: make(map[common.Address]int),
+		stopped:   make(chan struct{}),
+	}
+}
+
+// Acquire takes an in-flight slot for from, waiting while the global or per-wallet
+// count is at its cap. A cap of 0 means unlimited. The check and the take happen
+// under one lock, so concurrent senders cannot overshoot a cap. Once Run has
+// returned it fails with ErrTrackerStopped.
+func (t *Tracker) Acquire(ctx context.Context, from common.Address, maxInFlight, maxPerWallet int) error {
+	ticker := time.NewTicker(50 * time.Millisecond)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-t.stopped:
+			return ErrTrackerStopped
+		default:
+		}
+		t.mu.Lock()
+		full := (maxInFlight > 0 && t.inFlight >= maxInFlight) ||
+			(maxPerWallet > 0 && t.perWallet[from] >= maxPerWallet)
+		if !full {
+			t.inFlight++
+			t.perWallet[from]++
+		}
+		t.mu.Unlock()
+		if !full {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-t.stopped:
+			return ErrTrackerStopped
+		case <-ticker.C:
+		}
+	}
+}
+
+// Release frees a slot of from whose transaction was given up on
+func (t *Tracker) Release(from common.Address) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.free(from)
+}
+
+// free frees a slot of from (caller holds mu)
+func (t *Tracker) free(from common.Address) {
+	t.inFlight--
+	t.perWallet[from]--
+}
+
+// Track registers the transaction of a slot taken by Acquire, before it is sent
+func (t *Tracker) Track(tx *types.Transaction, from common.Address) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.pending[tx.Hash()] = &TrackedTx{
+		Hash:     tx.Hash(),
+		From:     from,
+		Nonce:    tx.Nonce(),
+		GasPrice: tx.GasPrice(),
+		SentAt:   time.Now(),
+	}
+	atomic.AddInt64(&t.totalTracked, 1)
+}
+
+// Untrack forgets a transaction the node refused. Its slot stays taken for the
+// retry, or until Release.
+func (t *Tracker) Untrack(hash common.Hash) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if _, ok := t.pending[hash]; ok {
+		delete(t.pending, hash)
+		atomic.AddInt64(&t.totalTracked, -1)
+	}
+}
+
+// SetBlockObserver registers fn to be called with the stats of every scanned block.
+// It must be set before Run.
+func (t *Tracker) SetBlockObserver(fn func(BlockStats)) {
+	t.observer = fn
+}
+
+// SetInclusionObserver registers fn to be called for every tracked transaction
+// seen in a block. It must be set before Run.
+func (t *Tracker) SetInclusionObserver(fn func(tracked *TrackedTx, block uint64, at time.Time)) {
+	t.included = fn
+}
+
+// SetDropTimeout makes the tracker give up on transactions not included within
+// timeout, counting them as dropped and calling fn for each. It must be set before Run.
+func (t *Tracker) SetDropTimeout(timeout time.Duration, fn func(tracked *TrackedTx)) {
+	t.dropAfter, t.dropped = timeout, fn
+}
+
+// MarkVerified records that the confirmation check counted hash as succeeded, so a
+// later drop can take that back. It reports whether hash is still in flight.
+func (t *Tracker) MarkVerified(hash common.Hash) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	tracked, ok := t.pending[hash]
+	if ok {
+		tracked.verified = true
+	}
+	return ok
+}
+
+// SetContractObserver makes the tracker fetch the receipt of every included call to a
+// contract o watches and pass it on. It must be set before Run.
+func (t *Tracker) SetContractObserver(o ContractObserver) {
+	t.contracts = o
+}
+
+// SetAssertions makes the tracker check the events of included contract calls
+func (t *Tracker) SetAssertions(a *EventAssertions) {
+	t.assertions = a
+}
+
+// Start records the current head, so that blocks mined after it are scanned. It
+// must be called before the first transaction is tracked and before Run.
+func (t *Tracker) Start(ctx context.Context) error {
+	head, err := t.client.BlockNumber(ctx)
+	if err != nil {
+		return err
+	}
+	t.lastBlock = head
+	t.started = true
+	return nil
+}
+
+// Run scans new blocks every interval until the context is cancelled. It gives up
+// when scans fail maxScanFailures times in a row; Acquire then returns
+// ErrTrackerStopped instead of waiting on counts that no longer go down.
+func (t *Tracker) Run(ctx context.Context, interval time.Duration) error {
+	defer close(t.stopped)
+	if !t.started {
+		if err := t.Start(ctx); err != nil {
+			return err
+		}
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	failures := 0
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if err := t.scanNewBlocks(ctx); err != nil && ctx.Err() == nil {
+				if failures++; failures >= maxScanFailures {
+					return fmt.Errorf("block scan failed %d times in a row: %w", failures, err)
+				}
+				continue
+			}
+			failures = 0
+			t.settle(ctx, time.Now())
+			if t.dropAfter > 0 {
+				t.expire(time.Now())
+			}
+		}
+	}
+}
+
+// scanNewBlocks removes transactions included in blocks after lastBlock
+func (t *Tracker) scanNewBlocks(ctx context.Context) error {
+	head, err := t.client.BlockNumber(ctx)
+	if err != nil {
+		return err // Retry on next tick
+	}
+
+	for number := t.lastBlock + 1; number <= head; number++ {
+		block, err := t.client.BlockByNumber(ctx, new(big.Int).SetUint64(number))
+		if err != nil {
+			return err // Resume from this block on next tick
+		}
+		scan := t.markIncluded(block)
+		t.lastBlock = number
+		if t.assertions != nil {
+			t.assertions.verify(ctx, t, scan.asserted)
+		}
+		if len(scan.calls) > 0 {
+			t.recordCalls(ctx, scan.calls)
+		}
+		if t.observer != nil {
+			t.observer(scan.stats)
+		}
+		for _, tracked := range scan.included {
+			t.included(tracked, number, scan.stats.SeenAt)
+		}
+	}
+	return nil
+}
+
+// settle frees the slots of transactions whose nonce was used by another
+// transaction, such as a replacement sent from outside the run, or that were
+// included in a block the scan could not see. Only transactions pending for
+// settleAge are checked, with one nonce query per wallet at the last scanned block,
+// so ones mined in a block not yet scanned are still counted as included.
+func (t *Tracker) settle(ctx context.Context, now time.Time) {
+	t.mu.Lock()
+	lowest := make(map[common.Address]uint64)
+	for _, tracked := range t.pending {
+		if now.Sub(tracked.SentAt) < settleAge {
+			continue
+		}
+		if nonce, ok := lowest[tracked.From]; !ok || tracked.Nonce < nonce {
+			lowest[tracked.From] = tracked.Nonce
+		}
+	}
+	t.mu.Unlock()
+
+	block := new(big.Int).SetUint64(t.lastBlock)
+	for from, nonce := range lowest {
+		mined, err := t.client.NonceAt(ctx, from, block)
+		if err != nil {
+			return // Retry on next tick
+		}
+		if mined > nonce {
+			t.supersede(from, mined)
+		}
+	}
+}
+
+// supersede frees the slots of from's pending transactions with a nonce below mined
+func (t *Tracker) supersede(from common.Address, mined uint64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for hash, tracked := range t.pending {
+		if tracked.From != from || tracked.Nonce >= mined {
+			continue
+		}
+		delete(t.pending, hash)
+		t.free(from)
+		atomic.AddInt64(&t.totalSuperseded, 1)
+	}
+}
+
+// recordCalls passes the receipts of our included contract calls to the contract
+// observer. Receipts that cannot be fetched are skipped, so those calls are not
+// counted as mined.
+func (t *Tracker) recordCalls(ctx context.Context, calls []*types.Transaction) {
+	for _, tx := range calls {
+		receipt, err := t.client.TransactionReceipt(ctx, tx.Hash())
+		if err != nil {
+			continue
+		}
+		t.contracts.RecordReceipt(*tx.To(), receipt)
+	}
+}
+
+// blockScan is what markIncluded found in one block
+type blockScan struct {
+	stats    BlockStats
+	asserted []*types.Transaction // Included calls with event assertions to check
+	included []*TrackedTx         // Every included tracked transaction, when an inclusion observer is set
+	calls    []*types.Transaction // Included calls to contracts the contract observer watches
+}
+
+// markIncluded removes the block's transactions from the pending set and collects
+// what the observers and assertions need from them
+func (t *Tracker) markIncluded(block *types.Block) blockScan {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	txs := block.Transactions()
+	now := time.Now()
+	scan := blockScan{stats: BlockStats{Number: block.NumberU64(), SeenAt: now, TxCount: len(txs)}}
+	for i, tx := range txs {
+		tracked, ok := t.pending[tx.Hash()]
+		if !ok {
+			continue
+		}
+		delete(t.pending, tx.Hash())
+		t.free(tracked.From)
+		atomic.AddInt64(&t.totalMined, 1)
+		scan.stats.Ours++
+		scan.stats.Latencies = append(scan.stats.Latencies, now.Sub(tracked.SentAt))
+		scan.stats.Positions = append(scan.stats.Positions, i)
+		if t.included != nil {
+			scan.included = append(scan.included, tracked)
+		}
+		if t.assertions != nil && tx.To() != nil && t.assertions.expects(tx.Data()) {
+			scan.asserted = append(scan.asserted, tx)
+		}
+		if t.contracts != nil && tx.To() != nil && t.contracts.Watches(*tx.To()) {
+			scan.calls = append(scan.calls, tx)
+		}
+	}
+	t.positions.add(scan.stats.Positions, len(txs))
+	return scan
+}
+
+// expire moves transactions sent more than the drop timeout before now from the
+// pending set to the dropped counts. Blocks are scanned first, so a transaction
+// included in time is never dropped because the scan ran late.
+func (t *Tracker) expire(now time.Time) {
+	t.mu.Lock()
+	var expired []*TrackedTx
+	for hash, tracked := range t.pending {
+		if now.Sub(tracked.SentAt) < t.dropAfter {
+			continue
+		}
+		delete(t.pending, hash)
+		t.free(tracked.From)
+		t.drops.add(tracked)
+		expired = append(expired, tracked)
+	}
+	t.mu.Unlock()
+	if t.dropped != nil {
+		for _, tracked := range expired {
+			t.dropped(tracked)
+		}
+	}
+}
+
+// Drops returns the transactions counted as dropped so far
+func (t *Tracker) Drops() *DropReport {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.drops.report()
+}
+
+// InFlight returns the number of transactions being sent or not yet included in a block
+func (t *Tracker) InFlight() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.inFlight
+}
+
+// InFlightFor returns the number of in-flight transactions of address
+func (t *Tracker) InFlightFor(address common.Address) int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.perWallet[address]
+}
+
+// PendingAges returns how long each tracked transaction not yet included has been
+// waiting at now
+func (t *Tracker) PendingAges(now time.Time) []time.Duration {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	ages := make([]time.Duration, 0, len(t.pending))
+	for _, tracked := range t.pending {
+		ages = append(ages, now.Sub(tracked.SentAt))
+	}
+	return ages
+}
+
+// Mined returns the number of tracked transactions included in a block
+func (t *Tracker) Mined() int64 {
+	return atomic.LoadInt64(&t.totalMined)
+}
+
+// Superseded returns the number of tracked transactions whose nonce was mined in
+// another transaction
+func (t *Tracker) Superseded() int64 {
+	return atomic.LoadInt64(&t.totalSuperseded)
+}
+
+// Positions returns where the tracked transactions landed within their blocks
+func (t *Tracker) Positions() *PositionReport {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.positions.report()
+}
@@ -0,0 +1,163 @@
+package wallet
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+const (
+	defaultFundingWindow    = 16 // geth guarantees every account 16 executable pool slots
+	defaultFundingMaxWindow = 50 // Upper bound the window grows to while the node keeps accepting, see SetFundingWindow
+	fundingPollInterval     = 500 * time.Millisecond
+	fundingRetries          = 5               // Resends of one funding transaction refused for pool pressure
+	fundingStallTimeout     = 2 * time.Minute // Longest acquire waits without a funding transaction being mined
+)
+
+// errFundingGap reports a funder nonce that was given up on and could not be
+// filled, which holds back every later funding transaction
+var errFundingGap = errors.New("funder nonce gap")
+
+// fundingPacer paces the funder's transactions by how the node takes them. At most
+// window of them may be unmined at once. The window grows by one after a window's
+// worth of acceptances and halves whenever the node pushes back, so funding runs
+// as fast as the node's per-account limits allow instead of at a fixed concurrency.
+type fundingPacer struct {
+	client  *ethclient.Client
+	address common.Address
+
+	mu        sync.Mutex
+	window    int
+	maxWindow int
+	streak    int    // Acceptances since the window last changed
+	active    int    // Transactions admitted and not yet answered
+	sent      uint64 // Highest accepted nonce + 1
+	mined     uint64 // Funder's mined nonce at the last poll
+	lastPoll  time.Time
+	progress  time.Time // When the mined nonce last moved or a transaction was last accepted
+	pushbacks int       // Refusals that shrank the window
+}
+
+// newFundingPacer starts pacing the funder at address from its mined nonce
+func newFundingPacer(ctx context.Context, client *ethclient.Client, address common.Address, window, maxWindow int) (*fundingPacer, error) {
+	mined, err := client.NonceAt(ctx, address, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &fundingPacer{
+		client:    client,
+		address:   address,
+		window:    window,
+		maxWindow: maxWindow,
+		sent:      mined,
+		mined:     mined,
+		lastPoll:  time.Now(),
+		progress:  time.Now(),
+	}, nil
+}
+
+// acquire blocks until one more transaction fits in the window. It gives up when
+// no funding transaction is accepted or mined for fundingStallTimeout, since the
+// window can then only free up if the funder's pending transactions move again.
+func (p *fundingPacer) acquire(ctx context.Context) error {
+	for {
+		p.mu.Lock()
+		if p.admit() {
+			p.mu.Unlock()
+			return nil
+		}
+		poll := time.Since(p.lastPoll) >= fundingPollInterval
+		stalled := time.Since(p.progress) >= fundingStallTimeout
+		p.mu.Unlock()
+
+		if poll {
+			if mined, err := p.client.NonceAt(ctx, p.address, nil); err == nil {
+				p.mu.Lock()
+				if mined > p.mined {
+					p.mined = mined
+					p.progress = time.Now()
+				}
+				p.lastPoll = time.Now()
+				p.mu.Unlock()
+				continue
+			}
+		}
+		if stalled {
+			return fmt.Errorf("funding stalled: no funding transaction was mined for %s", fundingStallTimeout)
+		}
+		if err := sleepContext(ctx, fundingPollInterval/5); err != nil {
+			return err
+		}
+	}
+}
+
+// admit takes a slot if the unmined and in-progress transactions leave room; the
+// caller holds mu
+func (p *fundingPacer) admit() bool {
+	unmined := 0
+	if p.sent > p.mined {
+		unmined = int(p.sent - p.mined)
+	}
+	if unmined+p.active >= p.window {
+		return false
+	}
+	p.active++
+	return true
+}
+
+// readmit takes a slot for a resend of a transaction whose nonce is already held.
+// It skips the window check: the held nonce blocks every later one from being
+// mined, so waiting for the window could wait forever.
+func (p *fundingPacer) readmit() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.active++
+}
+
+// accepted releases the slot of a transaction the node accepted
+func (p *fundingPacer) accepted(nonce uint64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.active--
+	p.progress = time.Now()
+	if nonce+1 > p.sent {
+		p.sent = nonce + 1
+	}
+	p.streak++
+	if p.streak >= p.window && p.window < p.maxWindow {
+		p.window++
+		p.streak = 0
+	}
+}
+
+// pushedBack releases the slot of a transaction the node refused for pool
+// pressure and halves the window
+func (p *fundingPacer) pushedBack() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.active--
+	p.pushbacks++
+	p.streak = 0
+	if p.window > 1 {
+		p.window /= 2
+	}
+}
+
+// failed releases the slot of a transaction that failed for another reason
+func (p *fundingPacer) failed() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.active--
+}
+
+// stats returns the final window and how often the node pushed back
+func (p *fundingPacer) stats() (window, pushbacks int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.window, p.pushbacks
+}
@@ -0,0 +1,135 @@
+package wallet
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/aakash4dev/ethereum-transaction-simulator/internal/transaction"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// CancelOptions holds the arguments of the cancel-pending subcommand
+type CancelOptions struct {
+	WalletsPath string // Optional wallet file whose workers are cleared too
+	MaxBumps    int    // Fee bumps tried per nonce when the replacement is underpriced
+}
+
+// ParseCancelArgs parses `simulator cancel-pending [--wallets wallets.json] [--max-bumps 8]`
+func ParseCancelArgs(args []string) (*CancelOptions, error) {
+	fs := flag.NewFlagSet("cancel-pending", flag.ContinueOnError)
+	walletsPath := fs.String("wallets", "", "wallet file whose worker wallets are cleared too (optional)")
+	maxBumps := fs.Int("max-bumps", 8, "fee bumps of 12.5% tried per nonce when the replacement is underpriced")
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+	if *maxBumps < 0 {
+		return nil, errors.New("--max-bumps cannot be negative")
+	}
+	return &CancelOptions{WalletsPath: *walletsPath, MaxBumps: *maxBumps}, nil
+}
+
+// CancelResult is what cancel-pending did for one wallet
+type CancelResult struct {
+	Address   common.Address
+	Pending   int // Nonces between the mined and the pending nonce
+	Cancelled int // Replacements accepted by the node
+	Err       error
+}
+
+// CancelPending replaces every pending transaction of each wallet with a zero-value
+// transfer to itself at the same nonce. Replacements start at twice the suggested gas
+// price and are bumped by 12.5% up to maxBumps times while the node reports them as
+// underpriced. Only nonces below the pending nonce are seen; transactions queued
+// behind a nonce gap are left alone.
+func (m *Manager) CancelPending(ctx context.Context, wallets []*Wallet, maxBumps int) ([]*CancelResult, error) {
+	gasPrice, err := m.client.SuggestGasPrice(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get gas price: %w", err)
+	}
+	startPrice := new(big.Int).Mul(gasPrice, big.NewInt(2))
+
+	results := make([]*CancelResult, len(wallets))
+	var wg sync.WaitGroup
+	semaphore := make(chan struct{}, 50) // Limit concurrent operations
+	for i, wallet := range wallets {
+		results[i] = &CancelResult{Address: wallet.Address}
+		wg.Add(1)
+		go func(w *Wallet, result *CancelResult) {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+			result.Err = m.cancelWallet(ctx, w, startPrice, maxBumps, result)
+		}(wallet, results[i])
+	}
+	wg.Wait()
+	return results, nil
+}
+
+// cancelWallet replaces the pending transactions of one wallet
+func (m *Manager) cancelWallet(ctx context.Context, w *Wallet, startPrice *big.Int, maxBumps int, result *CancelResult) error {
+	mined, err := m.client.NonceAt(ctx, w.Address, nil)
+	if err != nil {
+		return fmt.Errorf("failed to get mined nonce: %w", err)
+	}
+	pending, err := m.client.PendingNonceAt(ctx, w.Address)
+	if err != nil {
+		return fmt.Errorf("failed to get pending nonce: %w", err)
+	}
+	if pending <= mined {
+		return nil
+	}
+	result.Pending = int(pending - mined)
+
+	for nonce := mined; nonce < pending; nonce++ {
+		price := new(big.Int).Set(startPrice)
+		for bump := 0; ; bump++ {
+			tx := types.NewTransaction(nonce, w.Address, big.NewInt(0), fundingTxGas, price, nil)
+			signedTx, err := transaction.SignTx(tx, m.chainID, w.PrivateKey)
+			if err != nil {
+				return fmt.Errorf("failed to sign replacement: %w", err)
+			}
+			err = m.submitter.SendTransaction(ctx, signedTx)
+			if err == nil {
+				result.Cancelled++
+				break
+			}
+			if transaction.IsNonceTooLow(err) {
+				break // Mined meanwhile, nothing left to cancel at this nonce
+			}
+			if !transaction.IsReplacementUnderpriced(err) || bump >= maxBumps {
+				return fmt.Errorf("failed to replace nonce %d: %w", nonce, err)
+			}
+			// Replacements must pay at least 10% more than the transaction they replace
+			price.Mul(price, big.NewInt(9))
+			price.Div(price, big.NewInt(8))
+			price.Add(price, big.NewInt(1))
+		}
+	}
+	return nil
+}
+
+// PrintCancelResults prints the pending and cancelled counts of every wallet that had pending transactions
+func PrintCancelResults(results []*CancelResult) {
+	fmt.Printf("\n=== Cancel Pending ===\n")
+	stuck, cancelled, failed := 0, 0, 0
+	for _, r := range results {
+		if r.Pending == 0 && r.Err == nil {
+			continue
+		}
+		stuck++
+		cancelled += r.Cancelled
+		fmt.Printf("%s: pending %d, cancelled %d\n", r.Address.Hex(), r.Pending, r.Cancelled)
+		if r.Err != nil {
+			failed++
+			fmt.Printf("  error: %v\n", r.Err)
+		}
+	}
+	fmt.Printf("Wallets checked: %d, with pending transactions: %d, failed: %d\n", len(results), stuck, failed)
+	fmt.Printf("Replacements sent: %d\n", cancelled)
+	fmt.Printf("==========================\n")
+}
@@ -0,0 +1,51 @@
+package wallet
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// BalanceSetter sets account balances without transactions, as development nodes
+// such as anvil and hardhat allow
+type BalanceSetter interface {
+	SetBalance(ctx context.Context, address common.Address, wei *big.Int) error
+}
+
+// FundBySetBalance gives every wallet the funding amount by setting its balance
+// directly. Nothing is sent or mined, so funding a thousand wallets takes as long as
+// a thousand RPC calls instead of a thousand transactions.
+func (m *Manager) FundBySetBalance(ctx context.Context, setter BalanceSetter, wallets []*Wallet) error {
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var failed int
+	semaphore := make(chan struct{}, 50) // Limit concurrent operations
+
+	for i, wallet := range wallets {
+		if wallet == nil {
+			continue
+		}
+		wg.Add(1)
+		go func(index int, w *Wallet) {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			if err := setter.SetBalance(ctx, w.Address, m.fundingAmount); err != nil {
+				fmt.Printf("Setting the balance of wallet %d (%s) failed: %v\n", index, w.Address.Hex(), err)
+				mu.Lock()
+				failed++
+				mu.Unlock()
+			}
+		}(i, wallet)
+	}
+	wg.Wait()
+
+	if failed > 0 {
+		return fmt.Errorf("set balance errors: %d wallets failed", failed)
+	}
+	return nil
+}
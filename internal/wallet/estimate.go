@@ -0,0 +1,122 @@
+package wallet
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"strings"
+	"time"
+)
+
+// EstimateLine is the planned transactions of one workload type
+type EstimateLine struct {
+	Type     string
+	Count    int
+	GasLimit uint64
+	Value    *big.Int // Sent with each transaction
+}
+
+// EstimateInput is the workload a run plans to send
+type EstimateInput struct {
+	Lines       []EstimateLine
+	WalletCount int     // Worker wallets funded before the run, 0 when sending from the funding wallet
+	Rate        float64 // Planned transactions per second, 0 when not paced
+}
+
+// Estimate is the cost preview of a run at the current gas price
+type Estimate struct {
+	Lines        []EstimateLine
+	GasPrice     *big.Int
+	Transactions int
+	Gas          uint64   // Gas limit of every planned transaction, summed; an upper bound on gas used
+	Fees         *big.Int // Gas * GasPrice
+	Value        *big.Int // Value transferred by the planned transactions
+	FundingFees  *big.Int // Fees of the transfers funding the worker wallets
+	Required     *big.Int // Fees + Value + FundingFees
+	WalletCount  int
+	Duration     time.Duration // Projected at the planned rate, 0 when not paced
+}
+
+// BuildEstimate prices input at gasPrice
+func BuildEstimate(input *EstimateInput, gasPrice *big.Int) *Estimate {
+	e := &Estimate{
+		Lines:       input.Lines,
+		GasPrice:    gasPrice,
+		Fees:        new(big.Int),
+		Value:       new(big.Int),
+		WalletCount: input.WalletCount,
+	}
+	for _, line := range input.Lines {
+		e.Transactions += line.Count
+		e.Gas += line.GasLimit * uint64(line.Count)
+		e.Value.Add(e.Value, new(big.Int).Mul(line.Value, big.NewInt(int64(line.Count))))
+	}
+	e.Fees.Mul(gasPrice, new(big.Int).SetUint64(e.Gas))
+	e.FundingFees = new(big.Int).Mul(gasPrice, big.NewInt(int64(fundingTxGas*input.WalletCount)))
+	e.Required = new(big.Int).Add(e.Fees, e.Value)
+	e.Required.Add(e.Required, e.FundingFees)
+	if input.Rate > 0 {
+		e.Duration = time.Duration(float64(e.Transactions) / input.Rate * float64(time.Second))
+	}
+	return e
+}
+
+// PrintEstimate prints the planned transactions, gas, fees, required funding and duration
+func PrintEstimate(e *Estimate) {
+	fmt.Printf("\n=== Run Estimate ===\n")
+	fmt.Printf("Gas price: %s wei\n", e.GasPrice.String())
+	for _, line := range e.Lines {
+		fmt.Printf("%-10s %8d txs x %d gas\n", line.Type, line.Count, line.GasLimit)
+	}
+	fmt.Printf("Transactions planned: %d\n", e.Transactions)
+	fmt.Printf("Gas (upper bound): %d\n", e.Gas)
+	fmt.Printf("Fees: %s wei\n", e.Fees.String())
+	fmt.Printf("Value transferred: %s wei\n", e.Value.String())
+	if e.WalletCount > 0 {
+		fmt.Printf("Funding %d wallets: %s wei in fees\n", e.WalletCount, e.FundingFees.String())
+	}
+	fmt.Printf("Required funding: %s wei\n", e.Required.String())
+	if e.Duration > 0 {
+		fmt.Printf("Projected duration: %s\n", e.Duration.Round(time.Second))
+	} else {
+		fmt.Printf("Projected duration: unknown (no target rate set)\n")
+	}
+	fmt.Printf("==========================\n")
+}
+
+// ConfirmEstimate asks on out whether to go ahead and reads the answer from in.
+// Anything but y or yes declines.
+func ConfirmEstimate(in io.Reader, out io.Writer) bool {
+	return confirm(in, out, "Proceed with this run?")
+}
+
+// confirm asks question on out and reports whether the answer read from in is y or yes
+func confirm(in io.Reader, out io.Writer, question string) bool {
+	fmt.Fprintf(out, "%s [y/N] ", question)
+	answer, _ := bufio.NewReader(in).ReadString('\n')
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
+
+// Interactive reports whether stdin is a terminal someone can answer a prompt on.
+// In CI it is not, and --estimate exits after printing instead of prompting.
+func Interactive() bool {
+	info, err := os.Stdin.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// EstimateFromArgs removes an --estimate flag from args and reports whether it was present
+func EstimateFromArgs(args []string) (bool, []string) {
+	estimate := false
+	rest := make([]string, 0, len(args))
+	for _, arg := range args {
+		if arg == "--estimate" || arg == "-estimate" {
+			estimate = true
+			continue
+		}
+		rest = append(rest, arg)
+	}
+	return estimate, rest
+}
@@ -0,0 +1,96 @@
+package wallet
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"sync"
+	"time"
+)
+
+// ExportAddresses writes the wallets' addresses to w, one per line or, with format
+// "json", as a JSON array. Private keys are never written.
+func ExportAddresses(w io.Writer, wallets []*Wallet, format string) error {
+	addresses := make([]string, 0, len(wallets))
+	for _, wallet := range wallets {
+		if wallet != nil {
+			addresses = append(addresses, wallet.Address.Hex())
+		}
+	}
+
+	switch format {
+	case "json":
+		data, err := json.MarshalIndent(addresses, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode addresses: %w", err)
+		}
+		_, err = fmt.Fprintf(w, "%s\n", data)
+		return err
+	case "", "lines":
+		for _, address := range addresses {
+			if _, err := fmt.Fprintln(w, address); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown address export format: %s", format)
+	}
+}
+
+// WaitForFunding polls the wallets' balances every interval until each holds at least
+// minBalance, for chains where wallets are funded externally (e.g. through a faucet)
+// rather than by the funding wallet. It returns early only when ctx is cancelled.
+func (m *Manager) WaitForFunding(ctx context.Context, wallets []*Wallet, minBalance *big.Int, interval time.Duration) error {
+	pending := make([]*Wallet, 0, len(wallets))
+	for _, w := range wallets {
+		if w != nil {
+			pending = append(pending, w)
+		}
+	}
+
+	for {
+		pending = m.unfunded(ctx, pending, minBalance)
+		if len(pending) == 0 {
+			fmt.Printf("All %d wallets funded\n", len(wallets))
+			return nil
+		}
+		fmt.Printf("Waiting for external funding: %d/%d wallets funded\n", len(wallets)-len(pending), len(wallets))
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("stopped waiting for funding with %d wallets unfunded: %w", len(pending), ctx.Err())
+		case <-time.After(interval):
+		}
+	}
+}
+
+// unfunded returns the wallets whose balance is below minBalance. Wallets whose
+// balance cannot be read are kept and checked again on the next poll.
+func (m *Manager) unfunded(ctx context.Context, wallets []*Wallet, minBalance *big.Int) []*Wallet {
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	remaining := make([]*Wallet, 0, len(wallets))
+	semaphore := make(chan struct{}, 50) // Limit concurrent operations
+
+	for _, wallet := range wallets {
+		wg.Add(1)
+		go func(w *Wallet) {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			balance, err := m.client.BalanceAt(ctx, w.Address, nil)
+			if err == nil && balance.Cmp(minBalance) >= 0 {
+				return
+			}
+			mu.Lock()
+			remaining = append(remaining, w)
+			mu.Unlock()
+		}(wallet)
+	}
+	wg.Wait()
+	return remaining
+}
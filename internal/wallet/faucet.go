@@ -0,0 +1,170 @@
+package wallet
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Faucet requests testnet funds for an address
+type Faucet interface {
+	Request(ctx context.Context, address common.Address) error
+}
+
+// faucetPreset describes a faucet API that can be reached by URL alone
+type faucetPreset struct {
+	path string // Appended to the faucet's base URL
+	body string // Request body template
+}
+
+// faucetPresets holds the faucet APIs supported without a custom body template
+var faucetPresets = map[string]faucetPreset{
+	// chainflag/eth-faucet, common on private devnets and testnet mirrors
+	"eth-faucet": {path: "/api/claim", body: `{"address":"{address}"}`},
+}
+
+// DefaultFaucetBody is the request body of the generic HTTP faucet
+const DefaultFaucetBody = `{"address":"{address}"}`
+
+// HTTPFaucet requests funds with a JSON POST whose body is rendered from a template
+type HTTPFaucet struct {
+	url          string
+	bodyTemplate string // {address} is replaced by the recipient
+	apiKey       string // Sent as a bearer token when set
+	client       *http.Client
+}
+
+// NewHTTPFaucet creates a faucet client that POSTs bodyTemplate to url
+func NewHTTPFaucet(url, bodyTemplate, apiKey string) *HTTPFaucet {
+	if bodyTemplate == "" {
+		bodyTemplate = DefaultFaucetBody
+	}
+	return &HTTPFaucet{
+		url:          url,
+		bodyTemplate: bodyTemplate,
+		apiKey:       apiKey,
+		client:       &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// NewFaucet creates a faucet client of the given kind: "http" posts bodyTemplate to
+// url as-is, while a preset name (e.g. "eth-faucet") only needs the faucet's base URL
+func NewFaucet(kind, url, bodyTemplate, apiKey string) (Faucet, error) {
+	if kind == "" || kind == "http" {
+		return NewHTTPFaucet(url, bodyTemplate, apiKey), nil
+	}
+	preset, ok := faucetPresets[kind]
+	if !ok {
+		return nil, fmt.Errorf("unknown faucet type: %s", kind)
+	}
+	return NewHTTPFaucet(strings.TrimSuffix(url, "/")+preset.path, preset.body, apiKey), nil
+}
+
+// Request asks the faucet to fund address
+func (f *HTTPFaucet) Request(ctx context.Context, address common.Address) error {
+	req, err := f.newRequest(ctx, address)
+	if err != nil {
+		return err
+	}
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("faucet request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("faucet returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(message)))
+	}
+	return nil
+}
+
+// newRequest builds the faucet request for address
+func (f *HTTPFaucet) newRequest(ctx context.Context, address common.Address) (*http.Request, error) {
+	body := strings.ReplaceAll(f.bodyTemplate, "{address}", address.Hex())
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, f.url, bytes.NewReader([]byte(body)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create faucet request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if f.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+f.apiKey)
+	}
+	return req, nil
+}
+
+// SetFaucet lets FundFromFaucet fund wallets through faucet, sending at most
+// requestsPerMinute requests and retrying each failed request up to retries times
+func (m *Manager) SetFaucet(faucet Faucet, requestsPerMinute, retries int) {
+	m.faucet = faucet
+	m.faucetRate = requestsPerMinute
+	m.faucetRetries = retries
+}
+
+// FundFromFaucet requests funds for every wallet from the configured faucet, for when
+// the funding wallet has no balance. Requests are paced to the faucet's rate limit and
+// failures are retried with exponential backoff starting at retryDelay. Faucets pay out
+// asynchronously, so callers should follow up with WaitForFunding.
+func (m *Manager) FundFromFaucet(ctx context.Context, wallets []*Wallet, retryDelay time.Duration) error {
+	if m.faucet == nil {
+		return fmt.Errorf("no faucet configured")
+	}
+
+	interval := time.Duration(0)
+	if m.faucetRate > 0 {
+		interval = time.Minute / time.Duration(m.faucetRate)
+	}
+
+	var failed int
+	var lastRequest time.Time
+	for i, w := range wallets {
+		if w == nil {
+			continue
+		}
+
+		var err error
+		delay := retryDelay
+		for attempt := 0; attempt <= m.faucetRetries; attempt++ {
+			if attempt > 0 {
+				if err := sleepContext(ctx, delay); err != nil {
+					return err
+				}
+				delay *= 2
+			}
+			if wait := interval - time.Since(lastRequest); wait > 0 {
+				if err := sleepContext(ctx, wait); err != nil {
+					return err
+				}
+			}
+			lastRequest = time.Now()
+			if err = m.faucet.Request(ctx, w.Address); err == nil {
+				break
+			}
+		}
+		if err != nil {
+			failed++
+			fmt.Printf("Faucet request for wallet %d (%s) failed: %v\n", i, w.Address.Hex(), err)
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("faucet funding errors: %d wallets failed", failed)
+	}
+	return nil
+}
+
+// sleepContext sleeps for d or until ctx is cancelled
+func sleepContext(ctx context.Context, d time.Duration) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(d):
+		return nil
+	}
+}
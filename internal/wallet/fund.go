@@ -0,0 +1,95 @@
+package wallet
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// FundOptions holds the arguments of the fund subcommand
+type FundOptions struct {
+	Count  int      // Wallets to generate and fund
+	Amount *big.Int // Wei sent to each wallet
+	Out    string   // File the generated wallets are written to
+	Force  bool     // Overwrite Out when it already exists
+}
+
+// ParseFundArgs parses `simulator fund --count 500 --amount 0.01ether --out wallets.json [--force]`
+func ParseFundArgs(args []string) (*FundOptions, error) {
+	fs := flag.NewFlagSet("fund", flag.ContinueOnError)
+	count := fs.Int("count", 1000, "number of wallets to generate and fund")
+	amount := fs.String("amount", "100", "amount per wallet: wei, or with a gwei/ether suffix")
+	out := fs.String("out", "wallets.json", "file to write the funded wallets to")
+	force := fs.Bool("force", false, "overwrite --out if it exists, losing the keys it holds")
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	if *count <= 0 {
+		return nil, errors.New("--count must be greater than 0")
+	}
+	parsed, err := ParseAmount(*amount)
+	if err != nil {
+		return nil, fmt.Errorf("--amount: %w", err)
+	}
+	if *out == "" {
+		return nil, errors.New("--out is required")
+	}
+	return &FundOptions{Count: *count, Amount: parsed, Out: *out, Force: *force}, nil
+}
+
+// ParseAmount parses an amount in wei, or a decimal amount with a "gwei" or "ether" suffix
+func ParseAmount(s string) (*big.Int, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	decimals := 0
+	switch {
+	case strings.HasSuffix(s, "gwei"):
+		s, decimals = strings.TrimSuffix(s, "gwei"), 9
+	case strings.HasSuffix(s, "ether"):
+		s, decimals = strings.TrimSuffix(s, "ether"), 18
+	case strings.HasSuffix(s, "wei"):
+		s = strings.TrimSuffix(s, "wei")
+	}
+
+	s = strings.TrimSpace(s)
+
+	whole, fraction, _ := strings.Cut(s, ".")
+	if len(fraction) > decimals {
+		return nil, fmt.Errorf("invalid amount %q: too many decimal places", s)
+	}
+	digits := whole + fraction + strings.Repeat("0", decimals-len(fraction))
+	amount, ok := new(big.Int).SetString(digits, 10)
+	if !ok || amount.Sign() < 0 {
+		return nil, fmt.Errorf("invalid amount %q", s)
+	}
+	return amount, nil
+}
+
+// FundToFile generates opts.Count wallets, writes them to opts.Out and funds each
+// with opts.Amount, so funding can run separately from (and ahead of) the load run.
+// The file is written before funding, and an existing file is only replaced with
+// opts.Force, so no funded key is ever lost.
+func (m *Manager) FundToFile(ctx context.Context, funder *Wallet, opts *FundOptions) error {
+	generated := m.GenerateWallets(opts.Count)
+	wallets := make([]*Wallet, 0, len(generated))
+	for _, w := range generated {
+		if w != nil {
+			wallets = append(wallets, w)
+		}
+	}
+
+	if err := SaveWallets(opts.Out, wallets, opts.Force); err != nil {
+		return fmt.Errorf("%w; pass --force to replace it", err)
+	}
+	fmt.Printf("Wrote %d wallets to %s\n", len(wallets), opts.Out)
+
+	m.SetFundingAmount(opts.Amount)
+	if err := m.FundWallets(ctx, funder, wallets); err != nil {
+		return err
+	}
+	fmt.Printf("Funded %d wallets with %s wei each\n", len(wallets), opts.Amount.String())
+	return nil
+}
@@ -0,0 +1,87 @@
+package wallet
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// fundingTxGas is the gas used by each funding transfer
+const fundingTxGas = 21000
+
+// FundingPlan describes how much each worker wallet needs for its share of the workload
+type FundingPlan struct {
+	TxPerWallet int      // Transactions each wallet is expected to send (0 = even split of balance)
+	GasPrice    *big.Int // Fee estimate the plan was computed with
+	CostPerTx   *big.Int // gasLimit * gasPrice + value
+	PerWallet   *big.Int // Amount to fund each wallet
+	Total       *big.Int // PerWallet * wallet count, excluding funding fees
+}
+
+// PlanFunding computes the funding for one wallet sending txPerWallet transactions,
+// scaled by safetyPercent (150 = 50% headroom for fee movement)
+func PlanFunding(txPerWallet int, walletCount int, gasLimit uint64, gasPrice, value *big.Int, safetyPercent int) *FundingPlan {
+	costPerTx := new(big.Int).Mul(gasPrice, new(big.Int).SetUint64(gasLimit))
+	costPerTx.Add(costPerTx, value)
+
+	perWallet := new(big.Int).Mul(costPerTx, big.NewInt(int64(txPerWallet)))
+	perWallet.Mul(perWallet, big.NewInt(int64(safetyPercent)))
+	perWallet.Div(perWallet, big.NewInt(100))
+
+	return &FundingPlan{
+		TxPerWallet: txPerWallet,
+		GasPrice:    gasPrice,
+		CostPerTx:   costPerTx,
+		PerWallet:   perWallet,
+		Total:       new(big.Int).Mul(perWallet, big.NewInt(int64(walletCount))),
+	}
+}
+
+// EstimateFunding derives the per-wallet funding amount from the planned workload at the
+// current gas price. With a transaction cap the cap is split across wallets; without one
+// (maxTransactions == 0) the funder's balance, less funding fees and the reserve, is split evenly.
+func (m *Manager) EstimateFunding(ctx context.Context, funder common.Address, maxTransactions, walletCount int, gasLimit uint64, value *big.Int, safetyPercent int) (*FundingPlan, error) {
+	if walletCount <= 0 {
+		return nil, fmt.Errorf("wallet count must be greater than 0")
+	}
+
+	gasPrice, err := m.client.SuggestGasPrice(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get gas price: %w", err)
+	}
+
+	if maxTransactions > 0 {
+		txPerWallet := (maxTransactions + walletCount - 1) / walletCount
+		return PlanFunding(txPerWallet, walletCount, gasLimit, gasPrice, value, safetyPercent), nil
+	}
+
+	balance, err := m.client.BalanceAt(ctx, funder, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get funder balance: %w", err)
+	}
+	fundingFees := new(big.Int).Mul(gasPrice, big.NewInt(int64(fundingTxGas*walletCount)))
+	spendable := new(big.Int).Sub(balance, fundingFees)
+	spendable.Sub(spendable, m.reserve)
+	if spendable.Sign() <= 0 {
+		return nil, fmt.Errorf("funder balance %s does not cover funding fees %s and reserve %s", balance.String(), fundingFees.String(), m.reserve.String())
+	}
+
+	plan := PlanFunding(0, walletCount, gasLimit, gasPrice, value, safetyPercent)
+	plan.PerWallet = new(big.Int).Div(spendable, big.NewInt(int64(walletCount)))
+	plan.Total = new(big.Int).Mul(plan.PerWallet, big.NewInt(int64(walletCount)))
+	return plan, nil
+}
+
+// SetFundingAmount sets the amount sent to each wallet by FundWallets
+func (m *Manager) SetFundingAmount(amount *big.Int) {
+	m.fundingAmount = amount
+}
+
+// SetFundingWindow sets how many funding transactions FundWallets may have unmined
+// at first, and how far that window may grow while the node keeps accepting them
+func (m *Manager) SetFundingWindow(window, maxWindow int) {
+	m.fundingWindow = window
+	m.fundingMaxWindow = maxWindow
+}
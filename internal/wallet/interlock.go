@@ -0,0 +1,122 @@
+package wallet
+
+import (
+	"fmt"
+	"io"
+	"math/big"
+)
+
+// AllowMainnetFlag must be passed to run against a known public mainnet
+const AllowMainnetFlag = "--i-know-this-is-mainnet"
+
+// ChainClass is what kind of network a chain ID belongs to
+type ChainClass int
+
+const (
+	ChainUnknown ChainClass = iota // Devnets and private chains
+	ChainTestnet                   // Public testnets, whose funds are free but limited
+	ChainMainnet                   // Public mainnets, whose funds are real
+)
+
+// publicMainnets maps the chain IDs of widely used public mainnets to their names
+var publicMainnets = map[int64]string{
+	1:      "Ethereum mainnet",
+	10:     "OP Mainnet",
+	56:     "BNB Smart Chain",
+	100:    "Gnosis",
+	137:    "Polygon PoS",
+	250:    "Fantom Opera",
+	324:    "zkSync Era",
+	1101:   "Polygon zkEVM",
+	8453:   "Base",
+	42161:  "Arbitrum One",
+	42170:  "Arbitrum Nova",
+	42220:  "Celo",
+	43114:  "Avalanche C-Chain",
+	59144:  "Linea",
+	81457:  "Blast",
+	534352: "Scroll",
+}
+
+// publicTestnets maps the chain IDs of widely used public testnets to their names
+var publicTestnets = map[int64]string{
+	5:        "Goerli",
+	97:       "BNB Smart Chain testnet",
+	17000:    "Holesky",
+	80002:    "Polygon Amoy",
+	84532:    "Base Sepolia",
+	421614:   "Arbitrum Sepolia",
+	560048:   "Hoodi",
+	11155111: "Sepolia",
+	11155420: "OP Sepolia",
+}
+
+// ClassifyChain returns the class and, for known public networks, the name of chainID
+func ClassifyChain(chainID *big.Int) (ChainClass, string) {
+	if chainID == nil || !chainID.IsInt64() {
+		return ChainUnknown, ""
+	}
+	if name, ok := publicMainnets[chainID.Int64()]; ok {
+		return ChainMainnet, name
+	}
+	if name, ok := publicTestnets[chainID.Int64()]; ok {
+		return ChainTestnet, name
+	}
+	return ChainUnknown, ""
+}
+
+// Interlock guards against running on a public network by mistake, e.g. after a
+// fat-fingered RPC_URL
+type Interlock struct {
+	AllowMainnet      bool      // Set by --i-know-this-is-mainnet
+	TestnetSpendLimit *big.Int  // Planned spend above which a testnet run must be confirmed
+	Interactive       bool      // Whether In is a terminal that can answer a prompt
+	In                io.Reader // Where the confirmation is read from
+	Out               io.Writer // Where the prompt is written
+}
+
+// Check refuses a mainnet run unless AllowMainnet is set, and asks for confirmation
+// before a testnet run whose planned spend exceeds TestnetSpendLimit. A nil spend
+// means the run has no fixed cost and is treated as over the limit. Devnets and
+// unknown chains pass.
+func (l *Interlock) Check(chainID, spend *big.Int) error {
+	class, name := ClassifyChain(chainID)
+	switch class {
+	case ChainMainnet:
+		if !l.AllowMainnet {
+			return fmt.Errorf("chain ID %s is %s, refusing to run; pass %s if this is intended", chainID, name, AllowMainnetFlag)
+		}
+		return nil
+	case ChainTestnet:
+		if spend != nil && spend.Cmp(l.TestnetSpendLimit) <= 0 {
+			return nil
+		}
+		planned := "an unlimited amount (MAX_TRANSACTIONS=0)"
+		if spend != nil {
+			planned = spend.String() + " wei"
+		}
+		if !l.Interactive {
+			return fmt.Errorf("chain ID %s is %s and the run may spend %s, over TESTNET_SPEND_LIMIT; confirmation needs an interactive terminal", chainID, name, planned)
+		}
+		if !confirm(l.In, l.Out, fmt.Sprintf("Chain ID %s is %s and the run may spend %s. Continue?", chainID, name, planned)) {
+			return fmt.Errorf("run on %s not confirmed", name)
+		}
+		return nil
+	default:
+		return nil
+	}
+}
+
+// AllowMainnetFromArgs removes --i-know-this-is-mainnet from args and reports whether it was present
+func AllowMainnetFromArgs(args []string) (bool, []string) {
+	allow := false
+	rest := make([]string, 0, len(args))
+	for _, arg := range args {
+		if arg == AllowMainnetFlag {
+			allow = true
+			continue
+		}
+		rest = append(rest, arg)
+	}
+	return allow, rest
+}
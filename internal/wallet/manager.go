@@ -3,14 +3,18 @@ package wallet
 import (
 	"context"
 	"crypto/ecdsa"
+	"errors"
 	"fmt"
+	"log"
 	"math/big"
 	"sync"
+	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
 	"github.com/aakash4dev/ethereum-transaction-simulator/internal/transaction"
 )
 
@@ -27,6 +31,13 @@ type Manager struct {
 	client       *ethclient.Client
 	chainID      *big.Int
 	fundingAmount *big.Int
+	submitter    transaction.Submitter
+	reserve      *big.Int // Balance the funding wallet must keep
+	faucet       Faucet   // Funds wallets when the funding wallet cannot
+	faucetRate   int      // Faucet requests per minute, 0 = unlimited
+	faucetRetries int
+	fundingWindow int // Funding transactions allowed unmined at first, see fundingPacer
+	fundingMaxWindow int
 }
 
 // NewManager creates a new wallet manager
@@ -35,9 +46,49 @@ func NewManager(client *ethclient.Client, chainID *big.Int, fundingAmount *big.I
 		client:       client,
 		chainID:      chainID,
 		fundingAmount: fundingAmount,
+		submitter:    client,
+		reserve:      big.NewInt(0),
+		fundingWindow:    defaultFundingWindow,
+		fundingMaxWindow: defaultFundingMaxWindow,
 	}
 }
 
+// SetReserve sets the minimum balance the funding wallet must keep after funding,
+// so the orchestration account itself never becomes unusable mid-run
+func (m *Manager) SetReserve(reserve *big.Int) {
+	m.reserve = reserve
+}
+
+// CheckReserve returns how many of n transfers of amount (plus gasLimit at gasPrice each)
+// the address can afford without dropping below the reserve
+func (m *Manager) CheckReserve(ctx context.Context, address common.Address, n int, amount *big.Int, gasLimit uint64, gasPrice *big.Int) (int, error) {
+	balance, err := m.client.BalanceAt(ctx, address, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get funder balance: %w", err)
+	}
+
+	available := new(big.Int).Sub(balance, m.reserve)
+	if available.Sign() <= 0 {
+		return 0, nil
+	}
+	costPerTransfer := new(big.Int).Mul(gasPrice, new(big.Int).SetUint64(gasLimit))
+	costPerTransfer.Add(costPerTransfer, amount)
+	if costPerTransfer.Sign() == 0 {
+		return n, nil
+	}
+
+	affordable := new(big.Int).Div(available, costPerTransfer)
+	if affordable.Cmp(big.NewInt(int64(n))) >= 0 {
+		return n, nil
+	}
+	return int(affordable.Int64()), nil
+}
+
+// SetSubmitter routes funding transactions to a separate write endpoint
+func (m *Manager) SetSubmitter(submitter transaction.Submitter) {
+	m.submitter = submitter
+}
+
 // GenerateWallets generates n new wallets
 func (m *Manager) GenerateWallets(n int) []*Wallet {
 	wallets := make([]*Wallet, n)
@@ -61,49 +112,46 @@ func (m *Manager) GenerateWallets(n int) []*Wallet {
 }
 
 
-// FundWallets funds all wallets from the funding wallet in parallel
+// FundWallets funds all wallets from the funding wallet in parallel.
+// If funding every wallet would breach the funder reserve, only the wallets that
+// fit are funded and an error reports how many were skipped.
 func (m *Manager) FundWallets(ctx context.Context, fundingWallet *Wallet, wallets []*Wallet) error {
+	gasPrice, err := m.client.SuggestGasPrice(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get gas price: %w", err)
+	}
+	affordable, err := m.CheckReserve(ctx, fundingWallet.Address, len(wallets), m.fundingAmount, fundingTxGas, gasPrice)
+	if err != nil {
+		return err
+	}
+	var reserveErr error
+	if affordable < len(wallets) {
+		reserveErr = fmt.Errorf("funder reserve of %s wei would be breached: funding %d of %d wallets", m.reserve.String(), affordable, len(wallets))
+		wallets = wallets[:affordable]
+	}
+
+	pacer, err := newFundingPacer(ctx, m.client, fundingWallet.Address, m.fundingWindow, m.fundingMaxWindow)
+	if err != nil {
+		return fmt.Errorf("failed to get funder nonce: %w", err)
+	}
+
+	// A nonce gap that cannot be filled holds back every later funding transaction,
+	// so it stops the whole batch
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
 	var wg sync.WaitGroup
 	errChan := make(chan error, len(wallets))
-	semaphore := make(chan struct{}, 50) // Limit concurrent operations
 
 	for _, wallet := range wallets {
 		wg.Add(1)
 		go func(targetWallet *Wallet) {
 			defer wg.Done()
-			semaphore <- struct{}{} // Acquire semaphore
-			defer func() { <-semaphore }() // Release semaphore
-
-			nonce, err := fundingWallet.NonceManager.GetNextNonce(ctx)
-			if err != nil {
-				errChan <- fmt.Errorf("failed to get nonce for funding: %w", err)
-				return
-			}
-
-			gasPrice, err := m.client.SuggestGasPrice(ctx)
-			if err != nil {
-				errChan <- fmt.Errorf("failed to get gas price: %w", err)
-				return
-			}
-
-			tx := types.NewTransaction(
-				nonce,
-				targetWallet.Address,
-				m.fundingAmount,
-				21000, // Standard transfer gas limit
-				gasPrice,
-				nil,
-			)
-
-			signedTx, err := types.SignTx(tx, types.NewEIP155Signer(m.chainID), fundingWallet.PrivateKey)
-			if err != nil {
-				errChan <- fmt.Errorf("failed to sign funding transaction: %w", err)
-				return
-			}
-
-			if err := m.client.SendTransaction(ctx, signedTx); err != nil {
-				errChan <- fmt.Errorf("failed to send funding transaction to %s: %w", targetWallet.Address.Hex(), err)
-				return
+			if err := m.fundWallet(ctx, pacer, fundingWallet, targetWallet); err != nil {
+				if errors.Is(err, errFundingGap) {
+					cancel()
+				}
+				errChan <- err
 			}
 		}(wallet)
 	}
@@ -112,16 +160,139 @@ func (m *Manager) FundWallets(ctx context.Context, fundingWallet *Wallet, wallet
 	close(errChan)
 
 	// Collect errors
-	var errors []error
+	var failures []error
+	var gapErr error
 	for err := range errChan {
-		errors = append(errors, err)
+		failures = append(failures, err)
+		if errors.Is(err, errFundingGap) {
+			gapErr = err
+		}
 	}
 
-	if len(errors) > 0 {
-		return fmt.Errorf("funding errors: %d wallets failed", len(errors))
+	if window, pushbacks := pacer.stats(); pushbacks > 0 {
+		log.Printf("Funding was throttled %d times by the node's pool limits (final window: %d transactions)", pushbacks, window)
 	}
 
-	return nil
+	if gapErr != nil {
+		return fmt.Errorf("funding stopped after %d wallets failed: %w", len(failures), gapErr)
+	}
+	if len(failures) > 0 {
+		return fmt.Errorf("funding errors: %d wallets failed", len(failures))
+	}
+
+	return reserveErr
+}
+
+// fundWallet sends one funding transaction once the pacer admits it. Once it holds
+// a nonce it resends that nonce until the node refuses it for good: a refusal for
+// pool pressure shrinks the pacer's window and a lost request is resent, both after
+// a backoff. A nonce taken by another transaction is skipped. When the transaction
+// is given up on, its nonce is handed back or filled with a self-transfer, so it
+// never leaves a gap.
+func (m *Manager) fundWallet(ctx context.Context, pacer *fundingPacer, fundingWallet, targetWallet *Wallet) error {
+	if err := pacer.acquire(ctx); err != nil {
+		return err
+	}
+	nonce, err := fundingWallet.NonceManager.GetNextNonce(ctx)
+	if err != nil {
+		pacer.failed()
+		return fmt.Errorf("failed to get nonce for funding: %w", err)
+	}
+
+	for attempt := 0; ; attempt++ {
+		err := m.sendFunding(ctx, fundingWallet, targetWallet.Address, m.fundingAmount, nonce)
+		switch {
+		case err == nil:
+			pacer.accepted(nonce)
+			return nil
+		case transaction.IsNonceTooLow(err) || transaction.IsReplacementUnderpriced(err):
+			// Another transaction holds the nonce; it will be mined in our place
+			if transaction.IsReplacementUnderpriced(err) {
+				pacer.pushedBack()
+			} else {
+				pacer.failed()
+			}
+			if _, resyncErr := fundingWallet.NonceManager.MarkRejected(ctx, nonce, err); resyncErr != nil {
+				return fmt.Errorf("failed to resync funder nonce after %q: %w", err, resyncErr)
+			}
+			if attempt >= fundingRetries {
+				return fmt.Errorf("failed to send funding transaction to %s: %w", targetWallet.Address.Hex(), err)
+			}
+			if err := pacer.acquire(ctx); err != nil {
+				return err
+			}
+			if nonce, err = fundingWallet.NonceManager.GetNextNonce(ctx); err != nil {
+				pacer.failed()
+				return fmt.Errorf("failed to get nonce for funding: %w", err)
+			}
+		case attempt < fundingRetries && retryableFunding(err):
+			if transaction.IsPoolPressure(err) {
+				pacer.pushedBack()
+			} else {
+				pacer.failed()
+			}
+			if sleepErr := sleepContext(ctx, time.Duration(attempt+1)*time.Second); sleepErr != nil {
+				return m.giveUpFunding(ctx, pacer, fundingWallet, nonce, sleepErr)
+			}
+			pacer.readmit()
+		default:
+			pacer.failed()
+			return m.giveUpFunding(ctx, pacer, fundingWallet, nonce,
+				fmt.Errorf("failed to send funding transaction to %s: %w", targetWallet.Address.Hex(), err))
+		}
+	}
+}
+
+// sendFunding signs and sends a transfer of amount from the funder with nonce
+func (m *Manager) sendFunding(ctx context.Context, fundingWallet *Wallet, to common.Address, amount *big.Int, nonce uint64) error {
+	gasPrice, err := m.client.SuggestGasPrice(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get gas price: %w", err)
+	}
+	tx := types.NewTransaction(nonce, to, amount, fundingTxGas, gasPrice, nil)
+	signedTx, err := transaction.SignTx(tx, m.chainID, fundingWallet.PrivateKey)
+	if err != nil {
+		return fmt.Errorf("failed to sign funding transaction: %w", err)
+	}
+	err = m.submitter.SendTransaction(ctx, signedTx)
+	if transaction.IsAlreadyKnown(err) {
+		return nil // The node already holds this exact transaction
+	}
+	return err
+}
+
+// retryableFunding reports whether a funding send may succeed when resent with the
+// same nonce: the pool was full, or the request failed before the node answered.
+// Other refusals from the node, such as insufficient funds, are final.
+func retryableFunding(err error) bool {
+	var refusal rpc.Error
+	return transaction.IsPoolPressure(err) || !errors.As(err, &refusal)
+}
+
+// giveUpFunding releases the nonce of a funding transaction that was given up on
+// and returns sendErr. The nonce is handed back when no later one was taken, and
+// otherwise filled with a zero-value self-transfer; if that fails too, the returned
+// error wraps errFundingGap.
+func (m *Manager) giveUpFunding(ctx context.Context, pacer *fundingPacer, fundingWallet *Wallet, nonce uint64, sendErr error) error {
+	if fundingWallet.NonceManager.Rollback(nonce) {
+		return sendErr
+	}
+	var err error
+	for attempt := 0; attempt <= fundingRetries; attempt++ {
+		pacer.readmit()
+		err = m.sendFunding(ctx, fundingWallet, fundingWallet.Address, big.NewInt(0), nonce)
+		if err == nil || transaction.IsNonceTooLow(err) || transaction.IsReplacementUnderpriced(err) {
+			pacer.accepted(nonce) // The nonce is used either way
+			log.Printf("Filled funder nonce %d with a self-transfer after: %v", nonce, sendErr)
+			return sendErr
+		}
+		pacer.failed()
+		if sleepErr := sleepContext(ctx, time.Duration(attempt+1)*time.Second); sleepErr != nil {
+			err = sleepErr
+			break
+		}
+	}
+	return fmt.Errorf("%w: nonce %d could not be filled (%v) after: %v", errFundingGap, nonce, err, sendErr)
 }
 
 // CheckBalance checks if balance is sufficient
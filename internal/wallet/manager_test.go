@@ -1,8 +1,24 @@
 package wallet
 
 import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
 	"math/big"
+	"path/filepath"
+	"strings"
+	"sync"
 	"testing"
+	"time"
+
+	"github.com/aakash4dev/ethereum-transaction-simulator/internal/transaction"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
 )
 
 func TestWalletGeneration(t *testing.T) {
@@ -31,3 +47,541 @@ func TestBalanceCheck(t *testing.T) {
 	})
 }
 
+
+func TestPlanFunding(t *testing.T) {
+	t.Run("CoversWorkloadWithHeadroom", func(t *testing.T) {
+		// 10 txs of 21000 gas at 1 gwei plus 1 wei value, with 50% headroom
+		plan := PlanFunding(10, 100, 21000, big.NewInt(1000000000), big.NewInt(1), 150)
+
+		expectedPerTx := big.NewInt(21000*1000000000 + 1)
+		if plan.CostPerTx.Cmp(expectedPerTx) != 0 {
+			t.Errorf("expected cost per tx %s, got %s", expectedPerTx, plan.CostPerTx)
+		}
+
+		expectedPerWallet := new(big.Int).Mul(expectedPerTx, big.NewInt(15))
+		if plan.PerWallet.Cmp(expectedPerWallet) != 0 {
+			t.Errorf("expected per wallet %s, got %s", expectedPerWallet, plan.PerWallet)
+		}
+
+		expectedTotal := new(big.Int).Mul(expectedPerWallet, big.NewInt(100))
+		if plan.Total.Cmp(expectedTotal) != 0 {
+			t.Errorf("expected total %s, got %s", expectedTotal, plan.Total)
+		}
+	})
+}
+
+func TestParseAmount(t *testing.T) {
+	t.Run("Units", func(t *testing.T) {
+		cases := map[string]string{
+			"100":       "100",
+			"5gwei":     "5000000000",
+			"0.01ether": "10000000000000000",
+			"1.5 ether": "1500000000000000000",
+		}
+		for input, want := range cases {
+			got, err := ParseAmount(input)
+			if err != nil {
+				t.Fatalf("%s: unexpected error: %v", input, err)
+			}
+			if got.String() != want {
+				t.Errorf("%s: expected %s, got %s", input, want, got)
+			}
+		}
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		for _, input := range []string{"abc", "1.5", "0.0000000001gwei", "-1"} {
+			if _, err := ParseAmount(input); err == nil {
+				t.Errorf("%s: expected error", input)
+			}
+		}
+	})
+}
+
+func TestSaveWallets(t *testing.T) {
+	manager := NewManager(nil, big.NewInt(1337), big.NewInt(0))
+	path := filepath.Join(t.TempDir(), "wallets.json")
+	first := manager.GenerateWallets(2)
+	if err := SaveWallets(path, first, false); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("KeepsExistingFile", func(t *testing.T) {
+		if err := SaveWallets(path, manager.GenerateWallets(2), false); err == nil {
+			t.Fatal("expected an existing wallets file to be refused")
+		}
+		loaded, err := LoadWallets(path, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(loaded) != 2 || loaded[0].Address != first[0].Address {
+			t.Errorf("expected the first wallets to be kept, got %d wallets", len(loaded))
+		}
+	})
+
+	t.Run("OverwritesWhenAsked", func(t *testing.T) {
+		second := manager.GenerateWallets(1)
+		if err := SaveWallets(path, second, true); err != nil {
+			t.Fatal(err)
+		}
+		loaded, err := LoadWallets(path, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(loaded) != 1 || loaded[0].Address != second[0].Address {
+			t.Errorf("expected the file to be replaced, got %d wallets", len(loaded))
+		}
+	})
+}
+
+func TestExportAddresses(t *testing.T) {
+	wallets := []*Wallet{
+		{Address: common.HexToAddress("0x1")},
+		nil,
+		{Address: common.HexToAddress("0x2")},
+	}
+
+	t.Run("Lines", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := ExportAddresses(&buf, wallets, "lines"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		want := wallets[0].Address.Hex() + "\n" + wallets[2].Address.Hex() + "\n"
+		if buf.String() != want {
+			t.Errorf("expected %q, got %q", want, buf.String())
+		}
+	})
+
+	t.Run("JSON", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := ExportAddresses(&buf, wallets, "json"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		want := "[\n  \"" + wallets[0].Address.Hex() + "\",\n  \"" + wallets[2].Address.Hex() + "\"\n]\n"
+		if buf.String() != want {
+			t.Errorf("expected %q, got %q", want, buf.String())
+		}
+	})
+
+	t.Run("UnknownFormat", func(t *testing.T) {
+		if err := ExportAddresses(&bytes.Buffer{}, wallets, "csv"); err == nil {
+			t.Error("expected error for unknown format")
+		}
+	})
+}
+
+func TestFaucetRequest(t *testing.T) {
+	address := common.HexToAddress("0x1")
+
+	t.Run("Preset", func(t *testing.T) {
+		faucet, err := NewFaucet("eth-faucet", "https://faucet.example/", "", "")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		req, err := faucet.(*HTTPFaucet).newRequest(context.Background(), address)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if req.URL.String() != "https://faucet.example/api/claim" {
+			t.Errorf("unexpected URL %s", req.URL)
+		}
+		body, _ := io.ReadAll(req.Body)
+		if want := `{"address":"` + address.Hex() + `"}`; string(body) != want {
+			t.Errorf("expected body %s, got %s", want, body)
+		}
+	})
+
+	t.Run("Template", func(t *testing.T) {
+		faucet := NewHTTPFaucet("https://faucet.example/drip", `{"to":"{address}","amount":1}`, "secret")
+		req, err := faucet.newRequest(context.Background(), address)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		body, _ := io.ReadAll(req.Body)
+		if want := `{"to":"` + address.Hex() + `","amount":1}`; string(body) != want {
+			t.Errorf("expected body %s, got %s", want, body)
+		}
+		if req.Header.Get("Authorization") != "Bearer secret" {
+			t.Errorf("expected bearer token, got %q", req.Header.Get("Authorization"))
+		}
+	})
+
+	t.Run("UnknownType", func(t *testing.T) {
+		if _, err := NewFaucet("nope", "https://faucet.example", "", ""); err == nil {
+			t.Error("expected error for unknown faucet type")
+		}
+	})
+}
+
+func TestParseCancelArgs(t *testing.T) {
+	t.Run("Defaults", func(t *testing.T) {
+		options, err := ParseCancelArgs(nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if options.WalletsPath != "" || options.MaxBumps != 8 {
+			t.Errorf("unexpected defaults: %+v", options)
+		}
+	})
+
+	t.Run("NegativeBumps", func(t *testing.T) {
+		if _, err := ParseCancelArgs([]string{"--max-bumps", "-1"}); err == nil {
+			t.Error("negative --max-bumps should be rejected")
+		}
+	})
+}
+
+func TestBuildEstimate(t *testing.T) {
+	input := &EstimateInput{
+		Lines: []EstimateLine{
+			{Type: "transfer", Count: 100, GasLimit: 21000, Value: big.NewInt(5)},
+			{Type: "deploy", Count: 10, GasLimit: 500000, Value: big.NewInt(0)},
+		},
+		WalletCount: 4,
+		Rate:        10,
+	}
+	e := BuildEstimate(input, big.NewInt(2))
+
+	if e.Transactions != 110 || e.Gas != 100*21000+10*500000 {
+		t.Errorf("unexpected totals: %d txs, %d gas", e.Transactions, e.Gas)
+	}
+	if e.Fees.Int64() != int64(e.Gas)*2 || e.Value.Int64() != 500 {
+		t.Errorf("unexpected fees %s or value %s", e.Fees, e.Value)
+	}
+	if e.FundingFees.Int64() != 4*21000*2 {
+		t.Errorf("unexpected funding fees %s", e.FundingFees)
+	}
+	if want := e.Fees.Int64() + 500 + e.FundingFees.Int64(); e.Required.Int64() != want {
+		t.Errorf("expected required %d, got %s", want, e.Required)
+	}
+	if e.Duration != 11*time.Second {
+		t.Errorf("expected 11s at 10 tx/s, got %s", e.Duration)
+	}
+}
+
+func TestConfirmEstimate(t *testing.T) {
+	for answer, want := range map[string]bool{"y\n": true, "YES\n": true, "n\n": false, "": false} {
+		if got := ConfirmEstimate(strings.NewReader(answer), io.Discard); got != want {
+			t.Errorf("answer %q: expected %v, got %v", answer, want, got)
+		}
+	}
+	estimate, rest := EstimateFromArgs([]string{"--estimate", "--profile", "local"})
+	if !estimate || len(rest) != 2 {
+		t.Errorf("expected --estimate to be removed, got %v %v", estimate, rest)
+	}
+}
+
+func TestInterlock(t *testing.T) {
+	limit := big.NewInt(1000)
+
+	t.Run("RefusesMainnet", func(t *testing.T) {
+		l := &Interlock{TestnetSpendLimit: limit}
+		if err := l.Check(big.NewInt(1), big.NewInt(1)); err == nil {
+			t.Error("expected mainnet to be refused without the flag")
+		}
+		l.AllowMainnet = true
+		if err := l.Check(big.NewInt(1), big.NewInt(1)); err != nil {
+			t.Errorf("expected mainnet to be allowed with the flag, got %v", err)
+		}
+	})
+
+	t.Run("ConfirmsTestnetSpend", func(t *testing.T) {
+		l := &Interlock{TestnetSpendLimit: limit}
+		sepolia := big.NewInt(11155111)
+		if err := l.Check(sepolia, big.NewInt(999)); err != nil {
+			t.Errorf("expected spend under the limit to pass, got %v", err)
+		}
+		if err := l.Check(sepolia, big.NewInt(1001)); err == nil {
+			t.Error("expected spend over the limit to need an interactive confirmation")
+		}
+		l.Interactive, l.In, l.Out = true, strings.NewReader("y\n"), io.Discard
+		if err := l.Check(sepolia, nil); err != nil {
+			t.Errorf("expected a confirmed run to pass, got %v", err)
+		}
+		l.In = strings.NewReader("n\n")
+		if err := l.Check(sepolia, big.NewInt(1001)); err == nil {
+			t.Error("expected a declined run to fail")
+		}
+	})
+
+	t.Run("PassesDevnets", func(t *testing.T) {
+		l := &Interlock{TestnetSpendLimit: limit}
+		if err := l.Check(big.NewInt(1337), nil); err != nil {
+			t.Errorf("expected a devnet to pass, got %v", err)
+		}
+	})
+}
+
+func TestManifest(t *testing.T) {
+	manager := NewManager(nil, big.NewInt(1337), big.NewInt(0))
+	wallets := manager.GenerateWallets(3)
+	manifest := BuildManifest("00000000000000ab", "1337", wallets)
+
+	t.Run("FingerprintsKeys", func(t *testing.T) {
+		if len(manifest.Wallets) != 3 {
+			t.Fatalf("expected 3 entries, got %d", len(manifest.Wallets))
+		}
+		first := manifest.Wallets[0].Fingerprint
+		if len(first) != 2+16 || first != KeyFingerprint(wallets[0].PrivateKey) {
+			t.Errorf("expected a stable 8-byte fingerprint, got %q", first)
+		}
+		if first == manifest.Wallets[1].Fingerprint {
+			t.Error("expected different keys to have different fingerprints")
+		}
+	})
+
+	t.Run("RoundTripsAndVerifies", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "manifest.json")
+		if err := WriteManifest(path, manifest); err != nil {
+			t.Fatal(err)
+		}
+		loaded, err := LoadManifest(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := loaded.Verify(wallets); err != nil {
+			t.Errorf("expected the run's own wallets to verify, got %v", err)
+		}
+		swapped := []*Wallet{
+			{PrivateKey: wallets[1].PrivateKey, Address: wallets[0].Address},
+			{PrivateKey: wallets[0].PrivateKey, Address: wallets[1].Address},
+		}
+		if err := loaded.Verify(swapped); err == nil {
+			t.Error("expected wallets with swapped keys to fail verification")
+		}
+	})
+
+	t.Run("LooksUpByIndex", func(t *testing.T) {
+		entries, err := manifest.Lookup([]string{"2"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if entries[0].Fingerprint != manifest.Wallets[2].Fingerprint {
+			t.Errorf("expected wallet 2, got %+v", entries[0])
+		}
+		if _, err := manifest.Lookup([]string{"7"}); err == nil {
+			t.Error("expected an error for an index outside the manifest")
+		}
+	})
+}
+
+func TestFundingPacer(t *testing.T) {
+	t.Run("WindowLimitsUnmined", func(t *testing.T) {
+		p := &fundingPacer{window: 2, maxWindow: 4, sent: 10, mined: 10}
+		if !p.admit() || !p.admit() {
+			t.Fatal("expected two slots in a window of 2")
+		}
+		if p.admit() {
+			t.Error("expected a full window to refuse a third slot")
+		}
+		p.accepted(10)
+		if p.admit() {
+			t.Error("expected an accepted but unmined transaction to keep its slot")
+		}
+		p.mined = 11
+		if !p.admit() {
+			t.Error("expected a mined transaction to free its slot")
+		}
+	})
+
+	t.Run("GrowsAndHalves", func(t *testing.T) {
+		p := &fundingPacer{window: 2, maxWindow: 3}
+		for nonce := uint64(0); nonce < 10; nonce++ {
+			p.active++
+			p.accepted(nonce)
+		}
+		if p.window != 3 {
+			t.Errorf("expected the window to grow to its maximum 3, got %d", p.window)
+		}
+		p.active++
+		p.pushedBack()
+		window, pushbacks := p.stats()
+		if window != 1 || pushbacks != 1 {
+			t.Errorf("expected a pushback to halve the window to 1, got window %d after %d pushbacks", window, pushbacks)
+		}
+		p.active++
+		p.pushedBack()
+		if p.window != 1 {
+			t.Errorf("expected the window never to drop below 1, got %d", p.window)
+		}
+	})
+
+	t.Run("AcquireGivesUpWhenStalled", func(t *testing.T) {
+		p := &fundingPacer{window: 1, active: 1, lastPoll: time.Now(), progress: time.Now().Add(-fundingStallTimeout)}
+		if err := p.acquire(context.Background()); err == nil || !strings.Contains(err.Error(), "stalled") {
+			t.Errorf("expected acquire to give up on a stalled funder, got %v", err)
+		}
+	})
+}
+
+// fakeEth serves the eth_ methods funding reads, over an in-process RPC server
+type fakeEth struct {
+	pending uint64
+}
+
+func (f *fakeEth) GetTransactionCount(address common.Address, block string) hexutil.Uint64 {
+	return hexutil.Uint64(f.pending)
+}
+
+func (f *fakeEth) GasPrice() *hexutil.Big {
+	return (*hexutil.Big)(big.NewInt(1e9))
+}
+
+// nodeError is a refusal answered by the node, as opposed to a lost request
+type nodeError string
+
+func (e nodeError) Error() string  { return string(e) }
+func (e nodeError) ErrorCode() int { return -32000 }
+
+// fundingSubmitter records every funding send and answers it with onSend
+type fundingSubmitter struct {
+	mu     sync.Mutex
+	sent   []*types.Transaction
+	onSend func(tx *types.Transaction, attempt int) error
+}
+
+func (s *fundingSubmitter) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	s.mu.Lock()
+	s.sent = append(s.sent, tx)
+	attempt := len(s.sent)
+	s.mu.Unlock()
+	return s.onSend(tx, attempt)
+}
+
+func TestFundWalletKeepsNonce(t *testing.T) {
+	server := rpc.NewServer()
+	if err := server.RegisterName("eth", &fakeEth{pending: 3}); err != nil {
+		t.Fatal(err)
+	}
+	client := ethclient.NewClient(rpc.DialInProc(server))
+	defer client.Close()
+
+	key, _ := crypto.GenerateKey()
+	newFunder := func() *Wallet {
+		address := crypto.PubkeyToAddress(key.PublicKey)
+		return &Wallet{PrivateKey: key, Address: address, NonceManager: transaction.NewNonceManager(client, address)}
+	}
+	target := &Wallet{Address: common.HexToAddress("0x02")}
+	newPacer := func() *fundingPacer {
+		return &fundingPacer{window: 4, maxWindow: 4, sent: 3, mined: 3, lastPoll: time.Now(), progress: time.Now()}
+	}
+
+	t.Run("ResendsAfterLostRequest", func(t *testing.T) {
+		submitter := &fundingSubmitter{onSend: func(tx *types.Transaction, attempt int) error {
+			if attempt == 1 {
+				return io.ErrUnexpectedEOF
+			}
+			return nil
+		}}
+		m := &Manager{client: client, chainID: big.NewInt(1337), fundingAmount: big.NewInt(1000), submitter: submitter}
+		if err := m.fundWallet(context.Background(), newPacer(), newFunder(), target); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(submitter.sent) != 2 || submitter.sent[0].Nonce() != 3 || submitter.sent[1].Nonce() != 3 {
+			t.Errorf("expected nonce 3 to be resent, got %d sends", len(submitter.sent))
+		}
+	})
+
+	t.Run("HandsBackNonceOnRefusal", func(t *testing.T) {
+		submitter := &fundingSubmitter{onSend: func(tx *types.Transaction, attempt int) error {
+			return nodeError("insufficient funds for gas * price + value")
+		}}
+		m := &Manager{client: client, chainID: big.NewInt(1337), fundingAmount: big.NewInt(1000), submitter: submitter}
+		funder := newFunder()
+		if err := m.fundWallet(context.Background(), newPacer(), funder, target); err == nil {
+			t.Fatal("expected a refused funding transaction to fail")
+		}
+		if nonce, _ := funder.NonceManager.GetNextNonce(context.Background()); nonce != 3 || len(submitter.sent) != 1 {
+			t.Errorf("expected nonce 3 to be handed back without resending, got next nonce %d after %d sends", nonce, len(submitter.sent))
+		}
+	})
+
+	t.Run("FillsNonceTakenPast", func(t *testing.T) {
+		funder := newFunder()
+		submitter := &fundingSubmitter{}
+		submitter.onSend = func(tx *types.Transaction, attempt int) error {
+			if attempt == 1 {
+				// Another funding transaction takes the next nonce meanwhile
+				funder.NonceManager.GetNextNonce(context.Background())
+				return nodeError("insufficient funds for gas * price + value")
+			}
+			return nil
+		}
+		m := &Manager{client: client, chainID: big.NewInt(1337), fundingAmount: big.NewInt(1000), submitter: submitter}
+		err := m.fundWallet(context.Background(), newPacer(), funder, target)
+		if err == nil || errors.Is(err, errFundingGap) {
+			t.Fatalf("expected the refusal without a gap, got %v", err)
+		}
+		if len(submitter.sent) != 2 {
+			t.Fatalf("expected a self-transfer after the refusal, got %d sends", len(submitter.sent))
+		}
+		fill := submitter.sent[1]
+		if fill.Nonce() != 3 || *fill.To() != funder.Address || fill.Value().Sign() != 0 {
+			t.Errorf("expected a zero-value self-transfer with nonce 3, got nonce %d to %s", fill.Nonce(), fill.To().Hex())
+		}
+	})
+}
+
+func TestWalletStats(t *testing.T) {
+	t.Run("ParsesArgs", func(t *testing.T) {
+		options, err := ParseWalletsArgs([]string{"stats"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if options.WalletsPath != "wallets.json" {
+			t.Errorf("expected the default wallet file, got %q", options.WalletsPath)
+		}
+		if _, err := ParseWalletsArgs([]string{"--wallets", "w.json"}); err == nil {
+			t.Error("expected an error without the stats action")
+		}
+	})
+
+	t.Run("SumsTotals", func(t *testing.T) {
+		totals := SumWalletStats([]*WalletStat{
+			{Balance: big.NewInt(100), Nonce: 5, Pending: 2},
+			{Balance: big.NewInt(0), Nonce: 3},
+			{Err: io.EOF},
+		})
+		if totals.Wallets != 3 || totals.Funded != 1 || totals.Failed != 1 {
+			t.Errorf("unexpected wallet counts: %+v", totals)
+		}
+		if totals.Balance.Int64() != 100 || totals.Sent != 8 || totals.Pending != 2 || totals.WithPending != 1 {
+			t.Errorf("unexpected totals: %+v", totals)
+		}
+	})
+}
+
+// recordingSetter records set balances and fails for one address
+type recordingSetter struct {
+	mu       sync.Mutex
+	balances map[common.Address]*big.Int
+	fail     common.Address
+}
+
+func (s *recordingSetter) SetBalance(ctx context.Context, address common.Address, wei *big.Int) error {
+	if address == s.fail {
+		return io.ErrUnexpectedEOF
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.balances[address] = wei
+	return nil
+}
+
+func TestFundBySetBalance(t *testing.T) {
+	m := &Manager{fundingAmount: big.NewInt(1000)}
+	wallets := []*Wallet{{Address: common.HexToAddress("0x01")}, nil, {Address: common.HexToAddress("0x02")}}
+	setter := &recordingSetter{balances: make(map[common.Address]*big.Int)}
+
+	if err := m.FundBySetBalance(context.Background(), setter, wallets); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(setter.balances) != 2 || setter.balances[common.HexToAddress("0x02")].Int64() != 1000 {
+		t.Errorf("unexpected balances: %v", setter.balances)
+	}
+
+	setter.fail = common.HexToAddress("0x01")
+	if err := m.FundBySetBalance(context.Background(), setter, wallets); err == nil {
+		t.Error("expected an error when a balance cannot be set")
+	}
+}
@@ -0,0 +1,176 @@
+package wallet
+
+import (
+	"crypto/ecdsa"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// fingerprintDomain separates key fingerprints from any other hash of the key
+const fingerprintDomain = "ethereum-transaction-simulator/wallet-fingerprint"
+
+// ManifestEntry identifies one simulated actor
+type ManifestEntry struct {
+	Index       int            `json:"index"`
+	Address     common.Address `json:"address"`
+	Fingerprint string         `json:"fingerprint"` // KeyFingerprint of the wallet's key
+}
+
+// Manifest maps each wallet index of a run to its address and key fingerprint, so
+// logs, traces and on-chain data can be tied back to the actor that produced them.
+// It holds no private keys.
+type Manifest struct {
+	RunID   string          `json:"runId,omitempty"`
+	ChainID string          `json:"chainId,omitempty"`
+	Created time.Time       `json:"created"`
+	Wallets []ManifestEntry `json:"wallets"`
+}
+
+// KeyFingerprint returns a short, stable identifier of a private key that reveals
+// nothing about it: the first 8 bytes of a domain-separated keccak256 of the key
+func KeyFingerprint(key *ecdsa.PrivateKey) string {
+	return hexutil.Encode(crypto.Keccak256([]byte(fingerprintDomain), crypto.FromECDSA(key))[:8])
+}
+
+// BuildManifest describes wallets by their position in the pool. Wallets that
+// failed to generate keep their index unused.
+func BuildManifest(runID, chainID string, wallets []*Wallet) *Manifest {
+	m := &Manifest{RunID: runID, ChainID: chainID, Created: time.Now().UTC()}
+	for i, w := range wallets {
+		if w == nil {
+			continue
+		}
+		entry := ManifestEntry{Index: i, Address: w.Address}
+		if w.PrivateKey != nil {
+			entry.Fingerprint = KeyFingerprint(w.PrivateKey)
+		}
+		m.Wallets = append(m.Wallets, entry)
+	}
+	return m
+}
+
+// WriteManifest writes m to path as JSON
+func WriteManifest(path string, m *Manifest) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode wallet manifest: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write wallet manifest: %w", err)
+	}
+	return nil
+}
+
+// LoadManifest reads a manifest written by WriteManifest
+func LoadManifest(path string) (*Manifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read wallet manifest: %w", err)
+	}
+	var m Manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("failed to decode wallet manifest: %w", err)
+	}
+	return &m, nil
+}
+
+// ByAddress returns the entry of address
+func (m *Manifest) ByAddress(address common.Address) (ManifestEntry, bool) {
+	for _, entry := range m.Wallets {
+		if entry.Address == address {
+			return entry, true
+		}
+	}
+	return ManifestEntry{}, false
+}
+
+// ByIndex returns the entry of the wallet at index
+func (m *Manifest) ByIndex(index int) (ManifestEntry, bool) {
+	for _, entry := range m.Wallets {
+		if entry.Index == index {
+			return entry, true
+		}
+	}
+	return ManifestEntry{}, false
+}
+
+// Verify checks that wallets, e.g. loaded back from a wallet file, are the actors
+// the manifest describes: every wallet must sit at its manifest index with the
+// same address and key
+func (m *Manifest) Verify(wallets []*Wallet) error {
+	for i, w := range wallets {
+		if w == nil {
+			continue
+		}
+		entry, ok := m.ByIndex(i)
+		if !ok {
+			return fmt.Errorf("wallet %d (%s) is not in the manifest", i, w.Address.Hex())
+		}
+		if entry.Address != w.Address {
+			return fmt.Errorf("wallet %d is %s, manifest has %s", i, w.Address.Hex(), entry.Address.Hex())
+		}
+		if w.PrivateKey != nil && entry.Fingerprint != "" && KeyFingerprint(w.PrivateKey) != entry.Fingerprint {
+			return fmt.Errorf("wallet %d key fingerprint does not match the manifest", i)
+		}
+	}
+	return nil
+}
+
+// ManifestOptions holds the arguments of the manifest subcommand
+type ManifestOptions struct {
+	Path    string   // Manifest to look up in
+	Queries []string // Addresses or indexes, empty lists every wallet
+}
+
+// ParseManifestArgs parses `simulator manifest --file manifest.json [address|index ...]`
+func ParseManifestArgs(args []string) (*ManifestOptions, error) {
+	fs := flag.NewFlagSet("manifest", flag.ContinueOnError)
+	path := fs.String("file", "", "wallet manifest written at run start")
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+	if *path == "" {
+		return nil, fmt.Errorf("--file is required")
+	}
+	return &ManifestOptions{Path: *path, Queries: fs.Args()}, nil
+}
+
+// Lookup resolves each query, an address or a wallet index, to its manifest entry
+func (m *Manifest) Lookup(queries []string) ([]ManifestEntry, error) {
+	if len(queries) == 0 {
+		return m.Wallets, nil
+	}
+	entries := make([]ManifestEntry, 0, len(queries))
+	for _, query := range queries {
+		var entry ManifestEntry
+		var ok bool
+		if index, err := strconv.Atoi(query); err == nil {
+			entry, ok = m.ByIndex(index)
+		} else if common.IsHexAddress(query) {
+			entry, ok = m.ByAddress(common.HexToAddress(query))
+		} else {
+			return nil, fmt.Errorf("%q is neither a wallet index nor an address", query)
+		}
+		if !ok {
+			return nil, fmt.Errorf("%s is not in the manifest", query)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// PrintManifestEntries writes one line per entry: index, address and fingerprint
+func PrintManifestEntries(w io.Writer, entries []ManifestEntry) {
+	for _, entry := range entries {
+		fmt.Fprintf(w, "%6d  %s  %s\n", entry.Index, entry.Address.Hex(), entry.Fingerprint)
+	}
+}
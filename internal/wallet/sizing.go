@@ -0,0 +1,148 @@
+package wallet
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/big"
+	"time"
+
+	"github.com/aakash4dev/ethereum-transaction-simulator/internal/transaction"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+const (
+	// MaxWalletCount is the largest wallet pool auto-sizing will propose
+	MaxWalletCount = 10000
+
+	// minBlockTime stands in for the block time when the sampled blocks share one
+	// timestamp, as they do on automining nodes (anvil, hardhat); timestamps only
+	// have one-second resolution
+	minBlockTime = time.Second
+)
+
+// Sizing holds the measurements and resulting wallet pool size for a target TPS
+type Sizing struct {
+	TargetTPS    float64
+	BlockTime    time.Duration
+	PerWalletTPS float64
+	WalletCount  int
+	Funding      *FundingPlan // Funding per wallet and overall, nil when the run is unbounded
+}
+
+// SizingRun describes the planned run that auto-sizing funds the wallets for
+type SizingRun struct {
+	Duration        time.Duration // Planned run length (0 = until MaxTransactions)
+	MaxTransactions int           // Transaction cap across all wallets (0 = no cap)
+	GasLimit        uint64
+	GasPrice        *big.Int
+	Value           *big.Int
+	SafetyPercent   int // Headroom applied to the funding, 150 = +50%
+}
+
+// MeasureBlockTime returns the average block time over the last sampleBlocks blocks,
+// or minBlockTime when they were all produced within the same second
+func MeasureBlockTime(ctx context.Context, client *ethclient.Client, sampleBlocks uint64) (time.Duration, error) {
+	latest, err := client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get latest header: %w", err)
+	}
+	if latest.Number.Uint64() < sampleBlocks {
+		sampleBlocks = latest.Number.Uint64()
+	}
+	if sampleBlocks == 0 {
+		return 0, fmt.Errorf("not enough blocks to measure block time")
+	}
+
+	oldest, err := client.HeaderByNumber(ctx, new(big.Int).Sub(latest.Number, new(big.Int).SetUint64(sampleBlocks)))
+	if err != nil {
+		return 0, fmt.Errorf("failed to get header: %w", err)
+	}
+
+	elapsed := time.Duration(latest.Time-oldest.Time) * time.Second
+	if elapsed == 0 {
+		return minBlockTime, nil
+	}
+	return elapsed / time.Duration(sampleBlocks), nil
+}
+
+// MeasureWalletRate runs the sender for the warm-up duration and returns the
+// achieved transactions per second per wallet
+func MeasureWalletRate(ctx context.Context, sender *transaction.ParallelSender, walletCount int, warmup time.Duration) (float64, error) {
+	if walletCount <= 0 {
+		return 0, fmt.Errorf("warm-up requires at least one wallet")
+	}
+
+	warmupCtx, cancel := context.WithTimeout(ctx, warmup)
+	defer cancel()
+
+	start := time.Now()
+	if err := sender.SendParallelTransactions(warmupCtx); err != nil {
+		return 0, err
+	}
+	sent, _, _, _ := sender.GetMetrics()
+
+	return float64(sent) / time.Since(start).Seconds() / float64(walletCount), nil
+}
+
+// PlanWalletCount returns the number of wallets needed to reach targetTPS when each
+// wallet sustains perWalletTPS, with 20% headroom, clamped to [1, MaxWalletCount]
+func PlanWalletCount(targetTPS, perWalletTPS float64) int {
+	if targetTPS <= 0 || perWalletTPS <= 0 {
+		return 1
+	}
+	count := int(math.Ceil(targetTPS / perWalletTPS * 1.2))
+	if count < 1 {
+		return 1
+	}
+	if count > MaxWalletCount {
+		return MaxWalletCount
+	}
+	return count
+}
+
+// PlanSizing combines block time and warm-up measurements into a wallet pool size
+// and the funding it needs for run. The per-wallet rate is capped by how many
+// transactions one account can get mined per block (maxPerBlock), since the txpool
+// limits pending txs per sender.
+func PlanSizing(targetTPS float64, blockTime time.Duration, measuredPerWalletTPS float64, maxPerBlock int, run SizingRun) *Sizing {
+	perWallet := measuredPerWalletTPS
+	if blockTime > 0 && maxPerBlock > 0 {
+		blockLimited := float64(maxPerBlock) / blockTime.Seconds()
+		if perWallet <= 0 || blockLimited < perWallet {
+			perWallet = blockLimited
+		}
+	}
+
+	sizing := &Sizing{
+		TargetTPS:    targetTPS,
+		BlockTime:    blockTime,
+		PerWalletTPS: perWallet,
+		WalletCount:  PlanWalletCount(targetTPS, perWallet),
+	}
+	if txPerWallet := plannedTxPerWallet(perWallet, sizing.WalletCount, run); txPerWallet > 0 && run.GasPrice != nil {
+		value := run.Value
+		if value == nil {
+			value = new(big.Int)
+		}
+		sizing.Funding = PlanFunding(txPerWallet, sizing.WalletCount, run.GasLimit, run.GasPrice, value, run.SafetyPercent)
+	}
+	return sizing
+}
+
+// plannedTxPerWallet returns the most transactions one wallet can send in run: what
+// it sends at perWalletTPS over the run's duration, bounded by its share of the
+// transaction cap. It returns 0 when neither bounds the run.
+func plannedTxPerWallet(perWalletTPS float64, walletCount int, run SizingRun) int {
+	txPerWallet := 0
+	if run.Duration > 0 && perWalletTPS > 0 {
+		txPerWallet = int(math.Ceil(perWalletTPS * run.Duration.Seconds()))
+	}
+	if run.MaxTransactions > 0 {
+		share := (run.MaxTransactions + walletCount - 1) / walletCount
+		if txPerWallet == 0 || share < txPerWallet {
+			txPerWallet = share
+		}
+	}
+	return txPerWallet
+}
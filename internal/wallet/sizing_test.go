@@ -0,0 +1,127 @@
+package wallet
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+func TestPlanWalletCount(t *testing.T) {
+	t.Run("AddsHeadroom", func(t *testing.T) {
+		if got := PlanWalletCount(1000, 10); got != 120 {
+			t.Errorf("expected 120 wallets, got %d", got)
+		}
+	})
+
+	t.Run("ClampsToRange", func(t *testing.T) {
+		if got := PlanWalletCount(0, 10); got != 1 {
+			t.Errorf("expected 1 wallet for zero target, got %d", got)
+		}
+		if got := PlanWalletCount(1e9, 1); got != MaxWalletCount {
+			t.Errorf("expected %d wallets, got %d", MaxWalletCount, got)
+		}
+	})
+}
+
+func TestPlanSizing(t *testing.T) {
+	t.Run("BlockLimitCapsPerWalletRate", func(t *testing.T) {
+		// 16 txs per block every 2s caps a wallet at 8 TPS even if warm-up measured 50
+		sizing := PlanSizing(800, 2*time.Second, 50, 16, SizingRun{})
+		if sizing.PerWalletTPS != 8 {
+			t.Errorf("expected 8 TPS per wallet, got %f", sizing.PerWalletTPS)
+		}
+		if sizing.WalletCount != 120 {
+			t.Errorf("expected 120 wallets, got %d", sizing.WalletCount)
+		}
+	})
+	t.Run("FundsTheRunDuration", func(t *testing.T) {
+		// 120 wallets at 8 TPS for 10s send at most 80 txs each
+		sizing := PlanSizing(800, 2*time.Second, 50, 16, SizingRun{
+			Duration:      10 * time.Second,
+			GasLimit:      21000,
+			GasPrice:      big.NewInt(1),
+			Value:         big.NewInt(1000),
+			SafetyPercent: 100,
+		})
+		if sizing.Funding == nil {
+			t.Fatal("expected a funding plan")
+		}
+		if sizing.Funding.TxPerWallet != 80 {
+			t.Errorf("expected 80 txs per wallet, got %d", sizing.Funding.TxPerWallet)
+		}
+		if want := big.NewInt(80 * 22000); sizing.Funding.PerWallet.Cmp(want) != 0 {
+			t.Errorf("expected %s per wallet, got %s", want, sizing.Funding.PerWallet)
+		}
+		if want := big.NewInt(120 * 80 * 22000); sizing.Funding.Total.Cmp(want) != 0 {
+			t.Errorf("expected %s overall, got %s", want, sizing.Funding.Total)
+		}
+	})
+
+	t.Run("TransactionCapBoundsFunding", func(t *testing.T) {
+		sizing := PlanSizing(800, 2*time.Second, 50, 16, SizingRun{
+			Duration:        time.Hour,
+			MaxTransactions: 1200,
+			GasLimit:        21000,
+			GasPrice:        big.NewInt(1),
+			SafetyPercent:   150,
+		})
+		if sizing.Funding == nil || sizing.Funding.TxPerWallet != 10 {
+			t.Fatalf("expected 10 txs per wallet, got %+v", sizing.Funding)
+		}
+	})
+
+	t.Run("UnboundedRunHasNoFunding", func(t *testing.T) {
+		sizing := PlanSizing(800, 2*time.Second, 50, 16, SizingRun{GasPrice: big.NewInt(1)})
+		if sizing.Funding != nil {
+			t.Errorf("expected no funding plan, got %+v", sizing.Funding)
+		}
+	})
+}
+
+// fakeChain serves headers whose timestamps are times[number]
+type fakeChain struct {
+	times []uint64
+}
+
+func (f *fakeChain) GetBlockByNumber(number string, full bool) *types.Header {
+	n := uint64(len(f.times) - 1)
+	if number != "latest" {
+		n = hexutil.MustDecodeUint64(number)
+	}
+	return &types.Header{Number: new(big.Int).SetUint64(n), Time: f.times[n], Difficulty: new(big.Int)}
+}
+
+func TestMeasureBlockTime(t *testing.T) {
+	measure := func(t *testing.T, times []uint64) time.Duration {
+		server := rpc.NewServer()
+		if err := server.RegisterName("eth", &fakeChain{times: times}); err != nil {
+			t.Fatal(err)
+		}
+		client := ethclient.NewClient(rpc.DialInProc(server))
+		defer client.Close()
+
+		blockTime, err := MeasureBlockTime(context.Background(), client, 4)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return blockTime
+	}
+
+	t.Run("AveragesSampledBlocks", func(t *testing.T) {
+		if got := measure(t, []uint64{100, 102, 104, 106, 108}); got != 2*time.Second {
+			t.Errorf("expected 2s, got %s", got)
+		}
+	})
+
+	t.Run("RepeatedTimestampsFallBack", func(t *testing.T) {
+		if got := measure(t, []uint64{100, 100, 100, 100, 100}); got != minBlockTime {
+			t.Errorf("expected %s, got %s", minBlockTime, got)
+		}
+	})
+}
@@ -0,0 +1,139 @@
+package wallet
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// WalletsOptions holds the arguments of the wallets subcommand
+type WalletsOptions struct {
+	WalletsPath string // Wallet file to audit
+}
+
+// ParseWalletsArgs parses `simulator wallets stats [--wallets wallets.json]`
+func ParseWalletsArgs(args []string) (*WalletsOptions, error) {
+	if len(args) == 0 || args[0] != "stats" {
+		return nil, errors.New("usage: simulator wallets stats [--wallets wallets.json]")
+	}
+	fs := flag.NewFlagSet("wallets stats", flag.ContinueOnError)
+	walletsPath := fs.String("wallets", "wallets.json", "wallet file to audit")
+	if err := fs.Parse(args[1:]); err != nil {
+		return nil, err
+	}
+	if *walletsPath == "" {
+		return nil, errors.New("--wallets is required")
+	}
+	return &WalletsOptions{WalletsPath: *walletsPath}, nil
+}
+
+// WalletStat is the on-chain state of one wallet from a wallet file
+type WalletStat struct {
+	Address common.Address
+	Balance *big.Int
+	Nonce   uint64 // Mined nonce
+	Pending int    // Nonces between the mined and the pending nonce
+	Err     error
+}
+
+// WalletTotals sums the state of every wallet in a wallet file
+type WalletTotals struct {
+	Wallets     int
+	Funded      int      // Wallets with a non-zero balance
+	Balance     *big.Int // Sum of balances
+	Sent        uint64   // Sum of mined nonces: transactions the wallets got on chain
+	Pending     int      // Sum of pending transactions
+	WithPending int      // Wallets with at least one pending transaction
+	Failed      int      // Wallets whose state could not be read
+}
+
+// WalletStats reads the balance, mined nonce and pending transaction count of
+// every wallet. A wallet that cannot be read keeps its error and does not stop the others.
+func (m *Manager) WalletStats(ctx context.Context, wallets []*Wallet) []*WalletStat {
+	stats := make([]*WalletStat, len(wallets))
+	var wg sync.WaitGroup
+	semaphore := make(chan struct{}, 50) // Limit concurrent operations
+	for i, wallet := range wallets {
+		stats[i] = &WalletStat{Address: wallet.Address}
+		wg.Add(1)
+		go func(stat *WalletStat) {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+			stat.Err = m.walletStat(ctx, stat)
+		}(stats[i])
+	}
+	wg.Wait()
+	return stats
+}
+
+// walletStat fills in the on-chain state of one wallet
+func (m *Manager) walletStat(ctx context.Context, stat *WalletStat) error {
+	balance, err := m.client.BalanceAt(ctx, stat.Address, nil)
+	if err != nil {
+		return fmt.Errorf("failed to get balance: %w", err)
+	}
+	mined, err := m.client.NonceAt(ctx, stat.Address, nil)
+	if err != nil {
+		return fmt.Errorf("failed to get mined nonce: %w", err)
+	}
+	pending, err := m.client.PendingNonceAt(ctx, stat.Address)
+	if err != nil {
+		return fmt.Errorf("failed to get pending nonce: %w", err)
+	}
+	stat.Balance = balance
+	stat.Nonce = mined
+	if pending > mined {
+		stat.Pending = int(pending - mined)
+	}
+	return nil
+}
+
+// SumWalletStats totals the wallets' state
+func SumWalletStats(stats []*WalletStat) WalletTotals {
+	totals := WalletTotals{Wallets: len(stats), Balance: big.NewInt(0)}
+	for _, s := range stats {
+		if s.Err != nil {
+			totals.Failed++
+			continue
+		}
+		totals.Balance.Add(totals.Balance, s.Balance)
+		if s.Balance.Sign() > 0 {
+			totals.Funded++
+		}
+		totals.Sent += s.Nonce
+		totals.Pending += s.Pending
+		if s.Pending > 0 {
+			totals.WithPending++
+		}
+	}
+	return totals
+}
+
+// PrintWalletStats prints one line per wallet with its balance, nonce and pending
+// transactions, then the totals
+func PrintWalletStats(stats []*WalletStat) {
+	fmt.Printf("\n=== Wallet Stats ===\n")
+	fmt.Printf("%-42s %24s %8s %8s\n", "address", "balance (wei)", "nonce", "pending")
+	for _, s := range stats {
+		if s.Err != nil {
+			fmt.Printf("%-42s error: %v\n", s.Address.Hex(), s.Err)
+			continue
+		}
+		fmt.Printf("%-42s %24s %8d %8d\n", s.Address.Hex(), s.Balance.String(), s.Nonce, s.Pending)
+	}
+	totals := SumWalletStats(stats)
+	fmt.Printf("Wallets: %d (%d funded, %d with pending transactions, %d unreadable)\n",
+		totals.Wallets, totals.Funded, totals.WithPending, totals.Failed)
+	fmt.Printf("Total balance: %s wei\n", totals.Balance.String())
+	fmt.Printf("Transactions mined: %d, pending: %d\n", totals.Sent, totals.Pending)
+	if totals.Pending > 0 {
+		fmt.Printf("Clear pending transactions with `cancel-pending --wallets` before reusing or sweeping\n")
+	}
+	fmt.Printf("==========================\n")
+}
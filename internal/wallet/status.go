@@ -0,0 +1,124 @@
+package wallet
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// StatusOptions holds the arguments of the status subcommand
+type StatusOptions struct {
+	WalletsPath  string // Optional wallet file whose balances are aggregated
+	ManifestPath string // Optional wallet manifest the wallet file is verified against
+}
+
+// ParseStatusArgs parses `simulator status [--wallets wallets.json [--manifest manifest.json]]`
+func ParseStatusArgs(args []string) (*StatusOptions, error) {
+	fs := flag.NewFlagSet("status", flag.ContinueOnError)
+	walletsPath := fs.String("wallets", "", "wallet file to aggregate worker balances from (optional)")
+	manifestPath := fs.String("manifest", "", "wallet manifest the wallet file must match (optional)")
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+	if *manifestPath != "" && *walletsPath == "" {
+		return nil, fmt.Errorf("--manifest needs --wallets")
+	}
+	return &StatusOptions{WalletsPath: *walletsPath, ManifestPath: *manifestPath}, nil
+}
+
+// Status describes chain and account readiness before a run
+type Status struct {
+	ChainID       *big.Int
+	LatestBlock   uint64
+	GasPrice      *big.Int
+	Funder        common.Address
+	FunderBalance *big.Int
+	FunderNonce   uint64
+	Workers       int      // Wallets loaded from the wallet file
+	WorkersFunded int      // Workers with a non-zero balance
+	WorkerBalance *big.Int // Sum of worker balances
+}
+
+// Status gathers chain information, the funder's balance and nonce, and the aggregate
+// balance of the given worker wallets (which may be empty)
+func (m *Manager) Status(ctx context.Context, funder common.Address, wallets []*Wallet) (*Status, error) {
+	latestBlock, err := m.client.BlockNumber(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get latest block: %w", err)
+	}
+	gasPrice, err := m.client.SuggestGasPrice(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get gas price: %w", err)
+	}
+	funderBalance, err := m.client.BalanceAt(ctx, funder, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get funder balance: %w", err)
+	}
+	funderNonce, err := m.client.PendingNonceAt(ctx, funder)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get funder nonce: %w", err)
+	}
+
+	status := &Status{
+		ChainID:       m.chainID,
+		LatestBlock:   latestBlock,
+		GasPrice:      gasPrice,
+		Funder:        funder,
+		FunderBalance: funderBalance,
+		FunderNonce:   funderNonce,
+		Workers:       len(wallets),
+		WorkerBalance: big.NewInt(0),
+	}
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var firstErr error
+	semaphore := make(chan struct{}, 50) // Limit concurrent operations
+	for _, wallet := range wallets {
+		wg.Add(1)
+		go func(w *Wallet) {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			balance, err := m.client.BalanceAt(ctx, w.Address, nil)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				if firstErr == nil {
+					firstErr = fmt.Errorf("failed to get balance of %s: %w", w.Address.Hex(), err)
+				}
+				return
+			}
+			status.WorkerBalance.Add(status.WorkerBalance, balance)
+			if balance.Sign() > 0 {
+				status.WorkersFunded++
+			}
+		}(wallet)
+	}
+	wg.Wait()
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	return status, nil
+}
+
+// PrintStatus prints a readiness report
+func PrintStatus(status *Status) {
+	fmt.Printf("\n=== Status ===\n")
+	fmt.Printf("Chain ID: %s\n", status.ChainID.String())
+	fmt.Printf("Latest block: %d\n", status.LatestBlock)
+	fmt.Printf("Gas price: %s wei\n", status.GasPrice.String())
+	fmt.Printf("Funding wallet: %s\n", status.Funder.Hex())
+	fmt.Printf("  Balance: %s wei\n", status.FunderBalance.String())
+	fmt.Printf("  Next nonce: %d\n", status.FunderNonce)
+	if status.Workers > 0 {
+		fmt.Printf("Worker wallets: %d (%d funded)\n", status.Workers, status.WorkersFunded)
+		fmt.Printf("  Total balance: %s wei\n", status.WorkerBalance.String())
+	}
+	fmt.Printf("==========================\n")
+}
@@ -0,0 +1,89 @@
+package wallet
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strings"
+
+	"github.com/aakash4dev/ethereum-transaction-simulator/internal/transaction"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// storedWallet is the on-disk form of a wallet
+type storedWallet struct {
+	Address    string `json:"address"`
+	PrivateKey string `json:"privateKey"`
+}
+
+// SaveWallets writes the wallets' addresses and private keys to path as JSON.
+// The file holds live keys, so it is created readable by the owner only. An
+// existing file is refused unless overwrite is set, as it may hold funded keys.
+func SaveWallets(path string, wallets []*Wallet, overwrite bool) error {
+	stored := make([]storedWallet, 0, len(wallets))
+	for _, w := range wallets {
+		if w == nil {
+			continue
+		}
+		stored = append(stored, storedWallet{
+			Address:    w.Address.Hex(),
+			PrivateKey: hexutil.Encode(crypto.FromECDSA(w.PrivateKey)),
+		})
+	}
+
+	data, err := json.MarshalIndent(stored, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode wallets: %w", err)
+	}
+	flags := os.O_WRONLY | os.O_CREATE | os.O_EXCL
+	if overwrite {
+		flags = os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	}
+	file, err := os.OpenFile(path, flags, 0600)
+	if errors.Is(err, fs.ErrExist) {
+		return fmt.Errorf("wallets file %s already exists and may hold funded keys", path)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create wallets file: %w", err)
+	}
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write wallets file: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to write wallets file: %w", err)
+	}
+	return nil
+}
+
+// LoadWallets reads wallets written by SaveWallets and attaches a nonce manager on client
+func LoadWallets(path string, client *ethclient.Client) ([]*Wallet, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read wallets file: %w", err)
+	}
+	var stored []storedWallet
+	if err := json.Unmarshal(data, &stored); err != nil {
+		return nil, fmt.Errorf("failed to decode wallets file: %w", err)
+	}
+
+	wallets := make([]*Wallet, 0, len(stored))
+	for i, s := range stored {
+		privateKey, err := crypto.HexToECDSA(strings.TrimPrefix(s.PrivateKey, "0x"))
+		if err != nil {
+			return nil, fmt.Errorf("wallet %d: failed to parse private key: %w", i, err)
+		}
+		address := crypto.PubkeyToAddress(privateKey.PublicKey)
+		wallets = append(wallets, &Wallet{
+			PrivateKey:   privateKey,
+			Address:      address,
+			NonceManager: transaction.NewNonceManager(client, address),
+			Client:       client,
+		})
+	}
+	return wallets, nil
+}
@@ -0,0 +1,106 @@
+package wallet
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/aakash4dev/ethereum-transaction-simulator/internal/transaction"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// SweepOptions holds the arguments of the sweep subcommand
+type SweepOptions struct {
+	WalletsPath string         // Wallet file written by the fund subcommand
+	To          common.Address // Address receiving the swept balances
+}
+
+// ParseSweepArgs parses `simulator sweep --wallets wallets.json --to 0x...`
+func ParseSweepArgs(args []string) (*SweepOptions, error) {
+	fs := flag.NewFlagSet("sweep", flag.ContinueOnError)
+	walletsPath := fs.String("wallets", "wallets.json", "wallet file to drain")
+	to := fs.String("to", "", "address receiving the swept balances")
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	if *walletsPath == "" {
+		return nil, errors.New("--wallets is required")
+	}
+	if !common.IsHexAddress(*to) {
+		return nil, fmt.Errorf("--to must be a valid address (got: %s)", *to)
+	}
+	return &SweepOptions{WalletsPath: *walletsPath, To: common.HexToAddress(*to)}, nil
+}
+
+// SweepWallets sends each wallet's balance, less the transfer fee, to the target address.
+// Wallets whose balance does not cover the fee are skipped. It returns the total swept.
+func (m *Manager) SweepWallets(ctx context.Context, wallets []*Wallet, to common.Address) (*big.Int, error) {
+	gasPrice, err := m.client.SuggestGasPrice(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get gas price: %w", err)
+	}
+	fee := new(big.Int).Mul(gasPrice, big.NewInt(fundingTxGas))
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	swept := big.NewInt(0)
+	errChan := make(chan error, len(wallets))
+	semaphore := make(chan struct{}, 50) // Limit concurrent operations
+
+	for _, wallet := range wallets {
+		wg.Add(1)
+		go func(w *Wallet) {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			balance, err := m.client.BalanceAt(ctx, w.Address, nil)
+			if err != nil {
+				errChan <- fmt.Errorf("failed to get balance of %s: %w", w.Address.Hex(), err)
+				return
+			}
+			amount := new(big.Int).Sub(balance, fee)
+			if amount.Sign() <= 0 {
+				return // Nothing worth sweeping
+			}
+
+			nonce, err := w.NonceManager.GetNextNonce(ctx)
+			if err != nil {
+				errChan <- fmt.Errorf("failed to get nonce for %s: %w", w.Address.Hex(), err)
+				return
+			}
+
+			tx := types.NewTransaction(nonce, to, amount, fundingTxGas, gasPrice, nil)
+			signedTx, err := transaction.SignTx(tx, m.chainID, w.PrivateKey)
+			if err != nil {
+				errChan <- fmt.Errorf("failed to sign sweep transaction: %w", err)
+				return
+			}
+			if err := m.submitter.SendTransaction(ctx, signedTx); err != nil {
+				errChan <- fmt.Errorf("failed to send sweep transaction from %s: %w", w.Address.Hex(), err)
+				return
+			}
+
+			mu.Lock()
+			swept.Add(swept, amount)
+			mu.Unlock()
+		}(wallet)
+	}
+
+	wg.Wait()
+	close(errChan)
+
+	var errs []error
+	for err := range errChan {
+		errs = append(errs, err)
+	}
+	if len(errs) > 0 {
+		return swept, fmt.Errorf("sweep errors: %d wallets failed (first: %w)", len(errs), errs[0])
+	}
+	return swept, nil
+}
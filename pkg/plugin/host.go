@@ -0,0 +1,194 @@
+package plugin
+
+import (
+	"fmt"
+	"math/big"
+	"net/rpc"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+
+	goplugin "github.com/hashicorp/go-plugin"
+)
+
+// Client is a running plugin, seen from the simulator
+type Client struct {
+	path    string
+	process *goplugin.Client // Nil when the plugin was not started by Open
+	client  *rpc.Client
+	info    Info
+}
+
+// Open starts the plugin executable at path and asks it what it provides
+func Open(path string) (*Client, error) {
+	process := goplugin.NewClient(&goplugin.ClientConfig{
+		HandshakeConfig:  Handshake,
+		Plugins:          pluginSet(nil),
+		Cmd:              exec.Command(path),
+		AllowedProtocols: []goplugin.Protocol{goplugin.ProtocolNetRPC},
+		Stderr:           os.Stderr,
+		Logger:           quietLogger(),
+	})
+	conn, err := process.Client()
+	if err != nil {
+		process.Kill()
+		return nil, fmt.Errorf("failed to start plugin %s: %w", path, err)
+	}
+	raw, err := conn.Dispense(pluginName)
+	if err != nil {
+		process.Kill()
+		return nil, fmt.Errorf("failed to start plugin %s: %w", path, err)
+	}
+	return newClient(path, process, raw.(*rpc.Client))
+}
+
+// newClient asks the plugin behind client what it provides
+func newClient(path string, process *goplugin.Client, client *rpc.Client) (*Client, error) {
+	c := &Client{path: path, process: process, client: client}
+	if err := c.client.Call("Plugin.Info", struct{}{}, &c.info); err != nil {
+		c.Close()
+		return nil, fmt.Errorf("plugin %s did not answer: %w", path, err)
+	}
+	return c, nil
+}
+
+// Info returns what the plugin provides
+func (c *Client) Info() Info {
+	return c.info
+}
+
+// Close stops the plugin
+func (c *Client) Close() error {
+	if c.process == nil {
+		return c.client.Close()
+	}
+	// Kill asks the plugin to exit and forces it after a grace period
+	c.process.Kill()
+	return nil
+}
+
+// remoteWorkload calls a workload of a plugin
+type remoteWorkload struct {
+	client *Client
+	name   string
+}
+
+// Build asks the plugin for the next transaction
+func (w *remoteWorkload) Build(req TxRequest) (*Tx, error) {
+	tx := new(Tx)
+	if err := w.client.client.Call("Plugin.Build", BuildArgs{Workload: w.name, Request: req}, tx); err != nil {
+		return nil, fmt.Errorf("plugin workload %s: %w", w.name, err)
+	}
+	return tx, nil
+}
+
+// remoteFeeStrategy calls a fee strategy of a plugin
+type remoteFeeStrategy struct {
+	client *Client
+	name   string
+}
+
+// GasPrice asks the plugin for the gas price to use
+func (f *remoteFeeStrategy) GasPrice(suggested *big.Int) (*big.Int, error) {
+	price := new(big.Int)
+	if err := f.client.client.Call("Plugin.GasPrice", GasPriceArgs{Strategy: f.name, Suggested: suggested}, price); err != nil {
+		return nil, fmt.Errorf("plugin fee strategy %s: %w", f.name, err)
+	}
+	return price, nil
+}
+
+// Registry holds the plugins discovered in a directory
+type Registry struct {
+	clients       []*Client
+	workloads     map[string]*remoteWorkload
+	feeStrategies map[string]*remoteFeeStrategy
+}
+
+// Discover starts every executable file in dir. Two plugins providing the same
+// workload or fee strategy name is an error.
+func Discover(dir string) (*Registry, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read plugin directory: %w", err)
+	}
+	r := &Registry{workloads: make(map[string]*remoteWorkload), feeStrategies: make(map[string]*remoteFeeStrategy)}
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil || !info.Mode().IsRegular() || info.Mode()&0111 == 0 {
+			continue
+		}
+		client, err := Open(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			r.Close()
+			return nil, err
+		}
+		if err := r.add(client); err != nil {
+			client.Close()
+			r.Close()
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+// add registers the workloads and fee strategies of client
+func (r *Registry) add(client *Client) error {
+	for _, name := range client.info.Workloads {
+		if _, ok := r.workloads[name]; ok {
+			return fmt.Errorf("workload %q is provided by more than one plugin", name)
+		}
+	}
+	for _, name := range client.info.FeeStrategies {
+		if _, ok := r.feeStrategies[name]; ok {
+			return fmt.Errorf("fee strategy %q is provided by more than one plugin", name)
+		}
+	}
+	for _, name := range client.info.Workloads {
+		r.workloads[name] = &remoteWorkload{client: client, name: name}
+	}
+	for _, name := range client.info.FeeStrategies {
+		r.feeStrategies[name] = &remoteFeeStrategy{client: client, name: name}
+	}
+	r.clients = append(r.clients, client)
+	return nil
+}
+
+// Workload returns the named workload
+func (r *Registry) Workload(name string) (Workload, error) {
+	w, ok := r.workloads[name]
+	if !ok {
+		names := make([]string, 0, len(r.workloads))
+		for n := range r.workloads {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		return nil, fmt.Errorf("no plugin provides workload %q (available: %v)", name, names)
+	}
+	return w, nil
+}
+
+// FeeStrategy returns the named fee strategy
+func (r *Registry) FeeStrategy(name string) (FeeStrategy, error) {
+	f, ok := r.feeStrategies[name]
+	if !ok {
+		names := make([]string, 0, len(r.feeStrategies))
+		for n := range r.feeStrategies {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		return nil, fmt.Errorf("no plugin provides fee strategy %q (available: %v)", name, names)
+	}
+	return f, nil
+}
+
+// Close stops every plugin
+func (r *Registry) Close() error {
+	var first error
+	for _, client := range r.clients {
+		if err := client.Close(); err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
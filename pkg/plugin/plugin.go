@@ -0,0 +1,162 @@
+// Package plugin is the contract between the simulator and external plugin
+// binaries. A plugin is an executable that provides named Workload and FeeStrategy
+// implementations. It calls Serve from main; the simulator starts it at run time
+// with hashicorp/go-plugin, discovers what it provides and calls it over go-plugin's
+// net/rpc protocol. Whatever the plugin writes to stdout or stderr is copied to the
+// simulator's stderr.
+package plugin
+
+import (
+	"fmt"
+	"math/big"
+	"net/rpc"
+	"os"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/hashicorp/go-hclog"
+	goplugin "github.com/hashicorp/go-plugin"
+)
+
+// Handshake is checked by the simulator and a plugin before they talk, so an
+// executable that is not a simulator plugin, or speaks another version of this
+// contract, is refused
+var Handshake = goplugin.HandshakeConfig{
+	ProtocolVersion:  1,
+	MagicCookieKey:   "SIMULATOR_PLUGIN",
+	MagicCookieValue: "ethereum-transaction-simulator",
+}
+
+// pluginName is the name under which go-plugin dispenses the simulator contract
+const pluginName = "simulator"
+
+// TxRequest asks a workload for the next transaction of a wallet
+type TxRequest struct {
+	Wallet  int            // Index of the wallet in the pool
+	Address common.Address // Address of the wallet
+	Seq     uint64         // Per-wallet sequence number
+	Seed    int64          // Drawn from the run's RNG so seeded runs stay reproducible
+}
+
+// Tx is a transaction built by a workload. The simulator fills in nonce and gas
+// price and signs it.
+type Tx struct {
+	To       common.Address
+	Value    *big.Int // nil sends zero
+	Data     []byte
+	GasLimit uint64 // 0 uses the run's gas limit
+}
+
+// Workload builds the transactions of a parallel run
+type Workload interface {
+	Build(req TxRequest) (*Tx, error)
+}
+
+// FeeStrategy picks the gas price of each transaction from the node's suggestion
+type FeeStrategy interface {
+	GasPrice(suggested *big.Int) (*big.Int, error)
+}
+
+// Info is what a plugin provides
+type Info struct {
+	Workloads     []string
+	FeeStrategies []string
+}
+
+// BuildArgs is the RPC argument of Plugin.Build
+type BuildArgs struct {
+	Workload string
+	Request  TxRequest
+}
+
+// GasPriceArgs is the RPC argument of Plugin.GasPrice
+type GasPriceArgs struct {
+	Strategy  string
+	Suggested *big.Int
+}
+
+// rpcPlugin is the simulator contract as a go-plugin plugin. On the plugin side it
+// serves server; on the simulator side it hands out the RPC client.
+type rpcPlugin struct {
+	server *server
+}
+
+// Server returns the RPC service of the plugin's implementations
+func (p *rpcPlugin) Server(*goplugin.MuxBroker) (interface{}, error) {
+	return p.server, nil
+}
+
+// Client returns the RPC client that calls the plugin
+func (p *rpcPlugin) Client(_ *goplugin.MuxBroker, client *rpc.Client) (interface{}, error) {
+	return client, nil
+}
+
+// server exposes a plugin's implementations as go-plugin's "Plugin" RPC service
+type server struct {
+	workloads map[string]Workload
+	fees      map[string]FeeStrategy
+}
+
+// Info lists the names of the plugin's workloads and fee strategies
+func (s *server) Info(args struct{}, reply *Info) error {
+	for name := range s.workloads {
+		reply.Workloads = append(reply.Workloads, name)
+	}
+	for name := range s.fees {
+		reply.FeeStrategies = append(reply.FeeStrategies, name)
+	}
+	sort.Strings(reply.Workloads)
+	sort.Strings(reply.FeeStrategies)
+	return nil
+}
+
+// Build calls the named workload
+func (s *server) Build(args BuildArgs, reply *Tx) error {
+	workload, ok := s.workloads[args.Workload]
+	if !ok {
+		return fmt.Errorf("unknown workload %q", args.Workload)
+	}
+	tx, err := workload.Build(args.Request)
+	if err != nil {
+		return err
+	}
+	*reply = *tx
+	return nil
+}
+
+// GasPrice calls the named fee strategy
+func (s *server) GasPrice(args GasPriceArgs, reply *big.Int) error {
+	strategy, ok := s.fees[args.Strategy]
+	if !ok {
+		return fmt.Errorf("unknown fee strategy %q", args.Strategy)
+	}
+	price, err := strategy.GasPrice(args.Suggested)
+	if err != nil {
+		return err
+	}
+	reply.Set(price)
+	return nil
+}
+
+// Serve answers the simulator until it stops the plugin. It is called from the
+// plugin's main with everything the plugin provides.
+func Serve(workloads map[string]Workload, fees map[string]FeeStrategy) error {
+	goplugin.Serve(&goplugin.ServeConfig{
+		HandshakeConfig: Handshake,
+		Plugins:         pluginSet(&server{workloads: workloads, fees: fees}),
+		Logger:          quietLogger(),
+	})
+	return nil
+}
+
+// pluginSet returns the go-plugin plugins of one side: the plugin's serving srv, or
+// the simulator's with srv nil
+func pluginSet(srv *server) map[string]goplugin.Plugin {
+	return map[string]goplugin.Plugin{pluginName: &rpcPlugin{server: srv}}
+}
+
+// quietLogger is go-plugin's logger on both sides: its own messages only matter
+// when something breaks
+func quietLogger() hclog.Logger {
+	return hclog.New(&hclog.LoggerOptions{Name: "plugin", Output: os.Stderr, Level: hclog.Error})
+}
@@ -0,0 +1,119 @@
+package plugin
+
+import (
+	"errors"
+	"math/big"
+	"net/rpc"
+	"os"
+	"testing"
+
+	goplugin "github.com/hashicorp/go-plugin"
+)
+
+type fixedWorkload struct{}
+
+func (fixedWorkload) Build(req TxRequest) (*Tx, error) {
+	if req.Seq == 0 {
+		return nil, errors.New("seq starts at 1")
+	}
+	return &Tx{Value: big.NewInt(int64(req.Seq)), Data: []byte{byte(req.Wallet)}, GasLimit: 30000}, nil
+}
+
+type doubleFee struct{}
+
+func (doubleFee) GasPrice(suggested *big.Int) (*big.Int, error) {
+	return new(big.Int).Mul(suggested, big.NewInt(2)), nil
+}
+
+// serveEnv makes the test binary serve the test plugins instead of running the tests,
+// so Open can start it as a real plugin process
+const serveEnv = "SIMULATOR_PLUGIN_TEST_SERVE"
+
+func TestMain(m *testing.M) {
+	if os.Getenv(serveEnv) != "" {
+		Serve(map[string]Workload{"fixed": fixedWorkload{}}, map[string]FeeStrategy{"double": doubleFee{}})
+		return
+	}
+	os.Exit(m.Run())
+}
+
+func TestOpen(t *testing.T) {
+	t.Setenv(serveEnv, "1")
+	client, err := Open(os.Args[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+	if info := client.Info(); len(info.Workloads) != 1 || info.Workloads[0] != "fixed" || len(info.FeeStrategies) != 1 {
+		t.Errorf("unexpected plugin info: %+v", info)
+	}
+	tx, err := (&remoteWorkload{client: client, name: "fixed"}).Build(TxRequest{Seq: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tx.Value.Int64() != 2 {
+		t.Errorf("expected value 2, got %s", tx.Value)
+	}
+}
+
+func TestPlugin(t *testing.T) {
+	conn, _ := goplugin.TestPluginRPCConn(t, pluginSet(&server{
+		workloads: map[string]Workload{"fixed": fixedWorkload{}},
+		fees:      map[string]FeeStrategy{"double": doubleFee{}},
+	}), nil)
+	defer conn.Close()
+	raw, err := conn.Dispense(pluginName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client, err := newClient("test", nil, raw.(*rpc.Client))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	r := &Registry{workloads: make(map[string]*remoteWorkload), feeStrategies: make(map[string]*remoteFeeStrategy)}
+	if err := r.add(client); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("Workload", func(t *testing.T) {
+		workload, err := r.Workload("fixed")
+		if err != nil {
+			t.Fatal(err)
+		}
+		tx, err := workload.Build(TxRequest{Wallet: 7, Seq: 3})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if tx.Value.Int64() != 3 || len(tx.Data) != 1 || tx.Data[0] != 7 || tx.GasLimit != 30000 {
+			t.Errorf("unexpected transaction: %+v", tx)
+		}
+		if _, err := workload.Build(TxRequest{}); err == nil {
+			t.Error("expected the workload's error to be returned")
+		}
+	})
+
+	t.Run("FeeStrategy", func(t *testing.T) {
+		strategy, err := r.FeeStrategy("double")
+		if err != nil {
+			t.Fatal(err)
+		}
+		price, err := strategy.GasPrice(big.NewInt(21))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if price.Int64() != 42 {
+			t.Errorf("expected 42, got %s", price)
+		}
+	})
+
+	t.Run("UnknownAndDuplicateNames", func(t *testing.T) {
+		if _, err := r.Workload("missing"); err == nil {
+			t.Error("expected an error for an unknown workload")
+		}
+		if err := r.add(client); err == nil {
+			t.Error("expected an error for a workload provided twice")
+		}
+	})
+}
@@ -23,7 +23,7 @@ fi
 
 # Build the project
 echo "Building project..."
-go build -o simulator cmd/simulator/main.go
+go build -o simulator ./cmd/simulator
 
 if [ $? -ne 0 ]; then
     echo "Error: Build failed"
@@ -97,5 +97,5 @@ echo "To run the simulator:"
 echo "  ./simulator"
 echo ""
 echo "Or:"
-echo "  go run cmd/simulator/main.go"
+echo "  go run ./cmd/simulator"
 